@@ -30,6 +30,7 @@ import (
 	"notiboy/pkg/repo/driver/medium"
 	"notiboy/pkg/usecases"
 	"notiboy/utilities"
+	"notiboy/utilities/jwt"
 )
 
 func initMediums(ctx context.Context) (*medium.DiscordMessenger, *medium.EmailClient) {
@@ -69,6 +70,10 @@ func Run() {
 	utilities.InitLogger(conf.LogLevel)
 	log := utilities.NewLogger("run")
 
+	if _, err = jwt.PublicKeySet(); err != nil {
+		log.WithError(err).Fatal("unable to load jwt keys")
+	}
+
 	if conf.Mode != "local" {
 		discordMsgr, emailClient := initMediums(ctx)
 		defer func() {
@@ -90,6 +95,9 @@ func Run() {
 		log.Fatal("unable to create cassandra session ", err.Error())
 	}
 
+	log.Info("Initialising webhook dispatcher")
+	go medium.NewWebhookDispatcher().SpawnSender(ctx)
+
 	log.Info("Initialising NFD client")
 	dns.InitDNSClient(ctx)
 
@@ -131,23 +139,50 @@ func Run() {
 		UserRepo := repoLib.NewUserRepo(session, conf)
 		OptinRepo := repoLib.NewOptinRepo(session, conf, channelRepo)
 		verifyRepo := repoLib.NewVerifyRepo(session, conf)
+		analyticsRepo := repoLib.NewAnalyticsRepo(session, conf)
+		webhookRepo := repoLib.NewWebhookRepo(session, conf)
+		linkRepo := repoLib.NewLinkRepo(session, conf, billingRepo)
+		exportRepo := repoLib.NewExportRepo(session, conf)
+		deletionRepo := repoLib.NewDeletionRepo(session, conf, linkRepo)
+		memberRepo := repoLib.NewChannelMemberRepo(session, conf)
+		transferRepo := repoLib.NewChannelTransferRepo(session, conf)
 		repo := repoLib.NewRepo(session, conf)
 
 		// initializing usecases
-		billingUsecases := usecases.NewBillingUsecases(billingRepo, UserRepo)
+		billingUsecases := usecases.NewBillingUsecases(billingRepo, UserRepo, memberRepo)
 		notificationUsecases := usecases.NewNotificationUsecases(
-			notificationRepo, UserRepo, verifyRepo, channelRepo, OptinRepo, notificationWS,
+			notificationRepo, UserRepo, verifyRepo, channelRepo, OptinRepo, memberRepo, notificationWS,
 		)
-		channelUseCases := usecases.NewChannelUseCases(channelRepo, UserRepo)
+		channelUseCases := usecases.NewChannelUseCases(channelRepo, UserRepo, memberRepo)
 		chatUseCases := usecases.NewChatUseCases(chatRepo, UserRepo, chatWS)
-		UserUseCases := usecases.NewUserUseCases(UserRepo)
-		OptinUseCases := usecases.NewOptinUseCases(OptinRepo)
+		UserUseCases := usecases.NewUserUseCases(UserRepo, deletionRepo)
+		OptinUseCases := usecases.NewOptinUseCases(OptinRepo, memberRepo, UserRepo)
 		verifyUseCases := usecases.NewVerifyUseCases(verifyRepo)
+		analyticsUseCases := usecases.NewAnalyticsUseCases(analyticsRepo, memberRepo, UserRepo)
+		webhookUseCases := usecases.NewWebhookUseCases(webhookRepo, memberRepo)
 		useCases := usecases.NewUseCases(repo)
+		linkUseCases := usecases.NewLinkUseCases(linkRepo, UserRepo, useCases)
+		exportUseCases := usecases.NewExportUseCases(exportRepo)
+		memberUseCases := usecases.NewChannelMemberUseCases(memberRepo, UserRepo)
+		transferUseCases := usecases.NewChannelTransferUseCases(
+			transferRepo, channelRepo, memberRepo, UserRepo, useCases,
+		)
 
 		log.Info("Initialising notification scheduler")
 		usecases.NotificationSchedulerStub(ctx, usecases.GetNotificationUsecases())
 
+		log.Info("Initialising account deletion worker")
+		usecases.AccountDeletionWorker(ctx, deletionRepo)
+
+		log.Info("Initialising channel domain checker")
+		usecases.DomainProofWorker(ctx, channelRepo)
+
+		log.Info("Initialising token gate checker")
+		usecases.TokenGateWorker(ctx, OptinRepo, channelRepo, UserRepo, memberRepo)
+
+		log.Info("Initialising channel discovery ranker")
+		usecases.DiscoveryWorker(ctx, channelRepo)
+
 		// initializing middleware
 		m := middlewares.NewMiddlewares(useCases)
 
@@ -162,6 +197,12 @@ func Run() {
 		UserControllers := controllersLib.NewUserController(api, UserUseCases, m)
 		OptinControllers := controllersLib.NewOptinController(api, OptinUseCases, m)
 		verifyControllers := controllersLib.NewVerifyController(api, verifyUseCases, m)
+		analyticsControllers := controllersLib.NewAnalyticsController(api, analyticsUseCases, m)
+		webhookControllers := controllersLib.NewWebhookController(api, webhookUseCases, m)
+		linkControllers := controllersLib.NewLinkController(api, linkUseCases, m)
+		exportControllers := controllersLib.NewExportController(api, exportUseCases, m)
+		memberControllers := controllersLib.NewChannelMemberController(api, memberUseCases, m)
+		transferControllers := controllersLib.NewChannelTransferController(api, transferUseCases, m)
 		controllers := controllersLib.NewController(api, useCases, m)
 
 		// init the routes
@@ -172,13 +213,43 @@ func Run() {
 		UserControllers.InitRoutes()
 		OptinControllers.InitRoutes()
 		verifyControllers.InitRoutes()
+		analyticsControllers.InitRoutes()
+		webhookControllers.InitRoutes()
+		linkControllers.InitRoutes()
+		exportControllers.InitRoutes()
+		memberControllers.InitRoutes()
+		transferControllers.InitRoutes()
 		controllers.InitRoutes()
+
+		router.GET("/.well-known/jwks.json", controllers.JWKS)
 	}
 
 	// run the app
 	launch(ctx, cancelFn, router)
 }
 
+// RotateJWTKey generates a new token signing key in jwt.keys_dir. Instances pick it up within a
+// minute and sign new tokens with it, tokens signed by older keys stay valid while their keys are kept.
+// Servers outside local mode don't start without a key, deployments that signed with the development
+// key keep it through the first rotation and remove its file once the tokens it signed expired.
+func RotateJWTKey() {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		logrus.Fatalf("unable to initialize environment variables %s", err.Error())
+	}
+
+	if conf.JWT.KeysDir == "" {
+		logrus.Fatal("jwt.keys_dir is not set")
+	}
+
+	kid, err := jwt.GenerateKey(conf.JWT.KeysDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to generate jwt signing key")
+	}
+
+	fmt.Printf("Generated jwt signing key %s in %s\n", kid, conf.JWT.KeysDir)
+}
+
 func initRouter(conf *config.NotiboyConfModel) *gin.Engine {
 
 	router := gin.Default()
@@ -192,7 +263,8 @@ func initRouter(conf *config.NotiboyConfModel) *gin.Engine {
 				AllowMethods: []string{"PUT", "PATCH", "POST", "DELETE", "GET", "OPTIONS"},
 				AllowHeaders: []string{
 					"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept",
-					"origin", "Cache-Control", "X-USER-ADDRESS", "X-SIGNED-TXN", "HOST",
+					"origin", "Cache-Control", "X-USER-ADDRESS", "X-SIGNED-TXN", "HOST", "X-NONCE", "X-SIGNATURE",
+					"X-PUBLIC-KEY",
 				},
 				AllowCredentials: true,
 				MaxAge:           12 * time.Hour,
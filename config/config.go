@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 
@@ -12,6 +13,13 @@ import (
 
 const configFilePath = "/etc/notiboy/config.yaml"
 
+// minTrackingSecretLength is the length below which the tracking secret is too easily guessed to sign links with
+const minTrackingSecretLength = 32
+
+// localTrackingSecret signs tracking links in local mode when no tracking secret is set, it is public and refused
+// in any other mode
+const localTrackingSecret = "notiboy-local-development-tracking-secret"
+
 var (
 	notiboyConf   *NotiboyConfModel
 	ServerBaseURL string
@@ -43,6 +51,10 @@ func loadViperConfig(filePath string) error {
 		return fmt.Errorf("error loading viper config to struct: %w", err)
 	}
 
+	if err = validateConfig(notiboyConf); err != nil {
+		return err
+	}
+
 	val, err := json.MarshalIndent(*notiboyConf, "", "  ")
 	if err == nil {
 		fmt.Println(string(val))
@@ -68,17 +80,53 @@ func loadViperConfig(filePath string) error {
 func setEnvConf() {
 	viper.BindEnv("db.username", "NOTIBOY_DB_USERNAME")
 	viper.BindEnv("db.password", "NOTIBOY_DB_PASSWORD")
+	viper.BindEnv("tracking.secret", "NOTIBOY_TRACKING_SECRET")
+	viper.BindEnv("jwt.keys", "NOTIBOY_JWT_KEYS")
+	viper.BindEnv("jwt.signing_kid", "NOTIBOY_JWT_SIGNING_KID")
 }
 
 func setDefault() {
 	viper.SetDefault("supported", []string{consts.Algorand})
 	viper.SetDefault("mode", "stage")
 	viper.SetDefault("auto_onboard_users", true)
+	viper.SetDefault("legacy_txn_login", true)
 	viper.SetDefault("chat.group.ttl", 604800)
 	viper.SetDefault("chat.personal.ttl", 604800)
+	viper.SetDefault("ttl.login_nonce", 300)
+	viper.SetDefault("ttl.channel_transfer", 604800)
+	viper.SetDefault("access_token_expiry", "15m")
+	viper.SetDefault("ttl.pat_grace_period", "24h")
+	viper.SetDefault("account_deletion_interval", "1m")
+	viper.SetDefault("domain_check_interval", "6h")
+	viper.SetDefault("token_gate_check_interval", "12h")
+	viper.SetDefault("discovery_interval", "1h")
+}
+
+// validateConfig refuses configurations the server cannot run safely with
+func validateConfig(conf *NotiboyConfModel) error {
+	if conf.Mode == "local" && conf.Tracking.Secret == "" {
+		conf.Tracking.Secret = localTrackingSecret
+	}
+	if conf.Mode != "local" && conf.Tracking.Secret == localTrackingSecret {
+		return errors.New("tracking.secret is the local development secret, it is only accepted in local mode")
+	}
+
+	if len(conf.Tracking.Secret) < minTrackingSecretLength {
+		return fmt.Errorf(
+			"tracking.secret must be set to at least %d characters, NOTIBOY_TRACKING_SECRET can set it",
+			minTrackingSecretLength,
+		)
+	}
+
+	return nil
 }
 
 // GetConfig returns env config
 func GetConfig() *NotiboyConfModel {
 	return notiboyConf
 }
+
+// SetConfig replaces the configuration, for tests that don't read a config file
+func SetConfig(conf *NotiboyConfModel) {
+	notiboyConf = conf
+}
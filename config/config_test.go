@@ -31,3 +31,63 @@ func Test_loadViperConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		secret     string
+		wantSecret string
+		wantErr    bool
+	}{
+		{
+			name:    "empty tracking secret",
+			mode:    "stage",
+			secret:  "",
+			wantErr: true,
+		},
+		{
+			name:    "short tracking secret",
+			mode:    "stage",
+			secret:  "0123456789abcdef",
+			wantErr: true,
+		},
+		{
+			name:       "tracking secret",
+			mode:       "stage",
+			secret:     "0123456789abcdef0123456789abcdef",
+			wantSecret: "0123456789abcdef0123456789abcdef",
+			wantErr:    false,
+		},
+		{
+			name:       "local tracking secret in local mode",
+			mode:       "local",
+			secret:     "",
+			wantSecret: localTrackingSecret,
+			wantErr:    false,
+		},
+		{
+			name:    "local tracking secret outside local mode",
+			mode:    "prod",
+			secret:  localTrackingSecret,
+			wantErr: true,
+		},
+		{
+			name:    "short tracking secret in local mode",
+			mode:    "local",
+			secret:  "0123456789abcdef",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &NotiboyConfModel{Mode: tt.mode, Tracking: Tracking{Secret: tt.secret}}
+			if err := validateConfig(conf); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && conf.Tracking.Secret != tt.wantSecret {
+				t.Errorf("validateConfig() secret = %q, want %q", conf.Tracking.Secret, tt.wantSecret)
+			}
+		})
+	}
+}
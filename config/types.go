@@ -2,9 +2,15 @@ package config
 
 type NotiboyConfModel struct {
 	AutoOnboardUsers          bool      `mapstructure:"auto_onboard_users"`
+	LegacyTxnLogin            bool      `mapstructure:"legacy_txn_login"`
 	LogLevel                  string    `mapstructure:"log_level"`
 	LoginTokenExpiry          string    `mapstructure:"login_token_expiry"`
+	AccessTokenExpiry         string    `mapstructure:"access_token_expiry"`
 	MembershipCheckerInterval string    `mapstructure:"membership_checker_interval"`
+	AccountDeletionInterval   string    `mapstructure:"account_deletion_interval"`
+	DomainCheckInterval       string    `mapstructure:"domain_check_interval"`
+	TokenGateCheckInterval    string    `mapstructure:"token_gate_check_interval"`
+	DiscoveryInterval         string    `mapstructure:"discovery_interval"`
 	Mode                      string    `mapstructure:"mode"`
 	AdminUsers                []string  `mapstructure:"admin_users"`
 	Server                    Server    `mapstructure:"server"`
@@ -20,6 +26,8 @@ type NotiboyConfModel struct {
 	Firebase                  Firebase  `mapstructure:"firebase"`
 	Chat                      Chat      `mapstructure:"chat"`
 	Dns                       Dns       `mapstructure:"dns"`
+	Tracking                  Tracking  `mapstructure:"tracking"`
+	JWT                       JWT       `mapstructure:"jwt"`
 }
 
 type Algorand struct {
@@ -112,12 +120,15 @@ type Server struct {
 }
 
 type TTL struct {
-	Metrics       int64  `mapstructure:"metrics"`
-	VerifyToken   int64  `mapstructure:"verify_token"`
-	Notifications int64  `mapstructure:"notifications"`
-	FCM           int64  `mapstructure:"fcm"`
-	PAToken       string `mapstructure:"pat_token"`
-	UserTotalSend int64  `mapstructure:"user_total_send"`
+	Metrics         int64  `mapstructure:"metrics"`
+	VerifyToken     int64  `mapstructure:"verify_token"`
+	Notifications   int64  `mapstructure:"notifications"`
+	FCM             int64  `mapstructure:"fcm"`
+	PAToken         string `mapstructure:"pat_token"`
+	PATGrace        string `mapstructure:"pat_grace_period"`
+	UserTotalSend   int64  `mapstructure:"user_total_send"`
+	LoginNonce      int64  `mapstructure:"login_nonce"`
+	ChannelTransfer int64  `mapstructure:"channel_transfer"`
 }
 
 type Firebase struct {
@@ -178,3 +189,13 @@ type XrpnsDomains struct {
 	Path  string `mapstructure:"path"`
 	Token string `mapstructure:"token"`
 }
+
+type Tracking struct {
+	Secret string `mapstructure:"secret"`
+}
+
+type JWT struct {
+	KeysDir    string `mapstructure:"keys_dir"`
+	Keys       string `mapstructure:"keys"`
+	SigningKid string `mapstructure:"signing_kid"`
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"time"
 	_ "time/tzdata"
 
@@ -14,5 +15,11 @@ func main() {
 	}
 
 	time.Local = loc
+
+	if len(os.Args) > 1 && os.Args[1] == "rotate-jwt-key" {
+		app.RotateJWTKey()
+		return
+	}
+
 	app.Run()
 }
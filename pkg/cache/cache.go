@@ -3,6 +3,7 @@ package cache
 func Init() {
 	_ = GetChannelNameCache()
 	_ = GetChannelVerifyCache()
+	_ = GetChannelSearchIndex()
 	_ = InitBlockedUserCache()
 	_ = InitUserContactsCache()
 	_ = InitUserGroupsCache()
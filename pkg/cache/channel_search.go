@@ -0,0 +1,316 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+var channelSearchIndexObject *ChannelSearchIndex
+
+// ChannelSearchIndex indexes the public channels of every chain by name, description, category and tags. A
+// search matches names exactly, by prefix, by substring and fuzzily, and descriptions by word.
+type ChannelSearchIndex struct {
+	chainChannels map[string]map[string]*searchDoc
+	sync.RWMutex
+}
+
+// ChannelSearchDoc is what a channel is searched by
+type ChannelSearchDoc struct {
+	AppID       string
+	Name        string
+	Description string
+	Category    string
+	Tags        []string
+}
+
+type searchDoc struct {
+	appID     string
+	name      string
+	nameWords []string
+	descWords []string
+	keywords  map[string]struct{}
+}
+
+type searchHit struct {
+	appID string
+	name  string
+	score int
+}
+
+func GetChannelSearchIndex() *ChannelSearchIndex {
+	log := utilities.NewLogger("GetChannelSearchIndex")
+
+	if channelSearchIndexObject != nil {
+		return channelSearchIndexObject
+	}
+
+	channelSearchIndexObject = new(ChannelSearchIndex)
+	channelSearchIndexObject.RWMutex = sync.RWMutex{}
+
+	chainChannels, err := loadSearchDocs()
+	if err != nil {
+		log.WithError(err).Fatal("failed to init cache")
+	} else {
+		log.Info("Loaded cache")
+	}
+	channelSearchIndexObject.chainChannels = chainChannels
+
+	return channelSearchIndexObject
+}
+
+// Reload indexes the channels again, picking up the channels created or changed through other instances. A
+// channel put while reloading is indexed by the next reload.
+func (c *ChannelSearchIndex) Reload() error {
+	chainChannels, err := loadSearchDocs()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.chainChannels = chainChannels
+
+	return nil
+}
+
+// Put indexes the channel, replacing what it was indexed by before
+func (c *ChannelSearchIndex) Put(chain string, doc ChannelSearchDoc) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.chainChannels[chain]; !ok {
+		c.chainChannels[chain] = make(map[string]*searchDoc)
+	}
+
+	c.chainChannels[chain][doc.AppID] = newSearchDoc(doc)
+}
+
+// Remove drops the channel from the index
+func (c *ChannelSearchIndex) Remove(chain, appID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.chainChannels[chain]; !ok {
+		return
+	}
+
+	delete(c.chainChannels[chain], appID)
+}
+
+// Search returns the app IDs of up to limit channels matching query, best matches first
+func (c *ChannelSearchIndex) Search(chain, query string, limit int) []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []string{}
+	}
+	terms := searchWords(query)
+
+	hits := make([]searchHit, 0)
+	for _, doc := range c.chainChannels[chain] {
+		if score := doc.score(query, terms); score > 0 {
+			hits = append(hits, searchHit{appID: doc.appID, name: doc.name, score: score})
+		}
+	}
+
+	sort.Slice(
+		hits, func(i, j int) bool {
+			if hits[i].score != hits[j].score {
+				return hits[i].score > hits[j].score
+			}
+			return hits[i].name < hits[j].name
+		},
+	)
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	appIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		appIDs = append(appIDs, hit.appID)
+	}
+
+	return appIDs
+}
+
+// loadSearchDocs reads the public channels of every chain to index
+func loadSearchDocs() (map[string]map[string]*searchDoc, error) {
+	cass := db.GetCassandraSession()
+	chainChannels := make(map[string]map[string]*searchDoc)
+
+	chains := config.GetConfig().Chain.Supported
+
+	var (
+		appID       string
+		name        string
+		description string
+		category    string
+		tags        []string
+		visibility  string
+	)
+	for _, chain := range chains {
+		chainChannels[chain] = make(map[string]*searchDoc)
+
+		for _, tbl := range []string{consts.VerifiedChannelInfo, consts.UnverifiedChannelInfo} {
+			query := fmt.Sprintf(
+				"SELECT app_id, name, description, category, tags, visibility FROM %s.%s WHERE chain = ?",
+				config.GetConfig().DB.Keyspace, tbl,
+			)
+			iter := cass.Query(query, chain).Iter()
+
+			for iter.Scan(&appID, &name, &description, &category, &tags, &visibility) {
+				if visibility != "" && visibility != consts.ChannelPublic {
+					continue
+				}
+				chainChannels[chain][appID] = newSearchDoc(
+					ChannelSearchDoc{
+						AppID:       appID,
+						Name:        name,
+						Description: description,
+						Category:    category,
+						Tags:        tags,
+					},
+				)
+			}
+
+			if err := iter.Close(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return chainChannels, nil
+}
+
+func newSearchDoc(doc ChannelSearchDoc) *searchDoc {
+	name := strings.ToLower(doc.Name)
+
+	keywords := make(map[string]struct{})
+	if doc.Category != "" {
+		keywords[doc.Category] = struct{}{}
+	}
+	for _, tag := range doc.Tags {
+		keywords[tag] = struct{}{}
+	}
+
+	return &searchDoc{
+		appID:     doc.AppID,
+		name:      name,
+		nameWords: searchWords(name),
+		descWords: searchWords(doc.Description),
+		keywords:  keywords,
+	}
+}
+
+// score rates how well the channel matches query, 0 if it doesn't
+func (d *searchDoc) score(query string, terms []string) int {
+	switch {
+	case d.name == query:
+		return 100
+	case strings.HasPrefix(d.name, query):
+		return 80
+	case strings.Contains(d.name, query):
+		return 60
+	}
+
+	score := 0
+	for _, term := range terms {
+		if _, ok := d.keywords[term]; ok {
+			score += 30
+		} else if hasWordWithPrefix(d.nameWords, term) {
+			score += 25
+		} else if dist := fuzzyDistance(d.nameWords, term); dist >= 0 {
+			score += 20 - 5*dist
+		} else if hasWordWithPrefix(d.descWords, term) {
+			score += 10
+		}
+	}
+
+	return score
+}
+
+// hasWordWithPrefix tells whether one of words starts with prefix
+func hasWordWithPrefix(words []string, prefix string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fuzzyDistance returns the fewest typos that turn the start of one of words into term, -1 if it takes more than
+// a term of its length tolerates. Terms too short to tell apart aren't matched fuzzily.
+func fuzzyDistance(words []string, term string) int {
+	runes := []rune(term)
+	if len(runes) < 4 {
+		return -1
+	}
+
+	maxEdits := 1
+	if len(runes) > 7 {
+		maxEdits = 2
+	}
+
+	best := -1
+	for _, word := range words {
+		// a mistyped prefix of a longer word still matches
+		candidate := []rune(word)
+		if len(candidate) > len(runes)+maxEdits {
+			candidate = candidate[:len(runes)]
+		}
+
+		if dist := editDistance(string(candidate), term); dist <= maxEdits && (best < 0 || dist < best) {
+			best = dist
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// searchWords splits text into its lowercase words
+func searchWords(text string) []string {
+	return strings.FieldsFunc(
+		strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		},
+	)
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChannelSearchIndex_Search(t *testing.T) {
+	index := &ChannelSearchIndex{chainChannels: map[string]map[string]*searchDoc{}}
+	for _, doc := range []ChannelSearchDoc{
+		{AppID: "1", Name: "Crypto News"},
+		{AppID: "2", Name: "News"},
+		{AppID: "3", Name: "Daily Digest", Description: "The news of the day", Tags: []string{"defi"}},
+		{AppID: "4", Name: "Weather", Category: "news"},
+	} {
+		index.Put("algorand", doc)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "name before keywords and descriptions", query: "news", want: []string{"2", "1", "4", "3"}},
+		{name: "name prefix", query: "cry", want: []string{"1"}},
+		{name: "typo", query: "wether", want: []string{"4"}},
+		{name: "tag", query: "DeFi", want: []string{"3"}},
+		{name: "no match", query: "sports", want: []string{}},
+		{name: "empty", query: " ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := index.Search("algorand", tt.query, 10); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Search() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+
+	index.Remove("algorand", "2")
+	if got := index.Search("algorand", "news", 1); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("Search() after Remove = %v, want [1]", got)
+	}
+}
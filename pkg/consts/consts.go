@@ -10,23 +10,309 @@ const (
 	AdminUser     = "ADMIN_USER"
 	UserChain     = "USER_CHAIN"
 	UserOnboarded = "USER_ON_BOARDED"
+	UserSession   = "USER_SESSION"
+	UserScopes    = "USER_SCOPES"
+	// BillingAddress is the address whose billing a channel billing request manages, that of the channel owner
+	BillingAddress = "BILLING_ADDRESS"
 )
 
+// Scopes a personal access token can be restricted to
+const (
+	ScopeNotificationsSend = "notifications:send"
+	ScopeNotificationsRead = "notifications:read"
+	ScopeChannelsRead      = "channels:read"
+	ScopeChannelsWrite     = "channels:write"
+	ScopeOptinsWrite       = "optins:write"
+	ScopeBillingRead       = "billing:read"
+	ScopeBillingWrite      = "billing:write"
+	ScopeUsersRead         = "users:read"
+	ScopeUsersWrite        = "users:write"
+	ScopeChatRead          = "chat:read"
+	ScopeChatWrite         = "chat:write"
+)
+
+// PATScopes lists the scopes that can be granted to a personal access token
+var PATScopes = []string{
+	ScopeNotificationsSend, ScopeNotificationsRead, ScopeChannelsRead, ScopeChannelsWrite, ScopeOptinsWrite,
+	ScopeBillingRead, ScopeBillingWrite, ScopeUsersRead, ScopeUsersWrite, ScopeChatRead, ScopeChatWrite,
+}
+
+// ChannelScopes can be narrowed down to a single channel as <scope>:<app_id>
+var ChannelScopes = []string{
+	ScopeNotificationsSend, ScopeNotificationsRead, ScopeChannelsRead, ScopeChannelsWrite,
+}
+
 const (
 	STATUS_CHANNEL_LIMIT_EXCEEDED = "CHANNEL_LIMIT_EXCEEDED"
 	STATUS_ACTIVE                 = "ACTIVE"
 	STATUS_CHANNEL_ORPHANED       = "CHANNEL_ORPHANED"
+	STATUS_DELETING               = "DELETING"
 )
 
 const (
 	OPTIN_OPTOUT_STATS      = "optin_optout_analytics"
 	CHANNEL_READ_SENT_STATS = "channel_read_sent_analytics"
+	CHANNEL_ADVANCED_STATS  = "channel_advanced_analytics"
+	CHANNEL_DATA_EXPORT     = "channel_data_export"
+)
+
+const (
+	ExportTraction      = "traction"
+	ExportSentRead      = "sent_read"
+	ExportNotifications = "notifications"
+	ExportSubscribers   = "subscribers"
+)
+
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+const (
+	NotificationRecalled = "notification_recalled"
+	NotificationEdited   = "notification_edited"
+)
+
+// ReceiverBatchSize caps the receivers whose inbox rows are written in one batch, larger batches go over the
+// batch size Cassandra accepts
+const ReceiverBatchSize = 50
+
+const (
+	ReachSent      = "sent"
+	ReachDelivered = "delivered"
+	ReachRead      = "read"
+	ReachClicked   = "clicked"
+)
+
+const (
+	WebhookOptin               = "optin"
+	WebhookOptout              = "optout"
+	WebhookNotificationRead    = "notification.read"
+	WebhookNotificationClicked = "notification.clicked"
+	WebhookDeliveryFailed      = "delivery.failed"
+)
+
+// WebhookEvents lists the events a channel webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookOptin, WebhookOptout, WebhookNotificationRead, WebhookNotificationClicked, WebhookDeliveryFailed,
+}
+
+// MaxChannelWebhooks is the number of webhooks a channel can register
+const MaxChannelWebhooks = 5
+
+// Webhook deliveries wait in an outbox spread over WebhookOutboxShards partitions until they succeed or are given
+// up, every instance delivers them with at most WebhookWorkers at a time
+const (
+	WebhookOutboxShards = 16
+	WebhookWorkers      = 8
+)
+
+// Roles of the members of a channel. The owner of the channel always has the owner role.
+const (
+	ChannelRoleOwner   = "owner"
+	ChannelRoleAdmin   = "admin"
+	ChannelRoleSender  = "sender"
+	ChannelRoleAnalyst = "analyst"
+)
+
+// Status of a channel membership, an invited member gets its role once it accepts the invite
+const (
+	MemberInvited = "INVITED"
+	MemberActive  = "ACTIVE"
+)
+
+// What the members of a channel are permitted to do
+const (
+	ChannelPermManageMembers = "manage_members"
+	ChannelPermBilling       = "billing"
+	ChannelPermDelete        = "delete"
+	ChannelPermEdit          = "edit"
+	ChannelPermSend          = "send"
+	ChannelPermStats         = "stats"
+	ChannelPermTransfer      = "transfer"
+	ChannelPermSubscribers   = "subscribers"
+)
+
+var ChannelRolePermissions = map[string][]string{
+	ChannelRoleOwner: {
+		ChannelPermManageMembers, ChannelPermBilling, ChannelPermDelete, ChannelPermEdit, ChannelPermSend,
+		ChannelPermStats, ChannelPermTransfer, ChannelPermSubscribers,
+	},
+	ChannelRoleAdmin:   {ChannelPermEdit, ChannelPermSend, ChannelPermStats, ChannelPermSubscribers},
+	ChannelRoleSender:  {ChannelPermSend},
+	ChannelRoleAnalyst: {ChannelPermStats},
+}
+
+// Visibility of a channel. An unlisted channel is left out of the listings, a private one is also opted in to
+// only with an invite or once the owner approves the request.
+const (
+	ChannelPublic   = "public"
+	ChannelUnlisted = "unlisted"
+	ChannelPrivate  = "private"
+)
+
+var ChannelVisibilities = []string{ChannelPublic, ChannelUnlisted, ChannelPrivate}
+
+// Kind of holding a token gated channel requires to opt in. A fungible token is an ASA on Algorand and an issued
+// currency on XRPL, an NFT is an ASA on Algorand and an NFT of the issuer, optionally of a taxon, on XRPL.
+const (
+	TokenGateFungible = "token"
+	TokenGateNFT      = "nft"
+)
+
+var TokenGateKinds = []string{TokenGateFungible, TokenGateNFT}
+
+// ChannelCategories are the categories a channel is listed under in discovery
+var ChannelCategories = []string{
+	"defi", "nft", "gaming", "dao", "infrastructure", "marketplace", "news", "security", "education", "community",
+	"other",
+}
+
+// MaxChannelTags caps the tags of a channel
+const MaxChannelTags = 10
+
+// Rankings of channels computed for discovery, over the last DiscoveryWindowDays days. The channels similar to a
+// channel are ranked as RankingSimilar followed by its app ID.
+const (
+	RankingTrending      = "trending"
+	RankingActiveSenders = "active_senders"
+	RankingSimilar       = "similar:"
+
+	DiscoveryWindowDays = 7
+	// RankingSize caps the channels kept in a ranking
+	RankingSize = 50
+	// ChannelSearchLimit caps the channels a search returns
+	ChannelSearchLimit = 50
+	// SimilarityMaxOptins caps the channels of a subscriber that count as similar to each other, a subscriber of
+	// more says little about them and would make the pairs to compare grow with its channels squared
+	SimilarityMaxOptins = 100
+)
+
+// OptinSourceDirect is the source of opt-ins that didn't come through a tagged opt-in link
+const OptinSourceDirect = "direct"
+
+// Side of the PNG QR codes of opt-in links, in pixels
+const (
+	OptinQRDefaultSize = 256
+	OptinQRMinSize     = 128
+	OptinQRMaxSize     = 1024
+)
+
+// Status of a request to verify a channel, a verified channel can be revoked later on
+const (
+	VerificationPending  = "PENDING"
+	VerificationApproved = "APPROVED"
+	VerificationRejected = "REJECTED"
+	VerificationRevoked  = "REVOKED"
+)
+
+var VerificationStatuses = []string{
+	VerificationPending, VerificationApproved, VerificationRejected, VerificationRevoked,
+}
+
+// Status of a domain claimed by a channel, a verified domain that no longer carries its proof fails
+const (
+	DomainPending  = "PENDING"
+	DomainVerified = "VERIFIED"
+	DomainFailed   = "FAILED"
+)
+
+// How a domain proves it belongs to a channel
+const (
+	DomainProofDNS       = "dns"
+	DomainProofWellKnown = "well_known"
+)
+
+// Kinds of a change of the owner of a channel. A transfer is offered by the owner and accepted by the new owner,
+// an orphaned channel is adopted once an admin approves it.
+const (
+	ChannelTransfer = "TRANSFER"
+	ChannelAdoption = "ADOPTION"
+)
+
+var ChannelTransferKinds = []string{ChannelTransfer, ChannelAdoption}
+
+// Status of an account deletion job
+const (
+	DeletionPending   = "PENDING"
+	DeletionCompleted = "COMPLETED"
+)
+
+// Steps of an account deletion job in the order they run. Every step is safe to run again, a job
+// interrupted halfway resumes with the step it was running.
+const (
+	DeletionStepSessions       = "sessions"
+	DeletionStepLinks          = "linked_accounts"
+	DeletionStepChannels       = "channels"
+	DeletionStepOptins         = "optins"
+	DeletionStepNotifications  = "notifications"
+	DeletionStepChats          = "chats"
+	DeletionStepDevices        = "fcm_devices"
+	DeletionStepVerifications  = "verifications"
+	DeletionStepDNS            = "dns"
+	DeletionStepBilling        = "billing"
+	DeletionStepTransfers      = "channel_transfers"
+	DeletionStepVerifyRequests = "channel_verifications"
+	DeletionStepOptinRequests  = "channel_optin_requests"
+	DeletionStepInvites        = "channel_invites"
+	DeletionStepProfile        = "user_info"
+)
+
+var AccountDeletionSteps = []string{
+	DeletionStepSessions, DeletionStepLinks, DeletionStepChannels, DeletionStepOptins, DeletionStepNotifications,
+	DeletionStepChats, DeletionStepDevices, DeletionStepVerifications, DeletionStepDNS, DeletionStepBilling,
+	DeletionStepTransfers, DeletionStepVerifyRequests, DeletionStepOptinRequests, DeletionStepInvites,
+	DeletionStepProfile,
+}
+
+// Fields of a user profile
+const (
+	ProfileDisplayName = "display_name"
+	ProfileAvatar      = "avatar"
+	ProfileBio         = "bio"
+	ProfileLocale      = "locale"
+	ProfileTimezone    = "timezone"
+)
+
+// Who gets to see a field of a user profile, besides the user itself
+const (
+	ProfilePublic   = "public"
+	ProfileContacts = "contacts"
+	ProfilePrivate  = "private"
+)
+
+// DefaultProfilePrivacy is the visibility of the fields the user hasn't chosen one for
+var DefaultProfilePrivacy = map[string]string{
+	ProfileDisplayName: ProfilePublic,
+	ProfileAvatar:      ProfilePublic,
+	ProfileBio:         ProfilePublic,
+	ProfileLocale:      ProfileContacts,
+	ProfileTimezone:    ProfileContacts,
+}
+
+const (
+	MaxDisplayNameLength = 64
+	MaxBioLength         = 280
+)
+
+// Formats of an account data export
+const (
+	ExportJSON = "json"
+	ExportZIP  = "zip"
 )
 
+// TrackingPixel is a transparent 1x1 GIF served for email open tracking
+var TrackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
 const (
 	Inapp   = "inapp"
 	Email   = "email"
 	Discord = "discord"
+	Push    = "push"
 )
 
 const (
@@ -43,6 +329,9 @@ const (
 	UnverifiedChannelInfo = "unverified_channel_info"
 	ChannelName           = "channel_name"
 	ChannelUsers          = "channel_users"
+	ChannelSubscribers    = "channel_subscribers"
+	SubscribersByOptin    = "channel_subscribers_by_optin"
+	UserSubscriptions     = "user_subscriptions"
 
 	UserNotificationChannelMetrics      = "user_notification_channel_metrics"
 	NotificationChannelMetrics          = "channel_notification_metrics"
@@ -50,35 +339,72 @@ const (
 	NotificationTotalSendPerUserMetrics = "user_notification_send_metrics"
 	ChannelActivityMetrics              = "channel_activity_metrics"
 	ChannelTractionMetrics              = "channel_traction_metrics"
+	ChannelSourceMetrics                = "channel_source_metrics"
 	UserActivityMetrics                 = "user_activity_metrics"
 	GlobalStatistics                    = "global_stats"
-	NotificationTotalSent               = "notification_total_sent"
 	NotificationChannelCounter          = "channel_notification_counter"
-	NotificationEmailMediumReach        = "notification_email_reach"
-	NotificationAppMediumReach          = "notification_app_reach"
-	NotificationDiscordMediumReach      = "notification_discord_reach"
+	NotificationReach                   = "notification_reach"
 
-	VerifyInfo = "verify_info"
-	LoginInfo  = "login_info"
-	PATInfo    = "pa_token"
+	VerifyInfo        = "verify_info"
+	UserVerifications = "user_verifications"
+	LoginInfo         = "login_info"
+	UserLoginTokens   = "user_login_tokens"
+	LoginNonce        = "login_nonce"
+	Sessions          = "user_sessions"
+	PATInfo           = "pa_token"
 
 	NotificationInfo          = "notification_info"
 	ScheduledNotificationInfo = "scheduled_notification_info"
 	NotificationReadStatus    = "notification_read_status"
+	SentNotificationInfo      = "sent_notification_info"
+	SentNotificationsByTime   = "sent_notifications_by_time"
+	NotificationRevisions     = "notification_revisions"
+
+	UserNotifications          = "user_notifications"
+	UserScheduledNotifications = "user_scheduled_notifications"
+
+	ChannelWebhooks          = "channel_webhooks"
+	ChannelWebhookDeliveries = "channel_webhook_deliveries"
+	WebhookOutbox            = "webhook_outbox"
 
 	BillingHistoryTable = "billing_history"
 	BillingTable        = "billing"
 
 	FcmTable = "fcm"
 
-	ChatUserTable         = "user_chat"
-	ChatUserBlockTable    = "user_chat_block"
-	ChatUserContactsTable = "user_chat_contacts"
-	ChatGroupTable        = "group_chat"
-	ChatGroupInfoTable    = "group_chat_info"
-	ChatUserGroupTable    = "user_chat_groups"
+	ChatUserTable          = "user_chat"
+	ChatUserBlockTable     = "user_chat_block"
+	ChatUserContactsTable  = "user_chat_contacts"
+	ChatUserBlockedByTable = "user_chat_blocked_by"
+	ChatGroupTable         = "group_chat"
+	ChatGroupInfoTable     = "group_chat_info"
+	ChatUserGroupTable     = "user_chat_groups"
 
 	UserDNSTable = "user_dns"
+
+	LinkedAccounts = "linked_accounts"
+
+	AccountDeletions = "account_deletions"
+
+	ChannelMembers    = "channel_members"
+	UserChannelRoles  = "user_channel_roles"
+	ChannelTransfers  = "channel_transfers"
+	TransfersByTarget = "channel_transfers_by_target"
+
+	ChannelVerifications     = "channel_verifications"
+	VerificationsByStatus    = "channel_verifications_by_status"
+	UserVerificationRequests = "user_verification_requests"
+	ChannelDomains           = "channel_domains"
+	ChannelInvites           = "channel_invites"
+	UserChannelInvites       = "user_channel_invites"
+	ChannelOptinRequests     = "channel_optin_requests"
+	UserOptinRequests        = "user_optin_requests"
+	ChannelTokenGates        = "channel_token_gates"
+	ChannelCategoryIndex     = "channel_categories"
+	ChannelTagIndex          = "channel_tags"
+	ChannelRankings          = "channel_rankings"
+
+	SchemaMigrations = "schema_migrations"
 )
 
 // DB
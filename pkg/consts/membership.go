@@ -53,4 +53,14 @@ var Analytics = map[string]map[MembershipTier]bool{
 		SilverTier: true,
 		FreeTier:   true,
 	},
+	CHANNEL_ADVANCED_STATS: {
+		GoldTier:   true,
+		SilverTier: true,
+		FreeTier:   false,
+	},
+	CHANNEL_DATA_EXPORT: {
+		GoldTier:   true,
+		SilverTier: true,
+		FreeTier:   false,
+	},
 }
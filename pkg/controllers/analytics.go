@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+const (
+	defaultAnalyticsDays  = 30
+	maxAnalyticsDays      = 365
+	defaultAnalyticsWeeks = 8
+	maxAnalyticsWeeks     = 52
+)
+
+type AnalyticsController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.AnalyticsUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewAnalyticsController
+func NewAnalyticsController(
+	router *gin.RouterGroup, analyticsUseCase usecases.AnalyticsUseCaseImply, middleWare *middlewares.Middlewares,
+) *AnalyticsController {
+	return &AnalyticsController{
+		router:      router,
+		useCases:    analyticsUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the AnalyticsController.
+func (a *AnalyticsController) InitRoutes() {
+	v1 := a.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", a.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", a.middleWares.VerifyUserOnboarded)
+
+	channelsRead := onboarded.Group("", a.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("chains/:chain/stats/channels/:app_id/analytics", a.ChannelAnalytics)
+		channelsRead.GET("chains/:chain/stats/channels/:app_id/export/:dataset", a.ExportChannelData)
+	}
+}
+
+// ChannelAnalytics is an API endpoint for fetching the retention cohorts, churn and read behaviour of a channel.
+func (a *AnalyticsController) ChannelAnalytics(ctx *gin.Context) {
+	log := utilities.NewLogger("ChannelAnalytics")
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	user := ctx.GetString(consts.UserAddress)
+
+	days := cast.ToInt(ctx.DefaultQuery("days", cast.ToString(defaultAnalyticsDays)))
+	weeks := cast.ToInt(ctx.DefaultQuery("weeks", cast.ToString(defaultAnalyticsWeeks)))
+	if days <= 0 || days > maxAnalyticsDays || weeks <= 0 || weeks > maxAnalyticsWeeks {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch channel analytics",
+				Message:    "days must be between 1 and 365 and weeks between 1 and 52",
+			},
+		)
+		return
+	}
+
+	log.Info("Received ChannelAnalytics request for chain:", chain, " appID:", appID)
+
+	data, err := a.useCases.ChannelAnalytics(ctx, chain, appID, user, days, weeks)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch channel analytics",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel analytics fetched successfully",
+			Data:       data,
+		},
+	)
+}
+
+// exportWriter sets the download headers on the first write so that a rejected export can still respond with JSON.
+type exportWriter struct {
+	ctx         *gin.Context
+	contentType string
+	filename    string
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.ctx.Writer.Written() {
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.ctx.Status(http.StatusOK)
+	}
+
+	return w.ctx.Writer.Write(p)
+}
+
+// ExportChannelData is an API endpoint for downloading a channel dataset as CSV or NDJSON.
+func (a *AnalyticsController) ExportChannelData(ctx *gin.Context) {
+	log := utilities.NewLogger("ExportChannelData")
+
+	chain, appID, dataset := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("dataset")
+	format := ctx.DefaultQuery("format", consts.ExportCSV)
+	startDate := ctx.DefaultQuery("start_date", "")
+	endDate := ctx.DefaultQuery("end_date", "")
+	user := ctx.GetString(consts.UserAddress)
+
+	log.Info("Received ExportChannelData request for chain:", chain, " appID:", appID, " dataset:", dataset)
+
+	contentType := "text/csv"
+	if format == consts.ExportNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	w := &exportWriter{
+		ctx:         ctx,
+		contentType: contentType,
+		filename:    fmt.Sprintf("%s-%s-%s.%s", chain, appID, dataset, format),
+	}
+
+	err := a.useCases.ExportChannelData(ctx, chain, appID, user, dataset, format, startDate, endDate, w)
+	if err != nil {
+		if ctx.Writer.Written() {
+			// the export has already started streaming, so the error can only be logged
+			log.WithError(err).Error("export failed midway")
+			return
+		}
+
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to export channel data",
+				Message:    err.Error(),
+			},
+		)
+	}
+}
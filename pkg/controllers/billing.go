@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"notiboy/config"
+	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/middlewares"
 	"notiboy/pkg/usecases"
@@ -33,19 +34,61 @@ func (n *BillingController) InitRoutes() {
 
 	verifyToken := v1.Group("", n.middleWares.ValidateToken)
 	onboarded := verifyToken.Group("", n.middleWares.VerifyUserOnboarded)
+
+	billingWrite := onboarded.Group("", n.middleWares.RequireScope(consts.ScopeBillingWrite))
 	{
-		onboarded.POST("/chains/:chain/users/:address/billing/fund", n.AddFund)
-		onboarded.PUT("/chains/:chain/users/:address/billing/membership", n.ChangeMembership)
-		onboarded.GET("/chains/:chain/users/:address/billing", n.GetBillingDetails)
-		onboarded.GET("/billing", n.GetMembershipTiers)
+		billingWrite.POST("/chains/:chain/users/:address/billing/fund", n.AddFund)
+		billingWrite.PUT("/chains/:chain/users/:address/billing/membership", n.ChangeMembership)
+		billingWrite.POST("/chains/:chain/channels/:app_id/billing/fund", n.ChannelBillingOwner, n.AddFund)
+		billingWrite.PUT(
+			"/chains/:chain/channels/:app_id/billing/membership", n.ChannelBillingOwner, n.ChangeMembership,
+		)
 	}
 
-	admin := onboarded.Group("", n.middleWares.IsAdminUser)
+	billingRead := onboarded.Group("", n.middleWares.RequireScope(consts.ScopeBillingRead))
+	{
+		billingRead.GET("/chains/:chain/users/:address/billing", n.GetBillingDetails)
+		billingRead.GET("/billing", n.GetMembershipTiers)
+		billingRead.GET("/chains/:chain/channels/:app_id/billing", n.ChannelBillingOwner, n.GetBillingDetails)
+	}
+
+	admin := onboarded.Group("", n.middleWares.RequireUnscoped, n.middleWares.IsAdminUser)
 	{
 		admin.PUT("/admin/chains/:chain/users/:address/billing/membership", n.AdminChangeMembership)
 	}
 }
 
+// ChannelBillingOwner lets the billing handlers after it manage the billing of the channel, that of its owner,
+// if the user has the billing permission on the channel
+func (n *BillingController) ChannelBillingOwner(ctx *gin.Context) {
+	log := utilities.NewLogger("ChannelBillingOwner")
+
+	owner, err := n.useCases.ChannelBillingOwner(
+		ctx, ctx.Param("chain"), ctx.Param("app_id"), ctx.GetString(consts.UserAddress),
+	)
+	if err != nil {
+		log.WithError(err).Error("channel billing denied")
+		ctx.AbortWithStatusJSON(http.StatusForbidden, entities.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Error:      err.Error(),
+			Message:    "Channel billing denied",
+		})
+		return
+	}
+
+	ctx.Set(consts.BillingAddress, owner)
+	ctx.Next()
+}
+
+// billingAddress returns the address whose billing the request manages, the channel owner for channel billing
+func billingAddress(ctx *gin.Context) string {
+	if owner := ctx.GetString(consts.BillingAddress); owner != "" {
+		return owner
+	}
+
+	return ctx.Param("address")
+}
+
 func (n *BillingController) AddFund(ctx *gin.Context) {
 	log := utilities.NewLogger("AddFund")
 
@@ -62,7 +105,7 @@ func (n *BillingController) AddFund(ctx *gin.Context) {
 	log.Info("Received request with payload:", req)
 
 	req.Chain = ctx.Param("chain")
-	req.Address = ctx.Param("address")
+	req.Address = billingAddress(ctx)
 
 	if req.Chain == "" || req.Address == "" || (req.SignedTxn == "" && req.TxnID == "") {
 		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
@@ -148,7 +191,7 @@ func (n *BillingController) ChangeMembership(ctx *gin.Context) {
 	log.Info("Received request with payload:", req)
 
 	req.Chain = ctx.Param("chain")
-	req.Address = ctx.Param("address")
+	req.Address = billingAddress(ctx)
 
 	if req.Chain == "" || req.Address == "" || req.Membership == "" {
 		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
@@ -183,7 +226,7 @@ func (n *BillingController) GetBillingDetails(ctx *gin.Context) {
 	log.Info("Received request with payload:", req)
 
 	req.Chain = ctx.Param("chain")
-	req.Address = ctx.Param("address")
+	req.Address = billingAddress(ctx)
 
 	if req.Chain == "" || req.Address == "" {
 		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
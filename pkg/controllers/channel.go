@@ -37,26 +37,46 @@ func (c *ChannelController) InitRoutes() {
 	v1 := c.router.Group(config.GetConfig().Server.APIVersion)
 	verifyToken := v1.Group("", c.middleWares.ValidateToken)
 	onboarded := verifyToken.Group("", c.middleWares.VerifyUserOnboarded)
-	{
-		onboarded.POST("/chains/:chain/channels", c.CreateChannel)
-		onboarded.PUT("/chains/:chain/channels/:app_id", c.ChannelUpdate)
-		onboarded.DELETE("/chains/:chain/channels/:app_id", c.DeleteChannel)
 
-		onboarded.GET("/chains/:chain/channels/:app_id/users", c.ChannelUsers)
-		onboarded.GET("/chains/:chain/channels", c.ListChannels)
-		onboarded.GET("/chains/:chain/channels/users/:address/owned", c.ListUserOwnedChannels)
-		onboarded.GET("/chains/:chain/channels/users/:address/optins", c.ListOptedInChannels)
+	channelsWrite := onboarded.Group("", c.middleWares.RequireScope(consts.ScopeChannelsWrite))
+	{
+		channelsWrite.POST("/chains/:chain/channels", c.CreateChannel)
+		channelsWrite.PUT("/chains/:chain/channels/:app_id", c.ChannelUpdate)
+		channelsWrite.DELETE("/chains/:chain/channels/:app_id", c.DeleteChannel)
+		channelsWrite.POST("/chains/:chain/channels/:app_id/verification", c.RequestVerification)
+		channelsWrite.PUT("/chains/:chain/channels/:app_id/domain", c.ClaimDomain)
+		channelsWrite.POST("/chains/:chain/channels/:app_id/domain/check", c.CheckDomain)
+		channelsWrite.DELETE("/chains/:chain/channels/:app_id/domain", c.RemoveDomainClaim)
+		channelsWrite.PUT("/chains/:chain/channels/:app_id/tags", c.SetChannelTags)
+	}
 
-		onboarded.GET("/chains/:chain/stats/channels", c.ChannelStatistics)
-		onboarded.GET("/chains/:chain/stats/channels/:app_id/notifications",
+	channelsRead := onboarded.Group("", c.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("/chains/:chain/channels/:app_id/users", c.ChannelUsers)
+		channelsRead.GET("/chains/:chain/channels", c.ListChannels)
+		channelsRead.GET("/chains/:chain/channels/users/:address/owned", c.ListUserOwnedChannels)
+		channelsRead.GET("/chains/:chain/channels/users/:address/optins", c.ListOptedInChannels)
+		channelsRead.GET("/chains/:chain/channels/:app_id/verification", c.GetVerificationRequest)
+		channelsRead.GET("/chains/:chain/channels/:app_id/domain", c.GetDomainClaim)
+		channelsRead.GET("/chains/:chain/channels/categories", c.ListCategories)
+		channelsRead.GET("/chains/:chain/channels/discover/trending", c.TrendingChannels)
+		channelsRead.GET("/chains/:chain/channels/discover/active", c.ActiveSenders)
+		channelsRead.GET("/chains/:chain/channels/:app_id/similar", c.SimilarChannels)
+
+		channelsRead.GET("/chains/:chain/stats/channels", c.ChannelStatistics)
+		channelsRead.GET("/chains/:chain/stats/channels/:app_id/notifications",
 			c.ChannelReadSentStatistics)
-		//onboarded.GET("/chains/:chain/stats/channels/:app_id/users/:address/notification",
+		//channelsRead.GET("/chains/:chain/stats/channels/:app_id/users/:address/notification",
 		//	c.ChannelNotificationStatistics)
 	}
 
-	admin := onboarded.Group("", c.middleWares.IsAdminUser)
+	admin := onboarded.Group("", c.middleWares.RequireUnscoped, c.middleWares.IsAdminUser)
 	{
 		admin.PUT("/admin/chains/:chain/channels/:app_id/verify", c.VerifyChannel)
+		admin.PUT("/admin/chains/:chain/channels/:app_id/verification/reject", c.RejectVerification)
+		admin.PUT("/admin/chains/:chain/channels/:app_id/unverify", c.RevokeVerification)
+		admin.GET("/admin/chains/:chain/channels/verifications", c.ListVerificationRequests)
+		admin.PUT("/admin/chains/:chain/channels/:app_id/tags", c.CurateChannelTags)
 	}
 }
 
@@ -386,6 +406,7 @@ func (c *ChannelController) ListChannels(ctx *gin.Context) {
 	withLogoStr := ctx.DefaultQuery("logo", "false")
 	verifiedStr := ctx.DefaultQuery("verified", "true")
 	nameSearchStr := ctx.DefaultQuery("name", "")
+	category, tag := ctx.Query("category"), ctx.Query("tag")
 
 	currPageState, err := base64.URLEncoding.DecodeString(pageState)
 	if err != nil {
@@ -420,6 +441,8 @@ func (c *ChannelController) ListChannels(ctx *gin.Context) {
 		WithLogo:   withLogo,
 		NameSearch: nameSearchStr,
 		Verified:   verified,
+		Category:   category,
+		Tag:        tag,
 	}
 
 	channelData, err := c.useCases.ListChannels(ctx, req)
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// SetChannelTags is an API endpoint for setting the category and tags a channel is discovered by.
+func (c *ChannelController) SetChannelTags(ctx *gin.Context) {
+	log := utilities.NewLogger("SetChannelTags")
+
+	var request entities.ChannelTagsRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to set channel tags",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received SetChannelTags request for chain:", chain, " appID:", appID)
+
+	err := c.useCases.SetChannelTags(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to set channel tags",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "channel tags set successfully",
+	})
+}
+
+// CurateChannelTags is an admin API endpoint for curating the category and tags of a channel.
+func (c *ChannelController) CurateChannelTags(ctx *gin.Context) {
+	log := utilities.NewLogger("CurateChannelTags")
+
+	var request entities.CurateChannelTagsRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to curate channel tags",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received CurateChannelTags request for chain:", chain, " appID:", appID)
+
+	if err := c.useCases.CurateChannelTags(ctx, chain, appID, request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to curate channel tags",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "channel tags curated successfully",
+	})
+}
+
+// ListCategories is an API endpoint for listing the categories channels are listed under.
+func (c *ChannelController) ListCategories(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "categories listed successfully",
+		Data:       c.useCases.ListCategories(),
+	})
+}
+
+// TrendingChannels is an API endpoint for listing the channels gaining subscribers the fastest.
+func (c *ChannelController) TrendingChannels(ctx *gin.Context) {
+	chain := ctx.Param("chain")
+	limit, withLogo := cast.ToInt(ctx.Query("limit")), cast.ToBool(ctx.DefaultQuery("logo", "false"))
+
+	channels, err := c.useCases.TrendingChannels(ctx, chain, limit, withLogo)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: 500,
+			Error:      "failed to list trending channels",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "trending channels listed successfully",
+		Data:       channels,
+	})
+}
+
+// ActiveSenders is an API endpoint for listing the channels sending the most notifications.
+func (c *ChannelController) ActiveSenders(ctx *gin.Context) {
+	chain := ctx.Param("chain")
+	limit, withLogo := cast.ToInt(ctx.Query("limit")), cast.ToBool(ctx.DefaultQuery("logo", "false"))
+
+	channels, err := c.useCases.ActiveSenders(ctx, chain, limit, withLogo)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: 500,
+			Error:      "failed to list active channels",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "active channels listed successfully",
+		Data:       channels,
+	})
+}
+
+// SimilarChannels is an API endpoint for listing the channels sharing the most subscribers with a channel.
+func (c *ChannelController) SimilarChannels(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	limit, withLogo := cast.ToInt(ctx.Query("limit")), cast.ToBool(ctx.DefaultQuery("logo", "false"))
+
+	channels, err := c.useCases.SimilarChannels(ctx, chain, appID, limit, withLogo)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: 500,
+			Error:      "failed to list similar channels",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "similar channels listed successfully",
+		Data:       channels,
+	})
+}
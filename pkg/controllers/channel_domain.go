@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// ClaimDomain is an API endpoint for claiming a domain for a channel.
+func (c *ChannelController) ClaimDomain(ctx *gin.Context) {
+	log := utilities.NewLogger("ClaimDomain")
+
+	var request entities.ChannelDomainRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to claim domain",
+			Message:    "domain is required",
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received ClaimDomain request for chain:", chain, " appID:", appID, " domain:", request.Domain)
+
+	claim, err := c.useCases.ClaimDomain(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to claim domain",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "domain claimed successfully",
+		Data:       claim,
+	})
+}
+
+// GetDomainClaim is an API endpoint for fetching the domain claimed by a channel and the status of its proof.
+func (c *ChannelController) GetDomainClaim(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	claim, err := c.useCases.GetDomainClaim(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, entities.ErrorResponse{
+			StatusCode: 404,
+			Error:      "failed to fetch domain claim",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "domain claim fetched successfully",
+		Data:       claim,
+	})
+}
+
+// CheckDomain is an API endpoint for checking the proof of the domain claimed by a channel right away.
+func (c *ChannelController) CheckDomain(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	claim, err := c.useCases.CheckDomain(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to check domain",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "domain checked successfully",
+		Data:       claim,
+	})
+}
+
+// RemoveDomainClaim is an API endpoint for removing the domain claimed by a channel.
+func (c *ChannelController) RemoveDomainClaim(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := c.useCases.RemoveDomainClaim(ctx, chain, appID, ctx.GetString(consts.UserAddress)); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to remove domain claim",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "domain claim removed successfully",
+	})
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// RequestVerification is an API endpoint for asking the admins to verify a channel.
+func (c *ChannelController) RequestVerification(ctx *gin.Context) {
+	log := utilities.NewLogger("RequestVerification")
+
+	var request entities.ChannelVerificationRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to request verification",
+			Message:    "website is required",
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received RequestVerification request for chain:", chain, " appID:", appID)
+
+	verification, err := c.useCases.RequestVerification(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to request verification",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "verification requested successfully",
+		Data:       verification,
+	})
+}
+
+// GetVerificationRequest is an API endpoint for fetching the status of the verification of a channel.
+func (c *ChannelController) GetVerificationRequest(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	verification, err := c.useCases.GetVerificationRequest(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, entities.ErrorResponse{
+			StatusCode: 404,
+			Error:      "failed to fetch verification request",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "verification request fetched successfully",
+		Data:       verification,
+	})
+}
+
+// ListVerificationRequests is an admin API endpoint for listing the requests to verify channels, optionally
+// only those with the status query parameter.
+func (c *ChannelController) ListVerificationRequests(ctx *gin.Context) {
+	verifications, err := c.useCases.ListVerificationRequests(ctx, ctx.Param("chain"), ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: 500,
+			Error:      "failed to fetch verification requests",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "verification requests fetched successfully",
+		Data:       verifications,
+	})
+}
+
+// RejectVerification is an admin API endpoint for rejecting the request to verify a channel.
+func (c *ChannelController) RejectVerification(ctx *gin.Context) {
+	var request entities.VerificationDecisionRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to reject verification",
+			Message:    "reason is required",
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := c.useCases.RejectVerification(ctx, chain, appID, request.Reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to reject verification",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "verification rejected successfully",
+	})
+}
+
+// RevokeVerification is an admin API endpoint for unverifying a verified channel.
+func (c *ChannelController) RevokeVerification(ctx *gin.Context) {
+	log := utilities.NewLogger("RevokeVerification")
+
+	var request entities.VerificationDecisionRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to revoke verification",
+			Message:    "reason is required",
+		})
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received RevokeVerification request for chain:", chain, " appID:", appID)
+
+	if err := c.useCases.RevokeVerification(ctx, chain, appID, request.Reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: 400,
+			Error:      "failed to revoke verification",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "Channel unverified successfully.",
+	})
+}
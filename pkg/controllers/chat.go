@@ -47,22 +47,32 @@ func (c *ChatController) InitRoutes(ctx context.Context) {
 
 	verifyToken := v1.Group("", c.middleWares.ValidateToken)
 	onboarded := verifyToken.Group("", c.middleWares.VerifyUserOnboarded)
+
+	chatRead := onboarded.Group("", c.middleWares.RequireScope(consts.ScopeChatRead))
+	{
+		chatRead.GET("/chains/:chain/chat/messages", c.GetPersonalChat)
+		chatRead.GET("/chains/:chain/chat/user/:user/messages", c.GetPersonalChatByUser)
+		chatRead.GET("/chains/:chain/chat/:user/block", c.IsUserBlocked)
+		chatRead.GET("/chains/:chain/chat/dns/contacts", c.GetDNSContacts)
+		chatRead.GET("/chains/:chain/chat/profiles/contacts", c.GetContactProfiles)
+
+		if false {
+			chatRead.GET("/chains/:chain/chat/group/:gid/messages", c.GetGroupChatByGroup)
+			chatRead.GET("/chains/:chain/chat/group/messages", c.GetGroupChats)
+		}
+	}
+
+	chatWrite := onboarded.Group("", c.middleWares.RequireScope(consts.ScopeChatWrite))
 	{
-		onboarded.GET("/chains/:chain/chat/messages", c.GetPersonalChat)
-		onboarded.GET("/chains/:chain/chat/user/:user/messages", c.GetPersonalChatByUser)
-		onboarded.POST("/chains/:chain/chat/:user/block", c.BlockUser)
-		onboarded.GET("/chains/:chain/chat/:user/block", c.IsUserBlocked)
-		onboarded.POST("/chains/:chain/chat/:user/unblock", c.UnBlockUser)
-		onboarded.GET("/chains/:chain/chat/dns/contacts", c.GetDNSContacts)
+		chatWrite.POST("/chains/:chain/chat/:user/block", c.BlockUser)
+		chatWrite.POST("/chains/:chain/chat/:user/unblock", c.UnBlockUser)
 
 		if false {
-			onboarded.POST("/chains/:chain/chat/group", c.CreateGroup)
-			onboarded.PUT("/chains/:chain/chat/group/:gid", c.UpdateGroupInfo)
-			onboarded.DELETE("/chains/:chain/chat/group/:gid", c.UpdateGroupInfo)
-			onboarded.PUT("/chains/:chain/chat/group/:gid/join", c.JoinGroup)
-			onboarded.PUT("/chains/:chain/chat/group/:gid/leave", c.LeaveGroup)
-			onboarded.GET("/chains/:chain/chat/group/:gid/messages", c.GetGroupChatByGroup)
-			onboarded.GET("/chains/:chain/chat/group/messages", c.GetGroupChats)
+			chatWrite.POST("/chains/:chain/chat/group", c.CreateGroup)
+			chatWrite.PUT("/chains/:chain/chat/group/:gid", c.UpdateGroupInfo)
+			chatWrite.DELETE("/chains/:chain/chat/group/:gid", c.UpdateGroupInfo)
+			chatWrite.PUT("/chains/:chain/chat/group/:gid/join", c.JoinGroup)
+			chatWrite.PUT("/chains/:chain/chat/group/:gid/leave", c.LeaveGroup)
 		}
 	}
 
@@ -95,7 +105,7 @@ func (c *ChatController) GetPersonalChat(ctx *gin.Context) {
 		from = time.Now().AddDate(0, 0, -7).Unix()
 	}
 
-	res, err := c.useCases.GetPersonalChat(ctx, chain, user, from, to)
+	res, profiles, err := c.useCases.GetPersonalChat(ctx, chain, user, from, to)
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError, entities.ErrorResponse{
@@ -111,6 +121,7 @@ func (c *ChatController) GetPersonalChat(ctx *gin.Context) {
 			StatusCode: 201,
 			Message:    "Personal chat retrieved successfully.",
 			Data:       res,
+			Profiles:   profiles,
 		},
 	)
 }
@@ -159,7 +170,7 @@ func (c *ChatController) GetPersonalChatByUser(ctx *gin.Context) {
 		return
 	}
 
-	res, nextPageState, err := c.useCases.GetPersonalChatByUser(ctx, chain, user, rcvr, numPageSize, currPageState)
+	res, profiles, nextPageState, err := c.useCases.GetPersonalChatByUser(ctx, chain, user, rcvr, numPageSize, currPageState)
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError, entities.ErrorResponse{
@@ -175,6 +186,7 @@ func (c *ChatController) GetPersonalChatByUser(ctx *gin.Context) {
 			StatusCode: 201,
 			Message:    "Personal chat by user retrieved successfully.",
 			Data:       res,
+			Profiles:   profiles,
 			PaginationMetaData: &entities.PaginationMetaData{
 				Size:     len(res),
 				PageSize: numPageSize,
@@ -341,12 +353,53 @@ func (c *ChatController) GetDNSContacts(ctx *gin.Context) {
 	)
 }
 
+// GetContactProfiles returns the profiles of the contacts of the user, or of the user given by lookup,
+// with the fields their owners share with the user
+func (c *ChatController) GetContactProfiles(ctx *gin.Context) {
+	log := utilities.NewLogger("GetContactProfiles")
+	log.Info("Received GetContactProfiles request")
+
+	chain := ctx.Param("chain")
+	user := ctx.GetString(consts.UserAddress)
+	if user == "" || chain == "" {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "user and chain are required",
+				Message:    "please provide chain/user parameter",
+			},
+		)
+		return
+	}
+
+	lookupAddr := ctx.Query("lookup")
+
+	res, err := c.useCases.GetContactProfiles(ctx, chain, user, lookupAddr)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Error:      "failed to get contact profiles",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 201,
+			Message:    "Contact profiles retrieved successfully.",
+			Data:       res,
+		},
+	)
+}
+
 func (c *ChatController) WebsocketHandler(ctx *gin.Context) {
 	chain := ctx.Query("chain")
 	address := ctx.Query("address")
 	token := ctx.Query("token")
 
-	if err := c.middleWares.VerifyWebsocketRequest(ctx, chain, address, token); err != nil {
+	if err := c.middleWares.VerifyWebsocketRequest(ctx, chain, address, token, consts.ScopeChatRead); err != nil {
 		ctx.JSON(
 			http.StatusUnauthorized, entities.Response{
 				StatusCode: http.StatusUnauthorized,
@@ -2,13 +2,17 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"notiboy/config"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/middlewares"
+	chainDriver "notiboy/pkg/repo/driver/chain"
 	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+	"notiboy/utilities/jwt"
 )
 
 type Controller struct {
@@ -36,10 +40,33 @@ func (c *Controller) InitRoutes() {
 		v1.GET("/", c.RootHandler)
 		v1.GET("/health", c.HealthHandler)
 		v1.GET("/db/health", c.DatabaseHealthHandler)
+		v1.POST("/chains/:chain/users/:address/challenge", c.LoginChallenge)
 	}
 
 }
 
+// JWKS publishes the public keys tokens are signed with, so that other services can verify them.
+// It is served at /.well-known/jwks.json outside of the API prefix.
+func (c *Controller) JWKS(ctx *gin.Context) {
+	log := utilities.NewLogger("JWKS")
+
+	set, err := jwt.PublicKeySet()
+	if err != nil {
+		log.WithError(err).Error("failed to get public keys")
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Error:      err.Error(),
+				Message:    "Failed to get public keys",
+			},
+		)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, set)
+}
+
 func (c *Controller) RootHandler(ctx *gin.Context) {
 	ctx.JSON(
 		http.StatusOK, entities.Response{
@@ -78,3 +105,44 @@ func (c *Controller) DatabaseHealthHandler(ctx *gin.Context) {
 		},
 	)
 }
+
+// LoginChallenge issues a one-time message for the wallet to sign, the signature is then used
+// to login or onboard through the wallet endpoints
+func (c *Controller) LoginChallenge(ctx *gin.Context) {
+	log := utilities.NewLogger("LoginChallenge")
+
+	chain := strings.ToLower(ctx.Param("chain"))
+	address := ctx.Param("address")
+
+	if !chainDriver.IsChainSupported(chain) || address == "" {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to create login challenge",
+				Message:    "missing or invalid parameters",
+			},
+		)
+		return
+	}
+
+	challenge, err := c.useCases.CreateLoginChallenge(ctx, chain, address)
+	if err != nil {
+		log.WithError(err).Errorf("failed to create login challenge for %s", address)
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to create login challenge",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "sign the message with your wallet to login",
+			Data:       challenge,
+		},
+	)
+}
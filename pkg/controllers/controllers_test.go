@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/repo/driver/medium"
+	"notiboy/pkg/usecases"
+)
+
+// idleChat doesn't process chat messages, InitRoutes of ChatController starts the processors
+type idleChat struct {
+	usecases.ChatUseCaseImply
+}
+
+func (idleChat) ChatProcessor(context.Context) {}
+
+func (idleChat) NotificationProcessor(context.Context) {}
+
+// TestInitRoutes registers the routes of every controller the way app.Init does, gin panics on
+// conflicting routes
+func TestInitRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.SetConfig(
+		&config.NotiboyConfModel{
+			Mode:           "local",
+			LegacyTxnLogin: true,
+			Server:         config.Server{APIVersion: "v1"},
+		},
+	)
+
+	router := gin.New()
+	api := router.Group("/api/local")
+	m := middlewares.NewMiddlewares(nil)
+
+	NewBillingController(api, nil, m).InitRoutes()
+	NewNotificationController(api, nil, medium.NewWebSocket(false), m).InitRoutes()
+	NewChannelController(api, nil, m).InitRoutes()
+	NewChatController(api, idleChat{}, medium.NewWebSocket(true), m).InitRoutes(context.Background())
+	NewUserController(api, nil, m).InitRoutes()
+	NewOptinController(api, nil, m).InitRoutes()
+	NewVerifyController(api, nil, m).InitRoutes()
+	NewAnalyticsController(api, nil, m).InitRoutes()
+	NewWebhookController(api, nil, m).InitRoutes()
+	NewLinkController(api, nil, m).InitRoutes()
+	NewExportController(api, nil, m).InitRoutes()
+	NewChannelMemberController(api, nil, m).InitRoutes()
+	NewChannelTransferController(api, nil, m).InitRoutes()
+	controllers := NewController(api, nil, m)
+	controllers.InitRoutes()
+	router.GET("/.well-known/jwks.json", controllers.JWKS)
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range []string{
+		"POST /api/local/v1/chains/:chain/users/:address/pat/kind/:kind/:name",
+		"DELETE /api/local/v1/chains/:chain/users/:address/pat/kind/:kind/:name",
+		"POST /api/local/v1/chains/:chain/users/:address/pat/kind/:kind/:name/rotate",
+	} {
+		if !registered[route] {
+			t.Errorf("route %s is not registered", route)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+type ExportController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.ExportUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewExportController
+func NewExportController(
+	router *gin.RouterGroup, exportUseCase usecases.ExportUseCaseImply, middleWare *middlewares.Middlewares,
+) *ExportController {
+	return &ExportController{
+		router:      router,
+		useCases:    exportUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the ExportController.
+func (e *ExportController) InitRoutes() {
+	v1 := e.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", e.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", e.middleWares.VerifyUserOnboarded)
+
+	// the export holds all the data of the account, so it's not open to scoped tokens
+	unscoped := onboarded.Group("", e.middleWares.RequireUnscoped)
+	{
+		unscoped.GET("/chains/:chain/users/:address/export", e.ExportAccount)
+	}
+}
+
+// ExportAccount is an API endpoint for downloading everything stored for the account of the user,
+// as JSON or, with format=zip, as a ZIP archive.
+func (e *ExportController) ExportAccount(ctx *gin.Context) {
+	log := utilities.NewLogger("ExportAccount")
+
+	user := tokenUser(ctx)
+	format := ctx.DefaultQuery("format", consts.ExportJSON)
+
+	log.Info("Received ExportAccount request for chain:", user.Chain, " format:", format)
+
+	archive, err := e.useCases.ExportAccount(ctx, user, format)
+	if err != nil {
+		log.WithError(err).Errorf("failed to export account %s", user.Address)
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to export account",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archive.Name))
+	ctx.Header("Content-Type", archive.ContentType)
+	ctx.Status(http.StatusOK)
+
+	if err = archive.Write(ctx.Writer); err != nil {
+		log.WithError(err).Errorf("failed to export account %s", user.Address)
+		if ctx.Writer.Written() {
+			// the archive is cut short, there is no way to report the error once it is being sent
+			ctx.Abort()
+			return
+		}
+
+		ctx.Header("Content-Disposition", "")
+		ctx.Header("Content-Type", "")
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to export account",
+				Message:    err.Error(),
+			},
+		)
+	}
+}
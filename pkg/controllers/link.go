@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+type LinkController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.LinkUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewLinkController
+func NewLinkController(
+	router *gin.RouterGroup, linkUseCase usecases.LinkUseCaseImply, middleWare *middlewares.Middlewares,
+) *LinkController {
+	return &LinkController{
+		router:      router,
+		useCases:    linkUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the LinkController.
+func (l *LinkController) InitRoutes() {
+	v1 := l.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", l.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", l.middleWares.VerifyUserOnboarded)
+
+	// linking merges the accounts, so it's not open to scoped tokens
+	unscoped := onboarded.Group("", l.middleWares.RequireUnscoped)
+	{
+		unscoped.GET("/chains/:chain/users/:address/links", l.ListLinkedAccounts)
+		unscoped.DELETE("/chains/:chain/users/:address/links/:linked_chain", l.UnlinkAccount)
+
+		// the wallet of the account signs a fresh challenge too, the body carries the one of the linked account
+		signedChallenge := unscoped.Group("", l.middleWares.ValidateSignedChallenge)
+		signedChallenge.POST("/chains/:chain/users/:address/links", l.LinkAccount)
+	}
+}
+
+// LinkAccount is an API endpoint for linking the account of the user on another chain.
+func (l *LinkController) LinkAccount(ctx *gin.Context) {
+	log := utilities.NewLogger("LinkAccount")
+
+	var request entities.LinkAccountRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to link account",
+				Message:    "invalid request body",
+			},
+		)
+		return
+	}
+
+	user := tokenUser(ctx)
+	log.Info("Received LinkAccount request for chain:", user.Chain, " linked chain:", request.Chain)
+
+	link, err := l.useCases.LinkAccount(ctx, user, request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to link account",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "account linked successfully",
+			Data:       link,
+		},
+	)
+}
+
+// ListLinkedAccounts is an API endpoint for listing the accounts linked to the account of the user.
+func (l *LinkController) ListLinkedAccounts(ctx *gin.Context) {
+	links, err := l.useCases.ListLinkedAccounts(ctx, tokenUser(ctx))
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to fetch linked accounts",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "linked accounts fetched successfully",
+			Data:       links,
+		},
+	)
+}
+
+// UnlinkAccount is an API endpoint for unlinking the account of the user on another chain.
+func (l *LinkController) UnlinkAccount(ctx *gin.Context) {
+	log := utilities.NewLogger("UnlinkAccount")
+
+	user := tokenUser(ctx)
+	linkedChain := ctx.Param("linked_chain")
+
+	log.Info("Received UnlinkAccount request for chain:", user.Chain, " linked chain:", linkedChain)
+
+	if err := l.useCases.UnlinkAccount(ctx, user, linkedChain); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to unlink account",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "account unlinked successfully",
+		},
+	)
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+type ChannelMemberController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.ChannelMemberUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewChannelMemberController
+func NewChannelMemberController(
+	router *gin.RouterGroup, memberUseCase usecases.ChannelMemberUseCaseImply, middleWare *middlewares.Middlewares,
+) *ChannelMemberController {
+	return &ChannelMemberController{
+		router:      router,
+		useCases:    memberUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the ChannelMemberController.
+func (m *ChannelMemberController) InitRoutes() {
+	v1 := m.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", m.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", m.middleWares.VerifyUserOnboarded)
+
+	channelsRead := onboarded.Group("", m.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("/chains/:chain/channels/:app_id/members", m.ListMembers)
+		channelsRead.GET("/chains/:chain/channels/users/:address/memberships", m.ListUserChannelRoles)
+	}
+
+	// who gets to manage a channel is not up to a scoped token
+	unscoped := onboarded.Group("", m.middleWares.RequireUnscoped)
+	{
+		unscoped.POST("/chains/:chain/channels/:app_id/members", m.InviteMember)
+		unscoped.POST("/chains/:chain/channels/:app_id/members/accept", m.AcceptInvite)
+		unscoped.PUT("/chains/:chain/channels/:app_id/members/:member", m.UpdateMemberRole)
+		unscoped.DELETE("/chains/:chain/channels/:app_id/members/:member", m.RemoveMember)
+	}
+}
+
+// InviteMember is an API endpoint for inviting a user to share the management of a channel.
+func (m *ChannelMemberController) InviteMember(ctx *gin.Context) {
+	log := utilities.NewLogger("InviteMember")
+
+	var request entities.ChannelMemberRequest
+	if err := ctx.BindJSON(&request); err != nil || request.Address == "" {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to invite member",
+				Message:    "address and role are required",
+			},
+		)
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received InviteMember request for chain:", chain, " appID:", appID, " role:", request.Role)
+
+	member, err := m.useCases.InviteMember(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to invite member",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "member invited successfully",
+			Data:       member,
+		},
+	)
+}
+
+// AcceptInvite is an API endpoint for accepting the invite to a channel.
+func (m *ChannelMemberController) AcceptInvite(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := m.useCases.AcceptInvite(ctx, chain, appID, ctx.GetString(consts.UserAddress)); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to accept invite",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "invite accepted successfully",
+		},
+	)
+}
+
+// UpdateMemberRole is an API endpoint for changing the role of a member of a channel.
+func (m *ChannelMemberController) UpdateMemberRole(ctx *gin.Context) {
+	var request entities.ChannelMemberRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to update member",
+				Message:    "role is required",
+			},
+		)
+		return
+	}
+
+	chain, appID, address := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("member")
+
+	err := m.useCases.UpdateMemberRole(ctx, chain, appID, ctx.GetString(consts.UserAddress), address, request.Role)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to update member",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "member updated successfully",
+		},
+	)
+}
+
+// RemoveMember is an API endpoint for removing a member of a channel, leaving a channel or declining an invite.
+func (m *ChannelMemberController) RemoveMember(ctx *gin.Context) {
+	chain, appID, address := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("member")
+
+	if err := m.useCases.RemoveMember(ctx, chain, appID, ctx.GetString(consts.UserAddress), address); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to remove member",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "member removed successfully",
+		},
+	)
+}
+
+// ListMembers is an API endpoint for listing the members of a channel.
+func (m *ChannelMemberController) ListMembers(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	members, err := m.useCases.ListMembers(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch members",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "members fetched successfully",
+			Data:       members,
+		},
+	)
+}
+
+// ListUserChannelRoles is an API endpoint for listing the channels the user is a member of or invited to.
+func (m *ChannelMemberController) ListUserChannelRoles(ctx *gin.Context) {
+	user := tokenUser(ctx)
+
+	roles, err := m.useCases.ListUserChannelRoles(ctx, user.Chain, user.Address)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to fetch channel memberships",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel memberships fetched successfully",
+			Data:       roles,
+		},
+	)
+}
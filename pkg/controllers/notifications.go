@@ -43,16 +43,33 @@ func NewNotificationController(
 func (n *NotificationController) InitRoutes() {
 	v1 := n.router.Group(config.GetConfig().Server.APIVersion)
 	v1.GET("/ws", n.WebsocketHandler)
+	v1.GET("/r/:token", n.RedirectLink)
+	v1.GET("/o/:token", n.TrackOpen)
 
 	verifyToken := v1.Group("", n.middleWares.ValidateToken)
 	onboarded := verifyToken.Group("", n.middleWares.VerifyUserOnboarded)
+
+	notificationsSend := onboarded.Group("", n.middleWares.RequireScope(consts.ScopeNotificationsSend))
 	{
-		onboarded.POST("/chains/:chain/channels/:app_id/notifications/:kind", n.SendNotifications)
-		onboarded.GET("/chains/:chain/notifications", n.GetNotifications)
-		onboarded.GET("/chains/:chain/scheduled_notifications", n.GetScheduledNotifications)
-		onboarded.DELETE("/chains/:chain/scheduled_notifications/schedule/:schedule", n.DeleteScheduledNotification)
-		onboarded.PUT("/chains/:chain/scheduled_notifications/schedule/:schedule", n.UpdateScheduledNotification)
-		onboarded.GET("/chains/public-notification/:notification_id/count", n.NotificationReachCount)
+		notificationsSend.POST("/chains/:chain/channels/:app_id/notifications/:kind", n.SendNotifications)
+		notificationsSend.DELETE("/chains/:chain/channels/:app_id/notifications/:uuid", n.RecallNotification)
+		notificationsSend.PUT("/chains/:chain/channels/:app_id/notifications/:uuid", n.EditNotification)
+		notificationsSend.DELETE(
+			"/chains/:chain/scheduled_notifications/schedule/:schedule", n.DeleteScheduledNotification,
+		)
+		notificationsSend.PUT(
+			"/chains/:chain/scheduled_notifications/schedule/:schedule", n.UpdateScheduledNotification,
+		)
+	}
+
+	notificationsRead := onboarded.Group("", n.middleWares.RequireScope(consts.ScopeNotificationsRead))
+	{
+		notificationsRead.GET(
+			"/chains/:chain/channels/:app_id/notifications/:uuid/revisions", n.GetNotificationRevisions,
+		)
+		notificationsRead.GET("/chains/:chain/notifications", n.GetNotifications)
+		notificationsRead.GET("/chains/:chain/scheduled_notifications", n.GetScheduledNotifications)
+		notificationsRead.GET("/chains/:chain/stats/channels/:app_id/notifications/:uuid/reach", n.NotificationReach)
 	}
 }
 
@@ -103,6 +120,110 @@ func (n *NotificationController) SendNotifications(ctx *gin.Context) {
 
 }
 
+// RecallNotification is a handler function for recalling an already sent notification in the NotificationController.
+func (n *NotificationController) RecallNotification(ctx *gin.Context) {
+	log := utilities.NewLogger("RecallNotification")
+
+	chain, appID, uuid := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("uuid")
+	sender := ctx.GetString(consts.UserAddress)
+
+	log.Info("Received RecallNotification request for chain:", chain, " appID:", appID, " and uuid:", uuid)
+
+	err := n.useCases.RecallNotification(ctx, chain, appID, uuid, sender)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to recall notification",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "Successfully recalled notification",
+		},
+	)
+}
+
+// EditNotification is a handler function for editing an already sent notification in the NotificationController.
+func (n *NotificationController) EditNotification(ctx *gin.Context) {
+	log := utilities.NewLogger("EditNotification")
+
+	chain, appID, uuid := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("uuid")
+
+	var request entities.EditNotificationRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to edit notification",
+				Message:    fmt.Sprintf("binding failed: %s", err.Error()),
+			},
+		)
+		return
+	}
+
+	request.Chain = chain
+	request.Channel = appID
+	request.UUID = uuid
+	request.Sender = ctx.GetString(consts.UserAddress)
+
+	log.Info("Received EditNotification request for chain:", chain, " appID:", appID, " and uuid:", uuid)
+
+	err := n.useCases.EditNotification(ctx, request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to edit notification",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "Successfully edited notification",
+		},
+	)
+}
+
+// GetNotificationRevisions is a handler function for fetching the previous revisions of a notification in the NotificationController.
+func (n *NotificationController) GetNotificationRevisions(ctx *gin.Context) {
+	log := utilities.NewLogger("GetNotificationRevisions")
+
+	chain, appID, uuid := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("uuid")
+	user := ctx.GetString(consts.UserAddress)
+
+	log.Info("Received GetNotificationRevisions request for chain:", chain, " appID:", appID, " and uuid:", uuid)
+
+	data, err := n.useCases.GetNotificationRevisions(ctx, chain, appID, uuid, user)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed fetching notification revisions",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "Successfully fetched notification revisions",
+			Data:       data,
+		},
+	)
+}
+
 // DeleteScheduledNotification is a handler function for retrieving scheduled notifications in the NotificationController.
 func (n *NotificationController) DeleteScheduledNotification(ctx *gin.Context) {
 	log := utilities.NewLogger("DeleteScheduledNotification")
@@ -277,84 +398,67 @@ func (n *NotificationController) GetNotifications(ctx *gin.Context) {
 
 }
 
-// NotificationReachCount is a handler function for fetching the reach count of a notification in the NotificationController.
-func (n *NotificationController) NotificationReachCount(ctx *gin.Context) {
+// NotificationReach is a handler function for fetching the reach of a notification in the NotificationController.
+func (n *NotificationController) NotificationReach(ctx *gin.Context) {
+	log := utilities.NewLogger("NotificationReach")
 
-	notiId := ctx.Param("notification_id")
-	log := utilities.NewLogger("NotificationReachCount")
-	pageSize, pageState := ctx.DefaultQuery("page_size", consts.DefaultPageSize), ctx.Query("page_state")
-	if notiId == "" {
-		ctx.JSON(
-			http.StatusBadRequest, entities.ErrorResponse{
-				StatusCode: 400,
-				Error:      "failed to fetch notification reach count",
-				Message:    "please provide notification id",
-			},
-		)
-		return
-	}
+	chain, appID, uuid := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("uuid")
+	user := ctx.GetString(consts.UserAddress)
 
-	// docoding base64 encoded page state to []byte
-	currPageState, err := base64.URLEncoding.DecodeString(pageState)
-	if err != nil {
-		ctx.JSON(
-			http.StatusBadRequest, entities.ErrorResponse{
-				StatusCode: 400,
-				Error:      "failed to retreve channelinfo page state",
-				Message:    err.Error(),
-			},
-		)
-		return
-	}
+	log.Info("Received NotificationReach request for chain:", chain, " appID:", appID, " and uuid:", uuid)
 
-	numPageSize, err := strconv.Atoi(pageSize)
+	data, err := n.useCases.GetNotificationReach(ctx, chain, appID, uuid, user)
 	if err != nil {
 		ctx.JSON(
 			http.StatusBadRequest, entities.ErrorResponse{
 				StatusCode: 400,
-				Error:      "failed to convert page number to an integer",
+				Error:      "failed to fetch notification reach",
 				Message:    err.Error(),
 			},
 		)
 		return
 	}
-	log.Info("Received NotificationReachCount request for notification_id:", notiId)
 
-	data, nextPageState, err := n.useCases.NotificationReachCount(ctx, notiId, numPageSize, currPageState)
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "notification reach fetched successfully",
+			Data:       data,
+		},
+	)
+}
+
+// RedirectLink is a handler function for tracking a click on a notification link and redirecting to it.
+func (n *NotificationController) RedirectLink(ctx *gin.Context) {
+	log := utilities.NewLogger("RedirectLink")
+
+	link, err := n.useCases.TrackClick(ctx, ctx.Param("token"))
 	if err != nil {
+		log.WithError(err).Warn("failed to track link click")
 		ctx.JSON(
-			http.StatusBadRequest, entities.ErrorResponse{
-				StatusCode: 400,
-				Error:      "failed to fetch notification reach count",
+			http.StatusNotFound, entities.ErrorResponse{
+				StatusCode: 404,
+				Error:      "failed to redirect",
 				Message:    err.Error(),
 			},
 		)
 		return
 	}
-	if data == nil {
-		ctx.JSON(
-			http.StatusBadRequest, entities.ErrorResponse{
-				StatusCode: 400,
-				Error:      "failed to fetch notification reach count",
-				Message:    "unable to find notification reach count",
-			},
-		)
-		return
-	}
 
-	ctx.JSON(
-		http.StatusOK, entities.Response{
-			StatusCode: 200,
-			Message:    "notification reach count fetched successfully",
-			PaginationMetaData: &entities.PaginationMetaData{
-				PageSize: numPageSize,
-				Next:     base64.URLEncoding.EncodeToString(nextPageState),
-				Prev:     pageState,
-			},
-			Data: data,
-		},
-	)
+	ctx.Redirect(http.StatusFound, link)
+}
+
+// TrackOpen is a handler function for tracking email opens through a tracking pixel.
+// The pixel is served irrespective of the outcome so that email clients don't render a broken image.
+func (n *NotificationController) TrackOpen(ctx *gin.Context) {
+	log := utilities.NewLogger("TrackOpen")
+
+	if err := n.useCases.TrackOpen(ctx, ctx.Param("token")); err != nil {
+		log.WithError(err).Warn("failed to track email open")
+	}
 
+	ctx.Header("Cache-Control", "no-store, no-cache, must-revalidate")
+	ctx.Data(http.StatusOK, "image/gif", consts.TrackingPixel)
 }
 
 func (n *NotificationController) WebsocketHandler(ctx *gin.Context) {
@@ -362,7 +466,7 @@ func (n *NotificationController) WebsocketHandler(ctx *gin.Context) {
 	address := ctx.Query("address")
 	token := ctx.Query("token")
 
-	if err := n.middleWares.VerifyWebsocketRequest(ctx, chain, address, token); err != nil {
+	if err := n.middleWares.VerifyWebsocketRequest(ctx, chain, address, token, consts.ScopeNotificationsRead); err != nil {
 		ctx.JSON(
 			http.StatusUnauthorized, entities.Response{
 				StatusCode: http.StatusUnauthorized,
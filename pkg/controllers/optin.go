@@ -4,8 +4,10 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
 
 	"notiboy/config"
+	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/middlewares"
 	"notiboy/pkg/usecases"
@@ -34,14 +36,39 @@ func (Optin *OptinController) InitRoutes() {
 	validateToken := v1.Group("", Optin.middleWares.ValidateToken)
 
 	onboarded := validateToken.Group("", Optin.middleWares.VerifyUserOnboarded)
+
+	optinsWrite := onboarded.Group("", Optin.middleWares.RequireScope(consts.ScopeOptinsWrite))
+	{
+		optinsWrite.POST("chains/:chain/channels/:app_id/users/:address/optin", Optin.Optin)
+		optinsWrite.DELETE("chains/:chain/channels/:app_id/users/:address/optout", Optin.Optout)
+	}
+
+	channelsRead := onboarded.Group("", Optin.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("chains/:chain/stats/channels/:app_id/optinout", Optin.OptinoutStatistics)
+		channelsRead.GET("chains/:chain/channels/:app_id/optin-requests", Optin.ListOptinRequests)
+		channelsRead.GET("chains/:chain/channels/:app_id/token-gate", Optin.GetTokenGate)
+		channelsRead.GET("chains/:chain/channels/:app_id/optin-link", Optin.OptinLink)
+		channelsRead.GET("chains/:chain/channels/:app_id/optin-link/qr", Optin.OptinQRCode)
+	}
+
+	// who gets to opt in to a private or token gated channel is not up to a scoped token, neither are the invite
+	// codes that let anyone opt in
+	unscoped := onboarded.Group("", Optin.middleWares.RequireUnscoped)
 	{
-		onboarded.POST("chains/:chain/channels/:app_id/users/:address/optin", Optin.Optin)
-		onboarded.DELETE("chains/:chain/channels/:app_id/users/:address/optout", Optin.Optout)
-		onboarded.GET("chains/:chain/stats/channels/:app_id/optinout", Optin.OptinoutStatistics)
+		unscoped.GET("chains/:chain/channels/:app_id/invites", Optin.ListInvites)
+		unscoped.POST("chains/:chain/channels/:app_id/invites", Optin.CreateInvite)
+		unscoped.DELETE("chains/:chain/channels/:app_id/invites/:code", Optin.RevokeInvite)
+		unscoped.POST("chains/:chain/channels/:app_id/optin-requests/:requester/approve", Optin.ApproveOptinRequest)
+		unscoped.DELETE("chains/:chain/channels/:app_id/optin-requests/:requester", Optin.RejectOptinRequest)
+		unscoped.PUT("chains/:chain/channels/:app_id/token-gate", Optin.SetTokenGate)
+		unscoped.DELETE("chains/:chain/channels/:app_id/token-gate", Optin.RemoveTokenGate)
 	}
 }
 
-// Optin is an API endpoint for channel opt-in.
+// Optin is an API endpoint for channel opt-in, a private channel is opted in to with the invite query parameter
+// or once the request to opt in is approved. The source query parameter attributes the opt-in to a campaign or
+// referral.
 func (Optin *OptinController) Optin(ctx *gin.Context) {
 
 	chain := ctx.Param("chain")
@@ -59,7 +86,7 @@ func (Optin *OptinController) Optin(ctx *gin.Context) {
 	}
 	log.Info("Received Optin request for chain:", chain, ", app_id:", appId, ", address:", userAddr)
 
-	err := Optin.useCases.Optin(ctx, chain, appId, userAddr)
+	optedIn, err := Optin.useCases.Optin(ctx, chain, appId, userAddr, ctx.Query("invite"), ctx.Query("source"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
 			StatusCode: 400,
@@ -70,6 +97,14 @@ func (Optin *OptinController) Optin(ctx *gin.Context) {
 		return
 	}
 
+	if !optedIn {
+		ctx.JSON(http.StatusAccepted, entities.Response{
+			StatusCode: 202,
+			Message:    "channel optin requested, waiting for approval",
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, entities.Response{
 		StatusCode: 200,
 		Message:    "channel optin successfully",
@@ -145,3 +180,295 @@ func (Optin *OptinController) OptinoutStatistics(ctx *gin.Context) {
 		Data:       res,
 	})
 }
+
+// CreateInvite is an API endpoint for creating an invite to a private channel.
+func (Optin *OptinController) CreateInvite(ctx *gin.Context) {
+	var request entities.ChannelInviteRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.BindJSON(&request); err != nil {
+			ctx.JSON(
+				http.StatusBadRequest, entities.ErrorResponse{
+					StatusCode: 400,
+					Error:      "failed to create invite",
+					Message:    err.Error(),
+				},
+			)
+			return
+		}
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	invite, err := Optin.useCases.CreateInvite(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to create invite",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "invite created successfully",
+			Data:       invite,
+		},
+	)
+}
+
+// ListInvites is an API endpoint for listing the invites to a channel.
+func (Optin *OptinController) ListInvites(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	invites, err := Optin.useCases.ListInvites(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch invites",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "invites fetched successfully",
+			Data:       invites,
+		},
+	)
+}
+
+// RevokeInvite is an API endpoint for revoking an invite to a channel.
+func (Optin *OptinController) RevokeInvite(ctx *gin.Context) {
+	chain, appID, code := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("code")
+
+	if err := Optin.useCases.RevokeInvite(ctx, chain, appID, ctx.GetString(consts.UserAddress), code); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to revoke invite",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "invite revoked successfully",
+		},
+	)
+}
+
+// ListOptinRequests is an API endpoint for listing the requests to opt in to a private channel.
+func (Optin *OptinController) ListOptinRequests(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	requests, err := Optin.useCases.ListOptinRequests(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch optin requests",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "optin requests fetched successfully",
+			Data:       requests,
+		},
+	)
+}
+
+// ApproveOptinRequest is an API endpoint for approving the request of a user to opt in to a private channel.
+func (Optin *OptinController) ApproveOptinRequest(ctx *gin.Context) {
+	chain, appID, requester := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("requester")
+
+	err := Optin.useCases.ApproveOptinRequest(ctx, chain, appID, ctx.GetString(consts.UserAddress), requester)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to approve optin request",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "optin request approved successfully",
+		},
+	)
+}
+
+// RejectOptinRequest is an API endpoint for rejecting the request of a user to opt in to a private channel.
+func (Optin *OptinController) RejectOptinRequest(ctx *gin.Context) {
+	chain, appID, requester := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("requester")
+
+	err := Optin.useCases.RejectOptinRequest(ctx, chain, appID, ctx.GetString(consts.UserAddress), requester)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to reject optin request",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "optin request rejected successfully",
+		},
+	)
+}
+
+// SetTokenGate is an API endpoint for requiring a holding of a token or NFT to opt in to a channel.
+func (Optin *OptinController) SetTokenGate(ctx *gin.Context) {
+	var request entities.ChannelTokenGateRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to set token gate",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	gate, err := Optin.useCases.SetTokenGate(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to set token gate",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "token gate set successfully",
+			Data:       gate,
+		},
+	)
+}
+
+// GetTokenGate is an API endpoint for fetching what a channel requires to hold to opt in to it.
+func (Optin *OptinController) GetTokenGate(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	gate, err := Optin.useCases.GetTokenGate(ctx, chain, appID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch token gate",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "token gate fetched successfully",
+			Data:       gate,
+		},
+	)
+}
+
+// RemoveTokenGate is an API endpoint for letting anyone opt in to a channel again, whatever they hold.
+func (Optin *OptinController) RemoveTokenGate(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := Optin.useCases.RemoveTokenGate(ctx, chain, appID, ctx.GetString(consts.UserAddress)); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to remove token gate",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "token gate removed successfully",
+		},
+	)
+}
+
+// OptinLink is an API endpoint for building a shareable link to opt in to a channel, tagged with the campaign or
+// referral in the source query parameter.
+func (Optin *OptinController) OptinLink(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	link, err := Optin.useCases.OptinLink(ctx, chain, appID, ctx.Query("source"), ctx.Query("invite"))
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to build optin link",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "optin link built successfully",
+			Data:       link,
+		},
+	)
+}
+
+// OptinQRCode is an API endpoint for rendering the shareable link to opt in to a channel as a PNG QR code.
+func (Optin *OptinController) OptinQRCode(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	png, err := Optin.useCases.OptinQRCode(
+		ctx, chain, appID, ctx.Query("source"), ctx.Query("invite"), cast.ToInt(ctx.Query("size")),
+	)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to render optin QR code",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "image/png", png)
+}
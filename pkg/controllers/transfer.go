@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+type ChannelTransferController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.ChannelTransferUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewChannelTransferController
+func NewChannelTransferController(
+	router *gin.RouterGroup, transferUseCase usecases.ChannelTransferUseCaseImply, middleWare *middlewares.Middlewares,
+) *ChannelTransferController {
+	return &ChannelTransferController{
+		router:      router,
+		useCases:    transferUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the ChannelTransferController.
+func (t *ChannelTransferController) InitRoutes() {
+	v1 := t.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", t.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", t.middleWares.VerifyUserOnboarded)
+
+	channelsRead := onboarded.Group("", t.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("/chains/:chain/channels/transfers", t.ListIncomingTransfers)
+		channelsRead.GET("/chains/:chain/channels/:app_id/transfer", t.GetTransfer)
+	}
+
+	// who owns a channel is not up to a scoped token
+	unscoped := onboarded.Group("", t.middleWares.RequireUnscoped)
+	{
+		unscoped.POST("/chains/:chain/channels/:app_id/transfer", t.InitiateTransfer)
+		unscoped.POST("/chains/:chain/channels/:app_id/transfer/accept", t.AcceptTransfer)
+		unscoped.DELETE("/chains/:chain/channels/:app_id/transfer", t.CancelTransfer)
+		unscoped.POST("/chains/:chain/channels/:app_id/adoption", t.RequestAdoption)
+	}
+
+	admin := onboarded.Group("", t.middleWares.RequireUnscoped, t.middleWares.IsAdminUser)
+	{
+		admin.GET("/admin/chains/:chain/channels/adoptions", t.ListAdoptions)
+		admin.PUT("/admin/chains/:chain/channels/:app_id/adoption/approve", t.ApproveAdoption)
+		admin.DELETE("/admin/chains/:chain/channels/:app_id/adoption", t.RejectAdoption)
+	}
+}
+
+// InitiateTransfer is an API endpoint for offering a channel to another user.
+func (t *ChannelTransferController) InitiateTransfer(ctx *gin.Context) {
+	log := utilities.NewLogger("InitiateTransfer")
+
+	var request entities.ChannelTransferRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to transfer channel",
+				Message:    "address is required",
+			},
+		)
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received InitiateTransfer request for chain:", chain, " appID:", appID, " to:", request.Address)
+
+	transfer, err := t.useCases.InitiateTransfer(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to transfer channel",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel transfer initiated successfully",
+			Data:       transfer,
+		},
+	)
+}
+
+// AcceptTransfer is an API endpoint for accepting a channel offered to the user.
+func (t *ChannelTransferController) AcceptTransfer(ctx *gin.Context) {
+	var request entities.AcceptChannelTransferRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to accept channel transfer",
+				Message:    "nonce and signature are required",
+			},
+		)
+		return
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := t.useCases.AcceptTransfer(ctx, chain, appID, ctx.GetString(consts.UserAddress), request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to accept channel transfer",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel transferred successfully",
+		},
+	)
+}
+
+// CancelTransfer is an API endpoint for withdrawing or declining the change of the owner of a channel.
+func (t *ChannelTransferController) CancelTransfer(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := t.useCases.CancelTransfer(ctx, chain, appID, ctx.GetString(consts.UserAddress)); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to cancel channel transfer",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel transfer cancelled successfully",
+		},
+	)
+}
+
+// GetTransfer is an API endpoint for fetching the pending change of the owner of a channel.
+func (t *ChannelTransferController) GetTransfer(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	transfer, err := t.useCases.GetTransfer(ctx, chain, appID, ctx.GetString(consts.UserAddress))
+	if err != nil {
+		ctx.JSON(
+			http.StatusNotFound, entities.ErrorResponse{
+				StatusCode: 404,
+				Error:      "failed to fetch channel transfer",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel transfer fetched successfully",
+			Data:       transfer,
+		},
+	)
+}
+
+// ListIncomingTransfers is an API endpoint for listing the channels offered to the user.
+func (t *ChannelTransferController) ListIncomingTransfers(ctx *gin.Context) {
+	user := tokenUser(ctx)
+
+	transfers, err := t.useCases.ListIncomingTransfers(ctx, user.Chain, user.Address)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to fetch channel transfers",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel transfers fetched successfully",
+			Data:       transfers,
+		},
+	)
+}
+
+// RequestAdoption is an API endpoint for asking to become the owner of an orphaned channel.
+func (t *ChannelTransferController) RequestAdoption(ctx *gin.Context) {
+	var request entities.ChannelAdoptionRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.BindJSON(&request); err != nil {
+			ctx.JSON(
+				http.StatusBadRequest, entities.ErrorResponse{
+					StatusCode: 400,
+					Error:      "failed to request channel adoption",
+					Message:    err.Error(),
+				},
+			)
+			return
+		}
+	}
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	transfer, err := t.useCases.RequestAdoption(ctx, chain, appID, ctx.GetString(consts.UserAddress), request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to request channel adoption",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel adoption requested successfully",
+			Data:       transfer,
+		},
+	)
+}
+
+// ListAdoptions is an admin API endpoint for listing the adoptions of orphaned channels waiting for approval.
+func (t *ChannelTransferController) ListAdoptions(ctx *gin.Context) {
+	adoptions, err := t.useCases.ListAdoptions(ctx, ctx.Param("chain"))
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError, entities.ErrorResponse{
+				StatusCode: 500,
+				Error:      "failed to fetch channel adoptions",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel adoptions fetched successfully",
+			Data:       adoptions,
+		},
+	)
+}
+
+// ApproveAdoption is an admin API endpoint for handing an orphaned channel to the user who asked to adopt it.
+func (t *ChannelTransferController) ApproveAdoption(ctx *gin.Context) {
+	log := utilities.NewLogger("ApproveAdoption")
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	log.Info("Received ApproveAdoption request for chain:", chain, " appID:", appID)
+
+	if err := t.useCases.ApproveAdoption(ctx, chain, appID); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to approve channel adoption",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel adoption approved successfully",
+		},
+	)
+}
+
+// RejectAdoption is an admin API endpoint for rejecting the adoption of an orphaned channel.
+func (t *ChannelTransferController) RejectAdoption(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+
+	if err := t.useCases.RejectAdoption(ctx, chain, appID); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to reject channel adoption",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "channel adoption rejected successfully",
+		},
+	)
+}
@@ -3,6 +3,9 @@ package controllers
 import (
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/spf13/cast"
 
 	"notiboy/utilities"
 
@@ -15,6 +18,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// maxPATGrace is the longest a rotated personal access token keeps working
+const maxPATGrace = 7 * 24 * time.Hour
+
 type UserController struct {
 	router      *gin.RouterGroup
 	useCases    usecases.UserUseCaseImply
@@ -35,30 +41,67 @@ func (user *UserController) InitRoutes() {
 	v1 := user.router.Group(config.GetConfig().Server.APIVersion)
 	v1.GET("/stats/global", user.GlobalSatistics)
 
-	verifyAddress := v1.Group("", user.middleWares.ValidateUserAddress)
+	// a signed transaction can be replayed by whoever gets hold of it, the signed challenge replaces it
+	if config.GetConfig().LegacyTxnLogin {
+		verifyAddress := v1.Group("", middlewares.Deprecated, user.middleWares.ValidateUserAddress)
+		{
+			verifyAddress.POST("/chains/:chain/users/:address", user.Onboarding)
+
+			if config.GetConfig().AutoOnboardUsers {
+				verifyAddress.POST("/chains/:chain/users/:address/login", user.Login)
+			} else {
+				onboarded := verifyAddress.Group("", user.middleWares.VerifyUserOnboarded)
+				onboarded.POST("/chains/:chain/users/:address/login", user.Login)
+			}
+		}
+	}
+
+	signedChallenge := v1.Group("", user.middleWares.ValidateSignedChallenge)
 	{
-		verifyAddress.POST("/chains/:chain/users/:address", user.Onboarding)
+		signedChallenge.POST("/chains/:chain/users/:address/onboard/wallet", user.Onboarding)
 
 		if config.GetConfig().AutoOnboardUsers {
-			verifyAddress.POST("/chains/:chain/users/:address/login", user.Login)
+			signedChallenge.POST("/chains/:chain/users/:address/login/wallet", user.Login)
 		} else {
-			onboarded := verifyAddress.Group("", user.middleWares.VerifyUserOnboarded)
-			onboarded.POST("/chains/:chain/users/:address/login", user.Login)
+			onboarded := signedChallenge.Group("", user.middleWares.VerifyUserOnboarded)
+			onboarded.POST("/chains/:chain/users/:address/login/wallet", user.Login)
 		}
 	}
 
+	// the refresh token is the credential here, the access token may already have expired
+	v1.POST("/chains/:chain/users/:address/refresh", user.RefreshSession)
+
+	// the sessions of the user are deleted first, the deletion is followed with a token signed for the user
+	deletingUser := v1.Group("", user.middleWares.ValidateTokenSignature, user.middleWares.RequireUnscoped)
+	deletingUser.GET("/account-deletions/:id", user.GetAccountDeletion)
+
 	validToken := v1.Group("", user.middleWares.ValidateToken)
 	validTokenUser := validToken.Group("", user.middleWares.VerifyUserOnboarded)
+
+	usersRead := validTokenUser.Group("", user.middleWares.RequireScope(consts.ScopeUsersRead))
 	{
-		validTokenUser.DELETE("/chains/:chain/users/:address/logout", user.Logout)
-		validTokenUser.GET("/chains/:chain/users/:address", user.GetUser)
-		validTokenUser.PUT("/chains/:chain/users/:address", user.ProfileUpdate)
-		validTokenUser.DELETE("/chains/:chain/users/:address", user.Offboarding)
-		validTokenUser.GET("/chains/:chain/stats/users", user.UserStatistics)
-		validTokenUser.POST("/chains/:chain/users/:address/pat/kind/:kind/:name", user.GeneratePAT)
-		validTokenUser.GET("/chains/:chain/users/:address/pat/kind/:kind", user.GetPAT)
-		validTokenUser.DELETE("/chains/:chain/users/:address/pat/kind/:kind/:uuid", user.RevokePAT)
-		validTokenUser.POST("/chains/:chain/users/:address/fcm", user.StoreFCM)
+		usersRead.GET("/chains/:chain/users/:address", user.GetUser)
+		usersRead.GET("/chains/:chain/stats/users", user.UserStatistics)
+	}
+
+	usersWrite := validTokenUser.Group("", user.middleWares.RequireScope(consts.ScopeUsersWrite))
+	{
+		usersWrite.PUT("/chains/:chain/users/:address", user.ProfileUpdate)
+		usersWrite.POST("/chains/:chain/users/:address/fcm", user.StoreFCM)
+	}
+
+	// a scoped token can't manage tokens or the account, that would let it widen its own access
+	unscoped := validTokenUser.Group("", user.middleWares.RequireUnscoped)
+	{
+		unscoped.DELETE("/chains/:chain/users/:address/logout", user.Logout)
+		unscoped.GET("/chains/:chain/users/:address/sessions", user.ListSessions)
+		unscoped.DELETE("/chains/:chain/users/:address/sessions", user.RevokeOtherSessions)
+		unscoped.DELETE("/chains/:chain/users/:address/sessions/:session_id", user.RevokeSession)
+		unscoped.DELETE("/chains/:chain/users/:address", user.Offboarding)
+		unscoped.POST("/chains/:chain/users/:address/pat/kind/:kind/:name", user.GeneratePAT)
+		unscoped.GET("/chains/:chain/users/:address/pat/kind/:kind", user.GetPAT)
+		unscoped.DELETE("/chains/:chain/users/:address/pat/kind/:kind/:name", user.RevokePAT)
+		unscoped.POST("/chains/:chain/users/:address/pat/kind/:kind/:name/rotate", user.RotatePAT)
 	}
 }
 
@@ -158,7 +201,8 @@ func (user *UserController) Onboarding(ctx *gin.Context) {
 	})
 }
 
-// Offboarding is an API endpoint for handling user offboarding.
+// Offboarding is an API endpoint for handling user offboarding. With mode=erase all the data of the account
+// is deleted in the background instead, the response holds the deletion job to follow its progress.
 func (user *UserController) Offboarding(ctx *gin.Context) {
 
 	chain := ctx.Param("chain")
@@ -175,6 +219,26 @@ func (user *UserController) Offboarding(ctx *gin.Context) {
 	}
 	log.Info("Received Offboarding request")
 
+	if ctx.Query("mode") == "erase" {
+		job, err := user.useCases.EraseAccount(ctx, entities.UserIdentifier{Chain: chain, Address: address})
+		if err != nil {
+			log.WithError(err).Error("failed to schedule account deletion")
+			ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "offboarding failed",
+				Message:    err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, entities.Response{
+			StatusCode: 202,
+			Message:    "account deletion scheduled",
+			Data:       job,
+		})
+		return
+	}
+
 	err := user.useCases.Offboarding(ctx, address, chain)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
@@ -191,6 +255,34 @@ func (user *UserController) Offboarding(ctx *gin.Context) {
 	})
 }
 
+// GetAccountDeletion is an API endpoint for following the progress of an account deletion.
+func (user *UserController) GetAccountDeletion(ctx *gin.Context) {
+	log := utilities.NewLogger("GetAccountDeletion")
+
+	id := ctx.Param("id")
+	identifier := entities.UserIdentifier{
+		Chain:   ctx.GetString(consts.UserChain),
+		Address: ctx.GetString(consts.UserAddress),
+	}
+
+	job, err := user.useCases.GetAccountDeletion(ctx, identifier, id)
+	if err != nil {
+		log.WithError(err).Errorf("failed to get account deletion %s", id)
+		ctx.JSON(http.StatusNotFound, entities.ErrorResponse{
+			StatusCode: 404,
+			Error:      "failed to get account deletion",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: 200,
+		Message:    "account deletion retrieved successfully",
+		Data:       job,
+	})
+}
+
 // UserStatistics is an API endpoint for retrieving user statistics.
 func (user *UserController) UserStatistics(ctx *gin.Context) {
 	chain := ctx.Param("chain")
@@ -297,7 +389,7 @@ func (user *UserController) Login(ctx *gin.Context) {
 	}
 	log.Info("Received Login request")
 
-	token, err := user.useCases.Login(ctx, req)
+	tokens, err := user.useCases.Login(ctx, req, sessionClient(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
 			StatusCode: 500,
@@ -310,12 +402,126 @@ func (user *UserController) Login(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, entities.Response{
 		StatusCode: http.StatusOK,
 		Message:    "Login successful",
-		Data: map[string]string{
-			"token": token,
-		},
+		Data:       tokens,
 	})
 }
 
+// RefreshSession is an API endpoint that exchanges a refresh token for a new access token.
+// The refresh token is rotated, the one sent can't be used again.
+func (user *UserController) RefreshSession(ctx *gin.Context) {
+	log := utilities.NewLogger("RefreshSession")
+
+	var body entities.RefreshRequest
+	if err := ctx.BindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Error:      "binding error",
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	req := entities.UserIdentifier{
+		Chain:   ctx.Param("chain"),
+		Address: ctx.Param("address"),
+	}
+
+	tokens, err := user.useCases.RefreshSession(ctx, req, body.RefreshToken, sessionClient(ctx))
+	if err != nil {
+		log.WithError(err).Errorf("session refresh failed for user %s", req.Address)
+		ctx.JSON(http.StatusUnauthorized, entities.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Error:      err.Error(),
+			Message:    "Session refresh failed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: http.StatusOK,
+		Message:    "Session refreshed",
+		Data:       tokens,
+	})
+}
+
+// ListSessions is an API endpoint that lists the active sessions of the user.
+func (user *UserController) ListSessions(ctx *gin.Context) {
+	log := utilities.NewLogger("ListSessions")
+	log.Info("Received request")
+
+	sessions, err := user.useCases.ListSessions(ctx, tokenUser(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Error:      err.Error(),
+			Message:    "Session retrieval failed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: http.StatusOK,
+		Message:    "Sessions retrieved successfully",
+		Data:       sessions,
+	})
+}
+
+// RevokeSession is an API endpoint that signs the user out of one session.
+func (user *UserController) RevokeSession(ctx *gin.Context) {
+	log := utilities.NewLogger("RevokeSession")
+	log.Info("Received request")
+
+	if err := user.useCases.RevokeSession(ctx, tokenUser(ctx), ctx.Param("session_id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Error:      err.Error(),
+			Message:    "Session revocation failed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: http.StatusOK,
+		Message:    "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions is an API endpoint that signs the user out of every session but the current one.
+func (user *UserController) RevokeOtherSessions(ctx *gin.Context) {
+	log := utilities.NewLogger("RevokeOtherSessions")
+	log.Info("Received request")
+
+	if err := user.useCases.RevokeOtherSessions(ctx, tokenUser(ctx)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Error:      err.Error(),
+			Message:    "Session revocation failed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: http.StatusOK,
+		Message:    "Other sessions revoked successfully",
+	})
+}
+
+// sessionClient describes the client a session is started or refreshed from
+func sessionClient(ctx *gin.Context) entities.UserSession {
+	return entities.UserSession{
+		UserAgent: ctx.Request.UserAgent(),
+		IP:        ctx.ClientIP(),
+	}
+}
+
+// tokenUser is the user the access token of the request was issued to
+func tokenUser(ctx *gin.Context) entities.UserIdentifier {
+	return entities.UserIdentifier{
+		Chain:   ctx.GetString(consts.UserChain),
+		Address: ctx.GetString(consts.UserAddress),
+	}
+}
+
 // Logout is an API endpoint for user logout.
 func (user *UserController) Logout(ctx *gin.Context) {
 	var req entities.UserIdentifier
@@ -378,9 +584,19 @@ func (user *UserController) GeneratePAT(ctx *gin.Context) {
 		return
 	}
 
+	// there is one mobile token per user, with full access
+	if kind != "normal" && (len(req.Scopes) != 0 || req.ExpiryDays != 0) {
+		ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Error:      "scopes and expiry are only supported for normal tokens",
+			Message:    "PAT Generation failed",
+		})
+		return
+	}
+
 	log.Info("Received GeneratePAT request")
 
-	token, err := user.useCases.GeneratePAT(ctx, name, kind, req.Description)
+	token, err := user.useCases.GeneratePAT(ctx, name, kind, req.Description, req.Scopes, req.ExpiryDays)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
@@ -393,9 +609,10 @@ func (user *UserController) GeneratePAT(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, entities.Response{
 		StatusCode: http.StatusOK,
 		Message:    "PAT generated successfully",
-		Data: map[string]string{
-			"token": token,
-			"name":  name,
+		Data: map[string]interface{}{
+			"token":  token,
+			"name":   name,
+			"scopes": req.Scopes,
 		},
 	})
 }
@@ -447,7 +664,9 @@ func (user *UserController) RevokePAT(ctx *gin.Context) {
 		return
 	}
 
-	err := user.useCases.RevokePAT(ctx, ctx.Param("uuid"), kind)
+	// the path segment is named like the one of GeneratePAT because gin needs the same wildcard name at
+	// the same position of every route, it holds the uuid of the token
+	err := user.useCases.RevokePAT(ctx, ctx.Param("name"), kind)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
@@ -463,6 +682,48 @@ func (user *UserController) RevokePAT(ctx *gin.Context) {
 	})
 }
 
+// RotatePAT is an API endpoint that replaces a personal access token. The replaced token keeps working
+// for the grace_period query parameter, a duration such as 24h of at most a week.
+func (user *UserController) RotatePAT(ctx *gin.Context) {
+	log := utilities.NewLogger("RotatePAT")
+
+	log.Info("Received request")
+
+	grace := cast.ToDuration(config.GetConfig().TTL.PATGrace)
+	if val := ctx.Query("grace_period"); val != "" {
+		var err error
+		grace, err = time.ParseDuration(val)
+		if err != nil || grace < 0 || grace > maxPATGrace {
+			ctx.JSON(http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Error:      "grace_period must be a duration of at most 168h",
+				Message:    "PAT rotation failed",
+			})
+			return
+		}
+	}
+
+	// the name path segment holds the uuid of the token, see RevokePAT
+	token, err := user.useCases.RotatePAT(ctx, ctx.Param("name"), ctx.Param("kind"), grace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Error:      err.Error(),
+			Message:    "PAT rotation failed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Response{
+		StatusCode: http.StatusOK,
+		Message:    "PAT rotated successfully",
+		Data: map[string]interface{}{
+			"token":        token,
+			"grace_period": grace.String(),
+		},
+	})
+}
+
 func (user *UserController) StoreFCM(ctx *gin.Context) {
 	log := utilities.NewLogger("StoreFCM")
 	log.Info("Received request")
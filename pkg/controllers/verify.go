@@ -38,7 +38,8 @@ func (verify *VerifyController) InitRoutes() {
 
 	validateToken := v1.Group("", verify.middleWares.ValidateToken)
 	onboarded := validateToken.Group("", verify.middleWares.VerifyUserOnboarded)
-	onboarded.POST("chains/:chain/user/:address/verification/mediums/:medium", verify.Verify)
+	usersWrite := onboarded.Group("", verify.middleWares.RequireScope(consts.ScopeUsersWrite))
+	usersWrite.POST("chains/:chain/user/:address/verification/mediums/:medium", verify.Verify)
 }
 
 // Verify is an API endpoint for initiating the verification process.
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/middlewares"
+	"notiboy/pkg/usecases"
+	"notiboy/utilities"
+)
+
+const (
+	defaultWebhookDeliveries = 50
+	maxWebhookDeliveries     = 500
+)
+
+type WebhookController struct {
+	router      *gin.RouterGroup
+	useCases    usecases.WebhookUseCaseImply
+	middleWares *middlewares.Middlewares
+}
+
+// NewWebhookController
+func NewWebhookController(
+	router *gin.RouterGroup, webhookUseCase usecases.WebhookUseCaseImply, middleWare *middlewares.Middlewares,
+) *WebhookController {
+	return &WebhookController{
+		router:      router,
+		useCases:    webhookUseCase,
+		middleWares: middleWare,
+	}
+}
+
+// InitRoutes initializes the routes for the WebhookController.
+func (w *WebhookController) InitRoutes() {
+	v1 := w.router.Group(config.GetConfig().Server.APIVersion)
+
+	validateToken := v1.Group("", w.middleWares.ValidateToken)
+
+	onboarded := validateToken.Group("", w.middleWares.VerifyUserOnboarded)
+
+	channelsWrite := onboarded.Group("", w.middleWares.RequireScope(consts.ScopeChannelsWrite))
+	{
+		channelsWrite.POST("chains/:chain/channels/:app_id/webhooks", w.CreateWebhook)
+		channelsWrite.DELETE("chains/:chain/channels/:app_id/webhooks/:webhook_id", w.DeleteWebhook)
+	}
+
+	channelsRead := onboarded.Group("", w.middleWares.RequireScope(consts.ScopeChannelsRead))
+	{
+		channelsRead.GET("chains/:chain/channels/:app_id/webhooks", w.ListWebhooks)
+		channelsRead.GET("chains/:chain/channels/:app_id/webhooks/:webhook_id/deliveries", w.ListWebhookDeliveries)
+	}
+}
+
+// CreateWebhook is an API endpoint for registering a webhook for channel events.
+func (w *WebhookController) CreateWebhook(ctx *gin.Context) {
+	log := utilities.NewLogger("CreateWebhook")
+
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	user := ctx.GetString(consts.UserAddress)
+
+	var request entities.WebhookRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to create webhook",
+				Message:    "invalid request body",
+			},
+		)
+		return
+	}
+
+	log.Info("Received CreateWebhook request for chain:", chain, " appID:", appID)
+
+	webhook, err := w.useCases.CreateWebhook(ctx, chain, appID, user, request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to create webhook",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "webhook created successfully, store the secret as it will not be shown again",
+			Data:       webhook,
+		},
+	)
+}
+
+// ListWebhooks is an API endpoint for listing the webhooks of a channel.
+func (w *WebhookController) ListWebhooks(ctx *gin.Context) {
+	chain, appID := ctx.Param("chain"), ctx.Param("app_id")
+	user := ctx.GetString(consts.UserAddress)
+
+	webhooks, err := w.useCases.ListWebhooks(ctx, chain, appID, user)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch webhooks",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "webhooks fetched successfully",
+			Data:       webhooks,
+		},
+	)
+}
+
+// DeleteWebhook is an API endpoint for removing a webhook of a channel.
+func (w *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	log := utilities.NewLogger("DeleteWebhook")
+
+	chain, appID, webhookID := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("webhook_id")
+	user := ctx.GetString(consts.UserAddress)
+
+	log.Info("Received DeleteWebhook request for chain:", chain, " appID:", appID, " webhook:", webhookID)
+
+	if err := w.useCases.DeleteWebhook(ctx, chain, appID, user, webhookID); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to delete webhook",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "webhook deleted successfully",
+		},
+	)
+}
+
+// ListWebhookDeliveries is an API endpoint for fetching the delivery log of a webhook.
+func (w *WebhookController) ListWebhookDeliveries(ctx *gin.Context) {
+	chain, appID, webhookID := ctx.Param("chain"), ctx.Param("app_id"), ctx.Param("webhook_id")
+	user := ctx.GetString(consts.UserAddress)
+
+	limit := cast.ToInt(ctx.DefaultQuery("limit", cast.ToString(defaultWebhookDeliveries)))
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = defaultWebhookDeliveries
+	}
+
+	deliveries, err := w.useCases.ListWebhookDeliveries(ctx, chain, appID, user, webhookID, limit)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: 400,
+				Error:      "failed to fetch webhook deliveries",
+				Message:    err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK, entities.Response{
+			StatusCode: 200,
+			Message:    "webhook deliveries fetched successfully",
+			Data:       deliveries,
+		},
+	)
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+type ChannelSubscriber struct {
+	Chain      string    `json:"chain"`
+	AppID      string    `json:"app_id"`
+	Address    string    `json:"address"`
+	OptinTime  time.Time `json:"optin_time"`
+	OptoutTime time.Time `json:"optout_time,omitempty"`
+}
+
+type CohortRetention struct {
+	// Week is the date of the monday the cohort opted in on
+	Week string `json:"week"`
+	Size int    `json:"size"`
+	// Retention holds the fraction of the cohort still opted in at the end of each week since the cohort week
+	Retention []float64 `json:"retention"`
+}
+
+type MediumReadRate struct {
+	Sent     int     `json:"sent"`
+	Read     int     `json:"read"`
+	ReadRate float64 `json:"read_rate"`
+}
+
+type HourlyReadRate struct {
+	Hour     int     `json:"hour"`
+	Sent     int     `json:"sent"`
+	Read     int     `json:"read"`
+	ReadRate float64 `json:"read_rate"`
+}
+
+type ChannelAnalytics struct {
+	Days                 int                        `json:"days"`
+	Weeks                int                        `json:"weeks"`
+	Cohorts              []CohortRetention          `json:"cohorts"`
+	ChurnRate            float64                    `json:"churn_rate"`
+	ReadRate             map[string]*MediumReadRate `json:"read_rate"`
+	MedianTimeToReadSecs int64                      `json:"median_time_to_read_secs"`
+	// BestSendHour is the UTC hour of the day with the highest read rate, nil if nothing was sent
+	BestSendHour *int             `json:"best_send_hour"`
+	SendHours    []HourlyReadRate `json:"send_hours"`
+}
+
+// ExportRecordWriter receives the exported rows of a dataset one at a time, in the column order of the dataset
+type ExportRecordWriter func(record []any) error
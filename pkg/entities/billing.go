@@ -11,6 +11,19 @@ type BillingRequest struct {
 	TotalSent     int      `json:"total_sent"`
 	Days          int      `json:"days"`
 	OwnedChannels []string `json:"owned_channels"`
+	// BillingAccount is the linked account whose balance pays for the membership, nil if it's the own balance
+	BillingAccount *UserIdentifier `json:"-"`
+	// SharedWith are the other accounts paid for by the same balance, they always share the membership
+	SharedWith []UserIdentifier `json:"-"`
+}
+
+// BalanceAccount is the account whose billing balance is charged for the request
+func (req BillingRequest) BalanceAccount() UserIdentifier {
+	if req.BillingAccount != nil {
+		return *req.BillingAccount
+	}
+
+	return UserIdentifier{Chain: req.Chain, Address: req.Address}
 }
 
 type BillingRecords struct {
@@ -28,4 +41,5 @@ type BillingInfo struct {
 	RemainingNotifications int              `json:"remaining_notifications"`
 	BillingRecords         []BillingRecords `json:"billing_records"`
 	BlockedChannels        []string         `json:"blocked_channels"`
+	BillingAccount         *UserIdentifier  `json:"billing_account,omitempty"`
 }
@@ -17,6 +17,10 @@ type ChannelModel struct {
 	Owner            string    `json:"address"`
 	Verified         bool      `json:"verified"`
 	Status           string    `json:"status"`
+	Domain           string    `json:"domain,omitempty"`
+	Visibility       string    `json:"visibility"`
+	Category         string    `json:"category,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
 	CreatedTimestamp time.Time `json:"created_timestamp"`
 }
 
@@ -27,6 +31,7 @@ type ChannelInfo struct {
 	Chain       string `json:"chain"`
 	AppID       string `json:"app_id"`
 	Address     string `json:"address"`
+	Visibility  string `json:"visibility,omitempty"`
 }
 
 type ChannelStatsResponse struct {
@@ -46,6 +51,10 @@ type ChannelReadSentResponse struct {
 	Medium    string `json:"medium,omitempty"`
 	Read      int    `json:"read,omitempty"`
 	Sent      int    `json:"sent,omitempty"`
+	Recalled  int    `json:"recalled,omitempty"`
+	Clicked   int    `json:"clicked,omitempty"`
+	// ClickThroughRate is the ratio of unique link clicks to notifications sent
+	ClickThroughRate float64 `json:"click_through_rate"`
 }
 
 type ListChannelRequest struct {
@@ -54,6 +63,8 @@ type ListChannelRequest struct {
 	WithLogo   bool   `json:"withLogo,omitempty"`
 	Verified   bool   `json:"verified,omitempty"`
 	NameSearch string `json:"nameSearch,omitempty"`
+	Category   string `json:"category,omitempty"`
+	Tag        string `json:"tag,omitempty"`
 }
 
 type ListChannelUsersRequest struct {
@@ -64,3 +75,77 @@ type ListChannelUsersRequest struct {
 	WithLogo    bool
 	AddressOnly bool
 }
+
+// ChannelVerification is a request of the owner of a channel to get it verified, with the evidence for the admin
+// reviewing it
+type ChannelVerification struct {
+	Chain       string            `json:"chain"`
+	AppID       string            `json:"app_id"`
+	RequestedBy string            `json:"requested_by"`
+	Website     string            `json:"website"`
+	Socials     map[string]string `json:"socials,omitempty"`
+	Notes       string            `json:"notes,omitempty"`
+	Status      string            `json:"status"`
+	Reason      string            `json:"reason,omitempty"`
+	ReviewedBy  string            `json:"reviewed_by,omitempty"`
+	Created     time.Time         `json:"created"`
+	Updated     time.Time         `json:"updated"`
+}
+
+type ChannelVerificationRequest struct {
+	Website string            `json:"website" binding:"required"`
+	Socials map[string]string `json:"socials"`
+	Notes   string            `json:"notes"`
+}
+
+// VerificationDecisionRequest carries the reason of an admin rejecting a verification or revoking it
+type VerificationDecisionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ChannelDomain is a domain claimed by a channel. It proves to belong to the channel by a TXT record or a
+// well-known file carrying the token issued for the claim.
+type ChannelDomain struct {
+	Chain        string     `json:"chain"`
+	AppID        string     `json:"app_id"`
+	Domain       string     `json:"domain"`
+	Token        string     `json:"token"`
+	Status       string     `json:"status"`
+	Method       string     `json:"method,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	TXTRecord    string     `json:"txt_record"`
+	TXTValue     string     `json:"txt_value"`
+	WellKnownURL string     `json:"well_known_url"`
+	Checked      *time.Time `json:"checked,omitempty"`
+	Verified     *time.Time `json:"verified,omitempty"`
+	Created      time.Time  `json:"created"`
+}
+
+type ChannelDomainRequest struct {
+	Domain string `json:"domain" binding:"required"`
+}
+
+// ChannelTagsRequest sets the category and tags a channel is discovered by
+type ChannelTagsRequest struct {
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
+// CurateChannelTagsRequest sets the category and tags of a channel on behalf of the admins, its owner can't change
+// them while they are curated.
+type CurateChannelTagsRequest struct {
+	ChannelTagsRequest
+	Curated bool `json:"curated"`
+}
+
+// RankedChannel is a channel ranked for discovery with its score
+type RankedChannel struct {
+	ChannelModel
+	Score float64 `json:"score"`
+}
+
+// ChannelScore is the score of a channel in a ranking
+type ChannelScore struct {
+	AppID string
+	Score float64
+}
@@ -25,6 +25,8 @@ type Response struct {
 	MetaData           *MetaData           `json:"meta_data,omitempty"`
 	PaginationMetaData *PaginationMetaData `json:"pagination_meta_data,omitempty"`
 	Data               interface{}         `json:"data,omitempty"`
+	// Profiles are the profiles of the users Data refers to, keyed by address
+	Profiles map[string]*UserProfile `json:"profiles,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// AccountDeletion is a background job erasing all the data of an account
+type AccountDeletion struct {
+	ID string `json:"id"`
+	UserIdentifier
+	Status      string    `json:"status"`
+	StepsDone   int       `json:"steps_done"`
+	StepsTotal  int       `json:"steps_total"`
+	CurrentStep string    `json:"current_step,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Requested   time.Time `json:"requested"`
+	Updated     time.Time `json:"updated"`
+	LeaseUntil  time.Time `json:"-"`
+}
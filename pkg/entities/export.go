@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"io"
+	"time"
+)
+
+// ExportedAccount is the account section of a data export, the export of an account holds everything stored
+// for it, section by section
+type ExportedAccount struct {
+	UserIdentifier
+	DNS        string    `json:"dns_name,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type ExportedDevice struct {
+	DeviceID string    `json:"device_id"`
+	Updated  time.Time `json:"updated"`
+}
+
+type ExportedBilling struct {
+	Membership string           `json:"membership,omitempty"`
+	Expiry     time.Time        `json:"expiry"`
+	Updated    time.Time        `json:"updated"`
+	Balance    float64          `json:"balance"`
+	Records    []BillingRecords `json:"billing_records"`
+}
+
+// VerificationRecord is a medium verification of the account, its token isn't exported
+type VerificationRecord struct {
+	Medium        string `json:"medium"`
+	MediumAddress string `json:"medium_address"`
+	Expiry        string `json:"expiry,omitempty"`
+	Sent          bool   `json:"sent"`
+	Verified      bool   `json:"verified"`
+}
+
+// AccountArchive is a downloadable account export, Write streams it
+type AccountArchive struct {
+	Name        string
+	ContentType string
+	Write       func(w io.Writer) error
+}
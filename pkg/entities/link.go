@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// LinkAccountRequest links the account on another chain, the wallet of that account signs a login challenge
+// issued for it to prove its control.
+type LinkAccountRequest struct {
+	Chain         string `json:"chain" binding:"required"`
+	Address       string `json:"address" binding:"required"`
+	Nonce         string `json:"nonce" binding:"required"`
+	Signature     string `json:"signature" binding:"required"`
+	PublicKey     string `json:"public_key,omitempty"`
+	SharedBilling bool   `json:"shared_billing"`
+}
+
+// LinkedAccount is an account on another chain owned by the same user
+type LinkedAccount struct {
+	UserIdentifier
+	SharedBilling  bool            `json:"shared_billing"`
+	BillingAccount *UserIdentifier `json:"billing_account,omitempty"`
+	Created        time.Time       `json:"created"`
+}
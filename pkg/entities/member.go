@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ChannelMember is a user sharing the management of a channel with its owner
+type ChannelMember struct {
+	Chain     string    `json:"chain"`
+	AppID     string    `json:"app_id"`
+	Address   string    `json:"address"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	InvitedBy string    `json:"invited_by,omitempty"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+type ChannelMemberRequest struct {
+	Address string `json:"address"`
+	Role    string `json:"role" binding:"required"`
+}
@@ -72,17 +72,28 @@ type RequestNotification struct {
 	User    string
 }
 type ReadNotification struct {
-	Message     string    `json:"message,omitempty"`
-	Seen        bool      `json:"seen,omitempty"`
-	Link        string    `json:"link,omitempty"`
+	Chain       string     `json:"chain,omitempty"`
+	Receiver    string     `json:"receiver,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	Seen        bool       `json:"seen,omitempty"`
+	Link        string     `json:"link,omitempty"`
+	CreatedTime time.Time  `json:"created_time"`
+	AppID       string     `json:"app_id,omitempty"`
+	ChannelName string     `json:"channel_name"`
+	Hash        string     `json:"hash,omitempty"`
+	Uuid        string     `json:"uuid,omitempty"`
+	Kind        string     `json:"kind,omitempty"`
+	Logo        string     `json:"logo,omitempty"`
+	Verified    bool       `json:"verified"`
+	EditedTime  *time.Time `json:"edited_time,omitempty"`
+}
+
+// InboxCursor is the last notification of a page of a merged inbox, the next page starts after it
+type InboxCursor struct {
 	CreatedTime time.Time `json:"created_time"`
-	AppID       string    `json:"app_id,omitempty"`
-	ChannelName string    `json:"channel_name"`
-	Hash        string    `json:"hash,omitempty"`
-	Uuid        string    `json:"uuid,omitempty"`
-	Kind        string    `json:"kind,omitempty"`
-	Logo        string    `json:"logo,omitempty"`
-	Verified    bool      `json:"verified"`
+	Chain       string    `json:"chain"`
+	Receiver    string    `json:"receiver"`
+	Uuid        string    `json:"uuid"`
 }
 
 type UpdateReadStatusRequest struct {
@@ -93,13 +104,66 @@ type UpdateReadStatusRequest struct {
 	Address string
 }
 
+type NotificationReachEvent struct {
+	Chain     string
+	Channel   string
+	UUID      string
+	Medium    string
+	Event     string
+	Receiver  string
+	EventTime time.Time
+}
+
+type MediumReach struct {
+	Sent                int   `json:"sent"`
+	Delivered           int   `json:"delivered"`
+	Read                int   `json:"read"`
+	Clicked             int   `json:"clicked"`
+	TimeToFirstReadSecs int64 `json:"time_to_first_read_secs,omitempty"`
+}
+
 type NotificationReach struct {
-	MediumReadCount map[string]int `json:"medium_read_count"`
-	TotalSent       int            `json:"total_sent"`
+	UUID                string                  `json:"uuid"`
+	SentTime            time.Time               `json:"sent_time"`
+	Mediums             map[string]*MediumReach `json:"mediums"`
+	TimeToFirstReadSecs int64                   `json:"time_to_first_read_secs,omitempty"`
+}
+
+type SentNotification struct {
+	Chain           string            `json:"chain"`
+	Channel         string            `json:"app_id"`
+	UUID            string            `json:"uuid"`
+	Sender          string            `json:"sender"`
+	Receivers       []string          `json:"receivers"`
+	Message         string            `json:"message"`
+	Link            string            `json:"link"`
+	Type            string            `json:"type"`
+	CreatedTime     time.Time         `json:"created_time"`
+	DiscordMessages map[string]string `json:"-"`
+	Recalled        bool              `json:"recalled"`
+	TTL             int               `json:"-"`
+}
+
+type NotificationEvent struct {
+	Event      string     `json:"event"`
+	Uuid       string     `json:"uuid"`
+	AppID      string     `json:"app_id"`
+	Message    string     `json:"message,omitempty"`
+	Link       string     `json:"link,omitempty"`
+	EditedTime *time.Time `json:"edited_time,omitempty"`
+}
+
+type EditNotificationRequest struct {
+	Message string `json:"message" validate:"required"`
+	Link    string `json:"link"`
+	Chain   string
+	Channel string
+	UUID    string
+	Sender  string
 }
 
-type NotificationReachResponse struct {
-	Status  string              `json:"status"`
-	Message string              `json:"message"`
-	Data    []NotificationReach `json:"data"`
+type NotificationRevision struct {
+	Message    string    `json:"message"`
+	Link       string    `json:"link"`
+	EditedTime time.Time `json:"edited_time"`
 }
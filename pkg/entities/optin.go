@@ -1,5 +1,7 @@
 package entities
 
+import "time"
+
 type OptInOut struct {
 	Optin  int    `json:"optin"`
 	Optout int    `json:"optout"`
@@ -7,6 +9,75 @@ type OptInOut struct {
 }
 
 type ChannelOptInOutStats struct {
-	OptInOut   []OptInOut `json:"optInOut"`
-	TotalUsers int64      `json:"totalUsers"`
+	OptInOut   []OptInOut       `json:"optInOut"`
+	Sources    []OptInOutSource `json:"sources"`
+	TotalUsers int64            `json:"totalUsers"`
+}
+
+// OptInOutSource is the opt-ins and opt-outs of subscribers who came from a source, in total and by date
+type OptInOutSource struct {
+	Source   string     `json:"source"`
+	Optin    int        `json:"optin"`
+	Optout   int        `json:"optout"`
+	OptInOut []OptInOut `json:"optInOut"`
+}
+
+// OptinLink is a link to opt in to a channel, tagged with the campaign or referral users follow it from
+type OptinLink struct {
+	Chain  string `json:"chain"`
+	AppID  string `json:"app_id"`
+	Source string `json:"source"`
+	Invite string `json:"invite,omitempty"`
+	Link   string `json:"link"`
+}
+
+// ChannelInvite lets users opt in to a private channel without the approval of its owner
+type ChannelInvite struct {
+	Chain     string     `json:"chain"`
+	AppID     string     `json:"app_id"`
+	Code      string     `json:"code"`
+	Link      string     `json:"link"`
+	MaxUses   int        `json:"max_uses,omitempty"`
+	Uses      int        `json:"uses"`
+	Expires   *time.Time `json:"expires,omitempty"`
+	Revoked   bool       `json:"revoked"`
+	CreatedBy string     `json:"created_by"`
+	Created   time.Time  `json:"created"`
+}
+
+// ChannelInviteRequest creates an invite, an invite without max uses or expiry is redeemed any number of times
+// until it is revoked.
+type ChannelInviteRequest struct {
+	MaxUses   int    `json:"max_uses"`
+	ExpiresIn string `json:"expires_in"`
+}
+
+// OptinRequest is a request to opt in to a private channel waiting for the approval of its owner
+type OptinRequest struct {
+	Chain   string    `json:"chain"`
+	AppID   string    `json:"app_id"`
+	Address string    `json:"address"`
+	Source  string    `json:"source"`
+	Created time.Time `json:"created"`
+}
+
+// ChannelTokenGate is the holding users need to opt in to, and stay opted in to, the channel
+type ChannelTokenGate struct {
+	Chain      string    `json:"chain"`
+	AppID      string    `json:"app_id"`
+	Kind       string    `json:"kind"`
+	Asset      string    `json:"asset,omitempty"`
+	Issuer     string    `json:"issuer,omitempty"`
+	MinBalance float64   `json:"min_balance"`
+	UpdatedBy  string    `json:"updated_by"`
+	Updated    time.Time `json:"updated"`
+}
+
+// ChannelTokenGateRequest sets the holding to opt in to the channel. Asset is the ASA ID on Algorand, the
+// currency code on XRPL for a token and the optional taxon on XRPL for an NFT, which also needs the issuer.
+type ChannelTokenGateRequest struct {
+	Kind       string  `json:"kind" binding:"required"`
+	Asset      string  `json:"asset"`
+	Issuer     string  `json:"issuer"`
+	MinBalance float64 `json:"min_balance"`
 }
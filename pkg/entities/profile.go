@@ -0,0 +1,52 @@
+package entities
+
+import "notiboy/pkg/consts"
+
+// UserProfile is what a user tells others about itself. Privacy holds the visibility of every field,
+// it is only shown to the user itself.
+type UserProfile struct {
+	DisplayName string            `json:"display_name,omitempty"`
+	Avatar      string            `json:"avatar,omitempty"`
+	Bio         string            `json:"bio,omitempty"`
+	Locale      string            `json:"locale,omitempty"`
+	Timezone    string            `json:"timezone,omitempty"`
+	Privacy     map[string]string `json:"privacy,omitempty"`
+}
+
+// Visibility returns who gets to see field, falling back to consts.DefaultProfilePrivacy
+func (p *UserProfile) Visibility(field string) string {
+	if visibility, ok := p.Privacy[field]; ok {
+		return visibility
+	}
+
+	return consts.DefaultProfilePrivacy[field]
+}
+
+// VisibleTo returns the fields of the profile a user other than its owner gets to see. isContact tells
+// whether the owner has the user in its contacts.
+func (p *UserProfile) VisibleTo(isContact bool) *UserProfile {
+	visible := func(field, value string) string {
+		switch p.Visibility(field) {
+		case consts.ProfilePublic:
+			return value
+		case consts.ProfileContacts:
+			if isContact {
+				return value
+			}
+		}
+		return ""
+	}
+
+	return &UserProfile{
+		DisplayName: visible(consts.ProfileDisplayName, p.DisplayName),
+		Avatar:      visible(consts.ProfileAvatar, p.Avatar),
+		Bio:         visible(consts.ProfileBio, p.Bio),
+		Locale:      visible(consts.ProfileLocale, p.Locale),
+		Timezone:    visible(consts.ProfileTimezone, p.Timezone),
+	}
+}
+
+// IsEmpty tells whether none of the fields of the profile is set
+func (p *UserProfile) IsEmpty() bool {
+	return p.DisplayName == "" && p.Avatar == "" && p.Bio == "" && p.Locale == "" && p.Timezone == ""
+}
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"notiboy/pkg/consts"
+)
+
+func TestUserProfile_VisibleTo(t *testing.T) {
+	profile := &UserProfile{
+		DisplayName: "Alice",
+		Avatar:      "data:image/png;base64,AA==",
+		Bio:         "hello",
+		Locale:      "en",
+		Timezone:    "UTC",
+		Privacy: map[string]string{
+			consts.ProfileBio:      consts.ProfileContacts,
+			consts.ProfileTimezone: consts.ProfilePrivate,
+			consts.ProfileLocale:   consts.ProfilePublic,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		isContact bool
+		want      *UserProfile
+	}{
+		{
+			name: "stranger",
+			want: &UserProfile{
+				DisplayName: profile.DisplayName,
+				Avatar:      profile.Avatar,
+				Locale:      profile.Locale,
+			},
+		},
+		{
+			name:      "contact",
+			isContact: true,
+			want: &UserProfile{
+				DisplayName: profile.DisplayName,
+				Avatar:      profile.Avatar,
+				Bio:         profile.Bio,
+				Locale:      profile.Locale,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := profile.VisibleTo(tt.isContact); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("VisibleTo() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestUserProfile_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile UserProfile
+		want    bool
+	}{
+		{
+			name: "no fields",
+			want: true,
+		},
+		{
+			name:    "only privacy settings",
+			profile: UserProfile{Privacy: map[string]string{consts.ProfileBio: consts.ProfilePrivate}},
+			want:    true,
+		},
+		{
+			name:    "a field",
+			profile: UserProfile{Timezone: "UTC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := tt.profile.IsEmpty(); got != tt.want {
+					t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
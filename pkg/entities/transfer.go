@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// ChannelTransfer is a pending change of the owner of a channel, a transfer offered by its owner or the adoption
+// of an orphaned channel
+type ChannelTransfer struct {
+	Chain   string    `json:"chain"`
+	AppID   string    `json:"app_id"`
+	Kind    string    `json:"kind"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type ChannelTransferRequest struct {
+	Address string `json:"address" binding:"required"`
+}
+
+// AcceptChannelTransferRequest accepts a transfer, the wallet of the new owner signs a login challenge issued for
+// it to confirm.
+type AcceptChannelTransferRequest struct {
+	Nonce     string `json:"nonce" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+type ChannelAdoptionRequest struct {
+	Reason string `json:"reason"`
+}
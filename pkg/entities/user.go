@@ -1,6 +1,11 @@
 package entities
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"notiboy/pkg/consts"
+)
 
 type EmailMetadata struct {
 	EmailID string
@@ -17,6 +22,18 @@ type UserModel struct {
 	Channels         []string               `json:"channels,omitempty"`
 	Optins           []string               `json:"optins,omitempty"`
 	Privileges       map[string]interface{} `json:"privileges,omitempty"`
+	TrackingOptOut   bool                   `json:"tracking_opt_out"`
+	Profile          *UserProfile           `json:"profile,omitempty"`
+}
+
+// VerifyChannelCapacity returns an error unless the membership of the user allows it to own one more channel
+func (u *UserModel) VerifyChannelCapacity() error {
+	permittedChannelCount := consts.ChannelCount[consts.MembershipStringToEnum(u.Membership)]
+	if len(u.Channels)+1 > permittedChannelCount {
+		return fmt.Errorf("%s's membership allows only %d channels", u.Address, permittedChannelCount)
+	}
+
+	return nil
 }
 
 type UserInfo struct {
@@ -26,6 +43,14 @@ type UserInfo struct {
 	Membership       string   `json:"membership,omitempty"`
 	Logo             string   `json:"logo,omitempty"`
 	MediumMetadata   map[string]struct{}
+	TrackingOptOut   *bool `json:"tracking_opt_out,omitempty"`
+	// profile fields are left as they are when nil and cleared when empty
+	DisplayName    *string           `json:"display_name,omitempty"`
+	Avatar         *string           `json:"avatar,omitempty"`
+	Bio            *string           `json:"bio,omitempty"`
+	Locale         *string           `json:"locale,omitempty"`
+	Timezone       *string           `json:"timezone,omitempty"`
+	ProfilePrivacy map[string]string `json:"profile_privacy,omitempty"`
 }
 type OnboardingRequest struct {
 	UserIdentifier
@@ -45,14 +70,50 @@ type UserIdentifier struct {
 }
 
 type PATTokens struct {
-	Name        string    `json:"name"`
-	UUID        string    `json:"uuid"`
-	Created     time.Time `json:"created"`
-	Kind        string    `json:"kind"`
-	Description string    `json:"description"`
+	Name        string     `json:"name"`
+	UUID        string     `json:"uuid"`
+	Created     time.Time  `json:"created"`
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	Scopes      []string   `json:"scopes,omitempty"`
+	ExpiryDays  int        `json:"expiry_days,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsed    *time.Time `json:"last_used,omitempty"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
 }
 
 type FCM struct {
 	UserIdentifier
 	DeviceID string `json:"device_id"`
 }
+
+// LoginChallenge is a one-time message a wallet signs to sign in
+type LoginChallenge struct {
+	Chain     string    `json:"chain"`
+	Address   string    `json:"address"`
+	Nonce     string    `json:"nonce"`
+	Message   string    `json:"message"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserSession is a login of a user, kept alive by its rotating refresh token
+type UserSession struct {
+	SessionID string    `json:"session_id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Created   time.Time `json:"created"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
+
+// SessionTokens is the short-lived access token and the refresh token that renews it
+type SessionTokens struct {
+	Token        string `json:"token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	SessionID    string `json:"session_id"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
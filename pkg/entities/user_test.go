@@ -0,0 +1,48 @@
+package entities
+
+import "testing"
+
+func TestUserModel_VerifyChannelCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		membership string
+		channels   []string
+		wantErr    bool
+	}{
+		{
+			name: "free without channels",
+		},
+		{
+			name:     "free with a channel",
+			channels: []string{"1"},
+			wantErr:  true,
+		},
+		{
+			name:       "silver below its limit",
+			membership: "silver",
+			channels:   []string{"1", "2"},
+		},
+		{
+			name:       "silver at its limit",
+			membership: "silver",
+			channels:   []string{"1", "2", "3"},
+			wantErr:    true,
+		},
+		{
+			name:       "gold",
+			membership: "Gold",
+			channels:   []string{"1", "2", "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				userModel := &UserModel{Membership: tt.membership, Channels: tt.channels}
+				if err := userModel.VerifyChannelCapacity(); (err != nil) != tt.wantErr {
+					t.Errorf("VerifyChannelCapacity() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,63 @@
+package entities
+
+import "time"
+
+type Webhook struct {
+	ID          string    `json:"id"`
+	Chain       string    `json:"chain"`
+	Channel     string    `json:"app_id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedTime time.Time `json:"created_time"`
+}
+
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required"`
+	Events []string `json:"events" validate:"required"`
+}
+
+// WebhookPayload is the body posted to a webhook URL
+type WebhookPayload struct {
+	ID          string    `json:"id"`
+	Event       string    `json:"event"`
+	Chain       string    `json:"chain"`
+	Channel     string    `json:"app_id"`
+	CreatedTime time.Time `json:"created_time"`
+	Data        any       `json:"data"`
+}
+
+type WebhookDelivery struct {
+	ID          string    `json:"id"`
+	WebhookID   string    `json:"webhook_id"`
+	Chain       string    `json:"chain"`
+	Channel     string    `json:"app_id"`
+	Event       string    `json:"event"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	StatusCode  int       `json:"status_code"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	CreatedTime time.Time `json:"created_time"`
+	UpdatedTime time.Time `json:"updated_time"`
+}
+
+// WebhookOutboxItem is a webhook delivery waiting for its next attempt
+type WebhookOutboxItem struct {
+	Shard       int
+	Delivery    WebhookDelivery
+	NextAttempt time.Time
+	LeaseUntil  time.Time
+}
+
+type OptinWebhookData struct {
+	Address string `json:"address"`
+	Source  string `json:"source,omitempty"`
+}
+
+type NotificationWebhookData struct {
+	UUID     string `json:"uuid"`
+	Receiver string `json:"receiver"`
+	Medium   string `json:"medium"`
+	Error    string `json:"error,omitempty"`
+}
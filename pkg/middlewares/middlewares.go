@@ -34,6 +34,8 @@ func NewMiddlewares(useCases usecases.UseCaseImply) *Middlewares {
 	}
 }
 
+// ValidateUserAddress authenticates a user by a signed transaction of its wallet. It is deprecated since the signed
+// transaction can be replayed, ValidateSignedChallenge replaces it.
 func (m *Middlewares) ValidateUserAddress(ctx *gin.Context) {
 	log := utilities.NewLogger("ValidateUserAddress")
 
@@ -103,7 +105,97 @@ func (m *Middlewares) ValidateUserAddress(ctx *gin.Context) {
 	ctx.Next()
 }
 
+// Deprecated flags the responses of deprecated endpoints so that clients can tell they should move off them
+func Deprecated(ctx *gin.Context) {
+	ctx.Header("Deprecation", "true")
+	ctx.Next()
+}
+
+// ValidateSignedChallenge authenticates a user by a wallet signature over a sign-in challenge
+// issued by the challenge endpoint. The challenge nonce is burnt on use so that a signature can't be replayed.
+func (m *Middlewares) ValidateSignedChallenge(ctx *gin.Context) {
+	log := utilities.NewLogger("ValidateSignedChallenge")
+
+	chain := ctx.Param("chain")
+	addrParam := ctx.Param("address")
+
+	if !chainDriver.IsChainSupported(chain) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Unsupported chain",
+			},
+		)
+		return
+	}
+
+	address := ctx.GetHeader("X-USER-ADDRESS")
+	if len(address) == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Missing X-USER-ADDRESS in API header",
+			},
+		)
+		return
+	}
+
+	if addrParam != address {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Address parameter doesn't match with X-USER-ADDRESS in API header",
+			},
+		)
+		return
+	}
+
+	nonce := ctx.GetHeader("X-NONCE")
+	signature := ctx.GetHeader("X-SIGNATURE")
+	if len(nonce) == 0 || len(signature) == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest, entities.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Missing X-NONCE or X-SIGNATURE in API header",
+			},
+		)
+		return
+	}
+
+	err := m.useCases.VerifyLoginChallenge(
+		ctx, strings.ToLower(chain), address, nonce, signature, ctx.GetHeader("X-PUBLIC-KEY"),
+	)
+	if err != nil {
+		log.WithError(err).Errorf("challenge verification failed for user %s", address)
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized, entities.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "Bad Signature",
+			},
+		)
+		return
+	}
+
+	log.Debugf("Challenge validation done- user %s validated", address)
+
+	ctx.Set(consts.UserAddress, address)
+	ctx.Set(consts.UserChain, chain)
+
+	ctx.Next()
+}
+
 func (m *Middlewares) ValidateToken(ctx *gin.Context) {
+	m.validateToken(ctx, true)
+}
+
+// ValidateTokenSignature authenticates the user by the signature of its token alone, without requiring the
+// session of the token to be active. It is meant for the routes the user still needs once its sessions are
+// gone, like following the deletion of its account.
+func (m *Middlewares) ValidateTokenSignature(ctx *gin.Context) {
+	m.validateToken(ctx, false)
+}
+
+func (m *Middlewares) validateToken(ctx *gin.Context, requireActive bool) {
 	log := utilities.NewLogger("ValidateUserAddress")
 
 	tokenValue := ctx.GetHeader("Authorization")
@@ -171,8 +263,8 @@ func (m *Middlewares) ValidateToken(ctx *gin.Context) {
 		return
 	}
 
-	// if token was removed due to a sign-out
-	if present, _ := db.IsTokenPresent(ctx, address, claims["chain"], token, claims["kind"], claims["uuid"]); !present {
+	// if token was removed due to a sign-out or its session was revoked
+	if requireActive && !isTokenActive(ctx, address, token, claims) {
 		ctx.AbortWithStatusJSON(
 			http.StatusUnauthorized, entities.ErrorResponse{
 				StatusCode: http.StatusUnauthorized,
@@ -187,10 +279,29 @@ func (m *Middlewares) ValidateToken(ctx *gin.Context) {
 	ctx.Set(consts.UserChain, claims["chain"])
 	ctx.Set(consts.UserAddress, claims["address"])
 	ctx.Set(consts.UserToken, token)
+	ctx.Set(consts.UserSession, claims["session"])
+	ctx.Set(consts.UserScopes, strings.Fields(claims["scopes"]))
 
 	ctx.Next()
 }
 
+// isTokenActive checks that a verified token wasn't revoked. Access tokens issued by login are bound to
+// a session, PATs and tokens issued before sessions existed are looked up as stored.
+func isTokenActive(ctx *gin.Context, address, token string, claims map[string]string) bool {
+	if claims["session"] != "" {
+		active, _ := db.IsSessionActive(ctx, claims["chain"], address, claims["session"])
+		return active
+	}
+
+	if claims["uuid"] != "" {
+		active, _ := db.IsPATActive(ctx, claims["chain"], address, claims["kind"], claims["uuid"], ctx.ClientIP())
+		return active
+	}
+
+	present, _ := db.IsTokenPresent(ctx, address, claims["chain"], token)
+	return present
+}
+
 func (m *Middlewares) IsAdminUser(ctx *gin.Context) {
 	log := utilities.NewLogger("IsAdminUser")
 
@@ -211,13 +322,15 @@ func (m *Middlewares) IsAdminUser(ctx *gin.Context) {
 
 	ctx.Next()
 }
-func (m *Middlewares) VerifyWebsocketRequest(ctx *gin.Context, chain, address, token string) error {
+
+// VerifyWebsocketRequest authenticates a websocket connection by the token in its query, the token must grant scope
+func (m *Middlewares) VerifyWebsocketRequest(ctx *gin.Context, chain, address, token, scope string) error {
 	claims, err := jwt.VerifyJWT(address, token)
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
-	if present, _ := db.IsTokenPresent(ctx, address, claims["chain"], token, claims["kind"], claims["uuid"]); !present {
+	if !isTokenActive(ctx, address, token, claims) {
 		return fmt.Errorf("authentication failed: token not found")
 	}
 
@@ -228,6 +341,12 @@ func (m *Middlewares) VerifyWebsocketRequest(ctx *gin.Context, chain, address, t
 	ctx.Set(consts.UserChain, claims["chain"])
 	ctx.Set(consts.UserAddress, claims["address"])
 	ctx.Set(consts.UserToken, token)
+	ctx.Set(consts.UserSession, claims["session"])
+	ctx.Set(consts.UserScopes, strings.Fields(claims["scopes"]))
+
+	if !HasScope(ctx, scope) {
+		return fmt.Errorf("token is missing scope %s", scope)
+	}
 
 	return nil
 }
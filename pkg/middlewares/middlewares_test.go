@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/utilities/jwt"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/legacy", Deprecated, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/legacy", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation header = %q, want true", got)
+	}
+}
+
+func TestValidateTokenSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.SetConfig(
+		&config.NotiboyConfModel{
+			Mode: "local",
+			DB:   config.DB{Keyspace: "notiboy"},
+		},
+	)
+
+	// the session of the token doesn't exist, the signature is all that is checked
+	token, _, err := jwt.GenerateSessionJWT("alice", "algorand", "deleted-session", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateSessionJWT() error = %v", err)
+	}
+
+	m := new(Middlewares)
+	router := gin.New()
+	router.GET(
+		"/account-deletions/:id", m.ValidateTokenSignature, func(ctx *gin.Context) {
+			if got := ctx.GetString(consts.UserAddress); got != "alice" {
+				t.Errorf("user address = %q, want alice", got)
+			}
+			ctx.Status(http.StatusOK)
+		},
+	)
+
+	tests := []struct {
+		name    string
+		token   string
+		address string
+		want    int
+	}{
+		{
+			name:    "token of the user",
+			token:   token,
+			address: "alice",
+			want:    http.StatusOK,
+		},
+		{
+			name:    "token of another user",
+			token:   token,
+			address: "bob",
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "forged token",
+			token:   token + "x",
+			address: "alice",
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "missing token",
+			address: "alice",
+			want:    http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/account-deletions/1", nil)
+				if tt.token != "" {
+					req.Header.Set("Authorization", "Bearer "+tt.token)
+				}
+				req.Header.Set("X-USER-ADDRESS", tt.address)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				if w.Code != tt.want {
+					t.Errorf("status = %d, want %d", w.Code, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/spf13/cast"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope allows the request only if the token grants scope. A scope narrowed down to a
+// channel, <scope>:<app_id>, is granted only on routes of that channel.
+// Login sessions and PATs issued without scopes are granted every scope.
+func (m *Middlewares) RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		log := utilities.NewLogger("RequireScope")
+
+		if !HasScope(ctx, scope) {
+			log.Errorf("token of %s is missing scope %s", ctx.GetString(consts.UserAddress), scope)
+			ctx.AbortWithStatusJSON(
+				http.StatusForbidden, entities.ErrorResponse{
+					StatusCode: http.StatusForbidden,
+					Message:    "Token is missing scope " + scope,
+				},
+			)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireUnscoped allows the request only for tokens with full access, it guards routes
+// like token management through which a scoped token could widen its own access.
+func (m *Middlewares) RequireUnscoped(ctx *gin.Context) {
+	if len(cast.ToStringSlice(ctx.Value(consts.UserScopes))) != 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusForbidden, entities.ErrorResponse{
+				StatusCode: http.StatusForbidden,
+				Message:    "Not allowed for scoped tokens",
+			},
+		)
+		return
+	}
+
+	ctx.Next()
+}
+
+// HasScope reports whether the token of the request grants scope
+func HasScope(ctx *gin.Context, scope string) bool {
+	granted := cast.ToStringSlice(ctx.Value(consts.UserScopes))
+	if len(granted) == 0 {
+		return true
+	}
+
+	appID := ctx.Param("app_id")
+	for _, s := range granted {
+		if s == scope || (appID != "" && s == scope+":"+appID) {
+			return true
+		}
+	}
+
+	return false
+}
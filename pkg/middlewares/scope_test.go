@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/pkg/consts"
+)
+
+// scopedRouter serves GET /channels/:app_id with the handlers, as a token granting scopes
+func scopedRouter(scopes []string, handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(
+		func(ctx *gin.Context) {
+			ctx.Set(consts.UserScopes, scopes)
+		},
+	)
+	router.GET(
+		"/channels/:app_id", append(
+			handlers, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			},
+		)...,
+	)
+
+	return router
+}
+
+func serve(router *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return w.Code
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		path   string
+		want   bool
+	}{
+		{
+			name: "unscoped token",
+			path: "/channels/1",
+			want: true,
+		},
+		{
+			name:   "granted scope",
+			scopes: []string{consts.ScopeChannelsRead, consts.ScopeNotificationsSend},
+			path:   "/channels/1",
+			want:   true,
+		},
+		{
+			name:   "scope of the channel",
+			scopes: []string{consts.ScopeNotificationsSend + ":1"},
+			path:   "/channels/1",
+			want:   true,
+		},
+		{
+			name:   "scope of another channel",
+			scopes: []string{consts.ScopeNotificationsSend + ":2"},
+			path:   "/channels/1",
+		},
+		{
+			name:   "missing scope",
+			scopes: []string{consts.ScopeBillingRead},
+			path:   "/channels/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var got bool
+				router := scopedRouter(
+					tt.scopes, func(ctx *gin.Context) {
+						got = HasScope(ctx, consts.ScopeNotificationsSend)
+					},
+				)
+				serve(router, tt.path)
+
+				if got != tt.want {
+					t.Errorf("HasScope() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	m := &Middlewares{}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   int
+	}{
+		{
+			name: "unscoped token",
+			want: http.StatusOK,
+		},
+		{
+			name:   "granted scope",
+			scopes: []string{consts.ScopeChannelsRead},
+			want:   http.StatusOK,
+		},
+		{
+			name:   "missing scope",
+			scopes: []string{consts.ScopeChannelsWrite},
+			want:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				router := scopedRouter(tt.scopes, m.RequireScope(consts.ScopeChannelsRead))
+
+				if got := serve(router, "/channels/1"); got != tt.want {
+					t.Errorf("status = %d, want %d", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestRequireUnscoped(t *testing.T) {
+	m := &Middlewares{}
+
+	if got := serve(scopedRouter(nil, m.RequireUnscoped), "/channels/1"); got != http.StatusOK {
+		t.Errorf("unscoped token: status = %d, want %d", got, http.StatusOK)
+	}
+
+	scopes := []string{consts.ScopeChannelsRead}
+	if got := serve(scopedRouter(scopes, m.RequireUnscoped), "/channels/1"); got != http.StatusForbidden {
+		t.Errorf("scoped token: status = %d, want %d", got, http.StatusForbidden)
+	}
+}
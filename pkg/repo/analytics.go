@@ -0,0 +1,406 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+type AnalyticsRepo struct {
+	db   *gocql.Session
+	conf *config.NotiboyConfModel
+}
+
+// AnalyticsRepoImply is an interface that defines the contract for retrieving raw channel analytics data.
+type AnalyticsRepoImply interface {
+	ListChannelSubscribers(ctx context.Context, chain, appID string) ([]entities.ChannelSubscriber, error)
+	ListSentNotifications(ctx context.Context, chain, appID string, since time.Time) ([]entities.SentNotification, error)
+	ListNotificationReachEvents(
+		ctx context.Context, chain, appID string, uuids []string,
+	) (map[string][]entities.NotificationReachEvent, error)
+	ExportTraction(ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter) error
+	ExportSentRead(ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter) error
+	ExportNotifications(ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter) error
+	ExportSubscribers(ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter) error
+}
+
+func NewAnalyticsRepo(db *gocql.Session, conf *config.NotiboyConfModel) AnalyticsRepoImply {
+	return &AnalyticsRepo{db: db, conf: conf}
+}
+
+// ListChannelSubscribers retrieves the opt-in and opt-out times of all users who ever opted in to the channel.
+func (repo *AnalyticsRepo) ListChannelSubscribers(
+	ctx context.Context, chain, appID string,
+) ([]entities.ChannelSubscriber, error) {
+	log := utilities.NewLogger("ListChannelSubscribers")
+
+	var subscribers = make([]entities.ChannelSubscriber, 0)
+
+	query := fmt.Sprintf(
+		`SELECT address, optin_time, optout_time FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelSubscribers,
+	)
+
+	subscriber := entities.ChannelSubscriber{
+		Chain: chain,
+		AppID: appID,
+	}
+	iter := repo.db.Query(query, chain, appID).WithContext(ctx).Iter()
+	for iter.Scan(&subscriber.Address, &subscriber.OptinTime, &subscriber.OptoutTime) {
+		subscribers = append(subscribers, subscriber)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve channel subscribers")
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+// ListSentNotifications retrieves the notifications sent from the channel since the given time, newest first.
+func (repo *AnalyticsRepo) ListSentNotifications(
+	ctx context.Context, chain, appID string, since time.Time,
+) ([]entities.SentNotification, error) {
+	log := utilities.NewLogger("ListSentNotifications")
+
+	var notifications = make([]entities.SentNotification, 0)
+
+	err := repo.eachSentNotification(
+		ctx, chain, appID, ` AND created_time >= ?`, []any{since},
+		func(notification entities.SentNotification) error {
+			notifications = append(notifications, notification)
+			return nil
+		},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to retrieve sent notifications")
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// eachSentNotification calls fn with the notifications sent from the channel within the bounds on created_time
+// appended to the lookup of sent_notifications_by_time, newest first. The notifications are looked up
+// consts.ReceiverBatchSize at a time.
+func (repo *AnalyticsRepo) eachSentNotification(
+	ctx context.Context, chain, appID, bounds string, boundArgs []any, fn func(entities.SentNotification) error,
+) error {
+	query := fmt.Sprintf(
+		`SELECT uuid FROM %s.%s WHERE chain = ? AND app_id = ?%s`,
+		repo.conf.DB.Keyspace, consts.SentNotificationsByTime, bounds,
+	)
+	infoQuery := fmt.Sprintf(
+		`SELECT uuid, sender, receivers, message, link, type, created_time, recalled FROM %s.%s
+	WHERE chain = ? AND app_id = ? AND uuid IN ?`,
+		repo.conf.DB.Keyspace, consts.SentNotificationInfo,
+	)
+
+	// the lookup by uuid comes in uuid order, the notifications are passed on in the order they were sent
+	flush := func(uuids []string) error {
+		found := make(map[string]entities.SentNotification, len(uuids))
+
+		notification := entities.SentNotification{Chain: chain, Channel: appID}
+		iter := repo.db.Query(infoQuery, chain, appID, uuids).WithContext(ctx).Iter()
+		for iter.Scan(
+			&notification.UUID, &notification.Sender, &notification.Receivers, &notification.Message,
+			&notification.Link, &notification.Type, &notification.CreatedTime, &notification.Recalled,
+		) {
+			found[notification.UUID] = notification
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+
+		for _, uuid := range uuids {
+			// expired since it was listed
+			if notification, ok := found[uuid]; ok {
+				if err := fn(notification); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	var (
+		uuid  string
+		uuids = make([]string, 0, consts.ReceiverBatchSize)
+	)
+	iter := repo.db.Query(query, append([]any{chain, appID}, boundArgs...)...).WithContext(ctx).Iter()
+	for iter.Scan(&uuid) {
+		uuids = append(uuids, uuid)
+		if len(uuids) < consts.ReceiverBatchSize {
+			continue
+		}
+
+		if err := flush(uuids); err != nil {
+			iter.Close()
+			return err
+		}
+		uuids = uuids[:0]
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if len(uuids) == 0 {
+		return nil
+	}
+
+	return flush(uuids)
+}
+
+// ListNotificationReachEvents retrieves every reach event recorded for the notifications, by notification uuid.
+// The notifications are looked up consts.ReceiverBatchSize at a time.
+func (repo *AnalyticsRepo) ListNotificationReachEvents(
+	ctx context.Context, chain, appID string, uuids []string,
+) (map[string][]entities.NotificationReachEvent, error) {
+	log := utilities.NewLogger("ListNotificationReachEvents")
+
+	var events = make(map[string][]entities.NotificationReachEvent, len(uuids))
+
+	query := fmt.Sprintf(
+		`SELECT uuid, medium, event, receiver, event_time FROM %s.%s WHERE chain = ? AND app_id = ? AND uuid IN ?`,
+		repo.conf.DB.Keyspace, consts.NotificationReach,
+	)
+
+	event := entities.NotificationReachEvent{
+		Chain:   chain,
+		Channel: appID,
+	}
+	for _, chunk := range utilities.Chunk(uuids, consts.ReceiverBatchSize) {
+		iter := repo.db.Query(query, chain, appID, chunk).WithContext(ctx).Iter()
+		for iter.Scan(&event.UUID, &event.Medium, &event.Event, &event.Receiver, &event.EventTime) {
+			events[event.UUID] = append(events[event.UUID], event)
+		}
+
+		if err := iter.Close(); err != nil {
+			log.WithError(err).Error("failed to retrieve notification reach events")
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// eventDateRange appends the event_date bounds to a metrics query. Dates are in the yyyy-mm-dd format and inclusive,
+// an empty date leaves that side of the range open.
+func eventDateRange(query string, args []any, startDate, endDate string) (string, []any) {
+	if startDate != "" {
+		query += " AND event_date >= ?"
+		args = append(args, startDate)
+	}
+	if endDate != "" {
+		query += " AND event_date <= ?"
+		args = append(args, endDate)
+	}
+
+	return query, args
+}
+
+// timeRange appends the bounds of the inclusive yyyy-mm-dd date range, in UTC, on a timestamp column to a query.
+// An empty date leaves that side of the range open.
+func timeRange(query string, args []any, column, startDate, endDate string) (string, []any, error) {
+	if startDate != "" {
+		start, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid date %s: %w", startDate, err)
+		}
+		query += fmt.Sprintf(" AND %s >= ?", column)
+		args = append(args, start)
+	}
+	if endDate != "" {
+		end, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid date %s: %w", endDate, err)
+		}
+		query += fmt.Sprintf(" AND %s < ?", column)
+		args = append(args, end.AddDate(0, 0, 1))
+	}
+
+	return query, args, nil
+}
+
+// ExportTraction streams the opt-in and opt-out events of a channel.
+func (repo *AnalyticsRepo) ExportTraction(
+	ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter,
+) error {
+	log := utilities.NewLogger("ExportTraction")
+
+	query, args := eventDateRange(
+		fmt.Sprintf(
+			`SELECT event_date, event_time, optin, optout FROM %s.%s WHERE chain = ? AND channel = ?`,
+			repo.conf.DB.Keyspace, consts.ChannelTractionMetrics,
+		), []any{chain, appID}, startDate, endDate,
+	)
+
+	var (
+		date      string
+		eventTime time.Time
+		optin     int
+		optout    int
+	)
+
+	iter := repo.db.Query(query, args...).WithContext(ctx).Iter()
+	for iter.Scan(&date, &eventTime, &optin, &optout) {
+		if err := write([]any{date, eventTime, optin, optout}); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve channel traction metrics")
+		return err
+	}
+
+	return nil
+}
+
+// ExportSentRead streams the sent, read, clicked and recalled counters of a channel.
+func (repo *AnalyticsRepo) ExportSentRead(
+	ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter,
+) error {
+	log := utilities.NewLogger("ExportSentRead")
+
+	query, args := eventDateRange(
+		fmt.Sprintf(
+			`SELECT event_date, event_time, medium, sent, read, clicked, recalled FROM %s.%s WHERE chain = ? AND channel = ?`,
+			repo.conf.DB.Keyspace, consts.ChannelSentReadMetrics,
+		), []any{chain, appID}, startDate, endDate,
+	)
+
+	var (
+		date      string
+		eventTime time.Time
+		medium    string
+		sent      int
+		read      int
+		clicked   int
+		recalled  int
+	)
+
+	iter := repo.db.Query(query, args...).WithContext(ctx).Iter()
+	for iter.Scan(&date, &eventTime, &medium, &sent, &read, &clicked, &recalled) {
+		if err := write([]any{date, eventTime, medium, sent, read, clicked, recalled}); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve channel sent read metrics")
+		return err
+	}
+
+	return nil
+}
+
+// ExportNotifications streams the notifications sent from a channel that are still retained, newest first.
+func (repo *AnalyticsRepo) ExportNotifications(
+	ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter,
+) error {
+	log := utilities.NewLogger("ExportNotifications")
+
+	bounds, boundArgs, err := timeRange("", nil, "created_time", startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	err = repo.eachSentNotification(
+		ctx, chain, appID, bounds, boundArgs, func(notification entities.SentNotification) error {
+			return write(
+				[]any{
+					notification.UUID, notification.CreatedTime, notification.Sender, notification.Type,
+					notification.Message, notification.Link, len(notification.Receivers), notification.Recalled,
+				},
+			)
+		},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to retrieve sent notifications")
+		return err
+	}
+
+	return nil
+}
+
+// ExportSubscribers streams the users currently opted in to a channel along with the time they opted in.
+// Users who opted in before opt-in times were recorded have an empty opt-in time and are left out of date filtered
+// exports, which are looked up by the time users opted in.
+func (repo *AnalyticsRepo) ExportSubscribers(
+	ctx context.Context, chain, appID, startDate, endDate string, write entities.ExportRecordWriter,
+) error {
+	log := utilities.NewLogger("ExportSubscribers")
+
+	if startDate != "" || endDate != "" {
+		query, args, err := timeRange(
+			fmt.Sprintf(
+				`SELECT address, optin_time FROM %s.%s WHERE chain = ? AND app_id = ?`,
+				repo.conf.DB.Keyspace, consts.SubscribersByOptin,
+			), []any{chain, appID}, "optin_time", startDate, endDate,
+		)
+		if err != nil {
+			return err
+		}
+
+		var (
+			user      string
+			optinTime time.Time
+		)
+		iter := repo.db.Query(query, args...).WithContext(ctx).Iter()
+		for iter.Scan(&user, &optinTime) {
+			if err = write([]any{user, optinTime}); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		if err = iter.Close(); err != nil {
+			log.WithError(err).Error("failed to retrieve channel subscribers")
+			return err
+		}
+
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		`SELECT users FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelUsers,
+	)
+
+	var users []string
+	err := repo.db.Query(query, chain, appID).WithContext(ctx).Scan(&users)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		log.WithError(err).Error("failed to retrieve channel users")
+		return err
+	}
+
+	subscribers, err := repo.ListChannelSubscribers(ctx, chain, appID)
+	if err != nil {
+		return err
+	}
+
+	optinTimes := make(map[string]time.Time, len(subscribers))
+	for _, subscriber := range subscribers {
+		optinTimes[subscriber.Address] = subscriber.OptinTime
+	}
+
+	for _, user := range users {
+		if err = write([]any{user, optinTimes[user]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_eventDateRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		startDate string
+		endDate   string
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "open",
+			wantQuery: "SELECT",
+			wantArgs:  []any{"algorand"},
+		},
+		{
+			name:      "from",
+			startDate: "2024-01-01",
+			wantQuery: "SELECT AND event_date >= ?",
+			wantArgs:  []any{"algorand", "2024-01-01"},
+		},
+		{
+			name:      "between",
+			startDate: "2024-01-01",
+			endDate:   "2024-01-31",
+			wantQuery: "SELECT AND event_date >= ? AND event_date <= ?",
+			wantArgs:  []any{"algorand", "2024-01-01", "2024-01-31"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				query, args := eventDateRange("SELECT", []any{"algorand"}, tt.startDate, tt.endDate)
+				if query != tt.wantQuery {
+					t.Errorf("eventDateRange() query = %q, want %q", query, tt.wantQuery)
+				}
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("eventDateRange() args = %v, want %v", args, tt.wantArgs)
+				}
+			},
+		)
+	}
+}
+
+func Test_timeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		startDate string
+		endDate   string
+		wantQuery string
+		wantArgs  []any
+		wantErr   bool
+	}{
+		{
+			name:      "open",
+			wantQuery: "SELECT",
+			wantArgs:  []any{"algorand"},
+		},
+		{
+			name:      "from",
+			startDate: "2024-01-01",
+			wantQuery: "SELECT AND created_time >= ?",
+			wantArgs:  []any{"algorand", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "through the end date",
+			startDate: "2024-01-01",
+			endDate:   "2024-01-31",
+			wantQuery: "SELECT AND created_time >= ? AND created_time < ?",
+			wantArgs: []any{
+				"algorand", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "invalid date",
+			endDate: "31-01-2024",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				query, args, err := timeRange("SELECT", []any{"algorand"}, "created_time", tt.startDate, tt.endDate)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("timeRange() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if query != tt.wantQuery {
+					t.Errorf("timeRange() query = %q, want %q", query, tt.wantQuery)
+				}
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("timeRange() args = %v, want %v", args, tt.wantArgs)
+				}
+			},
+		)
+	}
+}
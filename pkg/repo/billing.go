@@ -32,6 +32,7 @@ type BillingRepoImply interface {
 	ChangeMembership(context.Context, string, entities.BillingRequest, bool) error
 	GetBillingDetails(context.Context, string, entities.BillingRequest) (*entities.BillingInfo, error)
 	GetMembershipTiers(context.Context) (map[string]map[string]interface{}, error)
+	SyncMembership(context.Context, entities.UserIdentifier, string) error
 }
 
 func NewBillingRepo(db *gocql.Session, conf *config.NotiboyConfModel) BillingRepoImply {
@@ -51,10 +52,8 @@ func membershipCheckerStub() {
 		membership string
 	)
 
-	updateQuery := fmt.Sprintf(
-		"UPDATE %s.%s SET membership = ? WHERE address = ? AND chain = ?",
-		conf.DB.Keyspace, consts.UserInfo,
-	)
+	billing := &BillingRepo{db: dbClient, conf: conf}
+
 	query := fmt.Sprintf(
 		`SELECT expiry, membership, chain, address FROM %s.%s`,
 		conf.DB.Keyspace, consts.BillingTable,
@@ -70,10 +69,22 @@ func membershipCheckerStub() {
 			continue
 		}
 
-		if err := dbClient.Query(updateQuery, consts.FreeTier.String(), address, chain).Exec(); err != nil {
-			log.WithError(err).Errorf("failed to update membership in user info for %s:%s", address, chain)
-		} else {
-			log.Infof("Updated membership in user info for %s:%s", address, chain)
+		// the accounts sharing the balance lose the membership along with it, and fall back to what they pay for
+		accounts := []entities.UserIdentifier{{Chain: chain, Address: address}}
+		link, err := db.GetSharedBilling(context.Background(), chain, address)
+		if err != nil {
+			log.WithError(err).Errorf("failed to get shared billing of %s:%s", address, chain)
+		} else if link != nil && *link.BillingAccount == accounts[0] {
+			accounts = append(accounts, link.UserIdentifier)
+		}
+
+		for _, account := range accounts {
+			err = billing.SyncMembership(context.Background(), account, consts.FreeTier.String())
+			if err != nil {
+				log.WithError(err).Errorf("failed to sync membership of %s:%s", account.Address, account.Chain)
+			} else {
+				log.Infof("Synced membership of %s:%s", account.Address, account.Chain)
+			}
 		}
 	}
 
@@ -131,11 +142,12 @@ func getBalanceDaysAndMoney(
 }
 
 func (b *BillingRepo) ChangeMembership(
-	_ context.Context, curMembership string, req entities.BillingRequest, force bool,
+	ctx context.Context, curMembership string, req entities.BillingRequest, force bool,
 ) error {
 	chain := req.Chain
 	address := req.Address
 	leaseInDays := req.Days
+	account := req.BalanceAccount()
 
 	log := utilities.NewLoggerWithFields(
 		"repo.ChangeMembership", map[string]interface{}{
@@ -162,7 +174,9 @@ func (b *BillingRepo) ChangeMembership(
 		b.conf.DB.Keyspace, consts.BillingTable,
 	)
 
-	if err := b.db.Query(query, chain, address).Scan(&prevUpdatedTime, &curBalance, &curMembershipCost); err != nil {
+	if err := b.db.Query(query, account.Chain, account.Address).Scan(
+		&prevUpdatedTime, &curBalance, &curMembershipCost,
+	); err != nil {
 		if !errors.Is(err, gocql.ErrNotFound) {
 			log.WithError(err).Error("Getting fund details failed")
 			return fmt.Errorf("failed to get fund details: %w", err)
@@ -197,7 +211,7 @@ func (b *BillingRepo) ChangeMembership(
 	expiry := utilities.TimeNow().AddDate(0, 0, int(numDays))
 
 	if err := b.enforceMembershipChangeRestrictions(curMembershipTier, newMembershipTier, chain, address); err != nil {
-		return fmt.Errorf("failed to enfore membership change restrictions")
+		return fmt.Errorf("failed to enforce membership change restrictions: %w", err)
 	}
 
 	query = fmt.Sprintf(
@@ -206,8 +220,8 @@ func (b *BillingRepo) ChangeMembership(
 	)
 
 	if err := b.db.Query(
-		query, chain, address, expiry, newMembershipTier.String(), utilities.TimeNow(), availableBalance,
-		newMembershipCost,
+		query, account.Chain, account.Address, expiry, newMembershipTier.String(), utilities.TimeNow(),
+		availableBalance, newMembershipCost,
 	).Exec(); err != nil {
 		log.WithError(err).Error("billing table update failed")
 		return fmt.Errorf("failed to update billing table: %w", err)
@@ -222,9 +236,100 @@ func (b *BillingRepo) ChangeMembership(
 		return err
 	}
 
+	for _, member := range req.SharedWith {
+		if err := b.SyncMembership(ctx, member, newMembershipTier.String()); err != nil {
+			log.WithError(err).Errorf("failed to update membership of %s:%s", member.Chain, member.Address)
+			return err
+		}
+	}
+
 	return nil
 }
 
+// SyncMembership sets the membership of an account whose tier follows another balance, e.g. a linked
+// account sharing billing, activating or deactivating its channels to fit the channel limit of the tier.
+// The account keeps the tier paid by its own balance while that one is higher.
+func (b *BillingRepo) SyncMembership(_ context.Context, user entities.UserIdentifier, membership string) error {
+	log := utilities.NewLoggerWithFields(
+		"repo.SyncMembership", map[string]interface{}{
+			"chain":   user.Chain,
+			"address": user.Address,
+		},
+	)
+
+	var curMembership string
+
+	query := fmt.Sprintf(
+		"SELECT membership FROM %s.%s WHERE address = ? AND chain = ?",
+		b.conf.DB.Keyspace, consts.UserInfo,
+	)
+	if err := b.db.Query(query, user.Address, user.Chain).Scan(&curMembership); err != nil {
+		log.WithError(err).Error("failed to get membership from user info")
+		return fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	paidMembership, err := b.paidMembership(user)
+	if err != nil {
+		log.WithError(err).Error("failed to get paid membership")
+		return err
+	}
+
+	curMembershipTier := consts.MembershipStringToEnum(curMembership)
+	newMembershipTier := higherTier(consts.MembershipStringToEnum(membership), paidMembership)
+	if curMembershipTier == newMembershipTier {
+		return nil
+	}
+
+	if err := b.enforceMembershipChangeRestrictions(
+		curMembershipTier, newMembershipTier, user.Chain, user.Address,
+	); err != nil {
+		return fmt.Errorf("failed to enforce membership change restrictions: %w", err)
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s.%s SET membership = ? WHERE address = ? AND chain = ?",
+		b.conf.DB.Keyspace, consts.UserInfo,
+	)
+	if err := b.db.Query(query, newMembershipTier.String(), user.Address, user.Chain).Exec(); err != nil {
+		log.WithError(err).Error("failed to update membership in user info")
+		return err
+	}
+
+	return nil
+}
+
+// paidMembership is the tier paid by the own balance of user, free once it expired
+func (b *BillingRepo) paidMembership(user entities.UserIdentifier) (consts.MembershipTier, error) {
+	var (
+		expiry     time.Time
+		membership string
+	)
+
+	query := fmt.Sprintf(
+		`SELECT expiry, membership FROM %s.%s WHERE chain = ? AND address = ?`,
+		b.conf.DB.Keyspace, consts.BillingTable,
+	)
+	if err := b.db.Query(query, user.Chain, user.Address).Scan(&expiry, &membership); err != nil {
+		if !errors.Is(err, gocql.ErrNotFound) {
+			return consts.FreeTier, fmt.Errorf("failed to get fund details: %w", err)
+		}
+	}
+
+	if utilities.TimeNow().After(expiry) {
+		return consts.FreeTier, nil
+	}
+
+	return consts.MembershipStringToEnum(membership), nil
+}
+
+func higherTier(a, b consts.MembershipTier) consts.MembershipTier {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
 func (b *BillingRepo) enforceMembershipChangeRestrictions(old, new consts.MembershipTier, chain, address string) error {
 	log := utilities.NewLoggerWithFields(
 		"enforceMembershipChangeRestrictions", map[string]interface{}{
@@ -399,6 +504,7 @@ func (b *BillingRepo) enforceMembershipChangeRestrictions(old, new consts.Member
 func (b *BillingRepo) AddFund(ctx context.Context, curMembership string, req entities.BillingRequest) error {
 	chain := req.Chain
 	address := req.Address
+	account := req.BalanceAccount()
 
 	log := utilities.NewLoggerWithFields(
 		"repo.AddFund", map[string]interface{}{
@@ -431,7 +537,9 @@ func (b *BillingRepo) AddFund(ctx context.Context, curMembership string, req ent
 		b.conf.DB.Keyspace, consts.BillingTable,
 	)
 
-	if err = b.db.Query(query, chain, address).Scan(&prevUpdatedTime, &curBalance, &curMembershipCost); err != nil {
+	if err = b.db.Query(query, account.Chain, account.Address).Scan(
+		&prevUpdatedTime, &curBalance, &curMembershipCost,
+	); err != nil {
 		if !errors.Is(err, gocql.ErrNotFound) {
 			log.WithError(err).Error("Getting fund details failed")
 			return fmt.Errorf("failed to get fund details: %w", err)
@@ -457,13 +565,14 @@ func (b *BillingRepo) AddFund(ctx context.Context, curMembership string, req ent
 
 	columnClause := []string{"chain", "address", "updated", "balance", "charge"}
 	valuePlaceholderClause := utilities.DBMultiValuePlaceholders(5)
-	valueClause := []interface{}{chain, address, timeNow, availableBalance, curMembershipCost}
+	valueClause := []interface{}{account.Chain, account.Address, timeNow, availableBalance, curMembershipCost}
 	if numDays != math.MaxFloat64 {
 		columnClause = append(columnClause, "expiry")
 		valuePlaceholderClause = utilities.DBMultiValuePlaceholders(6)
 		valueClause = append(valueClause, expiry)
 	}
 
+	// the payment is recorded for the paying account, which is what duplicate payments are checked against
 	query = fmt.Sprintf(
 		`INSERT INTO %s.%s (chain, address, txn_id, paid_time, paid_amt) VALUES %s`,
 		b.conf.DB.Keyspace, consts.BillingHistoryTable, utilities.DBMultiValuePlaceholders(5),
@@ -492,6 +601,7 @@ func (b *BillingRepo) GetBillingDetails(
 ) (*entities.BillingInfo, error) {
 	chain := req.Chain
 	address := req.Address
+	account := req.BalanceAccount()
 
 	log := utilities.NewLoggerWithFields(
 		"repo.GetBillingDetails", map[string]interface{}{
@@ -512,7 +622,7 @@ func (b *BillingRepo) GetBillingDetails(
 		b.conf.DB.Keyspace, consts.BillingTable,
 	)
 
-	if err := b.db.Query(query, chain, address).Scan(
+	if err := b.db.Query(query, account.Chain, account.Address).Scan(
 		&expiry, &prevUpdatedTime, &curBalance, &curMembershipCost,
 	); err != nil {
 		if !errors.Is(err, gocql.ErrNotFound) {
@@ -567,6 +677,7 @@ func (b *BillingRepo) GetBillingDetails(
 		Membership:             curMembershipTier.String(),
 		RemainingNotifications: remainingNotifications,
 		BlockedChannels:        blockedChannels,
+		BillingAccount:         req.BillingAccount,
 	}
 
 	billingRecords := make([]entities.BillingRecords, 0)
@@ -582,24 +693,36 @@ func (b *BillingRepo) GetBillingDetails(
 		b.conf.DB.Keyspace, consts.BillingHistoryTable,
 	)
 
-	iter := b.db.Query(query, chain, address).Iter()
-	for iter.Scan(&paidTime, &txnID, &paidAmt) {
-		billingRecords = append(
-			billingRecords, entities.BillingRecords{
-				PaidAmount: paidAmt,
-				PaidTime:   paidTime,
-				TxnID:      txnID,
-			},
-		)
-	}
+	// a shared balance is funded by the payments of every account sharing it
+	payers := append([]entities.UserIdentifier{{Chain: chain, Address: address}}, req.SharedWith...)
+	for _, payer := range payers {
+		iter := b.db.Query(query, payer.Chain, payer.Address).Iter()
+		for iter.Scan(&paidTime, &txnID, &paidAmt) {
+			billingRecords = append(
+				billingRecords, entities.BillingRecords{
+					PaidAmount: paidAmt,
+					PaidTime:   paidTime,
+					TxnID:      txnID,
+				},
+			)
+		}
 
-	if err := iter.Close(); err != nil {
-		if !errors.Is(err, gocql.ErrNotFound) {
-			log.WithError(err).Error("Getting fund details failed")
-			return nil, fmt.Errorf("failed to get fund details: %w", err)
+		if err := iter.Close(); err != nil {
+			if !errors.Is(err, gocql.ErrNotFound) {
+				log.WithError(err).Error("Getting fund details failed")
+				return nil, fmt.Errorf("failed to get fund details: %w", err)
+			}
 		}
 	}
 
+	if len(payers) > 1 {
+		sort.Slice(
+			billingRecords, func(i, j int) bool {
+				return billingRecords[i].PaidTime.After(billingRecords[j].PaidTime)
+			},
+		)
+	}
+
 	info.BillingRecords = billingRecords
 
 	return info, nil
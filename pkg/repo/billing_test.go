@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"testing"
+
+	"notiboy/pkg/consts"
+)
+
+func Test_higherTier(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared consts.MembershipTier
+		paid   consts.MembershipTier
+		want   consts.MembershipTier
+	}{
+		{
+			name:   "shared tier is higher",
+			shared: consts.GoldTier,
+			paid:   consts.FreeTier,
+			want:   consts.GoldTier,
+		},
+		{
+			name:   "paid tier is higher",
+			shared: consts.SilverTier,
+			paid:   consts.GoldTier,
+			want:   consts.GoldTier,
+		},
+		{
+			name:   "same tier",
+			shared: consts.SilverTier,
+			paid:   consts.SilverTier,
+			want:   consts.SilverTier,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := higherTier(tt.shared, tt.paid); got != tt.want {
+					t.Errorf("higherTier() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -56,7 +56,26 @@ type ChannelRepoImpl interface {
 		typeStr, startDate, endDate string, limit int, page int,
 	) (*ChannelStats, error)
 	VerifyChannel(ctx context.Context, chain, channel string) error
+	RequestVerification(ctx context.Context, request *entities.ChannelVerification) error
+	GetVerificationRequest(ctx context.Context, chain, appID string) (*entities.ChannelVerification, error)
+	ListVerificationRequests(ctx context.Context, chain, status string) ([]entities.ChannelVerification, error)
+	UpdateVerificationStatus(ctx context.Context, chain, appID, from, to, reason, reviewer string) error
+	RevokeVerification(ctx context.Context, chain, appID, reason, reviewer string) error
+	ClaimDomain(ctx context.Context, claim *entities.ChannelDomain) error
+	GetDomainClaim(ctx context.Context, chain, appID string) (*entities.ChannelDomain, error)
+	ListDomainClaims(ctx context.Context) ([]entities.ChannelDomain, error)
+	UpdateDomainProof(ctx context.Context, claim *entities.ChannelDomain) error
+	DeleteDomainClaim(ctx context.Context, chain, appID string) error
+	ProveDomain(ctx context.Context, domain, token string) (string, error)
 	RetrieveChannelUsers(ctx context.Context, chain, appID string) ([]string, error)
+	SetChannelTags(ctx context.Context, chain, appID, category string, tags []string, curated *bool) error
+	ListDiscoverableChannels(ctx context.Context, chain string) ([]entities.ChannelModel, error)
+	ChannelTraction(ctx context.Context, chain, appID, since string) (int, int, error)
+	ChannelSentCount(ctx context.Context, chain, appID, since string) (int, error)
+	StoreRanking(ctx context.Context, chain, ranking string, scores []entities.ChannelScore) error
+	GetRanking(
+		ctx context.Context, chain, ranking string, limit int, withLogo bool,
+	) ([]entities.RankedChannel, error)
 }
 
 func NewChannelRepo(db *gocql.Session, conf *config.NotiboyConfModel) ChannelRepoImpl {
@@ -92,7 +111,8 @@ func (repo *ChannelRepo) ChannelReadSentStatistics(
 
 	tbl := fmt.Sprintf("%s.%s", repo.Conf.DB.Keyspace, consts.ChannelSentReadMetrics)
 	query := fmt.Sprintf(
-		`SELECT event_date, SUM("read") AS read, SUM("sent") AS sent FROM %s WHERE chain = ? AND channel = ?`, tbl,
+		`SELECT event_date, SUM("read") AS read, SUM("sent") AS sent, SUM("recalled") AS recalled, SUM("clicked") AS clicked FROM %s WHERE chain = ? AND channel = ?`,
+		tbl,
 	)
 	if fetchKind == "range" {
 		query = fmt.Sprintf(
@@ -104,20 +124,26 @@ func (repo *ChannelRepo) ChannelReadSentStatistics(
 	}
 
 	var (
-		date string
-		read int
-		sent int
+		date     string
+		read     int
+		sent     int
+		recalled int
+		clicked  int
 	)
 
 	iter := repo.Db.Query(query, chain, appID).Iter()
-	for iter.Scan(&date, &read, &sent) {
-		respData = append(
-			respData, entities.ChannelReadSentResponse{
-				EventDate: date,
-				Read:      read,
-				Sent:      sent,
-			},
-		)
+	for iter.Scan(&date, &read, &sent, &recalled, &clicked) {
+		resp := entities.ChannelReadSentResponse{
+			EventDate: date,
+			Read:      read,
+			Sent:      sent,
+			Recalled:  recalled,
+			Clicked:   clicked,
+		}
+		if sent > 0 {
+			resp.ClickThroughRate = float64(clicked) / float64(sent)
+		}
+		respData = append(respData, resp)
 	}
 	if err := iter.Close(); err != nil {
 		log.WithError(err).Error("failed to close iterator for channel read sent metrics")
@@ -272,7 +298,7 @@ func (repo *ChannelRepo) ChannelNotificationStatistics(
 
 // ChannelUsers retrieves the users associated with a specific channel.
 func (repo *ChannelRepo) ChannelUsers(
-	_ context.Context, _ *http.Request, req *entities.ListChannelUsersRequest,
+	ctx context.Context, _ *http.Request, req *entities.ListChannelUsersRequest,
 ) (*entities.Response, error) {
 	chain := req.Chain
 	appId := req.AppId
@@ -329,13 +355,20 @@ func (repo *ChannelRepo) ChannelUsers(
 	var allowedMediums, supportedMediums, channels, optins []string
 	var userAddr, status, membership string
 
+	// the profiles are read along, and narrowed down to what the requesting user gets to see once all are read
 	infoQuery := fmt.Sprintf(
-		"SELECT address, channels, optins, membership, status, allowed_mediums, supported_mediums FROM %s.%s WHERE chain = ? AND address in ?",
-		config.GetConfig().DB.Keyspace, consts.UserInfo,
+		"SELECT address, channels, optins, membership, status, allowed_mediums, supported_mediums, %s FROM %s.%s WHERE chain = ? AND address in ?",
+		db.ProfileColumns, config.GetConfig().DB.Keyspace, consts.UserInfo,
 	)
 	infoIter := repo.Db.Query(infoQuery, chain, userAddresses).Iter()
 
-	for infoIter.Scan(&userAddr, &channels, &optins, &membership, &status, &allowedMediums, &supportedMediums) {
+	profiles := make(map[string]*entities.UserProfile)
+	profile := new(entities.UserProfile)
+	dest := append(
+		[]interface{}{&userAddr, &channels, &optins, &membership, &status, &allowedMediums, &supportedMediums},
+		db.ProfileDest(profile)...,
+	)
+	for infoIter.Scan(dest...) {
 		userInfo := map[string]interface{}{
 			"address":           userAddr,
 			"chain":             chain,
@@ -346,8 +379,13 @@ func (repo *ChannelRepo) ChannelUsers(
 			"channels":          channels,
 			"optins":            optins,
 		}
+		if !profile.IsEmpty() {
+			copied := *profile
+			profiles[userAddr] = &copied
+		}
 
 		userInfos = append(userInfos, userInfo)
+		*profile = entities.UserProfile{}
 	}
 
 	if err := infoIter.Close(); err != nil {
@@ -355,6 +393,17 @@ func (repo *ChannelRepo) ChannelUsers(
 		return nil, err
 	}
 
+	profiles, err := db.ProfilesVisibleTo(ctx, chain, address, profiles)
+	if err != nil {
+		log.WithError(err).Error("failed to fetch user profiles")
+		return nil, err
+	}
+	for _, userInfo := range userInfos {
+		if profile, ok := profiles[userInfo["address"].(string)]; ok {
+			userInfo["profile"] = profile
+		}
+	}
+
 	// construct the response object
 	response := entities.Response{
 		MetaData: &entities.MetaData{},
@@ -395,12 +444,14 @@ func (repo *ChannelRepo) ChannelCreate(_ context.Context, data entities.ChannelI
 	}
 
 	query := fmt.Sprintf(
-		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, created) VALUES %s`,
-		config.GetConfig().DB.Keyspace, consts.UnverifiedChannelInfo, utilities.DBMultiValuePlaceholders(9),
+		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, visibility, created)
+		VALUES %s`,
+		config.GetConfig().DB.Keyspace, consts.UnverifiedChannelInfo, utilities.DBMultiValuePlaceholders(10),
 	)
 
 	if err := repo.Db.Query(
-		query, chain, data.Name, appID, data.Description, data.Logo, consts.STATUS_ACTIVE, data.Address, false, now,
+		query, chain, data.Name, appID, data.Description, data.Logo, consts.STATUS_ACTIVE, data.Address, false,
+		channelVisibility(data.Visibility), now,
 	).Exec(); err != nil {
 		log.WithError(err).Error("Logo validation failed")
 		return &response, fmt.Errorf("failed to insert channel info: %w", err)
@@ -437,6 +488,11 @@ func (repo *ChannelRepo) ChannelCreate(_ context.Context, data entities.ChannelI
 	}
 
 	cache.GetChannelNameCache().Add(chain, data.Name, appID)
+	if channelVisibility(data.Visibility) == consts.ChannelPublic {
+		cache.GetChannelSearchIndex().Put(
+			chain, cache.ChannelSearchDoc{AppID: appID, Name: data.Name, Description: data.Description},
+		)
+	}
 
 	return &response, nil
 }
@@ -462,19 +518,17 @@ func (repo *ChannelRepo) ChannelUpdate(ctx context.Context, data entities.Channe
 		}
 	}
 
-	userModel, err := db.GetUserModel(ctx, data.Chain, data.Address)
+	err := db.VerifyChannelPermission(ctx, data.Chain, data.AppID, data.Address, consts.ChannelPermEdit)
 	if err != nil {
-		return fmt.Errorf("getting user model failed")
-	}
-
-	if !utilities.ContainsString(userModel.Channels, data.AppID) {
-		return fmt.Errorf("user %s is not owner of channel %s", data.Address, data.AppID)
+		return err
 	}
 
 	var (
 		currentName string
 		currentDesc string
 		currentLogo string
+		currentVis  string
+		owner       string
 	)
 
 	tbl := consts.UnverifiedChannelInfo
@@ -483,10 +537,12 @@ func (repo *ChannelRepo) ChannelUpdate(ctx context.Context, data entities.Channe
 	}
 
 	channelQuery := fmt.Sprintf(
-		"SELECT name, description, logo FROM %s.%s where app_id = ? AND chain = ?",
+		"SELECT name, description, logo, visibility, owner FROM %s.%s where app_id = ? AND chain = ?",
 		config.GetConfig().DB.Keyspace, tbl,
 	)
-	err = repo.Db.Query(channelQuery, data.AppID, data.Chain).Scan(&currentName, &currentDesc, &currentLogo)
+	err = repo.Db.Query(channelQuery, data.AppID, data.Chain).Scan(
+		&currentName, &currentDesc, &currentLogo, &currentVis, &owner,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve channel details: %w", err)
 	}
@@ -501,8 +557,18 @@ func (repo *ChannelRepo) ChannelUpdate(ctx context.Context, data entities.Channe
 		setClause = append(setClause, "logo = ?")
 		args = append(args, data.Logo)
 	}
+	if data.Visibility != "" && channelVisibility(currentVis) != data.Visibility {
+		setClause = append(setClause, "visibility = ?")
+		args = append(args, data.Visibility)
+	}
 	if data.Name != "" && currentName != data.Name {
-		memTier := consts.MembershipStringToEnum(userModel.Membership)
+		// the membership of the owner applies, whoever of the members edits the channel
+		ownerModel, err := db.GetUserModel(ctx, data.Chain, owner)
+		if err != nil {
+			return fmt.Errorf("getting user model failed")
+		}
+
+		memTier := consts.MembershipStringToEnum(ownerModel.Membership)
 		if !consts.ChannelRename[memTier] {
 			return fmt.Errorf("membership tier %s doesn't allow setting channel name", memTier)
 		}
@@ -526,13 +592,17 @@ func (repo *ChannelRepo) ChannelUpdate(ctx context.Context, data entities.Channe
 		return fmt.Errorf("failed to execute update query: %w", err)
 	}
 
+	if err = repo.indexChannel(ctx, data.Chain, data.AppID); err != nil {
+		log.WithError(err).Error("failed to index channel for search")
+	}
+
 	//this is only for unverifying the channel
 	if data.Name != "" {
 		if !cache.GetChannelVerifyCache().IsVerified(data.Chain, data.AppID) {
 			return nil
 		}
 
-		if err = repo.unVerifyChannel(ctx, data.Chain, data.AppID); err != nil {
+		if err = repo.RevokeVerification(ctx, data.Chain, data.AppID, "channel renamed", ""); err != nil {
 			return fmt.Errorf("failed to unverify the channel: %w", err)
 		}
 	}
@@ -551,6 +621,8 @@ func (repo *ChannelRepo) DeleteChannel(ctx context.Context, chain, appID, addres
 	var (
 		owner       string
 		channelName string
+		category    string
+		tags        []string
 	)
 
 	tbl := consts.UnverifiedChannelInfo
@@ -559,11 +631,11 @@ func (repo *ChannelRepo) DeleteChannel(ctx context.Context, chain, appID, addres
 	}
 
 	query := fmt.Sprintf(
-		`SELECT owner, name FROM %s.%s WHERE chain=? AND app_id=?`,
+		`SELECT owner, name, category, tags FROM %s.%s WHERE chain=? AND app_id=?`,
 		config.GetConfig().DB.Keyspace, tbl,
 	)
 
-	err := repo.Db.Query(query, chain, appID).Scan(&owner, &channelName)
+	err := repo.Db.Query(query, chain, appID).Scan(&owner, &channelName, &category, &tags)
 	if err != nil {
 		if errors.Is(err, gocql.ErrNotFound) {
 			log.Errorf("No record found for chain %s and app_id %s", chain, appID)
@@ -572,9 +644,9 @@ func (repo *ChannelRepo) DeleteChannel(ctx context.Context, chain, appID, addres
 		return fmt.Errorf("failed to get owner information of channel %s: %w", appID, err)
 	}
 
-	if owner != address {
-		log.Errorf("user %s not an owner of app_id %s", address, appID)
-		return fmt.Errorf("user %s not an owner of chain %s and app_id %s", address, chain, appID)
+	if err = db.VerifyChannelPermission(ctx, chain, appID, address, consts.ChannelPermDelete); err != nil {
+		log.WithError(err).Errorf("user %s not permitted to delete app_id %s", address, appID)
+		return err
 	}
 
 	// Delete channel info
@@ -612,11 +684,25 @@ func (repo *ChannelRepo) DeleteChannel(ctx context.Context, chain, appID, addres
 		config.GetConfig().DB.Keyspace, consts.UserInfo,
 	)
 
-	if err := repo.Db.Query(query, appID, chain, address).Exec(); err != nil {
+	if err := repo.Db.Query(query, appID, chain, owner).Exec(); err != nil {
 		log.WithError(err).Error("Failed to remove channel from user_info")
 		return fmt.Errorf("failed to remove channel from user_info: %w", err)
 	}
 
+	if err = db.DeleteChannelMembers(ctx, chain, appID); err != nil {
+		log.WithError(err).Error("failed to delete channel members")
+	}
+
+	for _, tbl := range []string{
+		consts.ChannelTransfers, consts.ChannelVerifications, consts.ChannelDomains, consts.ChannelInvites,
+		consts.ChannelOptinRequests, consts.ChannelTokenGates,
+	} {
+		query = fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, repo.Conf.DB.Keyspace, tbl)
+		if err = repo.Db.Query(query, chain, appID).WithContext(ctx).Exec(); err != nil {
+			log.WithError(err).Errorf("failed to delete from %s", tbl)
+		}
+	}
+
 	var userAddresses []string
 	query = fmt.Sprintf(
 		`SELECT users FROM %s.%s WHERE chain = ? AND app_id = ?`,
@@ -668,18 +754,32 @@ func (repo *ChannelRepo) DeleteChannel(ctx context.Context, chain, appID, addres
 		}
 	}
 
-	query = fmt.Sprintf(
-		`DELETE FROM %s.%s WHERE chain = ? AND channel = ?`,
-		config.GetConfig().DB.Keyspace, consts.ChannelTractionMetrics,
-	)
+	for _, metricsTbl := range []string{consts.ChannelTractionMetrics, consts.ChannelSourceMetrics} {
+		query = fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE chain = ? AND channel = ?`,
+			config.GetConfig().DB.Keyspace, metricsTbl,
+		)
 
-	if err = repo.Db.Query(query, chain, appID).Exec(); err != nil {
-		if !errors.Is(err, gocql.ErrNotFound) {
-			log.WithError(err).Error("failed to delete channel traction metrics")
+		if err = repo.Db.Query(query, chain, appID).Exec(); err != nil {
+			if !errors.Is(err, gocql.ErrNotFound) {
+				log.WithError(err).Errorf("failed to delete channel %s", metricsTbl)
+			}
 		}
 	}
 
+	if err = repo.setDiscoveryIndex(ctx, chain, appID, category, tags, "", nil); err != nil {
+		log.WithError(err).Error("failed to delete channel from discovery")
+	}
+
+	query = fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND ranking = ?`, config.GetConfig().DB.Keyspace, consts.ChannelRankings,
+	)
+	if err = repo.Db.Query(query, chain, consts.RankingSimilar+appID).WithContext(ctx).Exec(); err != nil {
+		log.WithError(err).Error("failed to delete similar channels")
+	}
+
 	cache.GetChannelNameCache().Pop(chain, channelName, appID)
+	cache.GetChannelSearchIndex().Remove(chain, appID)
 
 	return nil
 }
@@ -764,6 +864,10 @@ func (repo *ChannelRepo) getChannels(
 		description string
 		logo        string
 		status      string
+		domain      string
+		visibility  string
+		category    string
+		tags        []string
 		created     time.Time
 	)
 
@@ -771,8 +875,12 @@ func (repo *ChannelRepo) getChannels(
 
 	whereClause := "chain = ?"
 	whereVal := []interface{}{chain}
-	getClause := []string{"app_id, name, verified, owner, description, status, created"}
-	args := []interface{}{&appId, &name, &verified, &owner, &description, &status, &created}
+	getClause := []string{
+		"app_id, name, verified, owner, description, status, domain, visibility, category, tags, created",
+	}
+	args := []interface{}{
+		&appId, &name, &verified, &owner, &description, &status, &domain, &visibility, &category, &tags, &created,
+	}
 	if withLogo {
 		getClause = append(getClause, "logo")
 		args = append(args, &logo)
@@ -800,6 +908,10 @@ func (repo *ChannelRepo) getChannels(
 			Verified:         verified,
 			Logo:             logo,
 			Status:           status,
+			Domain:           domain,
+			Visibility:       channelVisibility(visibility),
+			Category:         category,
+			Tags:             tags,
 			CreatedTimestamp: created,
 		}
 
@@ -851,6 +963,10 @@ func (repo *ChannelRepo) ListChannels(ctx context.Context, req *entities.ListCha
 		return repo.ListChannelsByName(ctx, chain, searchName, withLogo)
 	}
 
+	if req.Category != "" || req.Tag != "" {
+		return repo.listChannelsByTags(ctx, chain, req.Category, req.Tag, withLogo)
+	}
+
 	var (
 		currPageStateStr string
 		err              error
@@ -858,15 +974,15 @@ func (repo *ChannelRepo) ListChannels(ctx context.Context, req *entities.ListCha
 	channelModels := make([]entities.ChannelModel, 0)
 
 	if verifiedKind {
-		channelModels, currPageStateStr, err = repo.getChannels(
-			nil, consts.VerifiedChannelInfo, chain, withLogo, pageSize, pageState,
+		channelModels, currPageStateStr, err = repo.getListedChannels(
+			consts.VerifiedChannelInfo, chain, withLogo, pageSize, pageState,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("faield to get verified channels: %w", err)
 		}
 	} else {
-		channelModels, currPageStateStr, err = repo.getChannels(
-			nil, consts.UnverifiedChannelInfo, chain, withLogo, pageSize, pageState,
+		channelModels, currPageStateStr, err = repo.getListedChannels(
+			consts.UnverifiedChannelInfo, chain, withLogo, pageSize, pageState,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("faield to get unverified channels: %w", err)
@@ -894,12 +1010,13 @@ func (repo *ChannelRepo) ListChannelsByName(
 ) (*entities.Response, error) {
 	log := utilities.NewLogger("ListChannelsByName")
 
-	appIDs := cache.GetChannelNameCache().GetAppIDs(chain, channelName)
+	appIDs := cache.GetChannelSearchIndex().Search(chain, channelName, consts.ChannelSearchLimit)
 
 	channelModels, err := repo.getChannelsUnfilteredAppIDs(appIDs, chain, withLogo)
 	if err != nil {
 		return nil, err
 	}
+	channelModels = orderChannels(listedChannels(channelModels), appIDs)
 
 	// construct the response object
 	response := &entities.Response{
@@ -1033,25 +1150,35 @@ func (repo *ChannelRepo) VerifyChannel(ctx context.Context, chain, appID string)
 		status      string
 		description string
 		logo        string
+		domain      string
+		visibility  string
+		category    string
+		tags        []string
+		curated     bool
 		created     time.Time
 	)
 
 	channelQuery := fmt.Sprintf(
-		"SELECT name, status, owner, description, logo, created FROM %s.%s where app_id = ? AND chain = ?",
+		"SELECT name, status, owner, description, logo, domain, visibility, category, tags, tags_curated, created "+
+			"FROM %s.%s where app_id = ? AND chain = ?",
 		config.GetConfig().DB.Keyspace, consts.UnverifiedChannelInfo,
 	)
-	err := repo.Db.Query(channelQuery, appID, chain).Scan(&name, &status, &owner, &description, &logo, &created)
+	err := repo.Db.Query(channelQuery, appID, chain).Scan(
+		&name, &status, &owner, &description, &logo, &domain, &visibility, &category, &tags, &curated, &created,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve channel details: %w", err)
 	}
 
 	query := fmt.Sprintf(
-		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, created) VALUES %s`,
-		config.GetConfig().DB.Keyspace, consts.VerifiedChannelInfo, utilities.DBMultiValuePlaceholders(9),
+		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, domain, visibility,
+		category, tags, tags_curated, created) VALUES %s`,
+		config.GetConfig().DB.Keyspace, consts.VerifiedChannelInfo, utilities.DBMultiValuePlaceholders(14),
 	)
 
 	if err = repo.Db.Query(
-		query, chain, name, appID, description, logo, status, owner, true, created,
+		query, chain, name, appID, description, logo, status, owner, true, domain, visibility, category, tags,
+		curated, created,
 	).Exec(); err != nil {
 		log.WithError(err).Error("failed to insert verified channel record")
 		return fmt.Errorf("failed to insert channel info: %w", err)
@@ -1091,25 +1218,35 @@ func (repo *ChannelRepo) unVerifyChannel(ctx context.Context, chain, appID strin
 		status      string
 		description string
 		logo        string
+		domain      string
+		visibility  string
+		category    string
+		tags        []string
+		curated     bool
 		created     time.Time
 	)
 
 	channelQuery := fmt.Sprintf(
-		"SELECT name, status, owner, description, logo, created FROM %s.%s where app_id = ? AND chain = ?",
+		"SELECT name, status, owner, description, logo, domain, visibility, category, tags, tags_curated, created "+
+			"FROM %s.%s where app_id = ? AND chain = ?",
 		config.GetConfig().DB.Keyspace, consts.VerifiedChannelInfo,
 	)
-	err := repo.Db.Query(channelQuery, appID, chain).Scan(&name, &status, &owner, &description, &logo, &created)
+	err := repo.Db.Query(channelQuery, appID, chain).Scan(
+		&name, &status, &owner, &description, &logo, &domain, &visibility, &category, &tags, &curated, &created,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve channel details: %w", err)
 	}
 
 	query := fmt.Sprintf(
-		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, created) VALUES %s`,
-		config.GetConfig().DB.Keyspace, consts.UnverifiedChannelInfo, utilities.DBMultiValuePlaceholders(9),
+		`INSERT INTO %s.%s (chain, name, app_id, description, logo, status, owner, verified, domain, visibility,
+		category, tags, tags_curated, created) VALUES %s`,
+		config.GetConfig().DB.Keyspace, consts.UnverifiedChannelInfo, utilities.DBMultiValuePlaceholders(14),
 	)
 
 	if err = repo.Db.Query(
-		query, chain, name, appID, description, logo, status, owner, false, created,
+		query, chain, name, appID, description, logo, status, owner, false, domain, visibility, category, tags,
+		curated, created,
 	).Exec(); err != nil {
 		log.WithError(err).Error("failed to insert verified channel record")
 		return fmt.Errorf("failed to insert channel info: %w", err)
@@ -1130,3 +1267,63 @@ func (repo *ChannelRepo) unVerifyChannel(ctx context.Context, chain, appID strin
 
 	return nil
 }
+
+// channelVisibility returns the visibility of a channel, channels created before visibility existed are public
+func channelVisibility(visibility string) string {
+	if visibility == "" {
+		return consts.ChannelPublic
+	}
+
+	return visibility
+}
+
+// getListedChannels retrieves a page of pageSize public channels of tbl, only public channels are listed,
+// unlisted and private ones are reached by their link.
+func (repo *ChannelRepo) getListedChannels(
+	tbl, chain string, withLogo bool, pageSize int, pageState []byte,
+) ([]entities.ChannelModel, string, error) {
+	return fillListedPage(
+		pageSize, pageState, func(size int, state []byte) ([]entities.ChannelModel, string, error) {
+			return repo.getChannels(nil, tbl, chain, withLogo, size, state)
+		},
+	)
+}
+
+// fillListedPage fetches channels until the page has pageSize public channels or no channel is left. Each fetch
+// asks for as many channels as the page misses, so that the next page starts right after the last one listed.
+func fillListedPage(
+	pageSize int, pageState []byte, fetch func(size int, state []byte) ([]entities.ChannelModel, string, error),
+) ([]entities.ChannelModel, string, error) {
+	if pageSize <= 0 {
+		channelModels, nextPageState, err := fetch(pageSize, pageState)
+		return listedChannels(channelModels), nextPageState, err
+	}
+
+	listed := make([]entities.ChannelModel, 0, pageSize)
+	for {
+		channelModels, nextPageState, err := fetch(pageSize-len(listed), pageState)
+		if err != nil {
+			return nil, "", err
+		}
+		listed = append(listed, listedChannels(channelModels)...)
+
+		if len(listed) >= pageSize || nextPageState == "" {
+			return listed, nextPageState, nil
+		}
+		if pageState, err = base64.URLEncoding.DecodeString(nextPageState); err != nil {
+			return nil, "", fmt.Errorf("invalid page state: %w", err)
+		}
+	}
+}
+
+// listedChannels returns the public channels among channels
+func listedChannels(channels []entities.ChannelModel) []entities.ChannelModel {
+	listed := make([]entities.ChannelModel, 0, len(channels))
+	for _, channel := range channels {
+		if channel.Visibility == consts.ChannelPublic {
+			listed = append(listed, channel)
+		}
+	}
+
+	return listed
+}
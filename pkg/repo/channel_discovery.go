@@ -0,0 +1,354 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/cache"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// SetChannelTags sets the category and tags the channel is discovered by. Owners can't change what the admins
+// curated, the admins pass curated to set whether they do.
+func (repo *ChannelRepo) SetChannelTags(
+	ctx context.Context, chain, appID, category string, tags []string, curated *bool,
+) error {
+	tbl := channelInfoTable(chain, appID)
+
+	var (
+		currCategory string
+		currTags     []string
+		currCurated  bool
+	)
+	query := fmt.Sprintf(
+		`SELECT category, tags, tags_curated FROM %s.%s WHERE chain = ? AND app_id = ?`, repo.Conf.DB.Keyspace, tbl,
+	)
+	err := repo.Db.Query(query, chain, appID).WithContext(ctx).Scan(&currCategory, &currTags, &currCurated)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return fmt.Errorf("channel %s not found", appID)
+		}
+		return fmt.Errorf("failed to get tags of channel: %w", err)
+	}
+
+	if curated == nil {
+		if currCurated {
+			return errors.New("the category and tags of the channel are curated by the admins")
+		}
+		curated = &currCurated
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s.%s SET category = ?, tags = ?, tags_curated = ? WHERE chain = ? AND app_id = ?`,
+		repo.Conf.DB.Keyspace, tbl,
+	)
+	if err = repo.Db.Query(query, category, tags, *curated, chain, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to set tags of channel: %w", err)
+	}
+
+	if err = repo.setDiscoveryIndex(ctx, chain, appID, currCategory, currTags, category, tags); err != nil {
+		return err
+	}
+
+	return repo.indexChannel(ctx, chain, appID)
+}
+
+// ListDiscoverableChannels retrieves all the public channels of chain, without their logos.
+func (repo *ChannelRepo) ListDiscoverableChannels(ctx context.Context, chain string) ([]entities.ChannelModel, error) {
+	channelModels := make([]entities.ChannelModel, 0)
+
+	for _, tbl := range []string{consts.VerifiedChannelInfo, consts.UnverifiedChannelInfo} {
+		channels, _, err := repo.getChannels(nil, tbl, chain, false, 0, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channels: %w", err)
+		}
+		channelModels = append(channelModels, channels...)
+	}
+
+	return listedChannels(channelModels), nil
+}
+
+// ChannelTraction retrieves how many users opted in to and out of the channel since the date.
+func (repo *ChannelRepo) ChannelTraction(ctx context.Context, chain, appID, since string) (int, int, error) {
+	query := fmt.Sprintf(
+		`SELECT SUM(optin), SUM(optout) FROM %s.%s WHERE chain = ? AND channel = ? AND event_date >= ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelTractionMetrics,
+	)
+
+	var optins, optouts int
+	err := repo.Db.Query(query, chain, appID, since).WithContext(ctx).Scan(&optins, &optouts)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return 0, 0, fmt.Errorf("failed to get traction of channel: %w", err)
+	}
+
+	return optins, optouts, nil
+}
+
+// ChannelSentCount retrieves how many notifications the channel sent since the date.
+func (repo *ChannelRepo) ChannelSentCount(ctx context.Context, chain, appID, since string) (int, error) {
+	query := fmt.Sprintf(
+		`SELECT SUM(sent) FROM %s.%s WHERE chain = ? AND channel = ? AND event_date >= ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelSentReadMetrics,
+	)
+
+	var sent int
+	if err := repo.Db.Query(query, chain, appID, since).WithContext(ctx).Scan(&sent); err != nil &&
+		!errors.Is(err, gocql.ErrNotFound) {
+		return 0, fmt.Errorf("failed to get notifications sent by channel: %w", err)
+	}
+
+	return sent, nil
+}
+
+// StoreRanking replaces the ranking with the channels scored, which are expected best first. The ranking is
+// cleared and rewritten in one batch of its partition, so it is never read half written. The rows are written
+// right after the clearing, a tombstone would hide rows of the same timestamp.
+func (repo *ChannelRepo) StoreRanking(
+	ctx context.Context, chain, ranking string, scores []entities.ChannelScore,
+) error {
+	now := utilities.TimeNow().UnixMicro()
+
+	batch := repo.Db.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(
+			`DELETE FROM %s.%s USING TIMESTAMP ? WHERE chain = ? AND ranking = ?`,
+			repo.Conf.DB.Keyspace, consts.ChannelRankings,
+		), now-1, chain, ranking,
+	)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, ranking, score, app_id) VALUES (?, ?, ?, ?) USING TIMESTAMP ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelRankings,
+	)
+	for _, score := range scores {
+		batch.Query(query, chain, ranking, score.Score, score.AppID, now)
+	}
+
+	if err := repo.Db.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to store ranking %s: %w", ranking, err)
+	}
+
+	return nil
+}
+
+// GetRanking retrieves up to limit of the public channels in the ranking, best first.
+func (repo *ChannelRepo) GetRanking(
+	ctx context.Context, chain, ranking string, limit int, withLogo bool,
+) ([]entities.RankedChannel, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id, score FROM %s.%s WHERE chain = ? AND ranking = ? LIMIT ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelRankings,
+	)
+
+	var (
+		appID  string
+		score  float64
+		appIDs = make([]string, 0)
+		scores = make(map[string]float64)
+	)
+	iter := repo.Db.Query(query, chain, ranking, limit).WithContext(ctx).Iter()
+	for iter.Scan(&appID, &score) {
+		appIDs = append(appIDs, appID)
+		scores[appID] = score
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get ranking %s: %w", ranking, err)
+	}
+
+	ranked := make([]entities.RankedChannel, 0, len(appIDs))
+	if len(appIDs) == 0 {
+		return ranked, nil
+	}
+
+	channelModels, err := repo.getChannelsUnfilteredAppIDs(appIDs, chain, withLogo)
+	if err != nil {
+		return nil, err
+	}
+
+	// channels made private or deleted since the ranking was computed drop out
+	for _, channel := range orderChannels(listedChannels(channelModels), appIDs) {
+		ranked = append(ranked, entities.RankedChannel{ChannelModel: channel, Score: scores[channel.AppID]})
+	}
+
+	return ranked, nil
+}
+
+// listChannelsByTags retrieves the public channels of the category and with the tag, either may be empty
+func (repo *ChannelRepo) listChannelsByTags(
+	ctx context.Context, chain, category, tag string, withLogo bool,
+) (*entities.Response, error) {
+	var appIDs []string
+
+	if category != "" {
+		categoryAppIDs, err := repo.indexedAppIDs(ctx, chain, consts.ChannelCategoryIndex, "category", category)
+		if err != nil {
+			return nil, err
+		}
+		appIDs = categoryAppIDs
+	}
+
+	if tag != "" {
+		tagAppIDs, err := repo.indexedAppIDs(ctx, chain, consts.ChannelTagIndex, "tag", tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if category == "" {
+			appIDs = tagAppIDs
+		} else {
+			both := make([]string, 0)
+			for _, appID := range appIDs {
+				if utilities.ContainsString(tagAppIDs, appID) {
+					both = append(both, appID)
+				}
+			}
+			appIDs = both
+		}
+	}
+
+	channelModels := make([]entities.ChannelModel, 0)
+	if len(appIDs) > 0 {
+		var err error
+		if channelModels, err = repo.getChannelsUnfilteredAppIDs(appIDs, chain, withLogo); err != nil {
+			return nil, err
+		}
+		channelModels = listedChannels(channelModels)
+		sort.Slice(
+			channelModels, func(i, j int) bool {
+				return channelModels[i].Name < channelModels[j].Name
+			},
+		)
+	}
+
+	return &entities.Response{
+		PaginationMetaData: &entities.PaginationMetaData{
+			Size: len(channelModels),
+		},
+		Data: channelModels,
+	}, nil
+}
+
+// indexedAppIDs retrieves the app IDs of the channels indexed in tbl under the value of column
+func (repo *ChannelRepo) indexedAppIDs(ctx context.Context, chain, tbl, column, value string) ([]string, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND %s = ?`, repo.Conf.DB.Keyspace, tbl, column,
+	)
+
+	var (
+		appID  string
+		appIDs = make([]string, 0)
+	)
+	iter := repo.Db.Query(query, chain, value).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		appIDs = append(appIDs, appID)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get channels by %s: %w", column, err)
+	}
+
+	return appIDs, nil
+}
+
+// setDiscoveryIndex moves the channel in the category and tag indexes from the old category and tags to the new
+// ones
+func (repo *ChannelRepo) setDiscoveryIndex(
+	ctx context.Context, chain, appID, oldCategory string, oldTags []string, newCategory string, newTags []string,
+) error {
+	keyspace := repo.Conf.DB.Keyspace
+
+	batch := repo.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	if oldCategory != "" && oldCategory != newCategory {
+		batch.Query(
+			fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND category = ? AND app_id = ?`, keyspace,
+				consts.ChannelCategoryIndex),
+			chain, oldCategory, appID,
+		)
+	}
+	if newCategory != "" && oldCategory != newCategory {
+		batch.Query(
+			fmt.Sprintf(`INSERT INTO %s.%s (chain, category, app_id) VALUES (?, ?, ?)`, keyspace,
+				consts.ChannelCategoryIndex),
+			chain, newCategory, appID,
+		)
+	}
+	for _, tag := range oldTags {
+		if !utilities.ContainsString(newTags, tag) {
+			batch.Query(
+				fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND tag = ? AND app_id = ?`, keyspace,
+					consts.ChannelTagIndex),
+				chain, tag, appID,
+			)
+		}
+	}
+	for _, tag := range newTags {
+		if !utilities.ContainsString(oldTags, tag) {
+			batch.Query(
+				fmt.Sprintf(`INSERT INTO %s.%s (chain, tag, app_id) VALUES (?, ?, ?)`, keyspace,
+					consts.ChannelTagIndex),
+				chain, tag, appID,
+			)
+		}
+	}
+
+	if batch.Size() == 0 {
+		return nil
+	}
+
+	if err := repo.Db.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to index channel for discovery: %w", err)
+	}
+
+	return nil
+}
+
+// indexChannel indexes the channel for search as it is now, only public channels are searched
+func (repo *ChannelRepo) indexChannel(ctx context.Context, chain, appID string) error {
+	query := fmt.Sprintf(
+		`SELECT name, description, category, tags, visibility FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.Conf.DB.Keyspace, channelInfoTable(chain, appID),
+	)
+
+	doc := cache.ChannelSearchDoc{AppID: appID}
+	var visibility string
+	err := repo.Db.Query(query, chain, appID).WithContext(ctx).Scan(
+		&doc.Name, &doc.Description, &doc.Category, &doc.Tags, &visibility,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			cache.GetChannelSearchIndex().Remove(chain, appID)
+			return nil
+		}
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+
+	if channelVisibility(visibility) != consts.ChannelPublic {
+		cache.GetChannelSearchIndex().Remove(chain, appID)
+		return nil
+	}
+
+	cache.GetChannelSearchIndex().Put(chain, doc)
+
+	return nil
+}
+
+// orderChannels orders channels as their app IDs are in appIDs
+func orderChannels(channels []entities.ChannelModel, appIDs []string) []entities.ChannelModel {
+	position := make(map[string]int, len(appIDs))
+	for i, appID := range appIDs {
+		position[appID] = i
+	}
+
+	sort.SliceStable(
+		channels, func(i, j int) bool {
+			return position[channels[i].AppID] < position[channels[j].AppID]
+		},
+	)
+
+	return channels
+}
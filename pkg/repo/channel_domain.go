@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	domainLib "notiboy/pkg/repo/driver/domain"
+)
+
+// ClaimDomain stores the claim of the channel on a domain, replacing any previous claim. The channel shows no
+// domain until the new one is verified.
+func (repo *ChannelRepo) ClaimDomain(ctx context.Context, claim *entities.ChannelDomain) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, domain, token, status, method, error, checked, verified, created)
+		VALUES (?, ?, ?, ?, ?, '', '', null, null, ?)`,
+		repo.Conf.DB.Keyspace, consts.ChannelDomains,
+	)
+	if err := repo.Db.Query(
+		query, claim.Chain, claim.AppID, claim.Domain, claim.Token, claim.Status, claim.Created,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to store domain claim: %w", err)
+	}
+
+	return repo.setChannelDomain(ctx, claim.Chain, claim.AppID, "")
+}
+
+// GetDomainClaim retrieves the claim of the channel on a domain.
+func (repo *ChannelRepo) GetDomainClaim(ctx context.Context, chain, appID string) (*entities.ChannelDomain, error) {
+	query := fmt.Sprintf(
+		`SELECT domain, token, status, method, error, checked, verified, created FROM %s.%s
+		WHERE chain = ? AND app_id = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelDomains,
+	)
+
+	claim := entities.ChannelDomain{Chain: chain, AppID: appID}
+	var checked, verified time.Time
+	err := repo.Db.Query(query, chain, appID).WithContext(ctx).Scan(
+		&claim.Domain, &claim.Token, &claim.Status, &claim.Method, &claim.Error, &checked, &verified, &claim.Created,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("channel %s claims no domain", appID)
+		}
+		return nil, fmt.Errorf("failed to get domain claim: %w", err)
+	}
+	claim.Checked, claim.Verified = optionalTime(checked), optionalTime(verified)
+
+	return &claim, nil
+}
+
+// ListDomainClaims retrieves the claims of all channels on domains, for checking them periodically.
+func (repo *ChannelRepo) ListDomainClaims(ctx context.Context) ([]entities.ChannelDomain, error) {
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, domain, token, status, method, error, checked, verified, created FROM %s.%s`,
+		repo.Conf.DB.Keyspace, consts.ChannelDomains,
+	)
+
+	claims := make([]entities.ChannelDomain, 0)
+	iter := repo.Db.Query(query).WithContext(ctx).Iter()
+	for {
+		var (
+			claim             entities.ChannelDomain
+			checked, verified time.Time
+		)
+		if !iter.Scan(
+			&claim.Chain, &claim.AppID, &claim.Domain, &claim.Token, &claim.Status, &claim.Method, &claim.Error,
+			&checked, &verified, &claim.Created,
+		) {
+			break
+		}
+		claim.Checked, claim.Verified = optionalTime(checked), optionalTime(verified)
+		claims = append(claims, claim)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list domain claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// UpdateDomainProof records the outcome of checking the claim of the channel, and shows the domain on the channel
+// while it is verified. Nothing is recorded if the claim was replaced or removed in the meantime.
+func (repo *ChannelRepo) UpdateDomainProof(ctx context.Context, claim *entities.ChannelDomain) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET status = ?, method = ?, error = ?, checked = ?, verified = ? WHERE chain = ? AND app_id = ?
+		IF token = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelDomains,
+	)
+	applied, err := repo.Db.Query(
+		query, claim.Status, claim.Method, claim.Error, claim.Checked, claim.Verified, claim.Chain, claim.AppID,
+		claim.Token,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to update domain claim: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("the claim of channel %s on %s was replaced", claim.AppID, claim.Domain)
+	}
+
+	domain := ""
+	if claim.Status == consts.DomainVerified {
+		domain = claim.Domain
+	}
+
+	return repo.setChannelDomain(ctx, claim.Chain, claim.AppID, domain)
+}
+
+// DeleteDomainClaim removes the claim of the channel on its domain.
+func (repo *ChannelRepo) DeleteDomainClaim(ctx context.Context, chain, appID string) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, repo.Conf.DB.Keyspace, consts.ChannelDomains,
+	)
+	if err := repo.Db.Query(query, chain, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete domain claim: %w", err)
+	}
+
+	return repo.setChannelDomain(ctx, chain, appID, "")
+}
+
+// ProveDomain returns how domain proves to carry token, either by a TXT record or by its well-known file.
+func (repo *ChannelRepo) ProveDomain(ctx context.Context, domain, token string) (string, error) {
+	return domainLib.GetProver().Prove(ctx, domain, token)
+}
+
+// setChannelDomain sets the verified domain shown on the channel
+func (repo *ChannelRepo) setChannelDomain(ctx context.Context, chain, appID, domain string) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET domain = ? WHERE chain = ? AND app_id = ? IF EXISTS`,
+		repo.Conf.DB.Keyspace, channelInfoTable(chain, appID),
+	)
+	if _, err := repo.Db.Query(query, domain, chain, appID).WithContext(ctx).
+		MapScanCAS(map[string]interface{}{}); err != nil {
+		return fmt.Errorf("failed to set domain of channel: %w", err)
+	}
+
+	return nil
+}
+
+// optionalTime returns nil for a timestamp never set
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
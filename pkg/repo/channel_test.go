@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+// pagedChannels serves visibilities as channels named by their position, size at a time or all of them if size
+// isn't positive
+func pagedChannels(visibilities []string) func(int, []byte) ([]entities.ChannelModel, string, error) {
+	return func(size int, state []byte) ([]entities.ChannelModel, string, error) {
+		start := 0
+		if len(state) > 0 {
+			start, _ = strconv.Atoi(string(state))
+		}
+
+		end := start + size
+		if size <= 0 || end > len(visibilities) {
+			end = len(visibilities)
+		}
+
+		channels := make([]entities.ChannelModel, 0, end-start)
+		for i := start; i < end; i++ {
+			channels = append(channels, entities.ChannelModel{AppID: strconv.Itoa(i), Visibility: visibilities[i]})
+		}
+
+		next := ""
+		if end < len(visibilities) {
+			next = base64.URLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+		}
+
+		return channels, next, nil
+	}
+}
+
+func Test_fillListedPage(t *testing.T) {
+	public, unlisted, private := consts.ChannelPublic, consts.ChannelUnlisted, consts.ChannelPrivate
+	visibilities := []string{public, private, unlisted, public, private, public, public, unlisted}
+
+	tests := []struct {
+		name      string
+		pageSize  int
+		pageState []byte
+		want      []string
+		wantNext  bool
+	}{
+		{
+			name:     "page filled past hidden channels",
+			pageSize: 2,
+			want:     []string{"0", "3"},
+			wantNext: true,
+		},
+		{
+			name:      "next page starts after the last listed",
+			pageSize:  2,
+			pageState: []byte("4"),
+			want:      []string{"5", "6"},
+			wantNext:  true,
+		},
+		{
+			name:      "last page",
+			pageSize:  3,
+			pageState: []byte("4"),
+			want:      []string{"5", "6"},
+		},
+		{
+			name: "unpaged",
+			want: []string{"0", "3", "5", "6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				channels, next, err := fillListedPage(tt.pageSize, tt.pageState, pagedChannels(visibilities))
+				if err != nil {
+					t.Fatalf("fillListedPage() error = %v", err)
+				}
+
+				got := make([]string, 0, len(channels))
+				for _, channel := range channels {
+					got = append(got, channel.AppID)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("fillListedPage() = %v, want %v", got, tt.want)
+				}
+				if (next != "") != tt.wantNext {
+					t.Errorf("fillListedPage() next = %q, wantNext %v", next, tt.wantNext)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,257 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// RequestVerification stores the request to verify the channel. A channel has one pending request at most,
+// a rejected or revoked channel can be requested again.
+func (repo *ChannelRepo) RequestVerification(ctx context.Context, request *entities.ChannelVerification) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, requested_by, website, socials, notes, status, created, updated)
+		VALUES %s IF NOT EXISTS`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications, utilities.DBMultiValuePlaceholders(9),
+	)
+
+	previous := map[string]interface{}{}
+	applied, err := repo.Db.Query(
+		query, request.Chain, request.AppID, request.RequestedBy, request.Website, request.Socials, request.Notes,
+		request.Status, request.Created, request.Updated,
+	).WithContext(ctx).MapScanCAS(previous)
+	if err != nil {
+		return fmt.Errorf("failed to store verification request: %w", err)
+	}
+	if applied {
+		return repo.indexVerificationRequest(ctx, request)
+	}
+
+	status, _ := previous["status"].(string)
+	if status == consts.VerificationPending || status == consts.VerificationApproved {
+		return fmt.Errorf("verification of channel %s is already %s", request.AppID, status)
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s.%s SET requested_by = ?, website = ?, socials = ?, notes = ?, status = ?, reason = '',
+		reviewed_by = '', created = ?, updated = ? WHERE chain = ? AND app_id = ? IF status = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+	applied, err = repo.Db.Query(
+		query, request.RequestedBy, request.Website, request.Socials, request.Notes, request.Status,
+		request.Created, request.Updated, request.Chain, request.AppID, status,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to store verification request: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("verification of channel %s was requested concurrently", request.AppID)
+	}
+
+	return repo.indexVerificationRequest(ctx, request)
+}
+
+// indexVerificationRequest indexes the request by its status and by the user who made it
+func (repo *ChannelRepo) indexVerificationRequest(ctx context.Context, request *entities.ChannelVerification) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, requested_by, app_id) VALUES (?, ?, ?)`,
+		repo.Conf.DB.Keyspace, consts.UserVerificationRequests,
+	)
+	err := repo.Db.Query(query, request.Chain, request.RequestedBy, request.AppID).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to index verification request: %w", err)
+	}
+
+	return repo.indexVerificationStatus(ctx, request.Chain, request.AppID, request.Status)
+}
+
+// indexVerificationStatus moves the request to verify the channel to status in channel_verifications_by_status
+func (repo *ChannelRepo) indexVerificationStatus(ctx context.Context, chain, appID, status string) error {
+	batch := repo.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	for _, previous := range consts.VerificationStatuses {
+		if previous != status {
+			batch.Query(
+				fmt.Sprintf(
+					`DELETE FROM %s.%s WHERE chain = ? AND status = ? AND app_id = ?`,
+					repo.Conf.DB.Keyspace, consts.VerificationsByStatus,
+				), chain, previous, appID,
+			)
+		}
+	}
+	batch.Query(
+		fmt.Sprintf(
+			`INSERT INTO %s.%s (chain, status, app_id) VALUES (?, ?, ?)`,
+			repo.Conf.DB.Keyspace, consts.VerificationsByStatus,
+		), chain, status, appID,
+	)
+
+	if err := repo.Db.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to index verification status: %w", err)
+	}
+
+	return nil
+}
+
+// GetVerificationRequest retrieves the latest request to verify the channel.
+func (repo *ChannelRepo) GetVerificationRequest(
+	ctx context.Context, chain, appID string,
+) (*entities.ChannelVerification, error) {
+	query := fmt.Sprintf(
+		`SELECT requested_by, website, socials, notes, status, reason, reviewed_by, created, updated
+		FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+
+	request := entities.ChannelVerification{Chain: chain, AppID: appID}
+	err := repo.Db.Query(query, chain, appID).WithContext(ctx).Scan(
+		&request.RequestedBy, &request.Website, &request.Socials, &request.Notes, &request.Status, &request.Reason,
+		&request.ReviewedBy, &request.Created, &request.Updated,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("verification of channel %s was never requested", appID)
+		}
+		return nil, fmt.Errorf("failed to get verification request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// ListVerificationRequests retrieves the requests to verify channels on chain with the status, all of them if
+// status is empty. The requests with the status are looked up in channel_verifications_by_status.
+func (repo *ChannelRepo) ListVerificationRequests(
+	ctx context.Context, chain, status string,
+) ([]entities.ChannelVerification, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id, requested_by, website, socials, notes, status, reason, reviewed_by, created, updated
+		FROM %s.%s WHERE chain = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+	if status == "" {
+		return repo.scanVerificationRequests(ctx, chain, "", query, chain)
+	}
+
+	appIDs, err := repo.verificationsWithStatus(ctx, chain, status)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]entities.ChannelVerification, 0, len(appIDs))
+	for _, chunk := range utilities.Chunk(appIDs, consts.ReceiverBatchSize) {
+		chunkRequests, err := repo.scanVerificationRequests(ctx, chain, status, query+` AND app_id IN ?`, chain, chunk)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, chunkRequests...)
+	}
+
+	return requests, nil
+}
+
+// verificationsWithStatus returns the channels whose request to verify them has the status
+func (repo *ChannelRepo) verificationsWithStatus(ctx context.Context, chain, status string) ([]string, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND status = ?`,
+		repo.Conf.DB.Keyspace, consts.VerificationsByStatus,
+	)
+
+	var (
+		appIDs []string
+		appID  string
+	)
+
+	iter := repo.Db.Query(query, chain, status).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		appIDs = append(appIDs, appID)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list verification requests: %w", err)
+	}
+
+	return appIDs, nil
+}
+
+// scanVerificationRequests runs the query for requests to verify channels, leaving out the ones that don't have
+// the status, unless it is empty. The status index may still hold a request whose status just changed.
+func (repo *ChannelRepo) scanVerificationRequests(
+	ctx context.Context, chain, status, query string, args ...interface{},
+) ([]entities.ChannelVerification, error) {
+	requests := make([]entities.ChannelVerification, 0)
+	iter := repo.Db.Query(query, args...).WithContext(ctx).Iter()
+	for {
+		request := entities.ChannelVerification{Chain: chain}
+		if !iter.Scan(
+			&request.AppID, &request.RequestedBy, &request.Website, &request.Socials, &request.Notes,
+			&request.Status, &request.Reason, &request.ReviewedBy, &request.Created, &request.Updated,
+		) {
+			break
+		}
+		if status == "" || request.Status == status {
+			requests = append(requests, request)
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list verification requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// UpdateVerificationStatus records the decision of the admin on the request to verify the channel, provided
+// the request still has the status from.
+func (repo *ChannelRepo) UpdateVerificationStatus(
+	ctx context.Context, chain, appID, from, to, reason, reviewer string,
+) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET status = ?, reason = ?, reviewed_by = ?, updated = ? WHERE chain = ? AND app_id = ?
+		IF status = ?`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+	applied, err := repo.Db.Query(query, to, reason, reviewer, utilities.TimeNow(), chain, appID, from).
+		WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to update verification request: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("verification of channel %s is not %s", appID, from)
+	}
+
+	return repo.indexVerificationStatus(ctx, chain, appID, to)
+}
+
+// RevokeVerification moves the verified channel back to the unverified channels and records why.
+func (repo *ChannelRepo) RevokeVerification(ctx context.Context, chain, appID, reason, reviewer string) error {
+	if err := repo.unVerifyChannel(ctx, chain, appID); err != nil {
+		return err
+	}
+
+	return repo.revokeVerificationRequest(ctx, chain, appID, reason, reviewer)
+}
+
+// revokeVerificationRequest marks the request to verify the channel revoked. A channel verified without a
+// request has none to mark.
+func (repo *ChannelRepo) revokeVerificationRequest(ctx context.Context, chain, appID, reason, reviewer string) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET status = ?, reason = ?, reviewed_by = ?, updated = ? WHERE chain = ? AND app_id = ?
+		IF EXISTS`,
+		repo.Conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+	applied, err := repo.Db.Query(
+		query, consts.VerificationRevoked, reason, reviewer, utilities.TimeNow(), chain, appID,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to revoke verification request: %w", err)
+	}
+	if !applied {
+		return nil
+	}
+
+	return repo.indexVerificationStatus(ctx, chain, appID, consts.VerificationRevoked)
+}
@@ -16,6 +16,7 @@ import (
 	"notiboy/pkg/cache"
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
 	"notiboy/utilities"
 )
 
@@ -186,8 +187,16 @@ func (c ChatRepo) BlockUser(_ context.Context, chain, user, blockedUser string)
 		`UPDATE %s.%s SET blocked_users = blocked_users + ? WHERE chain = ? AND user = ?`,
 		config.GetConfig().DB.Keyspace, consts.ChatUserBlockTable,
 	)
+	blockedByQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, user, blocked_by) VALUES (?, ?, ?)`,
+		config.GetConfig().DB.Keyspace, consts.ChatUserBlockedByTable,
+	)
+
+	batch := c.Db.NewBatch(gocql.LoggedBatch)
+	batch.Query(query, []string{blockedUser}, chain, user)
+	batch.Query(blockedByQuery, chain, blockedUser, user)
 
-	err := c.Db.Query(query, []string{blockedUser}, chain, user).Exec()
+	err := c.Db.ExecuteBatch(batch)
 	if err != nil {
 		return &entities.Response{
 			StatusCode: http.StatusInternalServerError,
@@ -207,8 +216,16 @@ func (c ChatRepo) UnblockUser(_ context.Context, chain, user, blockedUser string
 		`UPDATE %s.%s SET blocked_users = blocked_users - ? WHERE chain = ? AND user = ?`,
 		config.GetConfig().DB.Keyspace, consts.ChatUserBlockTable,
 	)
+	blockedByQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND user = ? AND blocked_by = ?`,
+		config.GetConfig().DB.Keyspace, consts.ChatUserBlockedByTable,
+	)
+
+	batch := c.Db.NewBatch(gocql.LoggedBatch)
+	batch.Query(query, []string{blockedUser}, chain, user)
+	batch.Query(blockedByQuery, chain, blockedUser, user)
 
-	err := c.Db.Query(query, []string{blockedUser}, chain, user).Exec()
+	err := c.Db.ExecuteBatch(batch)
 	if err != nil {
 		return &entities.Response{
 			StatusCode: http.StatusInternalServerError,
@@ -600,6 +617,28 @@ func (c ChatRepo) GetDNSContactsList(ctx context.Context, chain, originUser, loo
 	return domains, nil
 }
 
+// GetContactProfiles retrieves the profiles of the contacts of originUser, or only of lookupAddr if given,
+// as originUser gets to see them
+func (c ChatRepo) GetContactProfiles(
+	ctx context.Context, chain, originUser, lookupAddr string,
+) (map[string]*entities.UserProfile, error) {
+	users := []string{lookupAddr}
+
+	if lookupAddr == "" {
+		contactsQuery := fmt.Sprintf(
+			`SELECT contacts FROM %s.%s WHERE chain = ? AND user = ?`,
+			config.GetConfig().DB.Keyspace, consts.ChatUserContactsTable,
+		)
+
+		err := c.Db.Query(contactsQuery, chain, originUser).Scan(&users)
+		if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("failed to fetch contacts: %w", err)
+		}
+	}
+
+	return db.GetUserProfiles(ctx, chain, originUser, users)
+}
+
 type ChatRepoImpl interface {
 	StorePersonalChat(ctx context.Context, data *entities.UserChat, ttl int) (*entities.Response, error)
 	GetPersonalChat(ctx context.Context, chain, user string, from, until int64) ([]*entities.UserChat, error)
@@ -611,6 +650,7 @@ type ChatRepoImpl interface {
 	UnblockUser(ctx context.Context, chain, user, blockedUser string) (*entities.Response, error)
 
 	GetDNSContactsList(ctx context.Context, chain, originUser, lookupAddr string) (map[string]string, error)
+	GetContactProfiles(ctx context.Context, chain, originUser, lookupAddr string) (map[string]*entities.UserProfile, error)
 
 	StoreGroupInfo(ctx context.Context, data *entities.GroupChatInfo) (
 		*entities.Response,
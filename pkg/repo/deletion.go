@@ -0,0 +1,856 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/cache"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+// reachMediums and reachEvents are those notification_reach records, the reach of a receiver is deleted by them
+var (
+	reachMediums = []string{consts.Inapp, consts.Email, consts.Discord, consts.Push}
+	reachEvents  = []string{consts.ReachSent, consts.ReachDelivered, consts.ReachRead, consts.ReachClicked}
+)
+
+type DeletionRepo struct {
+	db       *gocql.Session
+	conf     *config.NotiboyConfModel
+	linkRepo LinkRepoImply
+}
+
+// DeletionRepoImply is an interface that defines the contract for erasing the data of an account.
+type DeletionRepoImply interface {
+	CreateDeletionJob(ctx context.Context, job *entities.AccountDeletion) error
+	GetDeletionJob(ctx context.Context, id string) (*entities.AccountDeletion, error)
+	ListPendingDeletionJobs(ctx context.Context) ([]entities.AccountDeletion, error)
+	ClaimDeletionJob(ctx context.Context, job *entities.AccountDeletion, leaseUntil time.Time) (bool, error)
+	UpdateDeletionJob(ctx context.Context, job *entities.AccountDeletion) error
+	RunDeletionStep(ctx context.Context, user entities.UserIdentifier, step string) error
+}
+
+func NewDeletionRepo(db *gocql.Session, conf *config.NotiboyConfModel, linkRepo LinkRepoImply) DeletionRepoImply {
+	return &DeletionRepo{db: db, conf: conf, linkRepo: linkRepo}
+}
+
+// CreateDeletionJob stores a deletion job for the account and marks the account as being deleted,
+// which locks the user out right away.
+func (repo *DeletionRepo) CreateDeletionJob(ctx context.Context, job *entities.AccountDeletion) error {
+	log := utilities.NewLoggerWithFields(
+		"CreateDeletionJob", map[string]interface{}{
+			"chain":   job.Chain,
+			"address": job.Address,
+		},
+	)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (id, chain, address, status, steps_done, requested, updated, lease_until) VALUES %s`,
+		repo.conf.DB.Keyspace, consts.AccountDeletions, utilities.DBMultiValuePlaceholders(8),
+	)
+	err := repo.db.Query(
+		query, job.ID, job.Chain, job.Address, job.Status, job.StepsDone, job.Requested, job.Updated,
+		job.LeaseUntil,
+	).WithContext(ctx).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to create deletion job")
+		return fmt.Errorf("failed to create deletion job: %w", err)
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s.%s SET status = ?, modified = ? WHERE address = ? AND chain = ?",
+		repo.conf.DB.Keyspace, consts.UserInfo,
+	)
+	if err = repo.db.Query(query, consts.STATUS_DELETING, job.Requested, job.Address, job.Chain).
+		WithContext(ctx).Exec(); err != nil {
+		log.WithError(err).Error("failed to update user profile status")
+		return fmt.Errorf("failed to update user profile status: %w", err)
+	}
+
+	now := utilities.TimeNow()
+	query = fmt.Sprintf(
+		"INSERT INTO %s.%s (chain, event_date, event_time, offboard) VALUES (?, ?, ?, ?) USING TTL %d",
+		repo.conf.DB.Keyspace, consts.UserActivityMetrics, repo.conf.TTL.Metrics,
+	)
+	if err = repo.db.Query(query, job.Chain, utilities.ToDate(now), now, 1).WithContext(ctx).Exec(); err != nil {
+		log.WithError(err).Error("Failed to insert user activity metrics")
+	}
+
+	return nil
+}
+
+// GetDeletionJob retrieves a deletion job by its id.
+func (repo *DeletionRepo) GetDeletionJob(ctx context.Context, id string) (*entities.AccountDeletion, error) {
+	query := fmt.Sprintf(
+		`SELECT id, chain, address, status, steps_done, error, requested, updated, lease_until FROM %s.%s WHERE id = ?`,
+		repo.conf.DB.Keyspace, consts.AccountDeletions,
+	)
+
+	job := new(entities.AccountDeletion)
+	err := repo.db.Query(query, id).WithContext(ctx).Scan(
+		&job.ID, &job.Chain, &job.Address, &job.Status, &job.StepsDone, &job.Error, &job.Requested, &job.Updated,
+		&job.LeaseUntil,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("deletion job %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get deletion job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListPendingDeletionJobs retrieves the deletion jobs which haven't completed yet. Completed jobs are
+// kept so that their status can still be looked up.
+func (repo *DeletionRepo) ListPendingDeletionJobs(ctx context.Context) ([]entities.AccountDeletion, error) {
+	var jobs = make([]entities.AccountDeletion, 0)
+
+	query := fmt.Sprintf(
+		`SELECT id, chain, address, status, steps_done, error, requested, updated, lease_until FROM %s.%s`,
+		repo.conf.DB.Keyspace, consts.AccountDeletions,
+	)
+
+	var job entities.AccountDeletion
+	iter := repo.db.Query(query).WithContext(ctx).Iter()
+	for iter.Scan(
+		&job.ID, &job.Chain, &job.Address, &job.Status, &job.StepsDone, &job.Error, &job.Requested, &job.Updated,
+		&job.LeaseUntil,
+	) {
+		if job.Status != consts.DeletionCompleted {
+			jobs = append(jobs, job)
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list deletion jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimDeletionJob takes the job over until leaseUntil. It fails if another instance claimed the job since it was read.
+func (repo *DeletionRepo) ClaimDeletionJob(
+	ctx context.Context, job *entities.AccountDeletion, leaseUntil time.Time,
+) (bool, error) {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET lease_until = ? WHERE id = ? IF lease_until = ?`,
+		repo.conf.DB.Keyspace, consts.AccountDeletions,
+	)
+
+	applied, err := repo.db.Query(query, leaseUntil, job.ID, job.LeaseUntil).WithContext(ctx).
+		MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim deletion job: %w", err)
+	}
+	if applied {
+		job.LeaseUntil = leaseUntil
+	}
+
+	return applied, nil
+}
+
+// UpdateDeletionJob records the progress of a deletion job.
+func (repo *DeletionRepo) UpdateDeletionJob(ctx context.Context, job *entities.AccountDeletion) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET status = ?, steps_done = ?, error = ?, updated = ? WHERE id = ?`,
+		repo.conf.DB.Keyspace, consts.AccountDeletions,
+	)
+
+	err := repo.db.Query(query, job.Status, job.StepsDone, job.Error, job.Updated, job.ID).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to update deletion job: %w", err)
+	}
+
+	return nil
+}
+
+// RunDeletionStep erases the data of the account covered by step. Aggregated metrics hold no personal data
+// and are kept, the billing history is kept as billing law requires and everything else is deleted. Channels
+// of the user are kept for their subscribers, orphaned and without owner.
+func (repo *DeletionRepo) RunDeletionStep(ctx context.Context, user entities.UserIdentifier, step string) error {
+	switch step {
+	case consts.DeletionStepSessions:
+		return repo.deleteSessions(ctx, user)
+	case consts.DeletionStepLinks:
+		return repo.deleteLinks(ctx, user)
+	case consts.DeletionStepChannels:
+		return repo.orphanChannels(ctx, user)
+	case consts.DeletionStepOptins:
+		return repo.deleteOptins(ctx, user)
+	case consts.DeletionStepNotifications:
+		return repo.deleteNotifications(ctx, user)
+	case consts.DeletionStepChats:
+		return repo.deleteChats(ctx, user)
+	case consts.DeletionStepDevices:
+		return repo.deletePartition(ctx, consts.FcmTable, "address", user)
+	case consts.DeletionStepVerifications:
+		return repo.deleteVerifications(ctx, user)
+	case consts.DeletionStepDNS:
+		return repo.deletePartition(ctx, consts.UserDNSTable, "user", user)
+	case consts.DeletionStepBilling:
+		// billing_history is the record of payments billing law requires to keep
+		return repo.deletePartition(ctx, consts.BillingTable, "address", user)
+	case consts.DeletionStepTransfers:
+		return repo.deleteTransfers(ctx, user)
+	case consts.DeletionStepVerifyRequests:
+		return repo.deleteVerificationRequesters(ctx, user)
+	case consts.DeletionStepOptinRequests:
+		return repo.deleteOptinRequests(ctx, user)
+	case consts.DeletionStepInvites:
+		return repo.deleteInviteCreators(ctx, user)
+	case consts.DeletionStepProfile:
+		return repo.deleteProfile(ctx, user)
+	}
+
+	return fmt.Errorf("unknown deletion step %s", step)
+}
+
+func (repo *DeletionRepo) exec(ctx context.Context, query string, args ...interface{}) error {
+	return repo.db.Query(query, args...).WithContext(ctx).Exec()
+}
+
+// deletePartition deletes the rows of the user from a table partitioned by chain and the user column
+func (repo *DeletionRepo) deletePartition(
+	ctx context.Context, tbl, userColumn string, user entities.UserIdentifier,
+) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND %s = ?`, repo.conf.DB.Keyspace, tbl, userColumn,
+	)
+	if err := repo.exec(ctx, query, user.Chain, user.Address); err != nil {
+		return fmt.Errorf("failed to delete from %s: %w", tbl, err)
+	}
+
+	return nil
+}
+
+// deleteSessions removes every credential of the user, sessions, login tokens, PATs and sign-in challenges
+func (repo *DeletionRepo) deleteSessions(ctx context.Context, user entities.UserIdentifier) error {
+	for _, tbl := range []string{consts.Sessions, consts.PATInfo, consts.LoginNonce} {
+		if err := repo.deletePartition(ctx, tbl, "address", user); err != nil {
+			return err
+		}
+	}
+
+	// login_info is keyed by the token, the tokens of the user are indexed by user_login_tokens
+	query := fmt.Sprintf(
+		`SELECT jwt FROM %s.%s WHERE chain = ? AND address = ?`, repo.conf.DB.Keyspace, consts.UserLoginTokens,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE address = ? AND chain = ? AND jwt = ?`,
+		repo.conf.DB.Keyspace, consts.LoginInfo,
+	)
+
+	var token string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&token) {
+		if err := repo.exec(ctx, deleteQuery, user.Address, user.Chain, token); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete login token: %w", err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list login tokens: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserLoginTokens, "address", user)
+}
+
+func (repo *DeletionRepo) deleteLinks(ctx context.Context, user entities.UserIdentifier) error {
+	links, err := repo.linkRepo.ListLinkedAccounts(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		if err = repo.linkRepo.UnlinkAccount(ctx, user, link.Chain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orphanChannels keeps the channels of the user for their subscribers, but without owner. Their webhooks,
+// which point to servers of the user, and the notifications scheduled from them are deleted.
+func (repo *DeletionRepo) orphanChannels(ctx context.Context, user entities.UserIdentifier) error {
+	userModel, err := db.GetUserModel(ctx, user.Chain, user.Address)
+	if err != nil {
+		return err
+	}
+
+	for _, appID := range userModel.Channels {
+		tbl := consts.UnverifiedChannelInfo
+		if cache.GetChannelVerifyCache().IsVerified(user.Chain, appID) {
+			tbl = consts.VerifiedChannelInfo
+		}
+
+		query := fmt.Sprintf(
+			`UPDATE %s.%s SET status = ?, owner = ? WHERE chain = ? AND app_id = ? IF EXISTS`,
+			repo.conf.DB.Keyspace, tbl,
+		)
+		if _, err = repo.db.Query(query, consts.STATUS_CHANNEL_ORPHANED, "", user.Chain, appID).WithContext(ctx).
+			MapScanCAS(map[string]interface{}{}); err != nil {
+			return fmt.Errorf("failed to orphan channel %s: %w", appID, err)
+		}
+
+		var webhooks []entities.Webhook
+		if webhooks, err = db.GetChannelWebhooks(ctx, user.Chain, appID); err != nil {
+			return err
+		}
+		batch := repo.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		deleteChannelWebhooks(batch, repo.conf.DB.Keyspace, user.Chain, appID, webhooks)
+		if err = repo.db.ExecuteBatch(batch); err != nil {
+			return fmt.Errorf("failed to delete webhooks of channel %s: %w", appID, err)
+		}
+	}
+
+	if err = repo.leaveChannels(ctx, user); err != nil {
+		return err
+	}
+
+	return repo.deleteScheduledNotifications(ctx, user)
+}
+
+// leaveChannels removes the user from the channels of others it is a member of or invited to
+func (repo *DeletionRepo) leaveChannels(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND address = ?`, repo.conf.DB.Keyspace, consts.UserChannelRoles,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+
+	var appID string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		if err := repo.exec(ctx, deleteQuery, user.Chain, appID, user.Address); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to leave channel %s: %w", appID, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list channel roles: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserChannelRoles, "address", user)
+}
+
+// deleteScheduledNotifications deletes the notifications scheduled by the user and removes the user
+// from the receivers of the ones scheduled by others
+func (repo *DeletionRepo) deleteScheduledNotifications(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT schedule, sender FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.UserScheduledNotifications,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND schedule = ? AND sender = ?`,
+		repo.conf.DB.Keyspace, consts.ScheduledNotificationInfo,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET receivers = receivers - ? WHERE chain = ? AND schedule = ? AND sender = ?`,
+		repo.conf.DB.Keyspace, consts.ScheduledNotificationInfo,
+	)
+
+	var (
+		schedule time.Time
+		sender   string
+	)
+
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&schedule, &sender) {
+		var err error
+		if sender == user.Address {
+			err = repo.exec(ctx, deleteQuery, user.Chain, schedule, sender)
+		} else {
+			err = repo.exec(ctx, updateQuery, []string{user.Address}, user.Chain, schedule, sender)
+		}
+		if err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete scheduled notification: %w", err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list scheduled notifications: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserScheduledNotifications, "address", user)
+}
+
+// deleteOptins opts the user out of every channel and deletes its opt-in history
+func (repo *DeletionRepo) deleteOptins(ctx context.Context, user entities.UserIdentifier) error {
+	userModel, err := db.GetUserModel(ctx, user.Chain, user.Address)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET users = users - ? WHERE app_id = ? AND chain = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelUsers,
+	)
+	for _, appID := range userModel.Optins {
+		if err = repo.exec(ctx, query, []string{user.Address}, appID, user.Chain); err != nil {
+			return fmt.Errorf("failed to opt out of %s: %w", appID, err)
+		}
+	}
+
+	// channel_subscribers also has the channels the user opted out of, user_subscriptions indexes all of them
+	query = fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND address = ?`, repo.conf.DB.Keyspace, consts.UserSubscriptions,
+	)
+	optinQuery := fmt.Sprintf(
+		`SELECT optin_time FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelSubscribers,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelSubscribers,
+	)
+	deleteOptinQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND optin_time = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.SubscribersByOptin,
+	)
+
+	var (
+		appID     string
+		optinTime time.Time
+	)
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		err = repo.db.Query(optinQuery, user.Chain, appID, user.Address).WithContext(ctx).Scan(&optinTime)
+		if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+			iter.Close()
+			return fmt.Errorf("failed to get subscription to %s: %w", appID, err)
+		}
+		if err == nil && !optinTime.IsZero() {
+			if err = repo.exec(ctx, deleteOptinQuery, user.Chain, appID, optinTime, user.Address); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to delete subscription to %s: %w", appID, err)
+			}
+		}
+
+		if err = repo.exec(ctx, deleteQuery, user.Chain, appID, user.Address); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete subscription to %s: %w", appID, err)
+		}
+	}
+	if err = iter.Close(); err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserSubscriptions, "address", user)
+}
+
+// deleteNotifications deletes the inbox of the user and removes the user from the receivers and the reach
+// of every notification sent to it
+func (repo *DeletionRepo) deleteNotifications(ctx context.Context, user entities.UserIdentifier) error {
+	if err := repo.deletePartition(ctx, consts.NotificationInfo, "receiver", user); err != nil {
+		return err
+	}
+	if err := repo.deletePartition(ctx, consts.NotificationTotalSendPerUserMetrics, "address", user); err != nil {
+		return err
+	}
+	if err := repo.deletePartition(ctx, consts.NotificationReadStatus, "address", user); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT app_id, uuid FROM %s.%s WHERE chain = ? AND receiver = ?`,
+		repo.conf.DB.Keyspace, consts.UserNotifications,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET receivers = receivers - ? WHERE chain = ? AND app_id = ? AND uuid = ?`,
+		repo.conf.DB.Keyspace, consts.SentNotificationInfo,
+	)
+	reachQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND uuid = ? AND medium IN ? AND event IN ? AND receiver = ?`,
+		repo.conf.DB.Keyspace, consts.NotificationReach,
+	)
+
+	var appID, uuid string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID, &uuid) {
+		if err := repo.exec(ctx, updateQuery, []string{user.Address}, user.Chain, appID, uuid); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to remove receiver of notification %s: %w", uuid, err)
+		}
+		if err := repo.exec(
+			ctx, reachQuery, user.Chain, appID, uuid, reachMediums, reachEvents, user.Address,
+		); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete reach of notification %s: %w", uuid, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserNotifications, "receiver", user)
+}
+
+// deleteChats deletes the conversations of the user on both sides, its group messages and memberships,
+// and removes it from the block and contact lists of others
+func (repo *DeletionRepo) deleteChats(ctx context.Context, user entities.UserIdentifier) error {
+	keyspace := repo.conf.DB.Keyspace
+
+	// the copy of a conversation kept for the other user is deleted before the own one, which lists them
+	query := fmt.Sprintf(`SELECT user_b FROM %s.%s WHERE chain = ? AND user_a = ?`, keyspace, consts.ChatUserTable)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND user_a = ? AND user_b = ?`, keyspace, consts.ChatUserTable,
+	)
+
+	var other string
+	deleted := make(map[string]struct{})
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&other) {
+		if _, ok := deleted[other]; ok {
+			continue
+		}
+		if err := repo.exec(ctx, deleteQuery, user.Chain, other, user.Address); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete conversation with %s: %w", other, err)
+		}
+		deleted[other] = struct{}{}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if err := repo.deletePartition(ctx, consts.ChatUserTable, "user_a", user); err != nil {
+		return err
+	}
+
+	if err := repo.leaveGroups(ctx, user); err != nil {
+		return err
+	}
+
+	// contacts are added to both users at once, so the users having the user as contact are its own contacts
+	var contacts []string
+	query = fmt.Sprintf(`SELECT contacts FROM %s.%s WHERE chain = ? AND user = ?`, keyspace, consts.ChatUserContactsTable)
+	err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&contacts)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET contacts = contacts - ? WHERE chain = ? AND user = ?`, keyspace, consts.ChatUserContactsTable,
+	)
+	for _, contact := range contacts {
+		if err = repo.exec(ctx, updateQuery, []string{user.Address}, user.Chain, contact); err != nil {
+			return fmt.Errorf("failed to update contacts of %s: %w", contact, err)
+		}
+	}
+
+	if err = repo.deleteBlocks(ctx, user); err != nil {
+		return err
+	}
+
+	for _, tbl := range []string{consts.ChatUserContactsTable, consts.ChatUserBlockTable, consts.ChatUserBlockedByTable} {
+		if err = repo.deletePartition(ctx, tbl, "user", user); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBlocks removes the user from the block lists of the users who blocked it, and from the index of the
+// users it blocked
+func (repo *DeletionRepo) deleteBlocks(ctx context.Context, user entities.UserIdentifier) error {
+	keyspace := repo.conf.DB.Keyspace
+
+	query := fmt.Sprintf(
+		`SELECT blocked_by FROM %s.%s WHERE chain = ? AND user = ?`, keyspace, consts.ChatUserBlockedByTable,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET blocked_users = blocked_users - ? WHERE chain = ? AND user = ?`,
+		keyspace, consts.ChatUserBlockTable,
+	)
+
+	var blockedBy string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&blockedBy) {
+		if err := repo.exec(ctx, updateQuery, []string{user.Address}, user.Chain, blockedBy); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to update blocked users of %s: %w", blockedBy, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list users who blocked the user: %w", err)
+	}
+
+	var blocked []string
+	query = fmt.Sprintf(
+		`SELECT blocked_users FROM %s.%s WHERE chain = ? AND user = ?`, keyspace, consts.ChatUserBlockTable,
+	)
+	err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&blocked)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return fmt.Errorf("failed to get blocked users: %w", err)
+	}
+
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND user = ? AND blocked_by = ?`, keyspace, consts.ChatUserBlockedByTable,
+	)
+	for _, blockedUser := range blocked {
+		if err = repo.exec(ctx, deleteQuery, user.Chain, blockedUser, user.Address); err != nil {
+			return fmt.Errorf("failed to unblock %s: %w", blockedUser, err)
+		}
+	}
+
+	return nil
+}
+
+// leaveGroups removes the user from its groups along with the messages it sent to them. A group
+// owned by the user stays with its members, without owner.
+func (repo *DeletionRepo) leaveGroups(ctx context.Context, user entities.UserIdentifier) error {
+	keyspace := repo.conf.DB.Keyspace
+
+	var gids []string
+
+	query := fmt.Sprintf(`SELECT gids FROM %s.%s WHERE chain = ? AND user = ?`, keyspace, consts.ChatUserGroupTable)
+	if err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&gids); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	infoQuery := fmt.Sprintf(
+		`SELECT owner, created_at FROM %s.%s WHERE chain = ? AND gid = ?`, keyspace, consts.ChatGroupInfoTable,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET owner = ?, admins = admins - ?, users = users - ?, blocked_users = blocked_users - ?
+	WHERE chain = ? AND gid = ? AND created_at = ?`,
+		keyspace, consts.ChatGroupInfoTable,
+	)
+	chatQuery := fmt.Sprintf(
+		`SELECT sender, sent_time FROM %s.%s WHERE chain = ? AND gid = ?`, keyspace, consts.ChatGroupTable,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND gid = ? AND sent_time = ?`, keyspace, consts.ChatGroupTable,
+	)
+
+	removed := []string{user.Address}
+	for _, gid := range gids {
+		var (
+			owner     string
+			createdAt time.Time
+		)
+
+		iter := repo.db.Query(infoQuery, user.Chain, gid).WithContext(ctx).Iter()
+		for iter.Scan(&owner, &createdAt) {
+			if owner == user.Address {
+				owner = ""
+			}
+			if err := repo.exec(ctx, updateQuery, owner, removed, removed, removed, user.Chain, gid, createdAt); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to leave group %s: %w", gid, err)
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+
+		var (
+			sender   string
+			sentTime int64
+		)
+
+		iter = repo.db.Query(chatQuery, user.Chain, gid).WithContext(ctx).Iter()
+		for iter.Scan(&sender, &sentTime) {
+			if sender != user.Address {
+				continue
+			}
+			if err := repo.exec(ctx, deleteQuery, user.Chain, gid, sentTime); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to delete group message: %w", err)
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+	}
+
+	return repo.deletePartition(ctx, consts.ChatUserGroupTable, "user", user)
+}
+
+// deleteVerifications deletes the medium verifications, verify_info is keyed by token so they are looked up
+// by user in user_verifications
+func (repo *DeletionRepo) deleteVerifications(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT "token" FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.UserVerifications,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE "token" = ? AND address = ? AND chain = ?`,
+		repo.conf.DB.Keyspace, consts.VerifyInfo,
+	)
+
+	var token string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&token) {
+		if err := repo.exec(ctx, deleteQuery, token, user.Address, user.Chain); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete verification: %w", err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list verifications: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserVerifications, "address", user)
+}
+
+// deleteTransfers withdraws the changes of the owner of the channels of the user and the ones to the user
+func (repo *DeletionRepo) deleteTransfers(ctx context.Context, user entities.UserIdentifier) error {
+	userModel, err := db.GetUserModel(ctx, user.Chain, user.Address)
+	if err != nil {
+		return err
+	}
+
+	transfers := &ChannelTransferRepo{db: repo.db, conf: repo.conf}
+	query := fmt.Sprintf(
+		`SELECT kind, to_address FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelTransfers,
+	)
+	targetQuery := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND kind = ? AND to_address = ?`,
+		repo.conf.DB.Keyspace, consts.TransfersByTarget,
+	)
+
+	var pending []entities.ChannelTransfer
+	for _, appID := range userModel.Channels {
+		transfer := entities.ChannelTransfer{Chain: user.Chain, AppID: appID}
+		err = repo.db.Query(query, user.Chain, appID).WithContext(ctx).Scan(&transfer.Kind, &transfer.To)
+		if err != nil {
+			if errors.Is(err, gocql.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get channel transfer of %s: %w", appID, err)
+		}
+		pending = append(pending, transfer)
+	}
+
+	for _, kind := range consts.ChannelTransferKinds {
+		transfer := entities.ChannelTransfer{Chain: user.Chain, Kind: kind, To: user.Address}
+		iter := repo.db.Query(targetQuery, user.Chain, kind, user.Address).WithContext(ctx).Iter()
+		for iter.Scan(&transfer.AppID) {
+			pending = append(pending, transfer)
+		}
+		if err = iter.Close(); err != nil {
+			return fmt.Errorf("failed to list channel transfers: %w", err)
+		}
+	}
+
+	for i := range pending {
+		if err = transfers.DeleteTransfer(ctx, &pending[i]); err != nil {
+			return fmt.Errorf("failed to delete channel transfer of %s: %w", pending[i].AppID, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteVerificationRequesters removes the user from the requests to verify channels it made. The requests are
+// the evidence reviewed for the channel and are kept.
+func (repo *DeletionRepo) deleteVerificationRequesters(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND requested_by = ?`,
+		repo.conf.DB.Keyspace, consts.UserVerificationRequests,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET requested_by = '' WHERE chain = ? AND app_id = ? IF requested_by = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelVerifications,
+	)
+
+	var appID string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		// a later request of another member replaced the one of the user
+		_, err := repo.db.Query(updateQuery, user.Chain, appID, user.Address).WithContext(ctx).
+			MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to remove the requester of the verification of %s: %w", appID, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list verification requests: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserVerificationRequests, "requested_by", user)
+}
+
+// deleteOptinRequests withdraws the requests of the user to opt in to private channels
+func (repo *DeletionRepo) deleteOptinRequests(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT app_id FROM %s.%s WHERE chain = ? AND address = ?`, repo.conf.DB.Keyspace, consts.UserOptinRequests,
+	)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelOptinRequests,
+	)
+
+	var appID string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID) {
+		if err := repo.exec(ctx, deleteQuery, user.Chain, appID, user.Address); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete optin request to %s: %w", appID, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list optin requests: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserOptinRequests, "address", user)
+}
+
+// deleteInviteCreators removes the user from the invites it created. The invites belong to the channel and
+// keep working.
+func (repo *DeletionRepo) deleteInviteCreators(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`SELECT app_id, code FROM %s.%s WHERE chain = ? AND created_by = ?`,
+		repo.conf.DB.Keyspace, consts.UserChannelInvites,
+	)
+	updateQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET created_by = '' WHERE chain = ? AND app_id = ? AND code = ? IF EXISTS`,
+		repo.conf.DB.Keyspace, consts.ChannelInvites,
+	)
+
+	var appID, code string
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&appID, &code) {
+		_, err := repo.db.Query(updateQuery, user.Chain, appID, code).WithContext(ctx).
+			MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to remove the creator of an invite to %s: %w", appID, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list invites: %w", err)
+	}
+
+	return repo.deletePartition(ctx, consts.UserChannelInvites, "created_by", user)
+}
+
+func (repo *DeletionRepo) deleteProfile(ctx context.Context, user entities.UserIdentifier) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE address = ? AND chain = ?`, repo.conf.DB.Keyspace, consts.UserInfo,
+	)
+	if err := repo.exec(ctx, query, user.Address, user.Chain); err != nil {
+		return fmt.Errorf("failed to delete user info: %w", err)
+	}
+
+	return nil
+}
@@ -6,6 +6,9 @@ import (
 	"crypto/ed25519"
 	"encoding/base64"
 	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/spf13/cast"
@@ -24,6 +27,8 @@ import (
 type Algorand struct {
 	client    *algod.Client
 	blockInfo entities.BlockModel
+	// decimals of the ASAs by their ID, they can't change once an ASA is created
+	decimals sync.Map
 }
 
 // VerifyPayment verifies payment txn and returns dollars received
@@ -213,3 +218,119 @@ func (alg *Algorand) FetchValidBlock(_ context.Context) error {
 
 	return nil
 }
+
+// VerifySignedMessage verifies an ed25519 signature over arbitrary data in the ARC-60 style.
+// Both the raw message and the "MX" prefixed message signed by the SDKs' SignBytes are accepted.
+// Rekeyed accounts are verified against their current auth address.
+func (alg *Algorand) VerifySignedMessage(
+	ctx context.Context, address string, message []byte, signature string, _ string,
+) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to b64 decode signature: %w", err)
+	}
+
+	signer, err := types.DecodeAddress(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	if verifyArbitraryData(signer, message, sig) {
+		return nil
+	}
+
+	account, err := alg.client.AccountInformation(address).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get account information: %w", err)
+	}
+	if account.AuthAddr == "" {
+		return fmt.Errorf("message signature validation failed")
+	}
+
+	authAddr, err := types.DecodeAddress(account.AuthAddr)
+	if err != nil {
+		return fmt.Errorf("invalid auth address %s: %w", account.AuthAddr, err)
+	}
+
+	if !verifyArbitraryData(authAddr, message, sig) {
+		return fmt.Errorf("message signature validation failed")
+	}
+
+	return nil
+}
+
+func verifyArbitraryData(signer types.Address, message, sig []byte) bool {
+	return ed25519.Verify(signer[:], message, sig) ||
+		ed25519.Verify(signer[:], bytes.Join([][]byte{[]byte("MX"), message}, nil), sig)
+}
+
+// TokenBalance returns the balance of the ASA address holds, in whole units. An account that isn't opted in to
+// the ASA holds none of it.
+func (alg *Algorand) TokenBalance(ctx context.Context, address string, asset string, _ string) (float64, error) {
+	amount, decimals, err := alg.assetHolding(ctx, address, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(amount) / math.Pow10(int(decimals)), nil
+}
+
+// NFTHoldings returns 1 if address holds the NFT of the ASA, a fraction of a fractional NFT counts as holding it.
+func (alg *Algorand) NFTHoldings(ctx context.Context, address string, asset string, _ string) (int, error) {
+	amount, _, err := alg.assetHolding(ctx, address, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	if amount == 0 {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+// assetHolding returns the base units of the ASA address holds and the decimals of the ASA
+func (alg *Algorand) assetHolding(ctx context.Context, address string, asset string) (uint64, uint64, error) {
+	assetID, err := strconv.ParseUint(asset, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid asset id %s", asset)
+	}
+
+	account, err := alg.client.AccountInformation(address).Do(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get account information: %w", err)
+	}
+
+	var amount uint64
+	for _, holding := range account.Assets {
+		if holding.AssetId == assetID {
+			amount = holding.Amount
+			break
+		}
+	}
+	if amount == 0 {
+		return 0, 0, nil
+	}
+
+	decimals, err := alg.assetDecimals(ctx, assetID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return amount, decimals, nil
+}
+
+// assetDecimals returns the decimals of the ASA, they are only fetched the first time
+func (alg *Algorand) assetDecimals(ctx context.Context, assetID uint64) (uint64, error) {
+	if decimals, ok := alg.decimals.Load(assetID); ok {
+		return decimals.(uint64), nil
+	}
+
+	info, err := alg.client.GetAssetByID(assetID).Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get asset %d: %w", assetID, err)
+	}
+	alg.decimals.Store(assetID, info.Params.Decimals)
+
+	return info.Params.Decimals, nil
+}
@@ -0,0 +1,115 @@
+package algorand
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+)
+
+const holder = "7777777777777777777777777777777777777777777777777774MSJUVU"
+
+// serveAlgod answers the account of holder with the assets and the ASA 5 with 2 decimals, counting the
+// lookups of the ASA
+func serveAlgod(t *testing.T, assets string, assetLookups *int) *Algorand {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"/v2/accounts/"+holder, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"address": "` + holder + `", "assets": ` + assets + `}`))
+		},
+	)
+	mux.HandleFunc(
+		"/v2/assets/5", func(w http.ResponseWriter, _ *http.Request) {
+			*assetLookups++
+			_, _ = w.Write([]byte(`{"index": 5, "params": {"creator": "` + holder + `", "decimals": 2, "total": 1000}}`))
+		},
+	)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := algod.MakeClient(server.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Algorand{client: client}
+}
+
+func TestAlgorand_TokenBalance(t *testing.T) {
+	tests := []struct {
+		name        string
+		assets      string
+		want        float64
+		wantLookups int
+	}{
+		{
+			name:        "holds the ASA",
+			assets:      `[{"asset-id": 4, "amount": 10}, {"asset-id": 5, "amount": 1250}]`,
+			want:        12.5,
+			wantLookups: 1,
+		},
+		{
+			name:   "opted in without holding",
+			assets: `[{"asset-id": 5, "amount": 0}]`,
+		},
+		{
+			name:   "not opted in",
+			assets: `[{"asset-id": 4, "amount": 10}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var lookups int
+				alg := serveAlgod(t, tt.assets, &lookups)
+
+				// the decimals of the ASA are looked up once for every holder
+				for i := 0; i < 3; i++ {
+					got, err := alg.TokenBalance(context.Background(), holder, "5", "")
+					if err != nil {
+						t.Fatalf("TokenBalance() error = %v", err)
+					}
+					if got != tt.want {
+						t.Errorf("TokenBalance() = %v, want %v", got, tt.want)
+					}
+				}
+				if lookups != tt.wantLookups {
+					t.Errorf("TokenBalance() looked up the ASA %d times, want %d", lookups, tt.wantLookups)
+				}
+			},
+		)
+	}
+}
+
+func TestAlgorand_NFTHoldings(t *testing.T) {
+	tests := []struct {
+		name   string
+		assets string
+		want   int
+	}{
+		{name: "holds a fraction", assets: `[{"asset-id": 5, "amount": 1}]`, want: 1},
+		{name: "holds none", assets: `[{"asset-id": 5, "amount": 0}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var lookups int
+				alg := serveAlgod(t, tt.assets, &lookups)
+
+				got, err := alg.NFTHoldings(context.Background(), holder, "5", "")
+				if err != nil {
+					t.Fatalf("NFTHoldings() error = %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("NFTHoldings() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -23,8 +23,17 @@ type Store struct {
 
 type Chain interface {
 	VerifyTransaction(ctx context.Context, senderAddress string, signedTxn string) error
+	// VerifySignedMessage verifies that message was signed by the account of address, publicKey is only needed
+	// by chains where it can't be recovered from the address
+	VerifySignedMessage(ctx context.Context, address string, message []byte, signature string, publicKey string) error
 	FetchValidBlock(ctx context.Context) error
 	VerifyPayment(ctx context.Context, senderAddress string, signedTxnStr string, txnID string) (float64, string, error)
+	// TokenBalance returns how much of the fungible token address holds, in whole units. The token is the ASA of
+	// the ID in asset on Algorand and the currency code in asset issued by issuer on XRPL.
+	TokenBalance(ctx context.Context, address string, asset string, issuer string) (float64, error)
+	// NFTHoldings returns how many NFTs address holds. The NFT is the ASA of the ID in asset on Algorand and any
+	// NFT issued by issuer, of the taxon in asset unless it is empty, on XRPL.
+	NFTHoldings(ctx context.Context, address string, asset string, issuer string) (int, error)
 }
 
 // LoadChains initialises clients of blockchain networks
@@ -0,0 +1,208 @@
+package xrpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// maxHoldingPages bounds the pages of trust lines or NFTs read for one account
+const maxHoldingPages = 20
+
+// standardCurrencyChars are the characters of a standard currency code
+const standardCurrencyChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789?!@#$%^&*<>(){}[]|"
+
+// accountNotFound is the error of the ledger for an account that was never funded, it holds nothing
+const accountNotFound = "actNotFound"
+
+// TokenBalance returns the balance of the currency issued by issuer that address holds on its trust line to the
+// issuer. The currency is either a standard 3 character code, a 40 hex digit code or the name a 40 hex digit
+// code encodes.
+func (x *Xrpl) TokenBalance(ctx context.Context, address string, asset string, issuer string) (float64, error) {
+	if asset == "" || issuer == "" {
+		return 0, fmt.Errorf("currency and issuer are required")
+	}
+	currency := currencyCode(asset)
+
+	var (
+		balance float64
+		marker  interface{}
+	)
+	for page := 0; page < maxHoldingPages; page++ {
+		var res AccountLinesResponse
+		err := x.rpc(
+			ctx, "account_lines", AccountLinesRequestParams{
+				Account:     address,
+				Peer:        issuer,
+				LedgerIndex: "validated",
+				Marker:      marker,
+			}, &res,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		if res.Result.Error == accountNotFound {
+			return 0, nil
+		}
+		if res.Result.Status != "success" {
+			return 0, fmt.Errorf("account_lines failed: %s", res.Result.Error)
+		}
+
+		for _, line := range res.Result.Lines {
+			if line.Account == issuer && currencyCode(line.Currency) == currency {
+				balance += cast.ToFloat64(line.Balance)
+			}
+		}
+
+		if marker = res.Result.Marker; marker == nil {
+			return balance, nil
+		}
+	}
+
+	return balance, nil
+}
+
+// NFTHoldings returns how many NFTs issued by issuer address holds, only those of the taxon in asset if it is set.
+func (x *Xrpl) NFTHoldings(ctx context.Context, address string, asset string, issuer string) (int, error) {
+	if issuer == "" {
+		return 0, fmt.Errorf("issuer is required")
+	}
+
+	var taxon uint64
+	if asset != "" {
+		var err error
+		if taxon, err = strconv.ParseUint(asset, 10, 32); err != nil {
+			return 0, fmt.Errorf("invalid taxon %s", asset)
+		}
+	}
+
+	var (
+		count  int
+		marker interface{}
+	)
+	for page := 0; page < maxHoldingPages; page++ {
+		var res AccountNFTsResponse
+		err := x.rpc(
+			ctx, "account_nfts", AccountNFTsRequestParams{
+				Account:     address,
+				LedgerIndex: "validated",
+				Limit:       400,
+				Marker:      marker,
+			}, &res,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		if res.Result.Error == accountNotFound {
+			return 0, nil
+		}
+		if res.Result.Status != "success" {
+			return 0, fmt.Errorf("account_nfts failed: %s", res.Result.Error)
+		}
+
+		for _, nft := range res.Result.AccountNFTs {
+			if nft.Issuer == issuer && (asset == "" || uint64(nft.NFTokenTaxon) == taxon) {
+				count++
+			}
+		}
+
+		if marker = res.Result.Marker; marker == nil {
+			return count, nil
+		}
+	}
+
+	return count, nil
+}
+
+// currencyCode returns the code the ledger identifies the currency by, the 3 character code of a standard
+// currency and the 40 hex digits of any other in uppercase. A name longer than 3 characters is encoded the way
+// wallets encode it, padded with zeroes to 20 bytes.
+func currencyCode(currency string) string {
+	if len(currency) == 40 {
+		if code, err := hex.DecodeString(currency); err == nil {
+			// a standard currency can also be given in its 160 bit form, with the code in bytes 12 to 14
+			if code[0] == 0 && isStandardCurrency(code[12:15]) && allZero(code[1:12]) && allZero(code[15:]) {
+				return strings.ToUpper(string(code[12:15]))
+			}
+			return strings.ToUpper(currency)
+		}
+	}
+
+	if len(currency) == 3 {
+		return strings.ToUpper(currency)
+	}
+	if len(currency) > 20 {
+		return currency
+	}
+
+	code := make([]byte, 20)
+	copy(code, currency)
+
+	return strings.ToUpper(hex.EncodeToString(code))
+}
+
+// isStandardCurrency tells whether code is made of the characters the ledger allows in a standard currency code
+func isStandardCurrency(code []byte) bool {
+	for _, c := range code {
+		if !strings.ContainsRune(standardCurrencyChars, rune(c)) {
+			return false
+		}
+	}
+
+	return string(code) != "XRP"
+}
+
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rpc calls the method of the XRPL node with params and decodes the response into result
+func (x *Xrpl) rpc(ctx context.Context, method string, params interface{}, result interface{}) error {
+	reqBody, err := json.Marshal(RPCRequest{Method: method, Params: []interface{}{params}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, getAddr(), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make POST call to %s: %w", getAddr(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected http status code: %d, status: %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
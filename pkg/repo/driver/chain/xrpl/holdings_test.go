@@ -0,0 +1,181 @@
+package xrpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"notiboy/config"
+)
+
+// serveLedger answers the RPC methods of the XRPL node with the results, page after page
+func serveLedger(t *testing.T, results map[string][]string) *Xrpl {
+	t.Helper()
+
+	pages := make(map[string]int)
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var req RPCRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				page := pages[req.Method]
+				pages[req.Method]++
+				_, _ = w.Write([]byte(results[req.Method][page]))
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+
+	previous := config.GetConfig()
+	conf := &config.NotiboyConfModel{Mode: "local"}
+	conf.Xrpl.Daemon.Testnet.Address = server.URL
+	config.SetConfig(conf)
+	t.Cleanup(
+		func() {
+			config.SetConfig(previous)
+		},
+	)
+
+	return &Xrpl{client: server.Client()}
+}
+
+func TestXrpl_TokenBalance(t *testing.T) {
+	const issuer = "rIssuer"
+	// SOLO in its 40 hex digit form
+	const solo = "534F4C4F00000000000000000000000000000000"
+
+	tests := []struct {
+		name     string
+		currency string
+		pages    []string
+		want     float64
+		wantErr  bool
+	}{
+		{
+			name:     "standard currency",
+			currency: "usd",
+			pages: []string{
+				`{"result": {"status": "success", "lines": [
+					{"account": "rIssuer", "balance": "12.5", "currency": "USD"},
+					{"account": "rOther", "balance": "100", "currency": "USD"},
+					{"account": "rIssuer", "balance": "7", "currency": "EUR"}
+				]}}`,
+			},
+			want: 12.5,
+		},
+		{
+			name:     "currency of 40 hex digits",
+			currency: solo,
+			pages: []string{
+				`{"result": {"status": "success", "lines": [
+					{"account": "rIssuer", "balance": "3", "currency": "534f4c4f00000000000000000000000000000000"}
+				]}}`,
+			},
+			want: 3,
+		},
+		{
+			name:     "currency by the name its hex digits encode",
+			currency: "SOLO",
+			pages: []string{
+				`{"result": {"status": "success", "lines": [
+					{"account": "rIssuer", "balance": "3", "currency": "` + solo + `"}
+				]}}`,
+			},
+			want: 3,
+		},
+		{
+			name:     "standard currency of 40 hex digits",
+			currency: "0000000000000000000000005553440000000000",
+			pages: []string{
+				`{"result": {"status": "success", "lines": [
+					{"account": "rIssuer", "balance": "1", "currency": "USD"}
+				]}}`,
+			},
+			want: 1,
+		},
+		{
+			name:     "balances over pages",
+			currency: "USD",
+			pages: []string{
+				`{"result": {"status": "success", "marker": "next", "lines": [
+					{"account": "rIssuer", "balance": "1", "currency": "USD"}
+				]}}`,
+				`{"result": {"status": "success", "lines": [
+					{"account": "rIssuer", "balance": "2", "currency": "USD"}
+				]}}`,
+			},
+			want: 3,
+		},
+		{
+			name:     "unfunded account",
+			currency: "USD",
+			pages:    []string{`{"result": {"status": "error", "error": "actNotFound"}}`},
+		},
+		{
+			name:     "failure",
+			currency: "USD",
+			pages:    []string{`{"result": {"status": "error", "error": "invalidParams"}}`},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				x := serveLedger(t, map[string][]string{"account_lines": tt.pages})
+
+				got, err := x.TokenBalance(context.Background(), "rHolder", tt.currency, issuer)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("TokenBalance() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("TokenBalance() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestXrpl_NFTHoldings(t *testing.T) {
+	pages := []string{
+		`{"result": {"status": "success", "marker": "next", "account_nfts": [
+			{"Issuer": "rIssuer", "NFTokenTaxon": 1},
+			{"Issuer": "rIssuer", "NFTokenTaxon": 2},
+			{"Issuer": "rOther", "NFTokenTaxon": 1}
+		]}}`,
+		`{"result": {"status": "success", "account_nfts": [
+			{"Issuer": "rIssuer", "NFTokenTaxon": 1}
+		]}}`,
+	}
+
+	tests := []struct {
+		name    string
+		taxon   string
+		want    int
+		wantErr bool
+	}{
+		{name: "any taxon", want: 3},
+		{name: "of a taxon", taxon: "1", want: 2},
+		{name: "invalid taxon", taxon: "one", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				x := serveLedger(t, map[string][]string{"account_nfts": pages})
+
+				got, err := x.NFTHoldings(context.Background(), "rHolder", tt.taxon, "rIssuer")
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("NFTHoldings() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("NFTHoldings() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,32 @@
+package xrpl
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// verifySecp256k1 verifies a strict DER encoded ECDSA signature of digest by a compressed secp256k1 public key.
+// As on the XRP Ledger, only fully canonical signatures are accepted: a signature whose S is in the upper half of
+// the curve order is a malleated copy of a valid one.
+func verifySecp256k1(publicKey, digest, signature []byte) bool {
+	if len(publicKey) != secp256k1.PubKeyBytesLenCompressed {
+		return false
+	}
+
+	pub, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return false
+	}
+
+	sig, err := ecdsa.ParseDERSignature(signature)
+	if err != nil {
+		return false
+	}
+
+	s := sig.S()
+	if s.IsOverHalfOrder() {
+		return false
+	}
+
+	return sig.Verify(digest, pub)
+}
@@ -0,0 +1,118 @@
+package xrpl
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	addresscodec "github.com/xyield/xrpl-go/address-codec"
+)
+
+// Known answer vectors produced with OpenSSL, over the SHA-512Half of "notiboy login nonce 1234"
+const (
+	katMessage   = "notiboy login nonce 1234"
+	katPublicKey = "0243d9d829a0edd4712f459d423a36fa8208070ca78ff9f9ea7e36c4f32cdc32a7"
+	katOtherKey  = "02441af8368199c79a87775c5162761310e1da0d38d6425097b2a903efc5f65842"
+	katDigest    = "f8915e478b2bf50b51ee26824c753159a3ef42c0b6d7673eab8f17f743fff374"
+	// S is in the lower half of the curve order
+	katLowS = "3045022100ae4da08155ff0ecfc614eeb2d4a71871f4d47f452ef984abf1dd1992be82537f" +
+		"02200ebcbad46497c15341c5a6bf7ac0b8a2d9a9ced1796e218baeaed0505912af2f"
+	// a valid signature, but S is in the upper half of the curve order
+	katHighS = "30460221009a0c5d4ac246ca408cbd6556a36023a07ddd1aa750f107d884b099468f188e4f" +
+		"022100ef4737e0c58d2466b0fe4134a5a538ae2f88b5e6598495dea0d4eff6f7b1ed75"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func Test_verifySecp256k1(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicKey string
+		digest    string
+		signature string
+		want      bool
+	}{
+		{name: "valid", publicKey: katPublicKey, digest: katDigest, signature: katLowS, want: true},
+		{name: "high S", publicKey: katPublicKey, digest: katDigest, signature: katHighS, want: false},
+		{name: "wrong key", publicKey: katOtherKey, digest: katDigest, signature: katLowS, want: false},
+		{
+			name: "wrong digest", publicKey: katPublicKey, digest: katDigest[:62] + "75", signature: katLowS,
+			want: false,
+		},
+		{
+			name: "uncompressed key", publicKey: "04" + katPublicKey[2:] + katPublicKey[2:], digest: katDigest,
+			signature: katLowS, want: false,
+		},
+		{name: "truncated DER", publicKey: katPublicKey, digest: katDigest, signature: katLowS[:len(katLowS)-2]},
+		{name: "trailing bytes", publicKey: katPublicKey, digest: katDigest, signature: katLowS + "00"},
+		{
+			name:      "wrong DER length",
+			publicKey: katPublicKey,
+			digest:    katDigest,
+			signature: "3046" + katLowS[4:],
+		},
+		{
+			name:      "non minimal R",
+			publicKey: katPublicKey,
+			digest:    katDigest,
+			signature: "30460222" + "00" + katLowS[8:],
+		},
+		{name: "empty", publicKey: katPublicKey, digest: katDigest, signature: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verifySecp256k1(
+				mustDecodeHex(t, tt.publicKey), mustDecodeHex(t, tt.digest), mustDecodeHex(t, tt.signature),
+			)
+			if got != tt.want {
+				t.Errorf("verifySecp256k1() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXrpl_VerifySignedMessage(t *testing.T) {
+	address, err := addresscodec.EncodeClassicAddressFromPublicKeyHex(katPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := addresscodec.EncodeClassicAddressFromPublicKeyHex(katOtherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(sha512Half([]byte(katMessage))) != katDigest {
+		t.Fatal("sha512Half() doesn't match the digest the vectors were signed over")
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", address: address, signature: katLowS},
+		{name: "key of another account", address: other, signature: katLowS, wantErr: true},
+		{name: "high S", address: address, signature: katHighS, wantErr: true},
+		{name: "not hex", address: address, signature: "zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Xrpl{}).VerifySignedMessage(
+				context.Background(), tt.address, []byte(katMessage), tt.signature, katPublicKey,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignedMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -72,3 +72,48 @@ type PricingAPIResponse struct {
 		Usd float64 `json:"usd"`
 	} `json:"ripple"`
 }
+
+type RPCRequest struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+type AccountLinesRequestParams struct {
+	Account     string      `json:"account"`
+	Peer        string      `json:"peer,omitempty"`
+	LedgerIndex string      `json:"ledger_index"`
+	Marker      interface{} `json:"marker,omitempty"`
+}
+
+type AccountLinesResponse struct {
+	Result struct {
+		Lines []struct {
+			Account  string `json:"account"`
+			Balance  string `json:"balance"`
+			Currency string `json:"currency"`
+		} `json:"lines"`
+		Marker interface{} `json:"marker"`
+		Status string      `json:"status"`
+		Error  string      `json:"error"`
+	} `json:"result"`
+}
+
+type AccountNFTsRequestParams struct {
+	Account     string      `json:"account"`
+	LedgerIndex string      `json:"ledger_index"`
+	Limit       int         `json:"limit"`
+	Marker      interface{} `json:"marker,omitempty"`
+}
+
+type AccountNFTsResponse struct {
+	Result struct {
+		AccountNFTs []struct {
+			Issuer       string `json:"Issuer"`
+			NFTokenID    string `json:"NFTokenID"`
+			NFTokenTaxon uint32 `json:"NFTokenTaxon"`
+		} `json:"account_nfts"`
+		Marker interface{} `json:"marker"`
+		Status string      `json:"status"`
+		Error  string      `json:"error"`
+	} `json:"result"`
+}
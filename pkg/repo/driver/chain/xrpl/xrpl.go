@@ -3,6 +3,9 @@ package xrpl
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,6 +22,7 @@ import (
 	"notiboy/utilities"
 	"notiboy/utilities/http_client"
 
+	addresscodec "github.com/xyield/xrpl-go/address-codec"
 	_ "github.com/xyield/xrpl-go/model/client/common"
 	_ "github.com/xyield/xrpl-go/model/client/transactions"
 
@@ -249,3 +253,47 @@ func (x *Xrpl) FetchValidBlock(_ context.Context) error {
 
 	return nil
 }
+
+// VerifySignedMessage verifies a message signed with the account master key, as done by the XRPL wallets'
+// message signing. The hex encoded public key must derive the address, ed25519 keys sign the raw message and
+// secp256k1 keys sign its SHA-512Half with a DER encoded signature.
+func (x *Xrpl) VerifySignedMessage(
+	_ context.Context, address string, message []byte, signature string, publicKey string,
+) error {
+	pub, err := hex.DecodeString(publicKey)
+	if err != nil || len(pub) != 33 {
+		return fmt.Errorf("invalid public key %s", publicKey)
+	}
+
+	derived, err := addresscodec.EncodeClassicAddressFromPublicKeyHex(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive address from public key: %w", err)
+	}
+	if derived != address {
+		return fmt.Errorf("public key belongs to %s instead of %s", derived, address)
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to hex decode signature: %w", err)
+	}
+
+	var valid bool
+	if pub[0] == 0xED {
+		valid = ed25519.Verify(pub[1:], message, sig)
+	} else {
+		valid = verifySecp256k1(pub, sha512Half(message), sig)
+	}
+
+	if !valid {
+		return fmt.Errorf("message signature validation failed")
+	}
+
+	return nil
+}
+
+// sha512Half returns the first half of the SHA-512 digest, the hash XRPL signs with
+func sha512Half(message []byte) []byte {
+	digest := sha512.Sum512(message)
+	return digest[:32]
+}
@@ -47,6 +47,12 @@ func NewCassandraSession(cfg config.DB) (*gocql.Session, error) {
 		return nil, err
 	}
 
+	if err = migrateColumns(session, cfg.Keyspace); err != nil {
+		return nil, err
+	}
+
+	go runDataMigrations(session, cfg.Keyspace)
+
 	return session, nil
 }
 
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// GetLinkedAccounts retrieves the accounts on other chains linked to the account of address.
+func GetLinkedAccounts(ctx context.Context, chain, address string) ([]entities.LinkedAccount, error) {
+	log := utilities.NewLoggerWithFields("GetLinkedAccounts", map[string]interface{}{
+		"chain":   chain,
+		"address": address,
+	})
+
+	var links = make([]entities.LinkedAccount, 0)
+
+	query := fmt.Sprintf(
+		`SELECT linked_chain, linked_address, shared_billing, billing_chain, billing_address, created
+	FROM %s.%s WHERE chain = ? AND address = ?`,
+		config.GetConfig().DB.Keyspace, consts.LinkedAccounts,
+	)
+
+	var (
+		link                         entities.LinkedAccount
+		billingChain, billingAddress string
+	)
+	iter := GetCassandraSession().Query(query, chain, address).WithContext(ctx).Iter()
+	for iter.Scan(
+		&link.Chain, &link.Address, &link.SharedBilling, &billingChain, &billingAddress, &link.Created,
+	) {
+		link.BillingAccount = nil
+		if link.SharedBilling {
+			link.BillingAccount = &entities.UserIdentifier{Chain: billingChain, Address: billingAddress}
+		}
+		links = append(links, link)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve linked accounts")
+		return nil, fmt.Errorf("failed to retrieve linked accounts: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetSharedBilling returns the link through which the account of address shares a billing balance,
+// nil when it pays for itself.
+func GetSharedBilling(ctx context.Context, chain, address string) (*entities.LinkedAccount, error) {
+	links, err := GetLinkedAccounts(ctx, chain, address)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range links {
+		if links[i].SharedBilling {
+			return &links[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ShareVerifiedMediums copies the verified email and Discord of from to the linked account to,
+// so that a medium verified once can be used by every account of the user.
+func ShareVerifiedMediums(ctx context.Context, from, to entities.UserIdentifier) error {
+	log := utilities.NewLoggerWithFields("ShareVerifiedMediums", map[string]interface{}{
+		"from": from.Chain + ":" + from.Address,
+		"to":   to.Chain + ":" + to.Address,
+	})
+
+	source, err := GetUserModel(ctx, from.Chain, from.Address)
+	if err != nil {
+		return err
+	}
+	target, err := GetUserModel(ctx, to.Chain, to.Address)
+	if err != nil {
+		return err
+	}
+
+	metadata := target.MediumMetadata
+	mediums := make([]string, 0, 2)
+
+	if email := source.MediumMetadata.Email; email != nil && email.Verified {
+		if metadata.Email == nil || *metadata.Email != *email {
+			shared := *email
+			metadata.Email = &shared
+			mediums = append(mediums, consts.Email)
+		}
+	}
+	if discord := source.MediumMetadata.Discord; discord != nil && discord.Verified {
+		if metadata.Discord == nil || *metadata.Discord != *discord {
+			shared := *discord
+			metadata.Discord = &shared
+			mediums = append(mediums, consts.Discord)
+		}
+	}
+
+	if len(mediums) == 0 {
+		return nil
+	}
+
+	metadataStr, err := metadata.Marshal()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET medium_metadata = ?, supported_mediums = supported_mediums + ?, allowed_mediums = allowed_mediums + ?
+	WHERE address = ? AND chain = ? IF EXISTS`,
+		config.GetConfig().DB.Keyspace, consts.UserInfo,
+	)
+	if err = GetCassandraSession().Query(query, metadataStr, mediums, mediums, to.Address, to.Chain).
+		WithContext(ctx).Exec(); err != nil {
+		log.WithError(err).Error("failed to share verified mediums")
+		return fmt.Errorf("failed to share verified mediums: %w", err)
+	}
+
+	log.Infof("Shared verified %s", strings.Join(mediums, ","))
+
+	return nil
+}
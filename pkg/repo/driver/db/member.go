@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/utilities"
+)
+
+// GetChannelRole returns the role of address in the channel, an empty string if it has none. The owner of the
+// channel has the owner role, other members get theirs once they accepted the invite.
+func GetChannelRole(ctx context.Context, chain, appID, address string) (string, error) {
+	keyspace := config.GetConfig().DB.Keyspace
+
+	var channels []string
+
+	query := fmt.Sprintf(`SELECT channels FROM %s.%s WHERE address = ? AND chain = ?`, keyspace, consts.UserInfo)
+	err := GetCassandraSession().Query(query, address, chain).WithContext(ctx).Scan(&channels)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return "", fmt.Errorf("failed to get channels of user: %w", err)
+	}
+	if utilities.ContainsString(channels, appID) {
+		return consts.ChannelRoleOwner, nil
+	}
+
+	var role, status string
+
+	query = fmt.Sprintf(
+		`SELECT role, status FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		keyspace, consts.ChannelMembers,
+	)
+	err = GetCassandraSession().Query(query, chain, appID, address).WithContext(ctx).Scan(&role, &status)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get channel member: %w", err)
+	}
+	if status != consts.MemberActive {
+		return "", nil
+	}
+
+	return role, nil
+}
+
+// GetChannelOwner returns the owner of the channel, whose membership applies to whatever the members of the
+// channel do.
+func GetChannelOwner(ctx context.Context, chain, appID string) (string, error) {
+	var owner string
+
+	for _, tbl := range []string{consts.VerifiedChannelInfo, consts.UnverifiedChannelInfo} {
+		query := fmt.Sprintf(
+			`SELECT owner FROM %s.%s WHERE chain = ? AND app_id = ?`, config.GetConfig().DB.Keyspace, tbl,
+		)
+		err := GetCassandraSession().Query(query, chain, appID).WithContext(ctx).Scan(&owner)
+		if err == nil {
+			return owner, nil
+		}
+		if !errors.Is(err, gocql.ErrNotFound) {
+			return "", fmt.Errorf("failed to get owner of channel %s: %w", appID, err)
+		}
+	}
+
+	return "", fmt.Errorf("channel %s not found", appID)
+}
+
+// VerifyChannelPermission returns an error unless the role of address in the channel grants permission
+func VerifyChannelPermission(ctx context.Context, chain, appID, address, permission string) error {
+	role, err := GetChannelRole(ctx, chain, appID, address)
+	if err != nil {
+		return err
+	}
+
+	return CheckChannelPermission(role, appID, address, permission)
+}
+
+// CheckChannelPermission returns an error unless role, the role of address in the channel, grants permission
+func CheckChannelPermission(role, appID, address, permission string) error {
+	if !utilities.ContainsString(consts.ChannelRolePermissions[role], permission) {
+		return fmt.Errorf("user %s lacks the %s permission on channel %s", address, permission, appID)
+	}
+
+	return nil
+}
+
+// DeleteChannelMembers removes all the members of a deleted channel
+func DeleteChannelMembers(ctx context.Context, chain, appID string) error {
+	keyspace := config.GetConfig().DB.Keyspace
+
+	query := fmt.Sprintf(`SELECT address FROM %s.%s WHERE chain = ? AND app_id = ?`, keyspace, consts.ChannelMembers)
+	deleteQuery := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND address = ? AND app_id = ?`, keyspace, consts.UserChannelRoles,
+	)
+
+	var address string
+	iter := GetCassandraSession().Query(query, chain, appID).WithContext(ctx).Iter()
+	for iter.Scan(&address) {
+		if err := GetCassandraSession().Query(deleteQuery, chain, address, appID).WithContext(ctx).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete channel role of %s: %w", address, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to list channel members: %w", err)
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, keyspace, consts.ChannelMembers)
+	if err := GetCassandraSession().Query(query, chain, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete channel members: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,606 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/consts"
+	"notiboy/utilities"
+)
+
+// dataMigrationLease is how long an instance has to apply a data migration before another one may take it over
+const dataMigrationLease = 30 * time.Minute
+
+// columnMigration adds a column to a table that existed before the column was introduced, CREATE TABLE IF NOT
+// EXISTS leaves the tables of existing deployments as they are
+type columnMigration struct {
+	table  string
+	column string
+	kind   string
+}
+
+var dbColumnMigrations = []columnMigration{
+	{consts.ChannelSentReadMetrics, "recalled", "int"},
+	{consts.NotificationInfo, "edited_time", "timestamp"},
+	{consts.ChannelSentReadMetrics, "clicked", "int"},
+	{consts.UserInfo, "tracking_opt_out", "boolean"},
+	{consts.Sessions, "previous_refresh_token", "text"},
+	{consts.PATInfo, "scopes", "set<text>"},
+	{consts.PATInfo, "expires_at", "timestamp"},
+	{consts.PATInfo, "last_used", "timestamp"},
+	{consts.PATInfo, "last_used_ip", "text"},
+	{consts.UserInfo, "display_name", "text"},
+	{consts.UserInfo, "avatar", "text"},
+	{consts.UserInfo, "bio", "text"},
+	{consts.UserInfo, "locale", "text"},
+	{consts.UserInfo, "timezone", "text"},
+	{consts.UserInfo, "profile_privacy", "map<text, text>"},
+	{consts.VerifiedChannelInfo, "domain", "varchar"},
+	{consts.UnverifiedChannelInfo, "domain", "varchar"},
+	{consts.VerifiedChannelInfo, "visibility", "varchar"},
+	{consts.UnverifiedChannelInfo, "visibility", "varchar"},
+	{consts.VerifiedChannelInfo, "category", "varchar"},
+	{consts.UnverifiedChannelInfo, "category", "varchar"},
+	{consts.VerifiedChannelInfo, "tags", "set<text>"},
+	{consts.UnverifiedChannelInfo, "tags", "set<text>"},
+	{consts.VerifiedChannelInfo, "tags_curated", "boolean"},
+	{consts.UnverifiedChannelInfo, "tags_curated", "boolean"},
+	{consts.ChannelSubscribers, "source", "text"},
+	{consts.ChannelOptinRequests, "source", "text"},
+}
+
+// migrateColumns adds the columns of dbColumnMigrations missing from the tables of keyspace
+func migrateColumns(session *gocql.Session, keyspace string) error {
+	existing := make(map[string]map[string]bool)
+
+	for _, migration := range dbColumnMigrations {
+		columns, ok := existing[migration.table]
+		if !ok {
+			var err error
+			if columns, err = tableColumns(session, keyspace, migration.table); err != nil {
+				return err
+			}
+			existing[migration.table] = columns
+		}
+
+		if columns[migration.column] {
+			continue
+		}
+
+		alterCmd := fmt.Sprintf(
+			`ALTER TABLE %s.%s ADD %s %s`, keyspace, migration.table, migration.column, migration.kind,
+		)
+		if err := session.Query(alterCmd).Exec(); err != nil {
+			return fmt.Errorf("failed to exec query for db column migration, CMD: %s: %w", alterCmd, err)
+		}
+		columns[migration.column] = true
+	}
+
+	return nil
+}
+
+// tableColumns returns the names of the columns table has
+func tableColumns(session *gocql.Session, keyspace, table string) (map[string]bool, error) {
+	columns := make(map[string]bool)
+
+	var column string
+	iter := session.Query(
+		`SELECT column_name FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?`, keyspace, table,
+	).Iter()
+	for iter.Scan(&column) {
+		columns[column] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get columns of %s: %w", table, err)
+	}
+
+	return columns, nil
+}
+
+// dataMigration rewrites existing rows once, for example to backfill a table introduced after the data it derives
+// from. Data migrations must be idempotent, one taken over once its lease ran out is applied again.
+type dataMigration struct {
+	name string
+	run  func(session *gocql.Session, keyspace string) error
+}
+
+var dbDataMigrations = []dataMigration{
+	{"backfill_channel_subscribers", backfillChannelSubscribers},
+	{"backfill_user_verifications", backfillUserVerifications},
+	{"backfill_user_login_tokens", backfillUserLoginTokens},
+	{"backfill_user_subscriptions", backfillUserSubscriptions},
+	{"backfill_user_notifications", backfillUserNotifications},
+	{"backfill_user_scheduled_notifications", backfillUserScheduledNotifications},
+	{"backfill_user_chat_blocked_by", backfillUserChatBlockedBy},
+	{"backfill_verification_request_indexes", backfillVerificationRequestIndexes},
+	{"backfill_user_channel_invites", backfillUserChannelInvites},
+	{"backfill_user_optin_requests", backfillUserOptinRequests},
+	{"copy_webhook_deliveries", copyWebhookDeliveries},
+	{"backfill_channel_transfers_by_target", backfillTransfersByTarget},
+	{"backfill_sent_notifications_by_time", backfillSentNotificationsByTime},
+	{"backfill_channel_subscribers_by_optin", backfillSubscribersByOptin},
+}
+
+// runDataMigrations applies the data migrations of dbDataMigrations no instance applied yet, in order. Each is
+// claimed first so that instances starting together don't apply it twice, and the ones after a migration another
+// instance is applying are left to that instance.
+func runDataMigrations(session *gocql.Session, keyspace string) {
+	log := utilities.NewLogger("runDataMigrations")
+
+	for _, migration := range dbDataMigrations {
+		claimed, completed, err := claimDataMigration(session, keyspace, migration.name)
+		if err != nil {
+			log.WithError(err).Errorf("failed to claim data migration %s", migration.name)
+			return
+		}
+		if completed {
+			continue
+		}
+		if !claimed {
+			log.Infof("Data migration %s is being applied by another instance", migration.name)
+			return
+		}
+
+		log.Infof("Applying data migration %s", migration.name)
+		if err = migration.run(session, keyspace); err != nil {
+			// the claim runs out and the migration is applied again by the next instance to start
+			log.WithError(err).Errorf("failed to apply data migration %s", migration.name)
+			return
+		}
+
+		query := fmt.Sprintf(
+			`UPDATE %s.%s SET completed_time = ? WHERE name = ?`, keyspace, consts.SchemaMigrations,
+		)
+		if err = session.Query(query, time.Now(), migration.name).Exec(); err != nil {
+			log.WithError(err).Errorf("failed to complete data migration %s", migration.name)
+			return
+		}
+		log.Infof("Applied data migration %s", migration.name)
+	}
+}
+
+// claimDataMigration claims the data migration for dataMigrationLease, unless it is completed or claimed by another
+// instance whose lease hasn't run out
+func claimDataMigration(session *gocql.Session, keyspace, name string) (claimed, completed bool, err error) {
+	now := time.Now()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (name, lease_until) VALUES (?, ?) IF NOT EXISTS`, keyspace, consts.SchemaMigrations,
+	)
+	existing := make(map[string]interface{})
+	claimed, err = session.Query(query, name, now.Add(dataMigrationLease)).MapScanCAS(existing)
+	if err != nil || claimed {
+		return claimed, false, err
+	}
+
+	if completedTime, _ := existing["completed_time"].(time.Time); !completedTime.IsZero() {
+		return false, true, nil
+	}
+
+	leaseUntil, _ := existing["lease_until"].(time.Time)
+	if now.Before(leaseUntil) {
+		return false, false, nil
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s.%s SET lease_until = ? WHERE name = ? IF lease_until = ?`, keyspace, consts.SchemaMigrations,
+	)
+	claimed, err = session.Query(query, now.Add(dataMigrationLease), name, leaseUntil).
+		MapScanCAS(make(map[string]interface{}))
+
+	return claimed, false, err
+}
+
+// backfillChannelSubscribers adds the users who opted in before channel_subscribers was introduced to it. Their
+// opt-in time wasn't recorded and is left unset.
+func backfillChannelSubscribers(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, app_id, users FROM %s.%s`, keyspace, consts.ChannelUsers)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, address) VALUES (?, ?, ?)`, keyspace, consts.ChannelSubscribers,
+	)
+
+	var (
+		chain string
+		appID string
+		users []string
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &users) {
+		// the subscribers of a channel share a partition
+		for _, chunk := range utilities.Chunk(users, consts.ReceiverBatchSize) {
+			batch := session.NewBatch(gocql.UnloggedBatch)
+			for _, user := range chunk {
+				batch.Query(insertQuery, chain, appID, user)
+			}
+
+			if err := session.ExecuteBatch(batch); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to backfill subscribers of %s: %w", appID, err)
+			}
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserVerifications indexes the verifications made before user_verifications was introduced by user.
+// verify_info only holds verifications which haven't expired yet, so it is scanned once.
+func backfillUserVerifications(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT "token", address, chain, TTL(medium) FROM %s.%s`, keyspace, consts.VerifyInfo)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, "token") VALUES (?, ?, ?) USING TTL ?`,
+		keyspace, consts.UserVerifications,
+	)
+
+	var (
+		token   string
+		address string
+		chain   string
+		ttl     int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&token, &address, &chain, &ttl) {
+		if err := session.Query(insertQuery, chain, address, token, ttl).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill verification of %s: %w", address, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserLoginTokens indexes the legacy login tokens by user. login_info isn't written anymore and only holds
+// tokens which haven't expired yet, so it is scanned once.
+func backfillUserLoginTokens(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT address, chain, jwt, TTL("created") FROM %s.%s`, keyspace, consts.LoginInfo)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, jwt) VALUES (?, ?, ?) USING TTL ?`, keyspace, consts.UserLoginTokens,
+	)
+
+	var (
+		address string
+		chain   string
+		jwt     string
+		ttl     int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&address, &chain, &jwt, &ttl) {
+		if err := session.Query(insertQuery, chain, address, jwt, ttl).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill login token of %s: %w", address, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserSubscriptions indexes the subscribers recorded before user_subscriptions was introduced by user
+func backfillUserSubscriptions(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, app_id, address FROM %s.%s`, keyspace, consts.ChannelSubscribers)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, app_id) VALUES (?, ?, ?)`, keyspace, consts.UserSubscriptions,
+	)
+
+	var (
+		chain   string
+		appID   string
+		address string
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &address) {
+		if err := session.Query(insertQuery, chain, address, appID).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill subscription of %s: %w", address, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserNotifications indexes the notifications sent before user_notifications was introduced by receiver.
+// The inbox holds a row for every receiver of a notification which hasn't expired yet.
+func backfillUserNotifications(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(
+		`SELECT chain, receiver, app_id, uuid, TTL(message) FROM %s.%s`, keyspace, consts.NotificationInfo,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, receiver, app_id, uuid) VALUES (?, ?, ?, ?) USING TTL ?`,
+		keyspace, consts.UserNotifications,
+	)
+
+	var (
+		chain    string
+		receiver string
+		appID    string
+		uuid     string
+		ttl      int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &receiver, &appID, &uuid, &ttl) {
+		if err := session.Query(insertQuery, chain, receiver, appID, uuid, ttl).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill notification %s of %s: %w", uuid, receiver, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserScheduledNotifications indexes the pending scheduled notifications by their sender and receivers
+func backfillUserScheduledNotifications(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(
+		`SELECT chain, schedule, sender, receivers, TTL(app_id) FROM %s.%s`, keyspace, consts.ScheduledNotificationInfo,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, schedule, sender) VALUES (?, ?, ?, ?) USING TTL ?`,
+		keyspace, consts.UserScheduledNotifications,
+	)
+
+	var (
+		chain     string
+		schedule  time.Time
+		sender    string
+		receivers []string
+		ttl       int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &schedule, &sender, &receivers, &ttl) {
+		for _, user := range append([]string{sender}, receivers...) {
+			if err := session.Query(insertQuery, chain, user, schedule, sender, ttl).Exec(); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to backfill scheduled notification of %s: %w", user, err)
+			}
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserChatBlockedBy indexes the chat block lists by the blocked users
+func backfillUserChatBlockedBy(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, user, blocked_users FROM %s.%s`, keyspace, consts.ChatUserBlockTable)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, user, blocked_by) VALUES (?, ?, ?)`, keyspace, consts.ChatUserBlockedByTable,
+	)
+
+	var (
+		chain   string
+		user    string
+		blocked []string
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &user, &blocked) {
+		for _, blockedUser := range blocked {
+			if err := session.Query(insertQuery, chain, blockedUser, user).Exec(); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to backfill block of %s by %s: %w", blockedUser, user, err)
+			}
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillVerificationRequestIndexes indexes the requests to verify channels made before they were indexed by
+// status and by requester
+func backfillVerificationRequestIndexes(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, app_id, requested_by, status FROM %s.%s`, keyspace, consts.ChannelVerifications)
+	statusQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, status, app_id) VALUES (?, ?, ?)`, keyspace, consts.VerificationsByStatus,
+	)
+	requesterQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, requested_by, app_id) VALUES (?, ?, ?)`,
+		keyspace, consts.UserVerificationRequests,
+	)
+
+	var (
+		chain       string
+		appID       string
+		requestedBy string
+		status      string
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &requestedBy, &status) {
+		if err := session.Query(statusQuery, chain, status, appID).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill status of verification of %s: %w", appID, err)
+		}
+		if requestedBy == "" {
+			continue
+		}
+		if err := session.Query(requesterQuery, chain, requestedBy, appID).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill requester of verification of %s: %w", appID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserChannelInvites indexes the invites created before they were indexed by their creator
+func backfillUserChannelInvites(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, app_id, code, created_by FROM %s.%s`, keyspace, consts.ChannelInvites)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, created_by, app_id, code) VALUES (?, ?, ?, ?)`, keyspace, consts.UserChannelInvites,
+	)
+
+	var chain, appID, code, createdBy string
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &code, &createdBy) {
+		if createdBy == "" {
+			continue
+		}
+		if err := session.Query(insertQuery, chain, createdBy, appID, code).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill invite %s of %s: %w", code, appID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillUserOptinRequests indexes the requests to opt in made before they were indexed by the requester
+func backfillUserOptinRequests(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(`SELECT chain, app_id, address FROM %s.%s`, keyspace, consts.ChannelOptinRequests)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, app_id) VALUES (?, ?, ?)`, keyspace, consts.UserOptinRequests,
+	)
+
+	var chain, appID, address string
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &address) {
+		if err := session.Query(insertQuery, chain, address, appID).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill optin request of %s to %s: %w", address, appID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// copyWebhookDeliveries copies the delivery log kept by channel before it was kept by webhook, the rows keep the
+// time they have left to live. The log of a keyspace created after that has nothing to copy.
+func copyWebhookDeliveries(session *gocql.Session, keyspace string) error {
+	const legacyTable = "webhook_deliveries"
+
+	columns, err := tableColumns(session, keyspace, legacyTable)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, created_time, id, webhook_id, event, payload, attempts, status_code, success, error,
+	updated_time, TTL(updated_time) FROM %s.%s`,
+		keyspace, legacyTable,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, created_time, id, webhook_id, event, payload, attempts, status_code, success,
+	error, updated_time) VALUES %s USING TTL ?`,
+		keyspace, consts.ChannelWebhookDeliveries, utilities.DBMultiValuePlaceholders(12),
+	)
+
+	var (
+		chain, appID, id, webhookID, event, payload, deliveryErr string
+		attempts, statusCode, ttl                                int
+		success                                                  bool
+		createdTime, updatedTime                                 time.Time
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(
+		&chain, &appID, &createdTime, &id, &webhookID, &event, &payload, &attempts, &statusCode, &success,
+		&deliveryErr, &updatedTime, &ttl,
+	) {
+		err = session.Query(
+			insertQuery, chain, appID, createdTime, id, webhookID, event, payload, attempts, statusCode, success,
+			deliveryErr, updatedTime, ttl,
+		).Exec()
+		if err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to copy delivery %s of webhook %s: %w", id, webhookID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillTransfersByTarget indexes the pending changes of owner made before they were indexed by their target, the
+// rows expire along with the transfer
+func backfillTransfersByTarget(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, kind, from_address, to_address, reason, created, TTL(created) FROM %s.%s`,
+		keyspace, consts.ChannelTransfers,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, kind, to_address, app_id, from_address, reason, created) VALUES %s USING TTL ?`,
+		keyspace, consts.TransfersByTarget, utilities.DBMultiValuePlaceholders(7),
+	)
+
+	var (
+		chain, appID, kind, from, to, reason string
+		created                              time.Time
+		ttl                                  int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &kind, &from, &to, &reason, &created, &ttl) {
+		err := session.Query(insertQuery, chain, kind, to, appID, from, reason, created, ttl).Exec()
+		if err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill channel transfer of %s: %w", appID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillSentNotificationsByTime indexes the notifications sent before they were indexed by the time they were
+// sent, the rows expire along with the notification
+func backfillSentNotificationsByTime(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, uuid, created_time, TTL(message) FROM %s.%s`, keyspace, consts.SentNotificationInfo,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, created_time, uuid) VALUES (?, ?, ?, ?) USING TTL ?`,
+		keyspace, consts.SentNotificationsByTime,
+	)
+
+	var (
+		chain, appID, uuid string
+		createdTime        time.Time
+		ttl                int
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &uuid, &createdTime, &ttl) {
+		if err := session.Query(insertQuery, chain, appID, createdTime, uuid, ttl).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill notification %s of %s: %w", uuid, appID, err)
+		}
+	}
+
+	return iter.Close()
+}
+
+// backfillSubscribersByOptin indexes the users opted in to a channel by the time they opted in. The users who
+// opted out, or opted in before opt-in times were recorded, are left out.
+func backfillSubscribersByOptin(session *gocql.Session, keyspace string) error {
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, address, optin_time, optout_time FROM %s.%s`, keyspace, consts.ChannelSubscribers,
+	)
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, optin_time, address) VALUES (?, ?, ?, ?)`,
+		keyspace, consts.SubscribersByOptin,
+	)
+
+	var (
+		chain, appID, address string
+		optinTime, optoutTime time.Time
+	)
+
+	iter := session.Query(query).Iter()
+	for iter.Scan(&chain, &appID, &address, &optinTime, &optoutTime) {
+		if optinTime.IsZero() || !optoutTime.IsZero() {
+			continue
+		}
+
+		if err := session.Query(insertQuery, chain, appID, optinTime, address).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to backfill subscriber %s of %s: %w", address, appID, err)
+		}
+	}
+
+	return iter.Close()
+}
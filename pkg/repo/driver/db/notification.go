@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// StoreDiscordMessageRef records the discord DM channel and message id of a sent notification
+// so that the message can be deleted if the notification is recalled.
+func StoreDiscordMessageRef(
+	ctx context.Context, chain, appID, uuid, receiver, dmChannelID, messageID string, ttl int,
+) error {
+	log := utilities.NewLoggerWithFields("StoreDiscordMessageRef", map[string]interface{}{
+		"chain":    chain,
+		"app_id":   appID,
+		"uuid":     uuid,
+		"receiver": receiver,
+	})
+
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.SentNotificationInfo)
+	query := fmt.Sprintf(
+		"UPDATE %s USING TTL %d SET discord_messages[?] = ? WHERE chain = ? AND app_id = ? AND uuid = ?", tbl, ttl,
+	)
+
+	err := GetCassandraSession().Query(
+		query, receiver, FormatDiscordMessageRef(dmChannelID, messageID), chain, appID, uuid,
+	).WithContext(ctx).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to store discord message reference")
+		return fmt.Errorf("failed to store discord message reference: %w", err)
+	}
+
+	return nil
+}
+
+// FormatDiscordMessageRef joins the discord DM channel id and message id into a single reference
+func FormatDiscordMessageRef(dmChannelID, messageID string) string {
+	return fmt.Sprintf("%s/%s", dmChannelID, messageID)
+}
+
+// InsertNotificationReach records a reach event of a notification for a receiver on a medium.
+// Only the first occurrence of an event is recorded so that time-to-first-read is not shifted by later reads,
+// the returned bool reports whether this was the first occurrence.
+func InsertNotificationReach(ctx context.Context, event entities.NotificationReachEvent) (bool, error) {
+	log := utilities.NewLoggerWithFields("InsertNotificationReach", map[string]interface{}{
+		"chain":  event.Chain,
+		"app_id": event.Channel,
+		"uuid":   event.UUID,
+		"medium": event.Medium,
+		"event":  event.Event,
+	})
+
+	if event.EventTime.IsZero() {
+		event.EventTime = utilities.TimeNow()
+	}
+
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.NotificationReach)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, app_id, uuid, medium, event, receiver, event_time) VALUES %s IF NOT EXISTS USING TTL %d`,
+		tbl, utilities.DBMultiValuePlaceholders(7), config.GetConfig().TTL.Metrics,
+	)
+
+	applied, err := GetCassandraSession().Query(
+		query, event.Chain, event.Channel, event.UUID, event.Medium, event.Event, event.Receiver, event.EventTime,
+	).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		log.WithError(err).Error("failed to insert notification reach")
+		return false, fmt.Errorf("failed to insert notification reach: %w", err)
+	}
+
+	return applied, nil
+}
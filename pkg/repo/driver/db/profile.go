@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// ProfileColumns are the user_info columns of a profile, in the order ProfileDest scans them
+const ProfileColumns = "display_name, avatar, bio, locale, timezone, profile_privacy"
+
+// ProfileDest returns the scan destinations of ProfileColumns for profile
+func ProfileDest(profile *entities.UserProfile) []interface{} {
+	return []interface{}{
+		&profile.DisplayName, &profile.Avatar, &profile.Bio, &profile.Locale, &profile.Timezone, &profile.Privacy,
+	}
+}
+
+// GetUserProfiles retrieves the profiles of addresses as viewer gets to see them, keyed by address. Users
+// without a profile are left out. The profiles are looked up consts.ReceiverBatchSize addresses at a time.
+func GetUserProfiles(
+	ctx context.Context, chain, viewer string, addresses []string,
+) (map[string]*entities.UserProfile, error) {
+	profiles := make(map[string]*entities.UserProfile)
+
+	query := fmt.Sprintf(
+		`SELECT address, %s FROM %s.%s WHERE chain = ? AND address IN ?`,
+		ProfileColumns, config.GetConfig().DB.Keyspace, consts.UserInfo,
+	)
+
+	for _, chunk := range utilities.Chunk(addresses, consts.ReceiverBatchSize) {
+		var address string
+		profile := new(entities.UserProfile)
+
+		iter := GetCassandraSession().Query(query, chain, chunk).WithContext(ctx).Iter()
+		for iter.Scan(append([]interface{}{&address}, ProfileDest(profile)...)...) {
+			if !profile.IsEmpty() {
+				profiles[address] = profile
+				profile = new(entities.UserProfile)
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to get user profiles: %w", err)
+		}
+	}
+
+	return ProfilesVisibleTo(ctx, chain, viewer, profiles)
+}
+
+// ProfilesVisibleTo narrows profiles, keyed by address, down to what viewer gets to see of them. Contacts are
+// added to both users at once, so whether viewer is a contact of a user is found in the contacts of viewer.
+func ProfilesVisibleTo(
+	ctx context.Context, chain, viewer string, profiles map[string]*entities.UserProfile,
+) (map[string]*entities.UserProfile, error) {
+	var contacts []string
+
+	if sharesWithContacts(viewer, profiles) {
+		query := fmt.Sprintf(
+			`SELECT contacts FROM %s.%s WHERE chain = ? AND user = ?`,
+			config.GetConfig().DB.Keyspace, consts.ChatUserContactsTable,
+		)
+
+		err := GetCassandraSession().Query(query, chain, viewer).WithContext(ctx).Scan(&contacts)
+		if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get contacts: %w", err)
+		}
+	}
+
+	return visibleProfiles(viewer, contacts, profiles), nil
+}
+
+// sharesWithContacts tells whether a profile of another user than viewer shares a field with contacts
+func sharesWithContacts(viewer string, profiles map[string]*entities.UserProfile) bool {
+	for address, profile := range profiles {
+		if address == viewer {
+			continue
+		}
+		for field := range consts.DefaultProfilePrivacy {
+			if profile.Visibility(field) == consts.ProfileContacts {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// visibleProfiles narrows the profiles of users other than viewer down to their fields viewer, who has contacts,
+// gets to see
+func visibleProfiles(
+	viewer string, contacts []string, profiles map[string]*entities.UserProfile,
+) map[string]*entities.UserProfile {
+	for address, profile := range profiles {
+		if address == viewer {
+			continue
+		}
+		profiles[address] = profile.VisibleTo(utilities.ContainsString(contacts, address))
+	}
+
+	return profiles
+}
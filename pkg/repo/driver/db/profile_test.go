@@ -0,0 +1,74 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+func Test_visibleProfiles(t *testing.T) {
+	public := map[string]string{consts.ProfileLocale: consts.ProfilePublic, consts.ProfileTimezone: consts.ProfilePublic}
+
+	tests := []struct {
+		name         string
+		contacts     []string
+		profiles     map[string]*entities.UserProfile
+		want         map[string]*entities.UserProfile
+		wantContacts bool
+	}{
+		{
+			name: "own profile",
+			profiles: map[string]*entities.UserProfile{
+				"alice": {DisplayName: "Alice", Timezone: "UTC"},
+			},
+			want: map[string]*entities.UserProfile{
+				"alice": {DisplayName: "Alice", Timezone: "UTC"},
+			},
+		},
+		{
+			name:     "profile of a contact",
+			contacts: []string{"bob"},
+			profiles: map[string]*entities.UserProfile{
+				"bob": {DisplayName: "Bob", Timezone: "UTC"},
+			},
+			want: map[string]*entities.UserProfile{
+				"bob": {DisplayName: "Bob", Timezone: "UTC"},
+			},
+			wantContacts: true,
+		},
+		{
+			name:     "profile of a stranger",
+			contacts: []string{"bob"},
+			profiles: map[string]*entities.UserProfile{
+				"carol": {DisplayName: "Carol", Timezone: "UTC"},
+			},
+			want: map[string]*entities.UserProfile{
+				"carol": {DisplayName: "Carol"},
+			},
+			wantContacts: true,
+		},
+		{
+			name: "public profile",
+			profiles: map[string]*entities.UserProfile{
+				"carol": {DisplayName: "Carol", Timezone: "UTC", Privacy: public},
+			},
+			want: map[string]*entities.UserProfile{
+				"carol": {DisplayName: "Carol", Timezone: "UTC"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := sharesWithContacts("alice", tt.profiles); got != tt.wantContacts {
+					t.Errorf("sharesWithContacts() = %v, want %v", got, tt.wantContacts)
+				}
+				if got := visibleProfiles("alice", tt.contacts, tt.profiles); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("visibleProfiles() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -9,34 +9,67 @@ var dbTableSchemas = map[string]string{
 	consts.ChannelName:                         channelNameSchema,
 	consts.ChannelActivityMetrics:              channeActivityMetricsSchema,
 	consts.ChannelTractionMetrics:              channelTractionMetricsSchema,
+	consts.ChannelSourceMetrics:                channelSourceMetricsSchema,
 	consts.ChannelUsers:                        channelUsersMetricsSchema,
+	consts.ChannelSubscribers:                  channelSubscribersSchema,
+	consts.SubscribersByOptin:                  subscribersByOptinSchema,
+	consts.UserSubscriptions:                   userSubscriptionsSchema,
 	consts.NotificationInfo:                    notificationInfoSchema,
 	consts.ScheduledNotificationInfo:           scheduledNotificationInfoSchema,
 	consts.UserActivityMetrics:                 userActivityMetricsSchema,
 	consts.UserInfo:                            userInfoSchema,
 	consts.VerifyInfo:                          verifyInfoSchema,
+	consts.UserVerifications:                   userVerificationsSchema,
 	consts.GlobalStatistics:                    globalStatsSchema,
 	consts.LoginInfo:                           loginInfoSchema,
+	consts.UserLoginTokens:                     userLoginTokensSchema,
+	consts.LoginNonce:                          loginNonceSchema,
+	consts.Sessions:                            sessionSchema,
 	consts.PATInfo:                             patSchema,
 	consts.NotificationChannelCounter:          notificationChannelCounterSchema,
 	consts.NotificationTotalSendPerUserMetrics: notificationTotalSendPerUserMetricsSchema,
 	consts.NotificationChannelMetrics:          notificationChannelMetricsSchema,
 	consts.ChannelSentReadMetrics:              channelSentReadMetricsSchema,
-	consts.NotificationTotalSent:               notificationTotalSentSchema,
-	consts.NotificationDiscordMediumReach:      notificationDiscordMediumReachSchema,
-	consts.NotificationEmailMediumReach:        notificationEmailMediumReachSchema,
-	consts.NotificationAppMediumReach:          notificationAppMediumReachSchema,
+	consts.NotificationReach:                   notificationReachSchema,
 	consts.NotificationReadStatus:              notificationReadStatusSchema,
+	consts.SentNotificationInfo:                sentNotificationInfoSchema,
+	consts.SentNotificationsByTime:             sentNotificationsByTimeSchema,
+	consts.UserNotifications:                   userNotificationsSchema,
+	consts.UserScheduledNotifications:          userScheduledNotificationsSchema,
+	consts.NotificationRevisions:               notificationRevisionsSchema,
+	consts.ChannelWebhooks:                     channelWebhooksSchema,
+	consts.ChannelWebhookDeliveries:            channelWebhookDeliveriesSchema,
 	consts.BillingHistoryTable:                 billingHistorySchema,
 	consts.BillingTable:                        billingSchema,
 	consts.FcmTable:                            fcmSchema,
 	consts.ChatUserTable:                       userChatSchema,
 	consts.ChatUserBlockTable:                  userChatBlockSchema,
 	consts.ChatUserContactsTable:               userChatContactsSchema,
+	consts.ChatUserBlockedByTable:              userChatBlockedBySchema,
 	consts.ChatGroupInfoTable:                  groupChatInfoSchema,
 	consts.ChatGroupTable:                      groupChatSchema,
 	consts.ChatUserGroupTable:                  userChatGroupSchema,
 	consts.UserDNSTable:                        userDNSSchema,
+	consts.LinkedAccounts:                      linkedAccountsSchema,
+	consts.AccountDeletions:                    accountDeletionsSchema,
+	consts.ChannelMembers:                      channelMembersSchema,
+	consts.UserChannelRoles:                    userChannelRolesSchema,
+	consts.ChannelTransfers:                    channelTransfersSchema,
+	consts.TransfersByTarget:                   transfersByTargetSchema,
+	consts.ChannelVerifications:                channelVerificationsSchema,
+	consts.VerificationsByStatus:               verificationsByStatusSchema,
+	consts.UserVerificationRequests:            userVerificationRequestsSchema,
+	consts.ChannelDomains:                      channelDomainsSchema,
+	consts.ChannelInvites:                      channelInvitesSchema,
+	consts.ChannelOptinRequests:                channelOptinRequestsSchema,
+	consts.UserChannelInvites:                  userChannelInvitesSchema,
+	consts.UserOptinRequests:                   userOptinRequestsSchema,
+	consts.ChannelTokenGates:                   channelTokenGatesSchema,
+	consts.ChannelCategoryIndex:                channelCategoryIndexSchema,
+	consts.ChannelTagIndex:                     channelTagIndexSchema,
+	consts.ChannelRankings:                     channelRankingsSchema,
+	consts.SchemaMigrations:                    schemaMigrationsSchema,
+	consts.WebhookOutbox:                       webhookOutboxSchema,
 }
 
 var channeActivityMetricsSchema = `
@@ -70,6 +103,11 @@ CREATE TABLE IF NOT EXISTS  %s.verified_channel_info (
 chain varchar,
 app_id varchar,
 description varchar,
+domain varchar,
+visibility varchar,
+category varchar,
+tags set<text>,
+tags_curated boolean,
 logo varchar,
 name varchar,
 owner varchar,
@@ -85,6 +123,11 @@ CREATE TABLE IF NOT EXISTS  %s.unverified_channel_info (
 chain varchar,
 app_id varchar,
 description varchar,
+domain varchar,
+visibility varchar,
+category varchar,
+tags set<text>,
+tags_curated boolean,
 logo varchar,
 name varchar,
 owner varchar,
@@ -116,6 +159,20 @@ PRIMARY KEY ((chain, channel), event_date, event_time)
 ) WITH CLUSTERING ORDER BY (event_date desc, event_time desc)
 `
 
+// Opt-ins and opt-outs of a channel by the source the subscriber came from
+var channelSourceMetricsSchema = `
+CREATE TABLE IF NOT EXISTS  %s.channel_source_metrics (
+chain varchar,
+channel varchar,
+event_date text,
+source text,
+event_time timestamp,
+optin int,
+optout int,
+PRIMARY KEY ((chain, channel), event_date, source, event_time)
+) WITH CLUSTERING ORDER BY (event_date desc, source asc, event_time desc)
+`
+
 var channelUsersMetricsSchema = `
 CREATE TABLE IF NOT EXISTS  %s.channel_users (
 chain varchar,
@@ -125,6 +182,41 @@ PRIMARY KEY (app_id, chain)
 )
 `
 
+// Tracks when each user opted in to and out of a channel
+var channelSubscribersSchema = `
+CREATE TABLE IF NOT EXISTS  %s.channel_subscribers (
+chain varchar,
+app_id varchar,
+address varchar,
+optin_time timestamp,
+optout_time timestamp,
+source text,
+PRIMARY KEY ((chain, app_id), address)
+)
+`
+
+// Users opted in to a channel by the time they opted in, a row is removed once the user opts out. Users who opted
+// in before opt-in times were recorded aren't in it.
+var subscribersByOptinSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_subscribers_by_optin (
+chain varchar,
+app_id varchar,
+optin_time timestamp,
+address varchar,
+PRIMARY KEY ((chain, app_id), optin_time, address)
+) WITH CLUSTERING ORDER BY (optin_time DESC, address ASC)
+`
+
+// Channels a user subscribed to, channel_subscribers is partitioned by channel
+var userSubscriptionsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_subscriptions (
+chain varchar,
+address varchar,
+app_id varchar,
+PRIMARY KEY ((chain, address), app_id)
+)
+`
+
 var userActivityMetricsSchema = `
 CREATE TABLE IF NOT EXISTS  %s.user_activity_metrics (
 chain varchar,
@@ -150,6 +242,13 @@ modified timestamp,
 optins set<TEXT>,
 status varchar,
 supported_mediums set<TEXT>,
+tracking_opt_out boolean,
+display_name text,
+avatar text,
+bio text,
+locale text,
+timezone text,
+profile_privacy map<text, text>,
 PRIMARY KEY (address, chain)
 ) WITH CLUSTERING ORDER BY (chain asc)
 `
@@ -178,6 +277,17 @@ PRIMARY KEY (("token", address, chain))
 )
 `
 
+// Tokens of the medium verifications of a user, verify_info is keyed by token. Rows expire along with the
+// verify_info row of their token.
+var userVerificationsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_verifications (
+chain text,
+address text,
+"token" text,
+PRIMARY KEY ((chain, address), "token")
+)
+`
+
 var globalStatsSchema = `
 CREATE TABLE IF NOT EXISTS %s.global_stats (
 chain TEXT,
@@ -198,6 +308,46 @@ PRIMARY KEY ((address,chain,jwt))
 )
 `
 
+// Legacy login tokens of a user, login_info is keyed by token. Rows expire along with the login_info row of
+// their token.
+var userLoginTokensSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_login_tokens (
+chain text,
+address text,
+jwt text,
+PRIMARY KEY ((chain, address), jwt)
+)
+`
+
+// One-time sign-in challenges, removed as soon as they are used
+var loginNonceSchema = `
+CREATE TABLE IF NOT EXISTS %s.login_nonce (
+chain text,
+address text,
+nonce text,
+message text,
+expires_at timestamp,
+PRIMARY KEY ((chain, address), nonce)
+)
+`
+
+// Login sessions, refresh_token holds the sha256 of the current refresh token and previous_refresh_token the one
+// it was rotated from
+var sessionSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_sessions (
+chain text,
+address text,
+session_id text,
+refresh_token text,
+previous_refresh_token text,
+user_agent text,
+ip text,
+created timestamp,
+last_seen timestamp,
+PRIMARY KEY ((chain, address), session_id)
+)
+`
+
 var patSchema = `
 CREATE TABLE IF NOT EXISTS %s.pa_token
 (
@@ -209,6 +359,10 @@ jwt TEXT,
 "created" TIMESTAMP,
 description TEXT,
 kind TEXT,
+scopes SET<TEXT>,
+expires_at TIMESTAMP,
+last_used TIMESTAMP,
+last_used_ip TEXT,
 PRIMARY KEY ((chain,address), kind, uuid)
 )
 `
@@ -244,6 +398,8 @@ event_date text,
 medium text,
 read int,
 sent int,
+recalled int,
+clicked int,
 PRIMARY KEY ((chain, channel), event_date, event_time)
 ) WITH CLUSTERING ORDER BY (event_date DESC, event_time DESC)
 `
@@ -279,6 +435,7 @@ seen boolean,
 verified boolean,
 type text,
 updated_time timestamp,
+edited_time timestamp,
 logo text,
 PRIMARY KEY ((chain, receiver), created_time, uuid)
 ) WITH CLUSTERING ORDER BY (created_time DESC, uuid ASC)
@@ -298,40 +455,86 @@ PRIMARY KEY (chain, schedule, sender)
 ) WITH CLUSTERING ORDER BY (schedule DESC, sender DESC)
 `
 
-var notificationTotalSentSchema = `
-CREATE TABLE IF NOT EXISTS %s.notification_total_sent (
-hash text,
-sent int,
-event_time timestamp,
-PRIMARY KEY (hash, event_time)
-)  WITH CLUSTERING ORDER BY (event_time DESC)
+// Tracks every notification sent from a channel by uuid so that it can be
+// located in the inbox of all its receivers later on
+var sentNotificationInfoSchema = `
+CREATE TABLE IF NOT EXISTS %s.sent_notification_info (
+chain text,
+app_id text,
+uuid text,
+sender text,
+receivers set<text>,
+message text,
+link text,
+type text,
+created_time timestamp,
+discord_messages map<text, text>,
+recalled boolean,
+PRIMARY KEY ((chain, app_id), uuid)
+)
 `
 
-var notificationEmailMediumReachSchema = `
-CREATE TABLE IF NOT EXISTS %s.notification_email_reach (
-hash text,
-read int,
-event_time timestamp,
-PRIMARY KEY (hash, event_time)
-)  WITH CLUSTERING ORDER BY (event_time DESC)
+// Notifications sent from a channel by the time they were sent, sent_notification_info is keyed by uuid. Rows
+// expire along with the notification.
+var sentNotificationsByTimeSchema = `
+CREATE TABLE IF NOT EXISTS %s.sent_notifications_by_time (
+chain text,
+app_id text,
+created_time timestamp,
+uuid text,
+PRIMARY KEY ((chain, app_id), created_time, uuid)
+) WITH CLUSTERING ORDER BY (created_time DESC, uuid ASC)
 `
 
-var notificationAppMediumReachSchema = `
-CREATE TABLE IF NOT EXISTS %s.notification_app_reach (
-hash text,
-read int,
-event_time timestamp,
-PRIMARY KEY (hash, event_time)
-)  WITH CLUSTERING ORDER BY (event_time DESC)
+// Keeps the previous message and link of a notification every time it is edited
+var notificationRevisionsSchema = `
+CREATE TABLE IF NOT EXISTS %s.notification_revisions (
+chain text,
+app_id text,
+uuid text,
+edited_time timestamp,
+message text,
+link text,
+PRIMARY KEY ((chain, app_id, uuid), edited_time)
+) WITH CLUSTERING ORDER BY (edited_time DESC)
 `
 
-var notificationDiscordMediumReachSchema = `
-CREATE TABLE IF NOT EXISTS %s.notification_discord_reach (
-hash text,
-read int,
+// Tracks the sent, delivered, read and clicked events of a notification per medium and receiver
+// Notifications sent to a receiver, sent_notification_info and notification_reach are partitioned by channel.
+// Rows expire along with the notification.
+var userNotificationsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_notifications (
+chain text,
+receiver text,
+app_id text,
+uuid text,
+PRIMARY KEY ((chain, receiver), app_id, uuid)
+)
+`
+
+// Scheduled notifications a user sends or receives, scheduled_notification_info is partitioned by chain. Rows
+// expire along with the scheduled notification.
+var userScheduledNotificationsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_scheduled_notifications (
+chain text,
+address text,
+schedule timestamp,
+sender text,
+PRIMARY KEY ((chain, address), schedule, sender)
+)
+`
+
+var notificationReachSchema = `
+CREATE TABLE IF NOT EXISTS %s.notification_reach (
+chain text,
+app_id text,
+uuid text,
+medium text,
+event text,
+receiver text,
 event_time timestamp,
-PRIMARY KEY (hash, event_time)
-)  WITH CLUSTERING ORDER BY (event_time DESC)
+PRIMARY KEY ((chain, app_id, uuid), medium, event, receiver)
+)
 `
 
 var billingHistorySchema = `
@@ -391,6 +594,16 @@ PRIMARY KEY ((chain, user))
 )
 `
 
+// Users who blocked a user, user_chat_block is partitioned by the blocking user
+var userChatBlockedBySchema = `
+CREATE TABLE IF NOT EXISTS %s.user_chat_blocked_by (
+chain text,
+user text,
+blocked_by text,
+PRIMARY KEY ((chain, user), blocked_by)
+)
+`
+
 var userChatContactsSchema = `
 CREATE TABLE IF NOT EXISTS %s.user_chat_contacts (
 chain text,
@@ -446,3 +659,306 @@ dns text,
 PRIMARY KEY (chain, user)
 )
 `
+
+var channelWebhooksSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_webhooks (
+chain text,
+app_id text,
+id text,
+url text,
+events set<text>,
+secret text,
+created_time timestamp,
+PRIMARY KEY ((chain, app_id), id)
+)
+`
+
+// One row per webhook delivery, updated after every attempt. The delivery log of a webhook is a partition
+var channelWebhookDeliveriesSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_webhook_deliveries (
+chain text,
+app_id text,
+created_time timestamp,
+id text,
+webhook_id text,
+event text,
+payload text,
+attempts int,
+status_code int,
+success boolean,
+error text,
+updated_time timestamp,
+PRIMARY KEY ((chain, app_id, webhook_id), created_time, id)
+) WITH CLUSTERING ORDER BY (created_time DESC, id ASC)
+`
+
+// Accounts of the same user on different chains, every link is stored from both sides.
+// billing_chain and billing_address are the account whose balance is used when billing is shared.
+var linkedAccountsSchema = `
+CREATE TABLE IF NOT EXISTS %s.linked_accounts (
+chain text,
+address text,
+linked_chain text,
+linked_address text,
+shared_billing boolean,
+billing_chain text,
+billing_address text,
+created timestamp,
+PRIMARY KEY ((chain, address), linked_chain)
+)
+`
+
+// Account deletion jobs, steps_done is the number of consts.AccountDeletionSteps completed.
+// An instance working on a job holds it until lease_until.
+var accountDeletionsSchema = `
+CREATE TABLE IF NOT EXISTS %s.account_deletions (
+id text,
+chain text,
+address text,
+status text,
+steps_done int,
+error text,
+requested timestamp,
+updated timestamp,
+lease_until timestamp,
+PRIMARY KEY (id)
+)
+`
+
+// Members of a channel besides its owner, with their role and whether they accepted the invite
+var channelMembersSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_members (
+chain text,
+app_id text,
+address text,
+role text,
+status text,
+invited_by text,
+created timestamp,
+updated timestamp,
+PRIMARY KEY ((chain, app_id), address)
+)
+`
+
+// Reverse lookup of channel_members, the channels a user is a member of or invited to
+var userChannelRolesSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_channel_roles (
+chain text,
+address text,
+app_id text,
+role text,
+status text,
+invited_by text,
+created timestamp,
+updated timestamp,
+PRIMARY KEY ((chain, address), app_id)
+)
+`
+
+// Pending changes of the owner of a channel, a row is removed once the change is accepted, approved or
+// withdrawn, or expires
+var channelTransfersSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_transfers (
+chain text,
+app_id text,
+kind text,
+from_address text,
+to_address text,
+reason text,
+created timestamp,
+PRIMARY KEY (chain, app_id)
+)
+`
+
+// Pending changes of the owner of a channel by kind and the address they make the owner, rows expire along with
+// the channel_transfers row
+var transfersByTargetSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_transfers_by_target (
+chain text,
+kind text,
+to_address text,
+app_id text,
+from_address text,
+reason text,
+created timestamp,
+PRIMARY KEY ((chain, kind), to_address, app_id)
+)
+`
+
+// The latest request to verify a channel and the decision of the admin reviewing it
+var channelVerificationsSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_verifications (
+chain text,
+app_id text,
+requested_by text,
+website text,
+socials map<text, text>,
+notes text,
+status text,
+reason text,
+reviewed_by text,
+created timestamp,
+updated timestamp,
+PRIMARY KEY (chain, app_id)
+)
+`
+
+// Requests to verify a channel by their status, for the admin review queue
+var verificationsByStatusSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_verifications_by_status (
+chain text,
+status text,
+app_id text,
+PRIMARY KEY ((chain, status), app_id)
+)
+`
+
+// Requests to verify a channel a user made, channel_verifications is partitioned by chain
+var userVerificationRequestsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_verification_requests (
+chain text,
+requested_by text,
+app_id text,
+PRIMARY KEY ((chain, requested_by), app_id)
+)
+`
+
+// The domain claimed by a channel and the token it proves it with, the domain shows on the channel once verified
+var channelDomainsSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_domains (
+chain text,
+app_id text,
+domain text,
+token text,
+status text,
+method text,
+error text,
+checked timestamp,
+verified timestamp,
+created timestamp,
+PRIMARY KEY (chain, app_id)
+)
+`
+
+// Invites to a private channel, an invite is used up once redeemed max_uses times
+var channelInvitesSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_invites (
+chain text,
+app_id text,
+code text,
+max_uses int,
+uses int,
+expires timestamp,
+revoked boolean,
+created_by text,
+created timestamp,
+PRIMARY KEY ((chain, app_id), code)
+)
+`
+
+// Requests to opt in to a private channel waiting for the approval of its owner
+var channelOptinRequestsSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_optin_requests (
+chain text,
+app_id text,
+address text,
+source text,
+created timestamp,
+PRIMARY KEY ((chain, app_id), address)
+)
+`
+
+// Invites a user created, channel_invites is partitioned by channel
+var userChannelInvitesSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_channel_invites (
+chain text,
+created_by text,
+app_id text,
+code text,
+PRIMARY KEY ((chain, created_by), app_id, code)
+)
+`
+
+// Channels a user requested to opt in to, channel_optin_requests is partitioned by channel
+var userOptinRequestsSchema = `
+CREATE TABLE IF NOT EXISTS %s.user_optin_requests (
+chain text,
+address text,
+app_id text,
+PRIMARY KEY ((chain, address), app_id)
+)
+`
+
+// Holding required to opt in to a token gated channel
+var channelTokenGatesSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_token_gates (
+chain text,
+app_id text,
+kind text,
+asset text,
+issuer text,
+min_balance double,
+updated_by text,
+updated timestamp,
+PRIMARY KEY (chain, app_id)
+)
+`
+
+// Public channels by category, for discovery
+var channelCategoryIndexSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_categories (
+chain text,
+category text,
+app_id text,
+PRIMARY KEY ((chain, category), app_id)
+)
+`
+
+// Public channels by tag, for discovery
+var channelTagIndexSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_tags (
+chain text,
+tag text,
+app_id text,
+PRIMARY KEY ((chain, tag), app_id)
+)
+`
+
+// Channels ranked for discovery, a ranking is rewritten as a whole every time it is computed
+var channelRankingsSchema = `
+CREATE TABLE IF NOT EXISTS %s.channel_rankings (
+chain text,
+ranking text,
+score double,
+app_id text,
+PRIMARY KEY ((chain, ranking), score, app_id)
+) WITH CLUSTERING ORDER BY (score DESC, app_id ASC)
+`
+
+// Webhook deliveries waiting for their next attempt, a delivery is claimed by an instance until lease_until
+var webhookOutboxSchema = `
+CREATE TABLE IF NOT EXISTS %s.webhook_outbox (
+shard int,
+id text,
+webhook_id text,
+chain text,
+app_id text,
+event text,
+payload text,
+attempts int,
+created_time timestamp,
+next_attempt timestamp,
+lease_until timestamp,
+PRIMARY KEY ((shard), id)
+)
+`
+
+// Data migrations claimed or applied, a claim that isn't completed before lease_until can be taken over
+var schemaMigrationsSchema = `
+CREATE TABLE IF NOT EXISTS %s.schema_migrations (
+name text,
+lease_until timestamp,
+completed_time timestamp,
+PRIMARY KEY (name)
+)
+`
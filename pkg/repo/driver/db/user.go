@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gocql/gocql"
 
@@ -14,32 +15,14 @@ import (
 	"notiboy/utilities"
 )
 
-func IsTokenPresent(ctx context.Context, address, chain, token, kind, uuid string) (bool, error) {
+// IsTokenPresent checks a login token issued before sessions against the login table
+func IsTokenPresent(ctx context.Context, address, chain, token string) (bool, error) {
 	log := utilities.NewLoggerWithFields("IsTokenPresent", map[string]interface{}{
 		"chain":   chain,
 		"address": address,
-		"uuid":    uuid,
-		"kind":    kind,
 	})
 	var tokenFromDB string
 
-	if uuid != "" {
-		patTable := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.PATInfo)
-
-		queryProfile := fmt.Sprintf("SELECT jwt FROM %s WHERE address = ? AND chain = ? AND kind = ? AND uuid = ?", patTable)
-		if err := GetCassandraSession().Query(queryProfile, address, chain, kind, uuid).Exec(); err != nil {
-			if errors.Is(err, gocql.ErrNotFound) {
-				log.WithError(err).Errorf("token doesn't exist in DB for user %s", address)
-				return false, nil
-			} else {
-				log.WithError(err).Error("failed to revoke pa token")
-				return false, fmt.Errorf("failed to revoke pa token: %w", err)
-			}
-		}
-
-		return true, nil
-	}
-
 	loginInfoTable := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.LoginTable)
 
 	query := fmt.Sprintf("SELECT jwt FROM %s WHERE address = ? AND chain = ? AND jwt = ? LIMIT 1", loginInfoTable)
@@ -56,6 +39,111 @@ func IsTokenPresent(ctx context.Context, address, chain, token, kind, uuid strin
 	return true, nil
 }
 
+// IsPATActive reports whether the personal access token uuid is still active and records its use from ip.
+// The use is written at most once a minute per ip, with the remaining lifetime of the token.
+func IsPATActive(ctx context.Context, chain, address, kind, uuid, ip string) (bool, error) {
+	log := utilities.NewLoggerWithFields("IsPATActive", map[string]interface{}{
+		"chain":   chain,
+		"address": address,
+		"uuid":    uuid,
+		"kind":    kind,
+	})
+
+	var (
+		lastUsed   time.Time
+		lastUsedIP string
+		ttl        *int
+	)
+
+	patTable := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.PATInfo)
+
+	query := fmt.Sprintf(
+		"SELECT last_used, last_used_ip, TTL(jwt) FROM %s WHERE address = ? AND chain = ? AND kind = ? AND uuid = ?",
+		patTable,
+	)
+	if err := GetCassandraSession().Query(
+		query, address, chain, kind, uuid,
+	).Scan(&lastUsed, &lastUsedIP, &ttl); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			log.Errorf("token doesn't exist in DB for user %s", address)
+			return false, nil
+		}
+		log.WithError(err).Errorf("error talking to DB for user %s", address)
+		return false, err
+	}
+
+	if ttl == nil || *ttl <= 0 {
+		return false, nil
+	}
+
+	if lastUsedIP == ip && utilities.TimeNow().Sub(lastUsed) < time.Minute {
+		return true, nil
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s USING TTL ? SET last_used = ?, last_used_ip = ? "+
+			"WHERE address = ? AND chain = ? AND kind = ? AND uuid = ?",
+		patTable,
+	)
+	if err := GetCassandraSession().Query(
+		query, *ttl, utilities.TimeNow(), ip, address, chain, kind, uuid,
+	).Exec(); err != nil {
+		log.WithError(err).Error("failed to record pa token use")
+	}
+
+	return true, nil
+}
+
+// IsSessionActive reports whether the login session sessionID is still active and records it as seen.
+// last_seen is written at most once a minute, with the remaining lifetime of the session.
+func IsSessionActive(ctx context.Context, chain, address, sessionID string) (bool, error) {
+	log := utilities.NewLoggerWithFields("IsSessionActive", map[string]interface{}{
+		"chain":   chain,
+		"address": address,
+		"session": sessionID,
+	})
+
+	var (
+		lastSeen time.Time
+		ttl      *int
+	)
+
+	sessionTable := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.Sessions)
+
+	query := fmt.Sprintf(
+		"SELECT last_seen, TTL(refresh_token) FROM %s WHERE chain = ? AND address = ? AND session_id = ?",
+		sessionTable,
+	)
+	if err := GetCassandraSession().Query(query, chain, address, sessionID).Scan(&lastSeen, &ttl); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			log.Errorf("session doesn't exist in DB for user %s", address)
+			return false, nil
+		}
+		log.WithError(err).Errorf("error talking to DB for user %s", address)
+		return false, err
+	}
+
+	// a revoked session may leave a last_seen cell behind
+	if ttl == nil || *ttl <= 0 {
+		return false, nil
+	}
+
+	if utilities.TimeNow().Sub(lastSeen) < time.Minute {
+		return true, nil
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s USING TTL ? SET last_seen = ? WHERE chain = ? AND address = ? AND session_id = ?", sessionTable,
+	)
+	if err := GetCassandraSession().Query(
+		query, *ttl, utilities.TimeNow(), chain, address, sessionID,
+	).Exec(); err != nil {
+		log.WithError(err).Error("failed to update session last seen")
+	}
+
+	return true, nil
+}
+
 func GetUserStatus(ctx context.Context, chain, address string) (string, error) {
 	var userStat string
 	query := fmt.Sprintf("SELECT status FROM %s.%s WHERE chain = ? AND address = ?",
@@ -67,16 +155,19 @@ func GetUserStatus(ctx context.Context, chain, address string) (string, error) {
 	return userStat, nil
 }
 
+// GetUserModel retrieves the user_info row of the user.
 func GetUserModel(ctx context.Context, chain, address string) (*entities.UserModel, error) {
 	var allowedMediums, supportedMediums, channels, optins []string
 	var status, membership string
 	var mediumMetadataStr, logo string
+	var trackingOptOut bool
+	profile := new(entities.UserProfile)
 
 	keyspace := config.GetConfig().DB.Keyspace
 	tblUserInfo := fmt.Sprintf("%s.%s", keyspace, consts.UserTable)
 
-	infoQuery := "SELECT channels, optins, membership, logo, status, allowed_mediums, supported_mediums, medium_metadata FROM " + tblUserInfo + " WHERE chain = ? AND address = ?"
-	if err := GetCassandraSession().Query(infoQuery, chain, address).Scan(&channels, &optins, &membership, &logo, &status, &allowedMediums, &supportedMediums, &mediumMetadataStr); err != nil {
+	infoQuery := "SELECT channels, optins, membership, logo, status, allowed_mediums, supported_mediums, medium_metadata, tracking_opt_out, display_name, avatar, bio, locale, timezone, profile_privacy FROM " + tblUserInfo + " WHERE chain = ? AND address = ?"
+	if err := GetCassandraSession().Query(infoQuery, chain, address).Scan(&channels, &optins, &membership, &logo, &status, &allowedMediums, &supportedMediums, &mediumMetadataStr, &trackingOptOut, &profile.DisplayName, &profile.Avatar, &profile.Bio, &profile.Locale, &profile.Timezone, &profile.Privacy); err != nil {
 		return nil, fmt.Errorf("failed to query db, query: %s (chain: %s, address: %s): %w", infoQuery, chain, address, err)
 	}
 
@@ -100,11 +191,14 @@ func GetUserModel(ctx context.Context, chain, address string) (*entities.UserMod
 		Status:           status,
 		Channels:         channels,
 		Optins:           optins,
+		TrackingOptOut:   trackingOptOut,
+		Profile:          profile,
 	}
 
 	return userInfo, nil
 }
 
+// IsUserOnboarded reports whether the user signed up.
 func IsUserOnboarded(ctx context.Context, chain, address string) (bool, error) {
 	var userStat string
 	query := fmt.Sprintf("SELECT status FROM %s.%s WHERE chain = ? AND address = ?",
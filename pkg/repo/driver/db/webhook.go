@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// GetChannelWebhooks retrieves the webhooks registered for a channel, including their signing secrets.
+func GetChannelWebhooks(ctx context.Context, chain, appID string) ([]entities.Webhook, error) {
+	log := utilities.NewLoggerWithFields("GetChannelWebhooks", map[string]interface{}{
+		"chain":  chain,
+		"app_id": appID,
+	})
+
+	var webhooks = make([]entities.Webhook, 0)
+
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.ChannelWebhooks)
+	query := fmt.Sprintf(
+		"SELECT id, url, events, secret, created_time FROM %s WHERE chain = ? AND app_id = ?", tbl,
+	)
+
+	webhook := entities.Webhook{
+		Chain:   chain,
+		Channel: appID,
+	}
+	iter := GetCassandraSession().Query(query, chain, appID).WithContext(ctx).Iter()
+	for iter.Scan(&webhook.ID, &webhook.URL, &webhook.Events, &webhook.Secret, &webhook.CreatedTime) {
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve channel webhooks")
+		return nil, fmt.Errorf("failed to retrieve channel webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// UpsertWebhookDelivery records the outcome of the latest attempt of a webhook delivery.
+func UpsertWebhookDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	log := utilities.NewLoggerWithFields("UpsertWebhookDelivery", map[string]interface{}{
+		"chain":    delivery.Chain,
+		"app_id":   delivery.Channel,
+		"delivery": delivery.ID,
+	})
+
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.ChannelWebhookDeliveries)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, app_id, created_time, id, webhook_id, event, payload, attempts, status_code, success, error, updated_time)
+	VALUES %s USING TTL %d`,
+		tbl, utilities.DBMultiValuePlaceholders(12), config.GetConfig().TTL.Metrics,
+	)
+
+	err := GetCassandraSession().Query(
+		query, delivery.Chain, delivery.Channel, delivery.CreatedTime, delivery.ID, delivery.WebhookID,
+		delivery.Event, delivery.Payload, delivery.Attempts, delivery.StatusCode, delivery.Success, delivery.Error,
+		delivery.UpdatedTime,
+	).WithContext(ctx).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to record webhook delivery")
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// InsertWebhookOutbox queues a webhook delivery for its first attempt.
+func InsertWebhookOutbox(ctx context.Context, item *entities.WebhookOutboxItem) error {
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.WebhookOutbox)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (shard, id, webhook_id, chain, app_id, event, payload, attempts, created_time, next_attempt, lease_until)
+	VALUES %s`,
+		tbl, utilities.DBMultiValuePlaceholders(11),
+	)
+
+	delivery := item.Delivery
+	err := GetCassandraSession().Query(
+		query, item.Shard, delivery.ID, delivery.WebhookID, delivery.Chain, delivery.Channel, delivery.Event,
+		delivery.Payload, delivery.Attempts, delivery.CreatedTime, item.NextAttempt, item.LeaseUntil,
+	).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueWebhookOutbox retrieves the deliveries of the outbox shard due for an attempt and not claimed by anyone.
+func ListDueWebhookOutbox(ctx context.Context, shard int, now time.Time) ([]entities.WebhookOutboxItem, error) {
+	var items = make([]entities.WebhookOutboxItem, 0)
+
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.WebhookOutbox)
+	query := fmt.Sprintf(
+		`SELECT id, webhook_id, chain, app_id, event, payload, attempts, created_time, next_attempt, lease_until
+	FROM %s WHERE shard = ?`,
+		tbl,
+	)
+
+	item := entities.WebhookOutboxItem{Shard: shard}
+	delivery := &item.Delivery
+	iter := GetCassandraSession().Query(query, shard).WithContext(ctx).Iter()
+	for iter.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.Chain, &delivery.Channel, &delivery.Event, &delivery.Payload,
+		&delivery.Attempts, &delivery.CreatedTime, &item.NextAttempt, &item.LeaseUntil,
+	) {
+		if item.NextAttempt.After(now) || item.LeaseUntil.After(now) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook outbox: %w", err)
+	}
+
+	return items, nil
+}
+
+// ClaimWebhookOutbox claims the delivery until leaseUntil, unless another instance claimed or completed it first.
+// The lease of the item is updated once claimed.
+func ClaimWebhookOutbox(ctx context.Context, item *entities.WebhookOutboxItem, leaseUntil time.Time) (bool, error) {
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.WebhookOutbox)
+	query := fmt.Sprintf(`UPDATE %s SET lease_until = ? WHERE shard = ? AND id = ? IF lease_until = ?`, tbl)
+
+	applied, err := GetCassandraSession().Query(
+		query, leaseUntil, item.Shard, item.Delivery.ID, item.LeaseUntil,
+	).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	if applied {
+		item.LeaseUntil = leaseUntil
+	}
+
+	return applied, nil
+}
+
+// RescheduleWebhookOutbox releases a claimed delivery until its next attempt.
+func RescheduleWebhookOutbox(ctx context.Context, item *entities.WebhookOutboxItem) error {
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.WebhookOutbox)
+	query := fmt.Sprintf(
+		`UPDATE %s SET attempts = ?, next_attempt = ?, lease_until = ? WHERE shard = ? AND id = ? IF lease_until = ?`,
+		tbl,
+	)
+
+	_, err := GetCassandraSession().Query(
+		query, item.Delivery.Attempts, item.NextAttempt, item.NextAttempt, item.Shard, item.Delivery.ID,
+		item.LeaseUntil,
+	).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("failed to reschedule webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookOutbox removes a delivery that succeeded or was given up from the outbox.
+func DeleteWebhookOutbox(ctx context.Context, item *entities.WebhookOutboxItem) error {
+	tbl := fmt.Sprintf("%s.%s", config.GetConfig().DB.Keyspace, consts.WebhookOutbox)
+	query := fmt.Sprintf(`DELETE FROM %s WHERE shard = ? AND id = ?`, tbl)
+
+	err := GetCassandraSession().Query(query, item.Shard, item.Delivery.ID).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to remove webhook delivery: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"notiboy/pkg/consts"
+	"notiboy/utilities/http_client"
+)
+
+// WellKnownPath is where a domain serves the tokens of the channels it belongs to, as {"tokens": [...]}
+const WellKnownPath = "/.well-known/notiboy.json"
+
+// TXTRecordPrefix prefixes the token in the TXT record of a domain proving it belongs to a channel
+const TXTRecordPrefix = "notiboy-verification="
+
+// maxWellKnownSize caps the size of the well-known file read from a domain
+const maxWellKnownSize = 64 << 10
+
+// Resolver looks up the TXT records of a name, net.Resolver implements it
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Fetcher sends an HTTP request, http.Client implements it
+type Fetcher interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Prover checks that a domain carries the token issued for a channel claiming it
+type Prover struct {
+	Resolver Resolver
+	Fetcher  Fetcher
+}
+
+var prover *Prover
+
+// GetProver returns the prover checking domains, it resolves with the system resolver and fetches with the
+// client restricted to public addresses, the domains are given by channel owners.
+func GetProver() *Prover {
+	if prover == nil {
+		prover = &Prover{
+			Resolver: net.DefaultResolver,
+			Fetcher:  http_client.GetPublicClient(),
+		}
+	}
+
+	return prover
+}
+
+// TXTRecordName returns the name of the TXT record carrying the token for domain
+func TXTRecordName(domain string) string {
+	return "_notiboy." + domain
+}
+
+// Prove returns how domain proves to carry token, either by a TXT record or by its well-known file.
+func (p *Prover) Prove(ctx context.Context, domain, token string) (string, error) {
+	dnsErr := p.proveTXTRecord(ctx, domain, token)
+	if dnsErr == nil {
+		return consts.DomainProofDNS, nil
+	}
+
+	wellKnownErr := p.proveWellKnown(ctx, domain, token)
+	if wellKnownErr == nil {
+		return consts.DomainProofWellKnown, nil
+	}
+
+	return "", fmt.Errorf("domain %s carries no proof: %w", domain, errors.Join(dnsErr, wellKnownErr))
+}
+
+func (p *Prover) proveTXTRecord(ctx context.Context, domain, token string) error {
+	records, err := p.Resolver.LookupTXT(ctx, TXTRecordName(domain))
+	if err != nil {
+		return fmt.Errorf("failed to look up TXT records: %w", err)
+	}
+
+	for _, record := range records {
+		if strings.TrimSpace(record) == TXTRecordPrefix+token {
+			return nil
+		}
+	}
+
+	return errors.New("no TXT record carries the token")
+}
+
+func (p *Prover) proveWellKnown(ctx context.Context, domain, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+domain+WellKnownPath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.Fetcher.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", WellKnownPath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s returned %d", WellKnownPath, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWellKnownSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", WellKnownPath, err)
+	}
+	if len(body) > maxWellKnownSize {
+		return fmt.Errorf("%s exceeds %d bytes", WellKnownPath, maxWellKnownSize)
+	}
+
+	var wellKnown struct {
+		Tokens []string `json:"tokens"`
+	}
+	if err = json.Unmarshal(body, &wellKnown); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", WellKnownPath, err)
+	}
+
+	for _, t := range wellKnown.Tokens {
+		if t == token {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s doesn't carry the token", WellKnownPath)
+}
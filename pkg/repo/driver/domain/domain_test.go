@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"notiboy/pkg/consts"
+)
+
+type stubResolver map[string][]string
+
+func (r stubResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+type stubFetcher struct {
+	status int
+	body   string
+}
+
+func (f stubFetcher) Do(req *http.Request) (*http.Response, error) {
+	if f.status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	if req.URL.String() != "https://example.com"+WellKnownPath {
+		return nil, errors.New("unexpected URL " + req.URL.String())
+	}
+	return &http.Response{StatusCode: f.status, Body: io.NopCloser(strings.NewReader(f.body))}, nil
+}
+
+func TestProver_Prove(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolver   stubResolver
+		fetcher    stubFetcher
+		wantMethod string
+		wantErr    bool
+	}{
+		{
+			name:       "TXT record",
+			resolver:   stubResolver{"_notiboy.example.com": {"other", " notiboy-verification=token "}},
+			wantMethod: consts.DomainProofDNS,
+		},
+		{
+			name:     "TXT record of another token",
+			resolver: stubResolver{"_notiboy.example.com": {"notiboy-verification=other"}},
+			wantErr:  true,
+		},
+		{
+			name:       "well-known file",
+			fetcher:    stubFetcher{status: http.StatusOK, body: `{"tokens": ["other", "token"]}`},
+			wantMethod: consts.DomainProofWellKnown,
+		},
+		{
+			name:    "well-known file of another token",
+			fetcher: stubFetcher{status: http.StatusOK, body: `{"tokens": ["other"]}`},
+			wantErr: true,
+		},
+		{
+			name:    "well-known file missing",
+			fetcher: stubFetcher{status: http.StatusNotFound, body: `{"tokens": ["token"]}`},
+			wantErr: true,
+		},
+		{
+			name: "well-known file oversized",
+			fetcher: stubFetcher{
+				status: http.StatusOK, body: `{"tokens": ["token"]}` + strings.Repeat(" ", maxWellKnownSize),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no proof",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				p := &Prover{Resolver: tt.resolver, Fetcher: tt.fetcher}
+
+				method, err := p.Prove(context.Background(), "example.com", "token")
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Prove() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if method != tt.wantMethod {
+					t.Errorf("Prove() = %s, want %s", method, tt.wantMethod)
+				}
+			},
+		)
+	}
+}
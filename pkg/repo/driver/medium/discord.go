@@ -12,6 +12,7 @@ import (
 	"notiboy/config"
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
 	"notiboy/utilities"
 	"notiboy/utilities/http_client"
 
@@ -93,6 +94,8 @@ func (d *DiscordMessenger) SpawnSender(ctx context.Context) {
 				continue
 			}
 
+			recordReach(ctx, notification, consts.Discord, consts.ReachSent)
+
 			msg := fmt.Sprintf("*Announcement from* **%s**\n", notification.ChannelName)
 			if notification.Type == "private" {
 				msg = fmt.Sprintf("*You have a notification from* **%s**\n", notification.ChannelName)
@@ -101,7 +104,7 @@ func (d *DiscordMessenger) SpawnSender(ctx context.Context) {
 			msg = fmt.Sprintf("%s ```%s```\n", msg, message)
 
 			if notification.Link != "" {
-				msg = fmt.Sprintf("%s\nLink: %s", msg, notification.Link)
+				msg = fmt.Sprintf("%s\nLink: %s", msg, trackedLink(notification, consts.Discord, notification.Link))
 			}
 
 			id, err := d.Client.ChannelMessageSendComplex(
@@ -111,10 +114,20 @@ func (d *DiscordMessenger) SpawnSender(ctx context.Context) {
 			)
 			if err != nil {
 				log.WithError(err).Errorf("failed to send discord message to %s", notification.Receiver)
+				emitDeliveryFailed(ctx, notification, consts.Discord, err)
 				continue
 			}
 
 			log.Debugf("Discord notification %s sent to channel %s of user %s", id.ID, discordMeta.DMChannelID, notification.Receiver)
+			recordReach(ctx, notification, consts.Discord, consts.ReachDelivered)
+
+			err = db.StoreDiscordMessageRef(
+				ctx, notification.Chain, notification.Channel, notification.UUID, notification.Receiver,
+				discordMeta.DMChannelID, id.ID, notification.TTL,
+			)
+			if err != nil {
+				log.WithError(err).Errorf("failed to store discord message id of %s", notification.Receiver)
+			}
 		case <-ctx.Done():
 			log.Infof("Shutting down")
 			return
@@ -126,6 +139,16 @@ func (d *DiscordMessenger) Enqueue(notification *entities.Notification) {
 	d.queue <- notification
 }
 
+// DeleteMessage deletes a previously sent discord message referenced by db.FormatDiscordMessageRef
+func (d *DiscordMessenger) DeleteMessage(ref string) error {
+	dmChannelID, messageID, found := strings.Cut(ref, "/")
+	if !found {
+		return fmt.Errorf("invalid discord message reference %s", ref)
+	}
+
+	return d.Client.ChannelMessageDelete(dmChannelID, messageID)
+}
+
 func GetToken(discordtoken string) (string, error) {
 	dc := config.GetConfig().Discord
 
@@ -85,6 +85,8 @@ func (ec *EmailClient) SpawnSender(ctx context.Context) {
 				continue
 			}
 
+			recordReach(ctx, notification, consts.Email, consts.ReachSent)
+
 			subject := fmt.Sprintf("Announcement from %s", notification.ChannelName)
 			if notification.Type == "private" {
 				subject = fmt.Sprintf("You have a notification from %s", notification.ChannelName)
@@ -97,13 +99,14 @@ func (ec *EmailClient) SpawnSender(ctx context.Context) {
 				continue
 			}
 			if notification.Link != "" {
-				link = notification.Link
+				link = trackedLink(notification, consts.Email, notification.Link)
 			}
 
 			body, err := utilities.TemplateRendering(templates.NotificationTemplate, map[string]string{
 				"Heading": subject,
 				"Message": msg,
 				"Link":    link,
+				"Pixel":   openTrackingPixel(notification),
 			})
 			if err != nil {
 				log.WithError(err).Error("failed to render email template")
@@ -115,9 +118,12 @@ func (ec *EmailClient) SpawnSender(ctx context.Context) {
 			)
 			if err != nil {
 				log.WithError(err).Errorf("failed to email %s", notification.Receiver)
+				emitDeliveryFailed(ctx, notification, consts.Email, err)
 				continue
 			}
 
+			recordReach(ctx, notification, consts.Email, consts.ReachDelivered)
+
 		case <-ctx.Done():
 			log.Infof("Shutting down")
 			return
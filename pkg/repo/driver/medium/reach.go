@@ -0,0 +1,79 @@
+package medium
+
+import (
+	"context"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+	"notiboy/utilities/tracking"
+)
+
+// recordReach records a reach event of the notification for its receiver on the given medium
+func recordReach(ctx context.Context, notification *entities.Notification, medium, event string) {
+	_, err := db.InsertNotificationReach(
+		ctx, entities.NotificationReachEvent{
+			Chain:    notification.Chain,
+			Channel:  notification.Channel,
+			UUID:     notification.UUID,
+			Medium:   medium,
+			Event:    event,
+			Receiver: notification.Receiver,
+		},
+	)
+	if err != nil {
+		utilities.NewLogger("recordReach").WithError(err).Errorf("failed to record %s %s reach", medium, event)
+	}
+}
+
+// emitDeliveryFailed notifies the channel webhooks that the notification could not be delivered on the medium
+func emitDeliveryFailed(ctx context.Context, notification *entities.Notification, medium string, err error) {
+	EmitWebhookEvent(
+		ctx, notification.Chain, notification.Channel, consts.WebhookDeliveryFailed,
+		entities.NotificationWebhookData{
+			UUID:     notification.UUID,
+			Receiver: notification.Receiver,
+			Medium:   medium,
+			Error:    err.Error(),
+		},
+	)
+}
+
+// openTrackingPixel returns the email open tracking pixel URL of the notification,
+// or an empty string if the receiver has opted out of tracking
+func openTrackingPixel(notification *entities.Notification) string {
+	if notification.ReceiverInfo.TrackingOptOut {
+		return ""
+	}
+
+	pixelURL, err := tracking.PixelURL(
+		tracking.Claims{
+			Chain:    notification.Chain,
+			AppID:    notification.Channel,
+			UUID:     notification.UUID,
+			Receiver: notification.Receiver,
+			Medium:   consts.Email,
+		},
+	)
+	if err != nil {
+		utilities.NewLogger("openTrackingPixel").WithError(err).Error("failed to create tracking pixel")
+		return ""
+	}
+
+	return pixelURL
+}
+
+// trackedLink rewrites the notification link to the click tracking redirect URL of the medium, unless the receiver
+// opted out of tracking
+func trackedLink(notification *entities.Notification, medium, link string) string {
+	return tracking.TrackedLink(
+		link, tracking.Claims{
+			Chain:    notification.Chain,
+			AppID:    notification.Channel,
+			UUID:     notification.UUID,
+			Receiver: notification.Receiver,
+			Medium:   medium,
+		}, notification.ReceiverInfo.TrackingOptOut,
+	)
+}
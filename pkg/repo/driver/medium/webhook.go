@@ -0,0 +1,322 @@
+package medium
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"time"
+
+	uuidLib "github.com/google/uuid"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+	"notiboy/utilities/http_client"
+)
+
+const (
+	WebhookSignatureHeader = "X-Notiboy-Signature"
+	WebhookEventHeader     = "X-Notiboy-Event"
+	WebhookDeliveryHeader  = "X-Notiboy-Delivery"
+)
+
+// webhookRetryDelays are the waits before each retry of a failed delivery, a delivery is given up after the last one
+var webhookRetryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+const (
+	// webhookPollInterval is how often the outbox is checked for deliveries due for an attempt
+	webhookPollInterval = 5 * time.Second
+	// webhookLease is how long an instance has to attempt a delivery it claimed before another one may claim it
+	webhookLease = 2 * time.Minute
+)
+
+var webhookDispatcher *WebhookDispatcher
+
+// WebhookDispatcher delivers the webhook deliveries of the outbox. Deliveries are claimed before they are attempted
+// so that every instance can run a dispatcher, and those claimed by an instance that went away are attempted again
+// once their claim runs out.
+type WebhookDispatcher struct {
+	client  *http.Client
+	workers chan struct{}
+}
+
+func GetWebhookDispatcher() *WebhookDispatcher {
+	return webhookDispatcher
+}
+
+func NewWebhookDispatcher() *WebhookDispatcher {
+	// webhooks of local setups are allowed to reach the host they run on
+	client := http_client.GetPublicClient()
+	if config.GetConfig().Mode == "local" {
+		client = http_client.GetClient()
+	}
+
+	webhookDispatcher = &WebhookDispatcher{
+		client:  client,
+		workers: make(chan struct{}, consts.WebhookWorkers),
+	}
+
+	return webhookDispatcher
+}
+
+// EmitWebhookEvent queues the event for delivery to every webhook of the channel subscribed to it. The deliveries
+// are in the outbox once it returns, so they survive a restart.
+func EmitWebhookEvent(ctx context.Context, chain, appID, event string, data any) {
+	if GetWebhookDispatcher() == nil {
+		return
+	}
+
+	log := utilities.NewLoggerWithFields("EmitWebhookEvent", map[string]interface{}{
+		"chain":  chain,
+		"app_id": appID,
+		"event":  event,
+	})
+
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := db.GetChannelWebhooks(ctx, chain, appID)
+	if err != nil {
+		log.WithError(err).Error("failed to get channel webhooks")
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !utilities.ContainsString(webhook.Events, event) {
+			continue
+		}
+
+		now := utilities.TimeNow()
+		payload := entities.WebhookPayload{
+			ID:          uuidLib.NewString(),
+			Event:       event,
+			Chain:       chain,
+			Channel:     appID,
+			CreatedTime: now,
+			Data:        data,
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			log.WithError(err).Errorf("failed to marshal payload for webhook %s", webhook.ID)
+			continue
+		}
+
+		item := &entities.WebhookOutboxItem{
+			Shard: webhookOutboxShard(payload.ID),
+			Delivery: entities.WebhookDelivery{
+				ID:          payload.ID,
+				WebhookID:   webhook.ID,
+				Chain:       chain,
+				Channel:     appID,
+				Event:       event,
+				Payload:     string(body),
+				CreatedTime: now,
+			},
+			NextAttempt: now,
+			LeaseUntil:  now,
+		}
+		if err = db.InsertWebhookOutbox(ctx, item); err != nil {
+			log.WithError(err).Errorf("failed to queue delivery to webhook %s", webhook.ID)
+		}
+	}
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+// Receivers recompute it to verify that a delivery came from notiboy and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SpawnSender attempts the deliveries of the outbox as they become due until ctx is done
+func (d *WebhookDispatcher) SpawnSender(ctx context.Context) {
+	log := utilities.NewLogger("Webhook.SpawnSender")
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+		case <-ctx.Done():
+			log.Infof("Shutting down")
+			return
+		}
+	}
+}
+
+// dispatchDue claims the deliveries due for an attempt and attempts them on the workers, a delivery is claimed
+// only once a worker is free for it
+func (d *WebhookDispatcher) dispatchDue(ctx context.Context) {
+	log := utilities.NewLogger("Webhook.dispatchDue")
+
+	for shard := 0; shard < consts.WebhookOutboxShards; shard++ {
+		items, err := db.ListDueWebhookOutbox(ctx, shard, utilities.TimeNow())
+		if err != nil {
+			log.WithError(err).Errorf("failed to get deliveries of shard %d", shard)
+			continue
+		}
+
+		for i := range items {
+			item := &items[i]
+
+			select {
+			case d.workers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			claimed, err := db.ClaimWebhookOutbox(ctx, item, utilities.TimeNow().Add(webhookLease))
+			if err != nil || !claimed {
+				if err != nil {
+					log.WithError(err).Errorf("failed to claim delivery %s", item.Delivery.ID)
+				}
+				<-d.workers
+				continue
+			}
+
+			go func() {
+				defer func() {
+					<-d.workers
+				}()
+
+				d.deliver(ctx, item)
+			}()
+		}
+	}
+}
+
+// deliver makes one attempt of the claimed delivery, records its outcome and reschedules it if it failed
+func (d *WebhookDispatcher) deliver(ctx context.Context, item *entities.WebhookOutboxItem) {
+	log := utilities.NewLoggerWithFields("Webhook.deliver", map[string]interface{}{
+		"webhook":  item.Delivery.WebhookID,
+		"delivery": item.Delivery.ID,
+	})
+
+	// the outcome is recorded even when shutting down interrupts the attempt
+	dbCtx := context.WithoutCancel(ctx)
+
+	webhook, err := d.webhook(dbCtx, &item.Delivery)
+	if err != nil {
+		log.WithError(err).Error("failed to get webhook")
+		return
+	}
+	if webhook == nil {
+		log.Info("webhook was deleted, dropping delivery")
+		if err = db.DeleteWebhookOutbox(dbCtx, item); err != nil {
+			log.WithError(err).Error("failed to remove webhook delivery")
+		}
+		return
+	}
+
+	delivery := &item.Delivery
+	delivery.Attempts++
+	delivery.StatusCode, delivery.Error = 0, ""
+
+	statusCode, err := d.post(ctx, webhook.URL, webhook.Secret, delivery)
+	delivery.StatusCode = statusCode
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.UpdatedTime = utilities.TimeNow()
+
+	if err := db.UpsertWebhookDelivery(dbCtx, delivery); err != nil {
+		log.WithError(err).Error("failed to record webhook delivery")
+	}
+
+	nextAttempt, retry := nextWebhookAttempt(delivery.Attempts, delivery.UpdatedTime)
+	switch {
+	case delivery.Success:
+	case !retry:
+		log.WithError(err).Warnf("giving up webhook delivery after %d attempts", delivery.Attempts)
+	default:
+		log.WithError(err).Debugf("webhook delivery attempt %d failed, retrying", delivery.Attempts)
+
+		item.NextAttempt = nextAttempt
+		if err = db.RescheduleWebhookOutbox(dbCtx, item); err != nil {
+			log.WithError(err).Error("failed to reschedule webhook delivery")
+		}
+		return
+	}
+
+	if err = db.DeleteWebhookOutbox(dbCtx, item); err != nil {
+		log.WithError(err).Error("failed to remove webhook delivery")
+	}
+}
+
+// webhook returns the webhook the delivery is for, nil if it was deleted since
+func (d *WebhookDispatcher) webhook(ctx context.Context, delivery *entities.WebhookDelivery) (*entities.Webhook, error) {
+	webhooks, err := db.GetChannelWebhooks(ctx, delivery.Chain, delivery.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		if webhooks[i].ID == delivery.WebhookID {
+			return &webhooks[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// nextWebhookAttempt returns when a delivery that failed its attempts so far is attempted next, and false once it
+// is given up
+func nextWebhookAttempt(attempts int, failedAt time.Time) (time.Time, bool) {
+	if attempts < 1 || attempts > len(webhookRetryDelays) {
+		return time.Time{}, false
+	}
+
+	return failedAt.Add(webhookRetryDelays[attempts-1]), true
+}
+
+// webhookOutboxShard spreads the deliveries of the outbox over its shards
+func webhookOutboxShard(id string) int {
+	return int(crc32.ChecksumIEEE([]byte(id)) % consts.WebhookOutboxShards)
+}
+
+func (d *WebhookDispatcher) post(
+	ctx context.Context, url, secret string, delivery *entities.WebhookDelivery,
+) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(
+		WebhookSignatureHeader,
+		fmt.Sprintf("t=%d,v1=%s", timestamp, SignWebhookPayload(secret, timestamp, body)),
+	)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
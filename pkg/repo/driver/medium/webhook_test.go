@@ -0,0 +1,96 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+func Test_nextWebhookAttempt(t *testing.T) {
+	failedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		attempts  int
+		want      time.Time
+		wantRetry bool
+	}{
+		{attempts: 1, want: failedAt.Add(time.Minute), wantRetry: true},
+		{attempts: 2, want: failedAt.Add(5 * time.Minute), wantRetry: true},
+		{attempts: len(webhookRetryDelays), want: failedAt.Add(2 * time.Hour), wantRetry: true},
+		{attempts: len(webhookRetryDelays) + 1, wantRetry: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempts), func(t *testing.T) {
+			got, retry := nextWebhookAttempt(tt.attempts, failedAt)
+			if retry != tt.wantRetry || !got.Equal(tt.want) {
+				t.Errorf("nextWebhookAttempt() = %v, %v, want %v, %v", got, retry, tt.want, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func Test_webhookOutboxShard(t *testing.T) {
+	for _, id := range []string{"", "a", "4f9f4d5e-8f62-4a0b-9c0e-1a2b3c4d5e6f"} {
+		shard := webhookOutboxShard(id)
+		if shard < 0 || shard >= consts.WebhookOutboxShards {
+			t.Errorf("webhookOutboxShard(%q) = %d, out of range", id, shard)
+		}
+		if shard != webhookOutboxShard(id) {
+			t.Errorf("webhookOutboxShard(%q) is not stable", id)
+		}
+	}
+}
+
+func TestWebhookDispatcher_post(t *testing.T) {
+	delivery := &entities.WebhookDelivery{
+		ID:      "delivery",
+		Event:   consts.WebhookOptin,
+		Payload: `{"event":"optin"}`,
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "delivered", status: http.StatusNoContent, wantStatus: http.StatusNoContent},
+		{name: "rejected", status: http.StatusInternalServerError, wantStatus: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				var timestamp int64
+				var signature string
+				_, err := fmt.Sscanf(r.Header.Get(WebhookSignatureHeader), "t=%d,v1=%s", &timestamp, &signature)
+				if err != nil || signature != SignWebhookPayload("secret", timestamp, body) {
+					t.Errorf("signature %q doesn't match the body", r.Header.Get(WebhookSignatureHeader))
+				}
+				if r.Header.Get(WebhookEventHeader) != delivery.Event || r.Header.Get(WebhookDeliveryHeader) != delivery.ID {
+					t.Errorf("unexpected headers %v", r.Header)
+				}
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			d := &WebhookDispatcher{client: server.Client()}
+			status, err := d.post(context.Background(), server.URL, "secret", delivery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("post() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("post() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
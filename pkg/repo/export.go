@@ -0,0 +1,444 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type ExportRepo struct {
+	db   *gocql.Session
+	conf *config.NotiboyConfModel
+}
+
+// ExportRepoImply is an interface that defines the contract for collecting the data stored for an account.
+type ExportRepoImply interface {
+	ExportAccount(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error
+}
+
+// ExportWriter receives an account export section by section as it is read, the items of a list section one
+// at a time, so that an export never has to be held in memory.
+type ExportWriter interface {
+	// Value writes the section name holding v
+	Value(name string, v interface{}) error
+	// List starts the section name, a list of the items written up to the next section
+	List(name string) error
+	Item(v interface{}) error
+}
+
+func NewExportRepo(db *gocql.Session, conf *config.NotiboyConfModel) ExportRepoImply {
+	return &ExportRepo{db: db, conf: conf}
+}
+
+// ExportAccount writes everything stored for the account of user to w. Secrets like tokens are left out.
+func (repo *ExportRepo) ExportAccount(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	log := utilities.NewLoggerWithFields(
+		"ExportAccount", map[string]interface{}{
+			"chain":   user.Chain,
+			"address": user.Address,
+		},
+	)
+
+	userInfo, err := db.GetUserModel(ctx, user.Chain, user.Address)
+	if err != nil {
+		log.WithError(err).Error("failed to get user info")
+		return err
+	}
+
+	account := entities.ExportedAccount{
+		UserIdentifier: user,
+		ExportedAt:     utilities.TimeNow(),
+	}
+	if account.DNS, err = repo.getDNS(ctx, user); err != nil {
+		log.WithError(err).Error("failed to export dns name")
+		return fmt.Errorf("failed to export dns name: %w", err)
+	}
+
+	optins := userInfo.Optins
+	if optins == nil {
+		optins = make([]string, 0)
+	}
+
+	steps := []struct {
+		name string
+		fn   func(context.Context, entities.UserIdentifier, ExportWriter) error
+	}{
+		{"account", exportValue("account", account)},
+		{"user info", exportValue("user_info", userInfo)},
+		{"optins", exportValue("optins", optins)},
+		{"inbox", repo.exportInbox},
+		{"personal chats", repo.exportPersonalChats},
+		{"groups", repo.exportGroups},
+		{"group chats", repo.exportGroupChats},
+		{"chat lists", repo.exportChatLists},
+		{"fcm devices", repo.exportDevices},
+		{"personal access tokens", repo.exportPATs},
+		{"billing", repo.exportBilling},
+		{"verifications", repo.exportVerifications},
+	}
+
+	for _, step := range steps {
+		if err = step.fn(ctx, user, w); err != nil {
+			log.WithError(err).Errorf("failed to export %s", step.name)
+			return fmt.Errorf("failed to export %s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// exportValue is an export step writing the section name holding v
+func exportValue(name string, v interface{}) func(context.Context, entities.UserIdentifier, ExportWriter) error {
+	return func(_ context.Context, _ entities.UserIdentifier, w ExportWriter) error {
+		return w.Value(name, v)
+	}
+}
+
+func (repo *ExportRepo) exportInbox(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	var lastRead time.Time
+
+	query := fmt.Sprintf(
+		`SELECT last_read FROM %s.%s WHERE address = ? AND chain = ?`,
+		repo.conf.DB.Keyspace, consts.NotificationReadStatus,
+	)
+	err := repo.db.Query(query, user.Address, user.Chain).WithContext(ctx).Scan(&lastRead)
+	if err != nil {
+		if !errors.Is(err, gocql.ErrNotFound) {
+			return err
+		}
+	}
+
+	query = fmt.Sprintf(
+		`SELECT uuid, app_id, channel_name, logo, created_time, hash, link, message, type, verified, edited_time FROM %s.%s WHERE chain = ? AND receiver = ?`,
+		repo.conf.DB.Keyspace, consts.NotificationInfo,
+	)
+
+	if err = w.List("inbox"); err != nil {
+		return err
+	}
+
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	err = eachReadNotification(
+		iter, lastRead, func(notification entities.ReadNotification) error {
+			return w.Item(notification)
+		},
+	)
+	if err != nil {
+		iter.Close()
+		return err
+	}
+
+	return iter.Close()
+}
+
+func (repo *ExportRepo) exportPersonalChats(
+	ctx context.Context, user entities.UserIdentifier, w ExportWriter,
+) error {
+	query := fmt.Sprintf(
+		`SELECT user_b, sender, message, uuid, status, sent_time FROM %s.%s WHERE chain = ? AND user_a = ?`,
+		repo.conf.DB.Keyspace, consts.ChatUserTable,
+	)
+
+	if err := w.List("personal_chats"); err != nil {
+		return err
+	}
+
+	msg := entities.UserChat{
+		Chain: user.Chain,
+		UserA: user.Address,
+	}
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&msg.UserB, &msg.Sender, &msg.Message, &msg.Uuid, &msg.Status, &msg.SentTime) {
+		if err := w.Item(msg); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// getGroups returns the groups the user is a member of
+func (repo *ExportRepo) getGroups(ctx context.Context, user entities.UserIdentifier) ([]string, error) {
+	var gids []string
+
+	query := fmt.Sprintf(
+		`SELECT gids FROM %s.%s WHERE chain = ? AND user = ?`,
+		repo.conf.DB.Keyspace, consts.ChatUserGroupTable,
+	)
+	err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&gids)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return nil, err
+	}
+
+	return gids, nil
+}
+
+// exportGroups exports the groups the user is a member of
+func (repo *ExportRepo) exportGroups(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	gids, err := repo.getGroups(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT name, description, owner, admins, users, blocked_users, created_at FROM %s.%s WHERE chain = ? AND gid = ?`,
+		repo.conf.DB.Keyspace, consts.ChatGroupInfoTable,
+	)
+
+	if err = w.List("groups"); err != nil {
+		return err
+	}
+
+	for _, gid := range gids {
+		info := entities.GroupChatInfo{
+			Chain: user.Chain,
+			GID:   gid,
+		}
+		iter := repo.db.Query(query, user.Chain, gid).WithContext(ctx).Iter()
+		for iter.Scan(
+			&info.Name, &info.Description, &info.Owner, &info.Admins, &info.Users, &info.BlockedUsers,
+			&info.CreatedAt,
+		) {
+			if err = w.Item(info); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		if err = iter.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportGroupChats exports the messages of the groups the user is a member of
+func (repo *ExportRepo) exportGroupChats(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	gids, err := repo.getGroups(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT sender, message, uuid, status, sent_time FROM %s.%s WHERE chain = ? AND gid = ?`,
+		repo.conf.DB.Keyspace, consts.ChatGroupTable,
+	)
+
+	if err = w.List("group_chats"); err != nil {
+		return err
+	}
+
+	for _, gid := range gids {
+		msg := entities.GroupChat{
+			Chain: user.Chain,
+			GID:   gid,
+		}
+		iter := repo.db.Query(query, user.Chain, gid).WithContext(ctx).Iter()
+		for iter.Scan(&msg.Sender, &msg.Message, &msg.UUID, &msg.Status, &msg.SentTime) {
+			if err = w.Item(msg); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		if err = iter.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportChatLists exports the block and contact lists of the user
+func (repo *ExportRepo) exportChatLists(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	for _, list := range []struct {
+		tbl     string
+		column  string
+		section string
+	}{
+		{consts.ChatUserBlockTable, "blocked_users", "blocked_users"},
+		{consts.ChatUserContactsTable, "contacts", "contacts"},
+	} {
+		users := make([]string, 0)
+
+		query := fmt.Sprintf(
+			`SELECT %s FROM %s.%s WHERE chain = ? AND user = ?`,
+			list.column, repo.conf.DB.Keyspace, list.tbl,
+		)
+		err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&users)
+		if err != nil {
+			if !errors.Is(err, gocql.ErrNotFound) {
+				return err
+			}
+		}
+		if users == nil {
+			users = make([]string, 0)
+		}
+
+		if err = w.Value(list.section, users); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *ExportRepo) exportDevices(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	query := fmt.Sprintf(
+		`SELECT device_id, updated FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.FcmTable,
+	)
+
+	if err := w.List("fcm_devices"); err != nil {
+		return err
+	}
+
+	var device entities.ExportedDevice
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&device.DeviceID, &device.Updated) {
+		if err := w.Item(device); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// exportPATs exports the metadata of the personal access tokens, the tokens themselves are left out
+func (repo *ExportRepo) exportPATs(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	query := fmt.Sprintf(
+		`SELECT name, uuid, kind, created, description, scopes, expires_at, last_used, last_used_ip FROM %s.%s
+	WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.PATInfo,
+	)
+
+	if err := w.List("personal_access_tokens"); err != nil {
+		return err
+	}
+
+	var (
+		pat      entities.PATTokens
+		lastUsed time.Time
+	)
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(
+		&pat.Name, &pat.UUID, &pat.Kind, &pat.Created, &pat.Description, &pat.Scopes, &pat.ExpiresAt, &lastUsed,
+		&pat.LastUsedIP,
+	) {
+		pat.LastUsed = nil
+		if !lastUsed.IsZero() {
+			used := lastUsed
+			pat.LastUsed = &used
+		}
+		if err := w.Item(pat); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+func (repo *ExportRepo) exportBilling(ctx context.Context, user entities.UserIdentifier, w ExportWriter) error {
+	billing := entities.ExportedBilling{
+		Records: make([]entities.BillingRecords, 0),
+	}
+
+	query := fmt.Sprintf(
+		`SELECT membership, expiry, updated, balance FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.BillingTable,
+	)
+	if err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(
+		&billing.Membership, &billing.Expiry, &billing.Updated, &billing.Balance,
+	); err != nil {
+		if !errors.Is(err, gocql.ErrNotFound) {
+			return err
+		}
+	}
+
+	query = fmt.Sprintf(
+		`SELECT paid_time, txn_id, paid_amt FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.BillingHistoryTable,
+	)
+
+	var record entities.BillingRecords
+	iter := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&record.PaidTime, &record.TxnID, &record.PaidAmount) {
+		billing.Records = append(billing.Records, record)
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	return w.Value("billing", billing)
+}
+
+// exportVerifications exports the pending and completed medium verifications. verify_info is keyed by
+// token, the tokens of the user are looked up in user_verifications.
+func (repo *ExportRepo) exportVerifications(
+	ctx context.Context, user entities.UserIdentifier, w ExportWriter,
+) error {
+	tokenQuery := fmt.Sprintf(
+		`SELECT "token" FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.UserVerifications,
+	)
+	query := fmt.Sprintf(
+		`SELECT medium, metadata, expiry, sent, verified FROM %s.%s WHERE "token" = ? AND address = ? AND chain = ?`,
+		repo.conf.DB.Keyspace, consts.VerifyInfo,
+	)
+
+	if err := w.List("verifications"); err != nil {
+		return err
+	}
+
+	var token string
+	iter := repo.db.Query(tokenQuery, user.Chain, user.Address).WithContext(ctx).Iter()
+	for iter.Scan(&token) {
+		var record entities.VerificationRecord
+
+		// metadata holds the address on the medium, e.g. the email address
+		err := repo.db.Query(query, token, user.Address, user.Chain).WithContext(ctx).Scan(
+			&record.Medium, &record.MediumAddress, &record.Expiry, &record.Sent, &record.Verified,
+		)
+		if err != nil {
+			if errors.Is(err, gocql.ErrNotFound) {
+				// expired since it was listed
+				continue
+			}
+			iter.Close()
+			return err
+		}
+
+		if err = w.Item(record); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+func (repo *ExportRepo) getDNS(ctx context.Context, user entities.UserIdentifier) (string, error) {
+	var dns string
+
+	query := fmt.Sprintf(
+		`SELECT dns FROM %s.%s WHERE chain = ? AND user = ?`,
+		repo.conf.DB.Keyspace, consts.UserDNSTable,
+	)
+	err := repo.db.Query(query, user.Chain, user.Address).WithContext(ctx).Scan(&dns)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return "", err
+	}
+
+	return dns, nil
+}
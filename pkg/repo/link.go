@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type LinkRepo struct {
+	db          *gocql.Session
+	conf        *config.NotiboyConfModel
+	billingRepo BillingRepoImply
+}
+
+// LinkRepoImply is an interface that defines the contract for linking the accounts of a user on different chains.
+type LinkRepoImply interface {
+	LinkAccounts(ctx context.Context, user, linked entities.UserIdentifier, sharedBilling bool) (*entities.LinkedAccount, error)
+	ListLinkedAccounts(ctx context.Context, user entities.UserIdentifier) ([]entities.LinkedAccount, error)
+	UnlinkAccount(ctx context.Context, user entities.UserIdentifier, linkedChain string) error
+}
+
+func NewLinkRepo(db *gocql.Session, conf *config.NotiboyConfModel, billingRepo BillingRepoImply) LinkRepoImply {
+	return &LinkRepo{db: db, conf: conf, billingRepo: billingRepo}
+}
+
+// LinkAccounts links the accounts of user and linked, an account can be linked to one account per chain.
+// With shared billing the balance of user pays for both accounts and linked gets its membership.
+func (repo *LinkRepo) LinkAccounts(
+	ctx context.Context, user, linked entities.UserIdentifier, sharedBilling bool,
+) (*entities.LinkedAccount, error) {
+	log := utilities.NewLoggerWithFields(
+		"LinkAccounts", map[string]interface{}{
+			"chain":   user.Chain,
+			"address": user.Address,
+		},
+	)
+
+	var billingAccount *entities.UserIdentifier
+	if sharedBilling {
+		billingAccount = &user
+	}
+
+	created := utilities.TimeNow()
+
+	if err := repo.insertLink(ctx, user, linked, billingAccount, created); err != nil {
+		return nil, err
+	}
+
+	if err := repo.insertLink(ctx, linked, user, billingAccount, created); err != nil {
+		if err := repo.deleteLink(ctx, user, linked.Chain); err != nil {
+			log.WithError(err).Error("failed to undo link")
+		}
+		return nil, err
+	}
+
+	if sharedBilling {
+		userModel, err := db.GetUserModel(ctx, user.Chain, user.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = repo.billingRepo.SyncMembership(ctx, linked, userModel.Membership); err != nil {
+			log.WithError(err).Error("failed to share membership")
+			return nil, err
+		}
+	}
+
+	return &entities.LinkedAccount{
+		UserIdentifier: linked,
+		SharedBilling:  sharedBilling,
+		BillingAccount: billingAccount,
+		Created:        created,
+	}, nil
+}
+
+// ListLinkedAccounts retrieves the accounts linked to the account of user.
+func (repo *LinkRepo) ListLinkedAccounts(ctx context.Context, user entities.UserIdentifier) ([]entities.LinkedAccount, error) {
+	return db.GetLinkedAccounts(ctx, user.Chain, user.Address)
+}
+
+// UnlinkAccount removes the link of user to its account on linkedChain. If they shared billing, the account
+// which didn't own the balance falls back to the membership paid by its own balance.
+func (repo *LinkRepo) UnlinkAccount(ctx context.Context, user entities.UserIdentifier, linkedChain string) error {
+	log := utilities.NewLoggerWithFields(
+		"UnlinkAccount", map[string]interface{}{
+			"chain":   user.Chain,
+			"address": user.Address,
+		},
+	)
+
+	links, err := db.GetLinkedAccounts(ctx, user.Chain, user.Address)
+	if err != nil {
+		return err
+	}
+
+	var link *entities.LinkedAccount
+	for i := range links {
+		if links[i].Chain == linkedChain {
+			link = &links[i]
+		}
+	}
+	if link == nil {
+		return fmt.Errorf("no account linked on %s", linkedChain)
+	}
+
+	if err = repo.deleteLink(ctx, user, link.Chain); err != nil {
+		return err
+	}
+	if err = repo.deleteLink(ctx, link.UserIdentifier, user.Chain); err != nil {
+		return err
+	}
+
+	if !link.SharedBilling {
+		return nil
+	}
+
+	member := link.UserIdentifier
+	if *link.BillingAccount == link.UserIdentifier {
+		member = user
+	}
+
+	// SyncMembership keeps the tier paid by the own balance of the member
+	if err = repo.billingRepo.SyncMembership(ctx, member, consts.FreeTier.String()); err != nil {
+		log.WithError(err).Error("failed to restore membership")
+		return err
+	}
+
+	return nil
+}
+
+func (repo *LinkRepo) insertLink(
+	ctx context.Context, user, linked entities.UserIdentifier, billingAccount *entities.UserIdentifier,
+	created time.Time,
+) error {
+	var billingChain, billingAddress string
+	if billingAccount != nil {
+		billingChain, billingAddress = billingAccount.Chain, billingAccount.Address
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, linked_chain, linked_address, shared_billing, billing_chain, billing_address, created)
+	VALUES %s IF NOT EXISTS`,
+		repo.conf.DB.Keyspace, consts.LinkedAccounts, utilities.DBMultiValuePlaceholders(8),
+	)
+
+	applied, err := repo.db.Query(
+		query, user.Chain, user.Address, linked.Chain, linked.Address, billingAccount != nil, billingChain,
+		billingAddress, created,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to link account: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("account %s is already linked to an account on %s", user.Address, linked.Chain)
+	}
+
+	return nil
+}
+
+func (repo *LinkRepo) deleteLink(ctx context.Context, user entities.UserIdentifier, linkedChain string) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND address = ? AND linked_chain = ?`,
+		repo.conf.DB.Keyspace, consts.LinkedAccounts,
+	)
+	if err := repo.db.Query(query, user.Chain, user.Address, linkedChain).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to unlink account: %w", err)
+	}
+
+	return nil
+}
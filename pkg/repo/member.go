@@ -0,0 +1,206 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type ChannelMemberRepo struct {
+	db   *gocql.Session
+	conf *config.NotiboyConfModel
+}
+
+// ChannelMemberRepoImply is an interface that defines the contract for managing the members of a channel.
+type ChannelMemberRepoImply interface {
+	InviteMember(ctx context.Context, member *entities.ChannelMember) error
+	AcceptInvite(ctx context.Context, chain, appID, address string) error
+	UpdateMemberRole(ctx context.Context, chain, appID, address, role string) error
+	RemoveMember(ctx context.Context, chain, appID, address string) error
+	ListMembers(ctx context.Context, chain, appID string) ([]entities.ChannelMember, error)
+	ListUserChannelRoles(ctx context.Context, chain, address string) ([]entities.ChannelMember, error)
+	GetChannelRole(ctx context.Context, chain, appID, address string) (string, error)
+	GetChannelOwner(ctx context.Context, chain, appID string) (string, error)
+}
+
+func NewChannelMemberRepo(db *gocql.Session, conf *config.NotiboyConfModel) ChannelMemberRepoImply {
+	return &ChannelMemberRepo{db: db, conf: conf}
+}
+
+// InviteMember stores the invite of a user to the channel, it fails if the user is already a member or invited.
+func (repo *ChannelMemberRepo) InviteMember(ctx context.Context, member *entities.ChannelMember) error {
+	log := utilities.NewLoggerWithFields(
+		"InviteMember", map[string]interface{}{
+			"chain":   member.Chain,
+			"app_id":  member.AppID,
+			"address": member.Address,
+		},
+	)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, address, role, status, invited_by, created, updated) VALUES %s IF NOT EXISTS`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers, utilities.DBMultiValuePlaceholders(8),
+	)
+	applied, err := repo.db.Query(
+		query, member.Chain, member.AppID, member.Address, member.Role, member.Status, member.InvitedBy,
+		member.Created, member.Updated,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		log.WithError(err).Error("failed to invite member")
+		return fmt.Errorf("failed to invite member: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("user %s is already a member of channel %s or invited to it", member.Address, member.AppID)
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, app_id, role, status, invited_by, created, updated) VALUES %s`,
+		repo.conf.DB.Keyspace, consts.UserChannelRoles, utilities.DBMultiValuePlaceholders(8),
+	)
+	if err = repo.db.Query(
+		query, member.Chain, member.Address, member.AppID, member.Role, member.Status, member.InvitedBy,
+		member.Created, member.Updated,
+	).WithContext(ctx).Exec(); err != nil {
+		log.WithError(err).Error("failed to store channel role of user")
+		return fmt.Errorf("failed to store channel role of user: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptInvite makes an invited user a member of the channel.
+func (repo *ChannelMemberRepo) AcceptInvite(ctx context.Context, chain, appID, address string) error {
+	now := utilities.TimeNow()
+
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET status = ?, updated = ? WHERE chain = ? AND app_id = ? AND address = ? IF status = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+	applied, err := repo.db.Query(query, consts.MemberActive, now, chain, appID, address, consts.MemberInvited).
+		WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to accept invite: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("no pending invite to channel %s", appID)
+	}
+
+	return repo.updateUserChannelRole(ctx, chain, appID, address, "status", consts.MemberActive, now)
+}
+
+// UpdateMemberRole changes the role of a member of the channel, or the role an invited user is going to get.
+func (repo *ChannelMemberRepo) UpdateMemberRole(ctx context.Context, chain, appID, address, role string) error {
+	now := utilities.TimeNow()
+
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET role = ?, updated = ? WHERE chain = ? AND app_id = ? AND address = ? IF EXISTS`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+	applied, err := repo.db.Query(query, role, now, chain, appID, address).WithContext(ctx).
+		MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to update role of member: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("user %s is not a member of channel %s", address, appID)
+	}
+
+	return repo.updateUserChannelRole(ctx, chain, appID, address, "role", role, now)
+}
+
+func (repo *ChannelMemberRepo) updateUserChannelRole(
+	ctx context.Context, chain, appID, address, column, value string, updated time.Time,
+) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET %s = ?, updated = ? WHERE chain = ? AND address = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.UserChannelRoles, column,
+	)
+	if err := repo.db.Query(query, value, updated, chain, address, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to update channel role of user: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a member of the channel, or withdraws the invite of a user.
+func (repo *ChannelMemberRepo) RemoveMember(ctx context.Context, chain, appID, address string) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+	if err := repo.db.Query(query, chain, appID, address).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	query = fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND address = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.UserChannelRoles,
+	)
+	if err := repo.db.Query(query, chain, address, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to remove channel role of user: %w", err)
+	}
+
+	return nil
+}
+
+// ListMembers retrieves the members of the channel and the users invited to it.
+func (repo *ChannelMemberRepo) ListMembers(ctx context.Context, chain, appID string) ([]entities.ChannelMember, error) {
+	query := fmt.Sprintf(
+		`SELECT address, role, status, invited_by, created, updated FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+
+	members := make([]entities.ChannelMember, 0)
+	member := entities.ChannelMember{Chain: chain, AppID: appID}
+	iter := repo.db.Query(query, chain, appID).WithContext(ctx).Iter()
+	for iter.Scan(&member.Address, &member.Role, &member.Status, &member.InvitedBy, &member.Created, &member.Updated) {
+		members = append(members, member)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// ListUserChannelRoles retrieves the channels the user is a member of or invited to.
+func (repo *ChannelMemberRepo) ListUserChannelRoles(
+	ctx context.Context, chain, address string,
+) ([]entities.ChannelMember, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id, role, status, invited_by, created, updated FROM %s.%s WHERE chain = ? AND address = ?`,
+		repo.conf.DB.Keyspace, consts.UserChannelRoles,
+	)
+
+	roles := make([]entities.ChannelMember, 0)
+	role := entities.ChannelMember{Chain: chain, Address: address}
+	iter := repo.db.Query(query, chain, address).WithContext(ctx).Iter()
+	for iter.Scan(&role.AppID, &role.Role, &role.Status, &role.InvitedBy, &role.Created, &role.Updated) {
+		roles = append(roles, role)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list channel roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// GetChannelRole returns the role of address in the channel, an empty string if it has none.
+func (repo *ChannelMemberRepo) GetChannelRole(ctx context.Context, chain, appID, address string) (string, error) {
+	return db.GetChannelRole(ctx, chain, appID, address)
+}
+
+// GetChannelOwner returns the owner of the channel.
+func (repo *ChannelMemberRepo) GetChannelOwner(ctx context.Context, chain, appID string) (string, error) {
+	return db.GetChannelOwner(ctx, chain, appID)
+}
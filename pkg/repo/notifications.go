@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"notiboy/config"
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/pkg/repo/driver/medium"
 	"notiboy/utilities"
 
 	"github.com/gocql/gocql"
@@ -30,15 +33,26 @@ type NotificationRepoImply interface {
 	UpdateScheduledNotificationInfo(context.Context, *entities.ScheduleNotificationRequest) error
 	GetScheduledNotificationInfoBySender(context.Context, string, string) ([]entities.NotificationRequest, error)
 	InsertChannelSendMetrics(context.Context, entities.NotificationRequest, time.Time, int) error
-	InsertUserSendMetrics(context.Context, entities.NotificationRequest, time.Time, int) error
+	InsertUserSendMetrics(ctx context.Context, chain, address string, now time.Time, sent int) error
 	InsertNotificationChannelCounter(context.Context, entities.NotificationRequest, int) error
 	ListOfReadUsers(context.Context, entities.NotificationRequest) ([]string, error)
 	GetNotificationInfo(context.Context, entities.RequestNotification, int, []byte) (
 		[]entities.ReadNotification, []byte, error,
 	)
+	GetLinkedNotificationInfo(context.Context, []entities.RequestNotification, int, *entities.InboxCursor) (
+		[]entities.ReadNotification, error,
+	)
 	InsertGlobalStats(context.Context, entities.NotificationRequest, int) error
-	NotificationReachCount(context.Context, string, int, []byte) ([]entities.NotificationReach, []byte, error)
-	InsertNotificationSentCountForReach(context.Context, entities.NotificationRequest, int) error
+	InsertNotificationReach(context.Context, entities.NotificationReachEvent) (bool, error)
+	GetNotificationReach(context.Context, *entities.SentNotification) (*entities.NotificationReach, error)
+	InsertSentNotificationInfo(context.Context, *entities.SentNotification) error
+	GetSentNotificationInfo(context.Context, string, string, string) (*entities.SentNotification, error)
+	RecallNotificationInfo(context.Context, *entities.SentNotification) error
+	InsertChannelRecallMetrics(context.Context, string, string, time.Time, int) error
+	InsertChannelClickMetrics(context.Context, string, string, string, time.Time) error
+	InsertChannelReadMetrics(context.Context, string, string, string, time.Time) error
+	EditNotificationInfo(context.Context, *entities.SentNotification, string, string, time.Time) error
+	GetNotificationRevisions(context.Context, string, string, string) ([]entities.NotificationRevision, error)
 }
 
 func NewNotificationRepo(db *gocql.Session, conf *config.NotiboyConfModel) NotificationRepoImply {
@@ -104,6 +118,27 @@ func (repo *NotificationRepo) UpdateScheduledNotificationInfo(
 	setVal = append(setVal, request.Chain, request.Schedule, request.Sender)
 
 	tblNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.ScheduledNotificationInfo)
+
+	if len(request.Receivers) != 0 {
+		// the new receivers are indexed for as long as the notification is kept, app_id is never updated
+		var ttl int
+		query := fmt.Sprintf(
+			`SELECT TTL(app_id) FROM %s WHERE chain = ? AND schedule = ? AND sender = ?`, tblNotificationInfo,
+		)
+		err := repo.db.Query(query, request.Chain, request.Schedule, request.Sender).Scan(&ttl)
+		if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+			log.WithError(err).Error("failed to retrieve schedule notification")
+			return err
+		}
+		if err == nil {
+			err = repo.indexScheduledNotification(request.Chain, request.Schedule, request.Sender, request.Receivers, ttl)
+			if err != nil {
+				log.WithError(err).Error("failed to index schedule notification")
+				return err
+			}
+		}
+	}
+
 	query := fmt.Sprintf(
 		`UPDATE %s SET %s WHERE chain = ? AND schedule = ? AND sender = ? IF EXISTS`,
 		tblNotificationInfo, strings.Join(set, ","),
@@ -157,11 +192,41 @@ func (repo *NotificationRepo) InsertScheduledNotificationInfo(
 		return err
 	}
 
+	users := append([]string{request.Sender}, request.Receivers...)
+	if err := repo.indexScheduledNotification(request.Chain, request.Schedule, request.Sender, users, ttl); err != nil {
+		log.WithError(err).Error("failed to index schedule notification")
+		return err
+	}
+
 	log.Debug("Schedule notification inserted")
 
 	return nil
 }
 
+// indexScheduledNotification records the scheduled notification against its sender and receivers in users, so
+// that it is found when one of them is deleted
+func (repo *NotificationRepo) indexScheduledNotification(
+	chain string, schedule time.Time, sender string, users []string, ttl int,
+) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, schedule, sender) VALUES (?, ?, ?, ?) USING TTL %d`,
+		config.GetConfig().DB.Keyspace, consts.UserScheduledNotifications, ttl,
+	)
+
+	for _, chunk := range utilities.Chunk(users, consts.ReceiverBatchSize) {
+		batch := repo.db.NewBatch(gocql.UnloggedBatch)
+		for _, user := range chunk {
+			batch.Query(query, chain, user, schedule, sender)
+		}
+
+		if err := repo.db.ExecuteBatch(batch); err != nil {
+			return fmt.Errorf("failed to index scheduled notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (repo *NotificationRepo) GetScheduledNotificationInfo(
 	_ context.Context, chain string, timeUntil time.Time,
 ) ([]entities.NotificationRequest, error) {
@@ -324,30 +389,19 @@ func (repo *NotificationRepo) InsertNotificationInfo(_ context.Context, request
 		return err
 	}
 
-	log.Debug("Notification sent")
-
-	return nil
-}
-
-// InsertNotificationSentCountForReach inserts the notification reach information into the notification repository.
-func (repo *NotificationRepo) InsertNotificationSentCountForReach(
-	_ context.Context, request entities.NotificationRequest, count int,
-) error {
-
-	log := utilities.NewLogger("InsertNotificationSentCountForReach")
-
-	tblNotificationSent := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationTotalSent)
-	query := fmt.Sprintf(
-		`INSERT INTO %s (hash, event_time, sent) VALUES (?, ?, ?) USING TTL %d`,
-		tblNotificationSent, config.GetConfig().TTL.Notifications,
+	tblUserNotifications := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.UserNotifications)
+	query = fmt.Sprintf(
+		`INSERT INTO %s (chain, receiver, app_id, uuid) VALUES (?, ?, ?, ?) USING TTL %d`,
+		tblUserNotifications, request.TTL,
 	)
-	if err := repo.db.Query(query, request.Hash, utilities.TimeNow(), count).Exec(); err != nil {
-		log.WithError(err).Error("failed to update notification sent count for reach")
+	if err = repo.db.Query(query, request.Chain, request.Receiver, request.Channel, request.UUID).Exec(); err != nil {
+		log.WithError(err).Error("failed to index notification by receiver")
 		return err
 	}
 
-	return nil
+	log.Debug("Notification sent")
 
+	return nil
 }
 
 // InsertChannelSendMetrics inserts the notification channel metrics into the notification repository.
@@ -373,8 +427,9 @@ func (repo *NotificationRepo) InsertChannelSendMetrics(
 	return nil
 }
 
+// InsertUserSendMetrics counts the notifications sent against the monthly quota of address.
 func (repo *NotificationRepo) InsertUserSendMetrics(
-	_ context.Context, request entities.NotificationRequest, now time.Time, sent int,
+	_ context.Context, chain, address string, now time.Time, sent int,
 ) error {
 	log := utilities.NewLogger("InsertUserSendMetrics")
 
@@ -386,7 +441,7 @@ func (repo *NotificationRepo) InsertUserSendMetrics(
 	)
 	err := repo.db.Query(
 		query,
-		request.Chain, request.Sender, now, utilities.ToDate(now), sent,
+		chain, address, now, utilities.ToDate(now), sent,
 	).Exec()
 	if err != nil {
 		log.WithError(err).Error("failed to insert")
@@ -474,6 +529,161 @@ func (repo *NotificationRepo) GetNotificationInfo(
 	log := utilities.NewLogger("GetNotificationInfo")
 
 	tblNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationInfo)
+
+	lastRead, err := repo.getLastRead(request)
+	if err != nil {
+		return notifications, nil, err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT uuid, app_id, channel_name, logo, created_time, hash, link, message, type, verified, edited_time FROM %s WHERE chain = ? AND receiver = ?`,
+		tblNotificationInfo,
+	)
+
+	iter := repo.db.Query(query, request.Chain, request.User).PageSize(pageSize).PageState(pageState).Iter()
+	currPageState := iter.PageState()
+
+	notifications = scanReadNotifications(iter, lastRead)
+
+	if err = iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve notifications")
+		return notifications, []byte{}, err
+	}
+
+	repo.markInboxRead(ctx, request, notifications)
+
+	return notifications, currPageState, nil
+}
+
+// GetLinkedNotificationInfo retrieves a page of the inbox merged from the inboxes of linked accounts, newest first.
+// Pages are split by time, a page holds the notifications created before the last one of the previous page.
+func (repo *NotificationRepo) GetLinkedNotificationInfo(
+	ctx context.Context, requests []entities.RequestNotification, pageSize int, after *entities.InboxCursor,
+) ([]entities.ReadNotification, error) {
+	var notifications = make([]entities.ReadNotification, 0)
+
+	for _, request := range requests {
+		lastRead, err := repo.getLastRead(request)
+		if err != nil {
+			return notifications, err
+		}
+
+		inbox, err := repo.getInboxPage(request, pageSize, after, lastRead)
+		if err != nil {
+			return notifications, err
+		}
+
+		notifications = append(notifications, inbox...)
+	}
+
+	sort.Slice(
+		notifications, func(i, j int) bool {
+			return inboxBefore(notifications[i], notifications[j])
+		},
+	)
+	if len(notifications) > pageSize {
+		notifications = notifications[:pageSize]
+	}
+
+	for _, request := range requests {
+		inbox := make([]entities.ReadNotification, 0, len(notifications))
+		for _, notification := range notifications {
+			if notification.Chain == request.Chain {
+				inbox = append(inbox, notification)
+			}
+		}
+
+		repo.markInboxRead(ctx, request, inbox)
+	}
+
+	return notifications, nil
+}
+
+// getInboxPage retrieves up to pageSize notifications of the inbox of request that come after the cursor
+// in the merged inbox. Notifications created at the time of the cursor are fetched separately, paging by
+// the creation time alone would skip those not returned yet.
+func (repo *NotificationRepo) getInboxPage(
+	request entities.RequestNotification, pageSize int, after *entities.InboxCursor, lastRead time.Time,
+) ([]entities.ReadNotification, error) {
+	log := utilities.NewLogger("getInboxPage")
+
+	tblNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationInfo)
+
+	query := fmt.Sprintf(
+		`SELECT uuid, app_id, channel_name, logo, created_time, hash, link, message, type, verified, edited_time FROM %s WHERE chain = ? AND receiver = ?`,
+		tblNotificationInfo,
+	)
+
+	var queries []*gocql.Query
+	if after == nil {
+		queries = append(queries, repo.db.Query(query+` LIMIT ?`, request.Chain, request.User, pageSize))
+	} else {
+		if uuid, ok := tiesAfter(request, *after); ok {
+			queries = append(
+				queries, repo.db.Query(
+					query+` AND created_time = ? AND uuid > ? LIMIT ?`,
+					request.Chain, request.User, after.CreatedTime, uuid, pageSize,
+				),
+			)
+		}
+		queries = append(
+			queries, repo.db.Query(
+				query+` AND created_time < ? LIMIT ?`, request.Chain, request.User, after.CreatedTime, pageSize,
+			),
+		)
+	}
+
+	inbox := make([]entities.ReadNotification, 0)
+	for _, q := range queries {
+		iter := q.Iter()
+		inbox = append(inbox, scanReadNotifications(iter, lastRead)...)
+		if err := iter.Close(); err != nil {
+			log.WithError(err).Error("failed to retrieve notifications")
+			return nil, err
+		}
+	}
+
+	for i := range inbox {
+		inbox[i].Chain = request.Chain
+		inbox[i].Receiver = request.User
+	}
+
+	return inbox, nil
+}
+
+// inboxBefore reports whether a comes before b in a merged inbox, newest first. Notifications created at the
+// same time are ordered by their inbox and uuid, the way each inbox is clustered.
+func inboxBefore(a, b entities.ReadNotification) bool {
+	if !a.CreatedTime.Equal(b.CreatedTime) {
+		return a.CreatedTime.After(b.CreatedTime)
+	}
+	if a.Chain != b.Chain {
+		return a.Chain < b.Chain
+	}
+	if a.Receiver != b.Receiver {
+		return a.Receiver < b.Receiver
+	}
+
+	return a.Uuid < b.Uuid
+}
+
+// tiesAfter returns the uuid after which the notifications of the inbox of request created at the time of the
+// cursor follow it in the merged inbox, and false when all of them came before it
+func tiesAfter(request entities.RequestNotification, cursor entities.InboxCursor) (string, bool) {
+	switch {
+	case request.Chain != cursor.Chain:
+		return "", request.Chain > cursor.Chain
+	case request.User != cursor.Receiver:
+		return "", request.User > cursor.Receiver
+	default:
+		return cursor.Uuid, true
+	}
+}
+
+// getLastRead returns the creation time of the newest notification read in the inbox of the user
+func (repo *NotificationRepo) getLastRead(request entities.RequestNotification) (time.Time, error) {
+	log := utilities.NewLogger("getLastRead")
+
 	tblNotificationReadStatus := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationReadStatus)
 
 	var lastRead time.Time
@@ -482,18 +692,55 @@ func (repo *NotificationRepo) GetNotificationInfo(
 	if err != nil {
 		if !errors.Is(err, gocql.ErrNotFound) {
 			log.WithError(err).Error("failed to get read status")
-			return notifications, nil, err
+			return lastRead, err
 		}
 	}
 
-	query = fmt.Sprintf(
-		`SELECT uuid, app_id, channel_name, logo, created_time, hash, link, message, type, verified FROM %s WHERE chain = ? AND receiver = ?`,
-		tblNotificationInfo,
+	return lastRead, nil
+}
+
+// markInboxRead updates the read status of the inbox of the user in the background with the notifications it was shown
+func (repo *NotificationRepo) markInboxRead(
+	ctx context.Context, request entities.RequestNotification, notifications []entities.ReadNotification,
+) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	log := utilities.NewLogger("markInboxRead")
+
+	data := &entities.UpdateReadStatusRequest{
+		Uuid:    notifications[0].Uuid,
+		Time:    notifications[0].CreatedTime,
+		Medium:  consts.Inapp,
+		Chain:   request.Chain,
+		Address: request.User,
+	}
+
+	go func() {
+		if err := repo.updateReadStatus(ctx, data, notifications); err != nil {
+			log.WithError(err).Error("failed to update read status")
+		}
+	}()
+}
+
+func scanReadNotifications(iter *gocql.Iter, lastRead time.Time) []entities.ReadNotification {
+	var notifications = make([]entities.ReadNotification, 0)
+
+	_ = eachReadNotification(
+		iter, lastRead, func(notification entities.ReadNotification) error {
+			notifications = append(notifications, notification)
+			return nil
+		},
 	)
 
-	iter := repo.db.Query(query, request.Chain, request.User).PageSize(pageSize).PageState(pageState).Iter()
-	currPageState := iter.PageState()
+	return notifications
+}
 
+// eachReadNotification calls fn with every notification of iter until it returns an error
+func eachReadNotification(
+	iter *gocql.Iter, lastRead time.Time, fn func(entities.ReadNotification) error,
+) error {
 	var (
 		uuid        string
 		appID       string
@@ -505,9 +752,12 @@ func (repo *NotificationRepo) GetNotificationInfo(
 		kind        string
 		logo        string
 		verified    bool
+		editedTime  time.Time
 	)
 
-	for iter.Scan(&uuid, &appID, &channelName, &logo, &createdTime, &hash, &link, &message, &kind, &verified) {
+	for iter.Scan(
+		&uuid, &appID, &channelName, &logo, &createdTime, &hash, &link, &message, &kind, &verified, &editedTime,
+	) {
 		seen := false
 		if createdTime.Before(lastRead) || createdTime == lastRead {
 			seen = true
@@ -526,32 +776,16 @@ func (repo *NotificationRepo) GetNotificationInfo(
 			Seen:        seen,
 			Verified:    verified,
 		}
-		notifications = append(notifications, notification)
-	}
-
-	if err = iter.Close(); err != nil {
-		log.WithError(err).Error("failed to retrieve notifications")
-		return notifications, []byte{}, err
-	}
-
-	if len(notifications) > 0 {
-		data := &entities.UpdateReadStatusRequest{
-			Uuid:    notifications[0].Uuid,
-			Time:    notifications[0].CreatedTime,
-			Medium:  consts.Inapp,
-			Chain:   request.Chain,
-			Address: request.User,
+		if !editedTime.IsZero() {
+			edited := editedTime
+			notification.EditedTime = &edited
+		}
+		if err := fn(notification); err != nil {
+			return err
 		}
-
-		go func() {
-			err = repo.updateReadStatus(ctx, data, notifications)
-			if err != nil {
-				log.WithError(err).Error("failed to update read status")
-			}
-		}()
 	}
 
-	return notifications, currPageState, nil
+	return nil
 }
 
 // updateReadStatus updates the read status of a notification for the specified UUID, chain, channel, and time.
@@ -561,14 +795,6 @@ func (repo *NotificationRepo) updateReadStatus(
 	log := utilities.NewLogger("updateReadStatus")
 
 	tblNotificationReadStatus := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationReadStatus)
-	tblNotificationEmailReach := fmt.Sprintf(
-		`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationEmailMediumReach,
-	)
-	tblNotificationDiscordReach := fmt.Sprintf(
-		`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationDiscordMediumReach,
-	)
-	tblNotificationAppReach := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationAppMediumReach)
-	tblNotificationChanMetrics := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.ChannelSentReadMetrics)
 
 	var prevLastRead time.Time
 	query := fmt.Sprintf(`SELECT last_read FROM %s WHERE address = ? AND chain = ? `, tblNotificationReadStatus)
@@ -587,44 +813,42 @@ func (repo *NotificationRepo) updateReadStatus(
 		return err
 	}
 
-	channelHashMap := make(map[string]string)
+	unseen := make([]entities.ReadNotification, 0, len(notifications))
 	for _, notification := range notifications {
 		if notification.Seen {
 			continue
 		}
-		channelHashMap[notification.Hash] = notification.AppID
+		unseen = append(unseen, notification)
 	}
 
-	log.Debugf("Updating metrics for %d notifications", len(channelHashMap))
-
-	for hash, appID := range channelHashMap {
-		query = `INSERT INTO %s (hash, event_time, read) VALUES (?, ?, ?) USING TTL ?`
-
-		switch data.Medium {
-		case consts.Discord:
-			query = fmt.Sprintf(query, tblNotificationDiscordReach)
-		case consts.Email:
-			query = fmt.Sprintf(query, tblNotificationEmailReach)
-		default:
-			query = fmt.Sprintf(query, tblNotificationAppReach)
-		}
-
-		err = repo.db.Query(query, hash, utilities.TimeNow(), 1, config.GetConfig().TTL.Metrics).Exec()
+	log.Debugf("Updating metrics for %d notifications", len(unseen))
+
+	for _, notification := range unseen {
+		first, err := repo.InsertNotificationReach(
+			ctx, entities.NotificationReachEvent{
+				Chain:    data.Chain,
+				Channel:  notification.AppID,
+				UUID:     notification.Uuid,
+				Medium:   data.Medium,
+				Event:    consts.ReachRead,
+				Receiver: data.Address,
+			},
+		)
 		if err != nil {
 			log.WithError(err).Error("failed to update notification reach")
 			continue
 		}
 
-		query = fmt.Sprintf(
-			`INSERT INTO %s (chain, channel, event_date, event_time, medium, sent, read) VALUES %s USING TTL %d`,
-			tblNotificationChanMetrics, utilities.DBMultiValuePlaceholders(7), config.GetConfig().TTL.Metrics,
-		)
+		if first {
+			medium.EmitWebhookEvent(
+				ctx, data.Chain, notification.AppID, consts.WebhookNotificationRead,
+				entities.NotificationWebhookData{UUID: notification.Uuid, Receiver: data.Address, Medium: data.Medium},
+			)
+		}
 
-		err = repo.db.Query(
-			query, data.Chain, appID, utilities.ToDate(utilities.TimeNow()), utilities.TimeNow(), data.Medium, 0, 1,
-		).Exec()
+		err = repo.InsertChannelReadMetrics(ctx, data.Chain, notification.AppID, data.Medium, utilities.TimeNow())
 		if err != nil {
-			log.WithError(err).Errorf("failed to update %s", tblNotificationChanMetrics)
+			log.WithError(err).Errorf("failed to update channel read metrics")
 			continue
 		}
 	}
@@ -632,67 +856,408 @@ func (repo *NotificationRepo) updateReadStatus(
 	return nil
 }
 
-// NotificationReachCount retrieves the notification reach count for a specific UUID.
-func (repo *NotificationRepo) NotificationReachCount(
-	_ context.Context, uuid string, pageSize int, pageState []byte,
-) ([]entities.NotificationReach, []byte, error) {
+// InsertNotificationReach records a sent, delivered, read or clicked event of a notification.
+// It reports whether the event was recorded for the first time for the receiver.
+func (repo *NotificationRepo) InsertNotificationReach(
+	ctx context.Context, event entities.NotificationReachEvent,
+) (bool, error) {
+	return db.InsertNotificationReach(ctx, event)
+}
 
-	var totalSent int
-	var mediumReadCount map[string]int
-	log := utilities.NewLogger("NotificationReachCount")
+// GetNotificationReach aggregates the reach events of a sent notification per medium.
+func (repo *NotificationRepo) GetNotificationReach(
+	_ context.Context, notification *entities.SentNotification,
+) (*entities.NotificationReach, error) {
+	log := utilities.NewLoggerWithFields(
+		"GetNotificationReach", map[string]interface{}{
+			"channel": notification.Channel,
+			"chain":   notification.Chain,
+			"uuid":    notification.UUID,
+		},
+	)
+
+	tblNotificationReach := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationReach)
+	query := fmt.Sprintf(
+		`SELECT medium, event, COUNT(*), MIN(event_time) FROM %s WHERE chain = ? AND app_id = ? AND uuid = ? GROUP BY medium, event`,
+		tblNotificationReach,
+	)
+
+	reach := &entities.NotificationReach{
+		UUID:     notification.UUID,
+		SentTime: notification.CreatedTime,
+		Mediums:  make(map[string]*entities.MediumReach),
+	}
+
+	var (
+		medium    string
+		event     string
+		count     int
+		firstTime time.Time
+	)
 
-	tblNotificationMediumReach := fmt.Sprintf(
-		`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationEmailMediumReach,
+	iter := repo.db.Query(query, notification.Chain, notification.Channel, notification.UUID).Iter()
+	for iter.Scan(&medium, &event, &count, &firstTime) {
+		addReachCount(reach, medium, event, count, firstTime)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve notification reach")
+		return nil, err
+	}
+
+	return reach, nil
+}
+
+// addReachCount adds the count of a reach event of a medium, first happened at firstTime, to reach. The time to
+// first read of the notification is the earliest of those of its mediums.
+func addReachCount(reach *entities.NotificationReach, medium, event string, count int, firstTime time.Time) {
+	mediumReach, ok := reach.Mediums[medium]
+	if !ok {
+		mediumReach = new(entities.MediumReach)
+		reach.Mediums[medium] = mediumReach
+	}
+
+	switch event {
+	case consts.ReachSent:
+		mediumReach.Sent = count
+	case consts.ReachDelivered:
+		mediumReach.Delivered = count
+	case consts.ReachRead:
+		mediumReach.Read = count
+		mediumReach.TimeToFirstReadSecs = int64(firstTime.Sub(reach.SentTime).Seconds())
+
+		reach.TimeToFirstReadSecs = mediumReach.TimeToFirstReadSecs
+		for _, other := range reach.Mediums {
+			if other.Read > 0 && other.TimeToFirstReadSecs < reach.TimeToFirstReadSecs {
+				reach.TimeToFirstReadSecs = other.TimeToFirstReadSecs
+			}
+		}
+	case consts.ReachClicked:
+		mediumReach.Clicked = count
+	}
+}
+
+// InsertSentNotificationInfo records a sent notification along with its receivers against the channel.
+func (repo *NotificationRepo) InsertSentNotificationInfo(_ context.Context, request *entities.SentNotification) error {
+	log := utilities.NewLoggerWithFields(
+		"InsertSentNotificationInfo", map[string]interface{}{
+			"channel": request.Channel,
+			"chain":   request.Chain,
+			"uuid":    request.UUID,
+		},
 	)
-	tblNotificationReach := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationTotalSent)
 
+	tblSentNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.SentNotificationInfo)
 	query := fmt.Sprintf(
-		`
-	SELECT
-	medium_read_count
-	FROM
-	%s
-	WHERE 
-	uuid = ?`, tblNotificationMediumReach,
+		`INSERT INTO %s
+	(chain, app_id, uuid, sender, receivers, message, link, type, created_time, recalled)
+	 VALUES %s USING TTL %d`, tblSentNotificationInfo, utilities.DBMultiValuePlaceholders(10), request.TTL,
 	)
 
-	iter1 := repo.db.Query(query, uuid).PageSize(pageSize).PageState([]byte{}).Iter()
-	if !iter1.Scan(&mediumReadCount) {
-		log.Errorf("no data found in notification_medium_reach for UUID: %s", uuid)
-		return nil, []byte{}, fmt.Errorf("no data found in notification_medium_reach for uuid provided")
+	params := []interface{}{
+		request.Chain,
+		request.Channel,
+		request.UUID,
+		request.Sender,
+		request.Receivers,
+		request.Message,
+		request.Link,
+		request.Type,
+		request.CreatedTime,
+		false,
 	}
-	if err := iter1.Close(); err != nil {
-		log.WithError(err).Error("failed to close iterator")
-		return nil, []byte{}, err
+
+	// the notifications of a channel are listed by the time they were sent
+	batch := repo.db.NewBatch(gocql.LoggedBatch)
+	batch.Query(query, params...)
+	batch.Query(
+		fmt.Sprintf(
+			`INSERT INTO %s.%s (chain, app_id, created_time, uuid) VALUES (?, ?, ?, ?) USING TTL %d`,
+			config.GetConfig().DB.Keyspace, consts.SentNotificationsByTime, request.TTL,
+		), request.Chain, request.Channel, request.CreatedTime, request.UUID,
+	)
+	if err := repo.db.ExecuteBatch(batch); err != nil {
+		log.WithError(err).Error("failed to execute query for inserting sent notification")
+		return err
+	}
+
+	return nil
+}
+
+// GetSentNotificationInfo retrieves a sent notification of a channel by its uuid.
+func (repo *NotificationRepo) GetSentNotificationInfo(
+	_ context.Context, chain, appID, uuid string,
+) (*entities.SentNotification, error) {
+	log := utilities.NewLoggerWithFields(
+		"GetSentNotificationInfo", map[string]interface{}{
+			"channel": appID,
+			"chain":   chain,
+			"uuid":    uuid,
+		},
+	)
+
+	tblSentNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.SentNotificationInfo)
+	query := fmt.Sprintf(
+		`SELECT sender, receivers, message, link, type, created_time, discord_messages, recalled, TTL(message)
+	FROM %s WHERE chain = ? AND app_id = ? AND uuid = ?`, tblSentNotificationInfo,
+	)
+
+	notification := &entities.SentNotification{
+		Chain:   chain,
+		Channel: appID,
+		UUID:    uuid,
+	}
+
+	var ttl *int
+	err := repo.db.Query(query, chain, appID, uuid).Scan(
+		&notification.Sender, &notification.Receivers, &notification.Message, &notification.Link,
+		&notification.Type, &notification.CreatedTime, &notification.DiscordMessages, &notification.Recalled, &ttl,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("notification %s not found: %w", uuid, err)
+		}
+		log.WithError(err).Error("failed to retrieve sent notification")
+		return nil, err
+	}
+
+	if ttl != nil {
+		notification.TTL = *ttl
+	}
+
+	return notification, nil
+}
+
+// RecallNotificationInfo removes a sent notification from the inbox of all its receivers
+// and marks it as recalled. The inbox rows are deleted ReceiverBatchSize receivers at a time, the notification is
+// marked as recalled only once all of them are, so a recall that fails midway can be retried.
+func (repo *NotificationRepo) RecallNotificationInfo(ctx context.Context, notification *entities.SentNotification) error {
+	log := utilities.NewLoggerWithFields(
+		"RecallNotificationInfo", map[string]interface{}{
+			"channel": notification.Channel,
+			"chain":   notification.Chain,
+			"uuid":    notification.UUID,
+		},
+	)
+
+	tblNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationInfo)
+	tblSentNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.SentNotificationInfo)
+
+	query := fmt.Sprintf(
+		`DELETE FROM %s WHERE chain = ? AND receiver = ? AND created_time = ? AND uuid = ?`, tblNotificationInfo,
+	)
+	for _, receivers := range utilities.Chunk(notification.Receivers, consts.ReceiverBatchSize) {
+		batch := repo.db.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, receiver := range receivers {
+			batch.Query(query, notification.Chain, receiver, notification.CreatedTime, notification.UUID)
+		}
+
+		if err := repo.db.ExecuteBatch(batch); err != nil {
+			log.WithError(err).Error("failed to delete notification from receivers")
+			return err
+		}
 	}
 
 	query = fmt.Sprintf(
-		`
-			SELECT
-			COUNT(hash)
-			FROM 
-			%s
-			WHERE
-			hash = ?
-			`, tblNotificationReach,
+		`UPDATE %s USING TTL %d SET recalled = ? WHERE chain = ? AND app_id = ? AND uuid = ?`,
+		tblSentNotificationInfo, notification.TTL,
 	)
+	if err := repo.db.Query(query, true, notification.Chain, notification.Channel, notification.UUID).Exec(); err != nil {
+		log.WithError(err).Error("failed to mark notification as recalled")
+		return err
+	}
+
+	log.Infof("Notification recalled from %d receivers", len(notification.Receivers))
 
-	iter2 := repo.db.Query(query, uuid).PageSize(pageSize).PageState(pageState).Iter()
-	currPageState := iter2.PageState()
-	if !iter2.Scan(&totalSent) {
-		log.Errorf("no data found in notification_total_sent for UUID: %s", uuid)
-		return nil, []byte{}, fmt.Errorf("no data found in notification_total_sent for uuid provided")
+	return nil
+}
+
+// InsertChannelRecallMetrics inserts the count of recalled notifications into the channel metrics.
+func (repo *NotificationRepo) InsertChannelRecallMetrics(
+	_ context.Context, chain, appID string, now time.Time, recalled int,
+) error {
+	log := utilities.NewLogger("InsertChannelRecallMetrics")
+
+	tblNotificationChanMetrics := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.ChannelSentReadMetrics)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, channel, event_time, event_date, sent, read, recalled) VALUES %s USING TTL %d`,
+		tblNotificationChanMetrics, utilities.DBMultiValuePlaceholders(7), config.GetConfig().TTL.Metrics,
+	)
+	err := repo.db.Query(query, chain, appID, now, utilities.ToDate(now), 0, 0, recalled).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to insert")
+		return err
 	}
-	if err := iter2.Close(); err != nil {
-		log.WithError(err).Error("failed to close iterator")
-		return nil, []byte{}, err
+
+	return nil
+}
+
+// EditNotificationInfo replaces the message and link of a sent notification for all its receivers.
+// The previous message and link are kept as a revision. Receivers are edited ReceiverBatchSize at a time and only
+// those still having the notification in their inbox.
+func (repo *NotificationRepo) EditNotificationInfo(
+	ctx context.Context, notification *entities.SentNotification, message, link string, editedTime time.Time,
+) error {
+	log := utilities.NewLoggerWithFields(
+		"EditNotificationInfo", map[string]interface{}{
+			"channel": notification.Channel,
+			"chain":   notification.Chain,
+			"uuid":    notification.UUID,
+		},
+	)
+
+	tblNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationInfo)
+	tblSentNotificationInfo := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.SentNotificationInfo)
+	tblNotificationRevisions := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationRevisions)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, app_id, uuid, edited_time, message, link) VALUES %s USING TTL %d`,
+		tblNotificationRevisions, utilities.DBMultiValuePlaceholders(6), notification.TTL,
+	)
+	err := repo.db.Query(
+		query, notification.Chain, notification.Channel, notification.UUID, editedTime,
+		notification.Message, notification.Link,
+	).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to insert notification revision")
+		return err
+	}
+
+	existsQuery := fmt.Sprintf(
+		`SELECT receiver FROM %s WHERE chain = ? AND receiver IN ? AND created_time = ? AND uuid = ?`,
+		tblNotificationInfo,
+	)
+	query = fmt.Sprintf(
+		`UPDATE %s USING TTL %d SET message = ?, link = ?, edited_time = ?, updated_time = ?
+	WHERE chain = ? AND receiver = ? AND created_time = ? AND uuid = ?`,
+		tblNotificationInfo, notification.TTL,
+	)
+	edited := 0
+	for _, receivers := range utilities.Chunk(notification.Receivers, consts.ReceiverBatchSize) {
+		// receivers may have deleted the notification from their inbox, or it may have expired, an UPDATE would
+		// bring it back
+		existing, err := repo.existingInboxRows(ctx, existsQuery, notification, receivers)
+		if err != nil {
+			log.WithError(err).Error("failed to get inbox rows of receivers")
+			return err
+		}
+		if len(existing) == 0 {
+			continue
+		}
+
+		batch := repo.db.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, receiver := range existing {
+			batch.Query(
+				query, message, link, editedTime, editedTime,
+				notification.Chain, receiver, notification.CreatedTime, notification.UUID,
+			)
+		}
+
+		if err = repo.db.ExecuteBatch(batch); err != nil {
+			log.WithError(err).Error("failed to edit notification of receivers")
+			return err
+		}
+		edited += len(existing)
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s USING TTL %d SET message = ?, link = ? WHERE chain = ? AND app_id = ? AND uuid = ?`,
+		tblSentNotificationInfo, notification.TTL,
+	)
+	err = repo.db.Query(query, message, link, notification.Chain, notification.Channel, notification.UUID).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to edit sent notification")
+		return err
+	}
+
+	log.Infof("Notification edited for %d receivers", edited)
+
+	return nil
+}
+
+// existingInboxRows returns which of receivers still have the notification in their inbox
+func (repo *NotificationRepo) existingInboxRows(
+	ctx context.Context, query string, notification *entities.SentNotification, receivers []string,
+) ([]string, error) {
+	existing := make([]string, 0, len(receivers))
+
+	var receiver string
+	iter := repo.db.Query(query, notification.Chain, receivers, notification.CreatedTime, notification.UUID).
+		WithContext(ctx).Iter()
+	for iter.Scan(&receiver) {
+		existing = append(existing, receiver)
+	}
+
+	return existing, iter.Close()
+}
+
+// GetNotificationRevisions retrieves the previous revisions of a notification, latest first.
+func (repo *NotificationRepo) GetNotificationRevisions(
+	_ context.Context, chain, appID, uuid string,
+) ([]entities.NotificationRevision, error) {
+	log := utilities.NewLogger("GetNotificationRevisions")
+
+	var revisions = make([]entities.NotificationRevision, 0)
+
+	tblNotificationRevisions := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.NotificationRevisions)
+	query := fmt.Sprintf(
+		`SELECT edited_time, message, link FROM %s WHERE chain = ? AND app_id = ? AND uuid = ?`,
+		tblNotificationRevisions,
+	)
+
+	var revision entities.NotificationRevision
+	iter := repo.db.Query(query, chain, appID, uuid).Iter()
+	for iter.Scan(&revision.EditedTime, &revision.Message, &revision.Link) {
+		revisions = append(revisions, revision)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve notification revisions")
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// InsertChannelClickMetrics inserts a notification link click into the channel metrics.
+func (repo *NotificationRepo) InsertChannelClickMetrics(
+	_ context.Context, chain, appID, medium string, now time.Time,
+) error {
+	log := utilities.NewLogger("InsertChannelClickMetrics")
+
+	tblNotificationChanMetrics := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.ChannelSentReadMetrics)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, channel, event_time, event_date, medium, sent, read, clicked) VALUES %s USING TTL %d`,
+		tblNotificationChanMetrics, utilities.DBMultiValuePlaceholders(8), config.GetConfig().TTL.Metrics,
+	)
+	err := repo.db.Query(query, chain, appID, now, utilities.ToDate(now), medium, 0, 0, 1).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to insert")
+		return err
 	}
 
-	notificationReach := entities.NotificationReach{
-		MediumReadCount: mediumReadCount,
-		TotalSent:       totalSent,
+	return nil
+}
+
+// InsertChannelReadMetrics inserts a notification read on a medium into the channel metrics.
+func (repo *NotificationRepo) InsertChannelReadMetrics(
+	_ context.Context, chain, appID, medium string, now time.Time,
+) error {
+	log := utilities.NewLogger("InsertChannelReadMetrics")
+
+	tblNotificationChanMetrics := fmt.Sprintf(`%s.%s`, config.GetConfig().DB.Keyspace, consts.ChannelSentReadMetrics)
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (chain, channel, event_date, event_time, medium, sent, read) VALUES %s USING TTL %d`,
+		tblNotificationChanMetrics, utilities.DBMultiValuePlaceholders(7), config.GetConfig().TTL.Metrics,
+	)
+	err := repo.db.Query(query, chain, appID, utilities.ToDate(now), now, medium, 0, 1).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to insert")
+		return err
 	}
 
-	result := []entities.NotificationReach{notificationReach}
-	return result, currPageState, nil
+	return nil
 }
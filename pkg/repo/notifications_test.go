@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+func Test_addReachCount(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type count struct {
+		medium    string
+		event     string
+		count     int
+		firstTime time.Time
+	}
+	tests := []struct {
+		name   string
+		counts []count
+		want   *entities.NotificationReach
+	}{
+		{
+			name: "no reach",
+			want: &entities.NotificationReach{Mediums: map[string]*entities.MediumReach{}},
+		},
+		{
+			name: "events of one medium",
+			counts: []count{
+				{consts.Inapp, consts.ReachSent, 3, sent},
+				{consts.Inapp, consts.ReachDelivered, 2, sent},
+				{consts.Inapp, consts.ReachRead, 1, sent.Add(time.Minute)},
+				{consts.Inapp, consts.ReachClicked, 1, sent.Add(2 * time.Minute)},
+			},
+			want: &entities.NotificationReach{
+				Mediums: map[string]*entities.MediumReach{
+					consts.Inapp: {Sent: 3, Delivered: 2, Read: 1, Clicked: 1, TimeToFirstReadSecs: 60},
+				},
+				TimeToFirstReadSecs: 60,
+			},
+		},
+		{
+			name: "earliest read across mediums",
+			counts: []count{
+				{consts.Inapp, consts.ReachRead, 1, sent.Add(time.Hour)},
+				{consts.Email, consts.ReachRead, 2, sent.Add(time.Minute)},
+				{consts.Discord, consts.ReachSent, 4, sent},
+			},
+			want: &entities.NotificationReach{
+				Mediums: map[string]*entities.MediumReach{
+					consts.Inapp:   {Read: 1, TimeToFirstReadSecs: 3600},
+					consts.Email:   {Read: 2, TimeToFirstReadSecs: 60},
+					consts.Discord: {Sent: 4},
+				},
+				TimeToFirstReadSecs: 60,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				reach := &entities.NotificationReach{SentTime: sent, Mediums: make(map[string]*entities.MediumReach)}
+				for _, c := range tt.counts {
+					addReachCount(reach, c.medium, c.event, c.count, c.firstTime)
+				}
+				tt.want.SentTime = sent
+
+				if !reflect.DeepEqual(reach, tt.want) {
+					t.Errorf("addReachCount() = %+v, want %+v", reach, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_inboxBefore(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	notifications := []entities.ReadNotification{
+		{CreatedTime: created, Chain: consts.Xrpl, Receiver: "r", Uuid: "a"},
+		{CreatedTime: created.Add(-time.Second), Chain: consts.Algorand, Receiver: "A", Uuid: "a"},
+		{CreatedTime: created, Chain: consts.Algorand, Receiver: "A", Uuid: "b"},
+		{CreatedTime: created.Add(time.Second), Chain: consts.Xrpl, Receiver: "r", Uuid: "c"},
+		{CreatedTime: created, Chain: consts.Algorand, Receiver: "A", Uuid: "a"},
+	}
+	sort.Slice(
+		notifications, func(i, j int) bool {
+			return inboxBefore(notifications[i], notifications[j])
+		},
+	)
+
+	want := []entities.ReadNotification{
+		{CreatedTime: created.Add(time.Second), Chain: consts.Xrpl, Receiver: "r", Uuid: "c"},
+		{CreatedTime: created, Chain: consts.Algorand, Receiver: "A", Uuid: "a"},
+		{CreatedTime: created, Chain: consts.Algorand, Receiver: "A", Uuid: "b"},
+		{CreatedTime: created, Chain: consts.Xrpl, Receiver: "r", Uuid: "a"},
+		{CreatedTime: created.Add(-time.Second), Chain: consts.Algorand, Receiver: "A", Uuid: "a"},
+	}
+	if !reflect.DeepEqual(notifications, want) {
+		t.Errorf("merged inbox = %v, want %v", notifications, want)
+	}
+}
+
+func Test_tiesAfter(t *testing.T) {
+	cursor := entities.InboxCursor{
+		CreatedTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Chain:       consts.Algorand,
+		Receiver:    "B",
+		Uuid:        "uuid",
+	}
+
+	tests := []struct {
+		name     string
+		request  entities.RequestNotification
+		wantUuid string
+		wantOk   bool
+	}{
+		{
+			name:     "inbox of the cursor",
+			request:  entities.RequestNotification{Chain: consts.Algorand, User: "B"},
+			wantUuid: "uuid",
+			wantOk:   true,
+		},
+		{
+			name:    "inbox merged before the inbox of the cursor",
+			request: entities.RequestNotification{Chain: consts.Algorand, User: "A"},
+		},
+		{
+			name:    "inbox merged after the inbox of the cursor",
+			request: entities.RequestNotification{Chain: consts.Xrpl, User: "A"},
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				uuid, ok := tiesAfter(tt.request, cursor)
+				if uuid != tt.wantUuid || ok != tt.wantOk {
+					t.Errorf("tiesAfter() = %q, %v, want %q, %v", uuid, ok, tt.wantUuid, tt.wantOk)
+				}
+			},
+		)
+	}
+}
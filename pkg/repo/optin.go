@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"notiboy/config"
 	"notiboy/pkg/cache"
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/medium"
 	"notiboy/utilities"
 
 	"github.com/gocql/gocql"
@@ -22,13 +25,27 @@ type OptinRepo struct {
 
 // OptinRepoImply represents the interface for the repository that handles opt-in and opt-out operations.
 type OptinRepoImply interface {
-	Optin(context.Context, string, string, string) error
+	Optin(ctx context.Context, chain, appId, userAddr, source string) error
 	Optout(context.Context, string, string, string) error
 	OptinoutStatistics(
 		ctx context.Context, chain, appId, statType, startDate, endDate string,
 	) (*entities.ChannelOptInOutStats, error)
 	VerifyUserOptin(ctx context.Context, chain, appId, userId string) (string, error)
 	OptinUsers(ctx context.Context, chain, appId string) ([]string, error)
+	GetChannelVisibility(ctx context.Context, chain, appID string) (string, error)
+	CreateInvite(ctx context.Context, invite *entities.ChannelInvite) error
+	ListInvites(ctx context.Context, chain, appID string) ([]entities.ChannelInvite, error)
+	RevokeInvite(ctx context.Context, chain, appID, code string) error
+	RedeemInvite(ctx context.Context, chain, appID, code string) error
+	CreateOptinRequest(ctx context.Context, request *entities.OptinRequest) error
+	GetOptinRequest(ctx context.Context, chain, appID, address string) (*entities.OptinRequest, error)
+	ListOptinRequests(ctx context.Context, chain, appID string) ([]entities.OptinRequest, error)
+	DeleteOptinRequest(ctx context.Context, chain, appID, address string) error
+	SetTokenGate(ctx context.Context, gate *entities.ChannelTokenGate) error
+	GetTokenGate(ctx context.Context, chain, appID string) (*entities.ChannelTokenGate, error)
+	ListTokenGates(ctx context.Context) ([]entities.ChannelTokenGate, error)
+	DeleteTokenGate(ctx context.Context, chain, appID string) error
+	TokenHolding(ctx context.Context, gate *entities.ChannelTokenGate, address string) (float64, error)
 }
 
 // NewUserRepo
@@ -36,8 +53,9 @@ func NewOptinRepo(db *gocql.Session, conf *config.NotiboyConfModel, repo Channel
 	return &OptinRepo{db: db, conf: conf, repo: repo}
 }
 
-// Optin adds a user to a channel by updating the channel user and user info in the database.
-func (user *OptinRepo) Optin(_ context.Context, chain, appId, userAddr string) error {
+// Optin adds a user to a channel by updating the channel user and user info in the database, attributing the
+// opt-in to the source the user came from.
+func (user *OptinRepo) Optin(ctx context.Context, chain, appId, userAddr, source string) error {
 	now := utilities.TimeNow()
 
 	// Get the status of the channel
@@ -116,11 +134,47 @@ func (user *OptinRepo) Optin(_ context.Context, chain, appId, userAddr string) e
 		return err
 	}
 
+	batch := user.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(
+			`INSERT INTO %s.%s (chain, app_id, address, optin_time, optout_time, source) VALUES (?, ?, ?, ?, null, ?)`,
+			user.conf.DB.Keyspace, consts.ChannelSubscribers,
+		), chain, appId, userAddr, now, source,
+	)
+	batch.Query(
+		fmt.Sprintf(
+			`INSERT INTO %s.%s (chain, app_id, optin_time, address) VALUES (?, ?, ?, ?)`,
+			user.conf.DB.Keyspace, consts.SubscribersByOptin,
+		), chain, appId, now, userAddr,
+	)
+	if err := user.db.ExecuteBatch(batch); err != nil {
+		log.WithError(err).Error("Failed to record channel subscriber")
+		return err
+	}
+
+	if err := user.recordSourceMetrics(chain, appId, source, 1, 0, now); err != nil {
+		log.WithError(err).Error("Failed to record channel opt-in source metrics")
+		return err
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, app_id) VALUES (?, ?, ?)`,
+		user.conf.DB.Keyspace, consts.UserSubscriptions,
+	)
+	if err := user.db.Query(query, chain, userAddr, appId).Exec(); err != nil {
+		log.WithError(err).Error("Failed to record user subscription")
+		return err
+	}
+
+	medium.EmitWebhookEvent(
+		ctx, chain, appId, consts.WebhookOptin, entities.OptinWebhookData{Address: userAddr, Source: source},
+	)
+
 	return nil
 }
 
 // Optout removes a user from a channel by updating the channel user and user info in the database.
-func (user *OptinRepo) Optout(_ context.Context, chain, appId, userAddr string) error {
+func (user *OptinRepo) Optout(ctx context.Context, chain, appId, userAddr string) error {
 	log := utilities.NewLoggerWithFields(
 		"optout",
 		map[string]interface{}{
@@ -202,6 +256,54 @@ func (user *OptinRepo) Optout(_ context.Context, chain, appId, userAddr string)
 		return err
 	}
 
+	// the opt-out is attributed to the source the subscriber opted in from
+	var (
+		source    = consts.OptinSourceDirect
+		optinTime time.Time
+	)
+	query = fmt.Sprintf(
+		`SELECT source, optin_time FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		user.conf.DB.Keyspace, consts.ChannelSubscribers,
+	)
+	if err := user.db.Query(query, chain, appId, userAddr).Scan(&source, &optinTime); err != nil &&
+		!errors.Is(err, gocql.ErrNotFound) {
+		log.WithError(err).Error("failed to query source of channel subscriber")
+		return err
+	}
+	if source == "" {
+		source = consts.OptinSourceDirect
+	}
+
+	if err := user.recordSourceMetrics(chain, appId, source, 0, 1, now); err != nil {
+		log.WithError(err).Error("failed to insert source metrics")
+		return err
+	}
+
+	batch := user.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(
+			`UPDATE %s.%s SET optout_time = ? WHERE chain = ? AND app_id = ? AND address = ?`,
+			user.conf.DB.Keyspace, consts.ChannelSubscribers,
+		), now, chain, appId, userAddr,
+	)
+	// subscribers who opted in before opt-in times were recorded aren't indexed
+	if !optinTime.IsZero() {
+		batch.Query(
+			fmt.Sprintf(
+				`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND optin_time = ? AND address = ?`,
+				user.conf.DB.Keyspace, consts.SubscribersByOptin,
+			), chain, appId, optinTime, userAddr,
+		)
+	}
+	if err := user.db.ExecuteBatch(batch); err != nil {
+		log.WithError(err).Error("failed to record channel subscriber opt-out")
+		return err
+	}
+
+	medium.EmitWebhookEvent(
+		ctx, chain, appId, consts.WebhookOptout, entities.OptinWebhookData{Address: userAddr, Source: source},
+	)
+
 	return nil
 }
 
@@ -256,23 +358,108 @@ func (user *OptinRepo) OptinoutStatistics(
 		return nil, err
 	}
 
+	sources, err := user.sourceStatistics(chain, appId, statType, startDate, endDate)
+	if err != nil {
+		log.WithError(err).Error("Failed to get channel source metrics")
+		return nil, err
+	}
+
 	userList := fmt.Sprintf(
 		`SELECT users FROM %s.%s WHERE chain = ? AND app_id = ?`,
 		config.GetConfig().DB.Keyspace, consts.ChannelUsers,
 	)
 	var users []string
 
-	err := user.db.Query(userList, chain, appId).Scan(&users)
+	err = user.db.Query(userList, chain, appId).Scan(&users)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user list for channel")
 	}
 
 	return &entities.ChannelOptInOutStats{
 		OptInOut:   userData,
+		Sources:    sources,
 		TotalUsers: int64(len(users)),
 	}, nil
 }
 
+// sourceStatistics retrieves the opt-ins and opt-outs of a channel by source, within a given time range or
+// overall, the sources that brought the most subscribers first.
+func (user *OptinRepo) sourceStatistics(
+	chain, appId, statType, startDate, endDate string,
+) ([]entities.OptInOutSource, error) {
+	query := fmt.Sprintf(
+		`SELECT event_date, source, SUM("optin") AS optin, SUM("optout") AS optout FROM %s.%s `+
+			`WHERE chain = ? AND channel = ?`,
+		user.conf.DB.Keyspace, consts.ChannelSourceMetrics,
+	)
+	args := []interface{}{chain, appId}
+	if statType == "range" {
+		query += " AND event_date >= ? AND event_date <= ?"
+		args = append(args, startDate, endDate)
+	}
+	query += " GROUP BY event_date, source"
+
+	var (
+		metric  sourceMetric
+		metrics []sourceMetric
+	)
+	iter := user.db.Query(query, args...).Iter()
+	for iter.Scan(&metric.Date, &metric.Source, &metric.Optin, &metric.Optout) {
+		metrics = append(metrics, metric)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return groupSourceMetrics(metrics), nil
+}
+
+// sourceMetric is the opt-ins and opt-outs of a channel from a source on a day
+type sourceMetric struct {
+	entities.OptInOut
+	Source string
+}
+
+// groupSourceMetrics totals the daily metrics, which come latest day first, by source. Each source lists its days
+// oldest first, and the sources that brought the most subscribers come first.
+func groupSourceMetrics(metrics []sourceMetric) []entities.OptInOutSource {
+	sources := make([]entities.OptInOutSource, 0)
+	index := make(map[string]int)
+	for _, metric := range metrics {
+		i, ok := index[metric.Source]
+		if !ok {
+			i = len(sources)
+			index[metric.Source] = i
+			sources = append(
+				sources, entities.OptInOutSource{Source: metric.Source, OptInOut: make([]entities.OptInOut, 0)},
+			)
+		}
+
+		sources[i].Optin += metric.Optin
+		sources[i].Optout += metric.Optout
+		sources[i].OptInOut = append([]entities.OptInOut{metric.OptInOut}, sources[i].OptInOut...)
+	}
+
+	sort.SliceStable(
+		sources, func(i, j int) bool {
+			return sources[i].Optin > sources[j].Optin
+		},
+	)
+
+	return sources
+}
+
+// recordSourceMetrics records an opt-in or opt-out of a channel against the source of the subscriber
+func (user *OptinRepo) recordSourceMetrics(chain, appId, source string, optin, optout int, now time.Time) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, channel, event_date, source, event_time, optin, optout)
+	VALUES (?, ?, ?, ?, ?, ?, ?) USING TTL %d`,
+		user.conf.DB.Keyspace, consts.ChannelSourceMetrics, config.GetConfig().TTL.Metrics,
+	)
+
+	return user.db.Query(query, chain, appId, utilities.ToDate(now), source, now, optin, optout).Exec()
+}
+
 // VerifyUserOptin verifies if a user is opted in to a specific channel.
 func (user *OptinRepo) VerifyUserOptin(_ context.Context, chain, appId, userId string) (string, error) {
 
@@ -0,0 +1,258 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// maxRedeemAttempts bounds retrying to redeem an invite redeemed concurrently by others
+const maxRedeemAttempts = 5
+
+// GetChannelVisibility retrieves the visibility of the channel.
+func (user *OptinRepo) GetChannelVisibility(ctx context.Context, chain, appID string) (string, error) {
+	query := fmt.Sprintf(
+		`SELECT visibility FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		user.conf.DB.Keyspace, channelInfoTable(chain, appID),
+	)
+
+	var visibility string
+	if err := user.db.Query(query, chain, appID).WithContext(ctx).Scan(&visibility); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return "", fmt.Errorf("channel %s not found", appID)
+		}
+		return "", fmt.Errorf("failed to get visibility of channel: %w", err)
+	}
+
+	return channelVisibility(visibility), nil
+}
+
+// CreateInvite stores an invite to the channel.
+func (user *OptinRepo) CreateInvite(ctx context.Context, invite *entities.ChannelInvite) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, code, max_uses, uses, expires, revoked, created_by, created)
+		VALUES %s IF NOT EXISTS`,
+		user.conf.DB.Keyspace, consts.ChannelInvites, utilities.DBMultiValuePlaceholders(9),
+	)
+
+	applied, err := user.db.Query(
+		query, invite.Chain, invite.AppID, invite.Code, invite.MaxUses, 0, invite.Expires, false, invite.CreatedBy,
+		invite.Created,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to store invite: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("invite %s already exists", invite.Code)
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, created_by, app_id, code) VALUES (?, ?, ?, ?)`,
+		user.conf.DB.Keyspace, consts.UserChannelInvites,
+	)
+	err = user.db.Query(query, invite.Chain, invite.CreatedBy, invite.AppID, invite.Code).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to index invite: %w", err)
+	}
+
+	return nil
+}
+
+// ListInvites retrieves the invites to the channel.
+func (user *OptinRepo) ListInvites(ctx context.Context, chain, appID string) ([]entities.ChannelInvite, error) {
+	query := fmt.Sprintf(
+		`SELECT code, max_uses, uses, expires, revoked, created_by, created FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		user.conf.DB.Keyspace, consts.ChannelInvites,
+	)
+
+	invites := make([]entities.ChannelInvite, 0)
+	iter := user.db.Query(query, chain, appID).WithContext(ctx).Iter()
+	for {
+		var (
+			invite  = entities.ChannelInvite{Chain: chain, AppID: appID}
+			expires time.Time
+		)
+		if !iter.Scan(
+			&invite.Code, &invite.MaxUses, &invite.Uses, &expires, &invite.Revoked, &invite.CreatedBy,
+			&invite.Created,
+		) {
+			break
+		}
+		invite.Expires = optionalTime(expires)
+		invites = append(invites, invite)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+
+	return invites, nil
+}
+
+// RevokeInvite revokes the invite to the channel, it can't be redeemed anymore.
+func (user *OptinRepo) RevokeInvite(ctx context.Context, chain, appID, code string) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET revoked = true WHERE chain = ? AND app_id = ? AND code = ? IF EXISTS`,
+		user.conf.DB.Keyspace, consts.ChannelInvites,
+	)
+
+	applied, err := user.db.Query(query, chain, appID, code).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("invite %s not found", code)
+	}
+
+	return nil
+}
+
+// RedeemInvite uses the invite to the channel once, provided it is neither revoked, expired nor used up.
+func (user *OptinRepo) RedeemInvite(ctx context.Context, chain, appID, code string) error {
+	query := fmt.Sprintf(
+		`SELECT max_uses, uses, expires, revoked FROM %s.%s WHERE chain = ? AND app_id = ? AND code = ?`,
+		user.conf.DB.Keyspace, consts.ChannelInvites,
+	)
+	redeemQuery := fmt.Sprintf(
+		`UPDATE %s.%s SET uses = ? WHERE chain = ? AND app_id = ? AND code = ? IF uses = ?`,
+		user.conf.DB.Keyspace, consts.ChannelInvites,
+	)
+
+	for attempt := 0; attempt < maxRedeemAttempts; attempt++ {
+		var (
+			maxUses, uses int
+			expires       time.Time
+			revoked       bool
+		)
+		err := user.db.Query(query, chain, appID, code).WithContext(ctx).Scan(&maxUses, &uses, &expires, &revoked)
+		if err != nil {
+			if errors.Is(err, gocql.ErrNotFound) {
+				return errors.New("invalid invite")
+			}
+			return fmt.Errorf("failed to get invite: %w", err)
+		}
+
+		switch {
+		case revoked:
+			return errors.New("the invite was revoked")
+		case !expires.IsZero() && utilities.TimeNow().After(expires):
+			return errors.New("the invite has expired")
+		case maxUses > 0 && uses >= maxUses:
+			return errors.New("the invite is used up")
+		}
+
+		applied, err := user.db.Query(redeemQuery, uses+1, chain, appID, code, uses).WithContext(ctx).
+			MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			return fmt.Errorf("failed to redeem invite: %w", err)
+		}
+		if applied {
+			return nil
+		}
+	}
+
+	return errors.New("the invite is being redeemed by others, try again")
+}
+
+// CreateOptinRequest stores the request of the user to opt in to the channel.
+func (user *OptinRepo) CreateOptinRequest(ctx context.Context, request *entities.OptinRequest) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, address, source, created) VALUES (?, ?, ?, ?, ?) IF NOT EXISTS`,
+		user.conf.DB.Keyspace, consts.ChannelOptinRequests,
+	)
+
+	applied, err := user.db.Query(
+		query, request.Chain, request.AppID, request.Address, request.Source, request.Created,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to store optin request: %w", err)
+	}
+	if !applied {
+		return errors.New("you have already requested to opt in to the channel")
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, app_id) VALUES (?, ?, ?)`,
+		user.conf.DB.Keyspace, consts.UserOptinRequests,
+	)
+	if err = user.db.Query(query, request.Chain, request.Address, request.AppID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to index optin request: %w", err)
+	}
+
+	return nil
+}
+
+// GetOptinRequest retrieves the request of the user to opt in to the channel.
+func (user *OptinRepo) GetOptinRequest(
+	ctx context.Context, chain, appID, address string,
+) (*entities.OptinRequest, error) {
+	query := fmt.Sprintf(
+		`SELECT source, created FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+		user.conf.DB.Keyspace, consts.ChannelOptinRequests,
+	)
+
+	request := entities.OptinRequest{Chain: chain, AppID: appID, Address: address}
+	err := user.db.Query(query, chain, appID, address).WithContext(ctx).Scan(&request.Source, &request.Created)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("%s hasn't requested to opt in to the channel", address)
+		}
+		return nil, fmt.Errorf("failed to get optin request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// ListOptinRequests retrieves the requests to opt in to the channel waiting for approval.
+func (user *OptinRepo) ListOptinRequests(ctx context.Context, chain, appID string) ([]entities.OptinRequest, error) {
+	query := fmt.Sprintf(
+		`SELECT address, source, created FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		user.conf.DB.Keyspace, consts.ChannelOptinRequests,
+	)
+
+	requests := make([]entities.OptinRequest, 0)
+	iter := user.db.Query(query, chain, appID).WithContext(ctx).Iter()
+	for {
+		request := entities.OptinRequest{Chain: chain, AppID: appID}
+		if !iter.Scan(&request.Address, &request.Source, &request.Created) {
+			break
+		}
+		requests = append(requests, request)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list optin requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// DeleteOptinRequest removes the request of the user to opt in to the channel.
+func (user *OptinRepo) DeleteOptinRequest(ctx context.Context, chain, appID, address string) error {
+	batch := user.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND address = ?`,
+			user.conf.DB.Keyspace, consts.ChannelOptinRequests,
+		), chain, appID, address,
+	)
+	batch.Query(
+		fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE chain = ? AND address = ? AND app_id = ?`,
+			user.conf.DB.Keyspace, consts.UserOptinRequests,
+		), chain, address, appID,
+	)
+
+	if err := user.db.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to delete optin request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	chainLib "notiboy/pkg/repo/driver/chain"
+	"notiboy/utilities"
+)
+
+// SetTokenGate stores the holding required to opt in to the channel, replacing the previous one.
+func (user *OptinRepo) SetTokenGate(ctx context.Context, gate *entities.ChannelTokenGate) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, kind, asset, issuer, min_balance, updated_by, updated) VALUES %s`,
+		user.conf.DB.Keyspace, consts.ChannelTokenGates, utilities.DBMultiValuePlaceholders(8),
+	)
+
+	if err := user.db.Query(
+		query, gate.Chain, gate.AppID, gate.Kind, gate.Asset, gate.Issuer, gate.MinBalance, gate.UpdatedBy,
+		gate.Updated,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to store token gate: %w", err)
+	}
+
+	return nil
+}
+
+// GetTokenGate retrieves the holding required to opt in to the channel, nil if the channel isn't token gated.
+func (user *OptinRepo) GetTokenGate(ctx context.Context, chain, appID string) (*entities.ChannelTokenGate, error) {
+	query := fmt.Sprintf(
+		`SELECT kind, asset, issuer, min_balance, updated_by, updated FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		user.conf.DB.Keyspace, consts.ChannelTokenGates,
+	)
+
+	gate := entities.ChannelTokenGate{Chain: chain, AppID: appID}
+	err := user.db.Query(query, chain, appID).WithContext(ctx).Scan(
+		&gate.Kind, &gate.Asset, &gate.Issuer, &gate.MinBalance, &gate.UpdatedBy, &gate.Updated,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get token gate: %w", err)
+	}
+
+	return &gate, nil
+}
+
+// ListTokenGates retrieves the holdings required by all token gated channels, for re-checking their subscribers.
+func (user *OptinRepo) ListTokenGates(ctx context.Context) ([]entities.ChannelTokenGate, error) {
+	query := fmt.Sprintf(
+		`SELECT chain, app_id, kind, asset, issuer, min_balance, updated_by, updated FROM %s.%s`,
+		user.conf.DB.Keyspace, consts.ChannelTokenGates,
+	)
+
+	gates := make([]entities.ChannelTokenGate, 0)
+	iter := user.db.Query(query).WithContext(ctx).Iter()
+	for {
+		var gate entities.ChannelTokenGate
+		if !iter.Scan(
+			&gate.Chain, &gate.AppID, &gate.Kind, &gate.Asset, &gate.Issuer, &gate.MinBalance, &gate.UpdatedBy,
+			&gate.Updated,
+		) {
+			break
+		}
+		gates = append(gates, gate)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list token gates: %w", err)
+	}
+
+	return gates, nil
+}
+
+// DeleteTokenGate removes the holding required to opt in to the channel.
+func (user *OptinRepo) DeleteTokenGate(ctx context.Context, chain, appID string) error {
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, user.conf.DB.Keyspace, consts.ChannelTokenGates,
+	)
+	if err := user.db.Query(query, chain, appID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete token gate: %w", err)
+	}
+
+	return nil
+}
+
+// TokenHolding returns how much of the token or how many of the NFTs of gate address holds on the chain.
+func (user *OptinRepo) TokenHolding(
+	ctx context.Context, gate *entities.ChannelTokenGate, address string,
+) (float64, error) {
+	if !chainLib.IsChainSupported(gate.Chain) {
+		return 0, fmt.Errorf("chain %s is not supported", gate.Chain)
+	}
+
+	client := chainLib.GetBlockchainClient(gate.Chain)
+	if gate.Kind == consts.TokenGateNFT {
+		count, err := client.NFTHoldings(ctx, address, gate.Asset, gate.Issuer)
+		return float64(count), err
+	}
+
+	return client.TokenBalance(ctx, address, gate.Asset, gate.Issuer)
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+
+	"notiboy/pkg/entities"
+)
+
+func Test_groupSourceMetrics(t *testing.T) {
+	day := func(date string, optin, optout int) entities.OptInOut {
+		return entities.OptInOut{Date: date, Optin: optin, Optout: optout}
+	}
+
+	tests := []struct {
+		name    string
+		metrics []sourceMetric
+		want    []entities.OptInOutSource
+	}{
+		{
+			name: "no metrics",
+			want: []entities.OptInOutSource{},
+		},
+		{
+			name: "totals each source oldest day first",
+			metrics: []sourceMetric{
+				{OptInOut: day("2024-01-02", 3, 1), Source: "twitter"},
+				{OptInOut: day("2024-01-02", 1, 0), Source: "direct"},
+				{OptInOut: day("2024-01-01", 2, 0), Source: "twitter"},
+			},
+			want: []entities.OptInOutSource{
+				{
+					Source: "twitter", Optin: 5, Optout: 1,
+					OptInOut: []entities.OptInOut{day("2024-01-01", 2, 0), day("2024-01-02", 3, 1)},
+				},
+				{
+					Source: "direct", Optin: 1,
+					OptInOut: []entities.OptInOut{day("2024-01-02", 1, 0)},
+				},
+			},
+		},
+		{
+			name: "most opt-ins first",
+			metrics: []sourceMetric{
+				{OptInOut: day("2024-01-02", 1, 0), Source: "twitter"},
+				{OptInOut: day("2024-01-02", 4, 2), Source: "newsletter"},
+				{OptInOut: day("2024-01-01", 1, 0), Source: "direct"},
+			},
+			want: []entities.OptInOutSource{
+				{Source: "newsletter", Optin: 4, Optout: 2, OptInOut: []entities.OptInOut{day("2024-01-02", 4, 2)}},
+				{Source: "twitter", Optin: 1, OptInOut: []entities.OptInOut{day("2024-01-02", 1, 0)}},
+				{Source: "direct", Optin: 1, OptInOut: []entities.OptInOut{day("2024-01-01", 1, 0)}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := groupSourceMetrics(tt.metrics); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("groupSourceMetrics() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gocql/gocql"
 
 	"notiboy/config"
 	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
 )
 
 type Repo struct {
@@ -18,6 +21,8 @@ type Repo struct {
 type Imply interface {
 	DBHealthCheck(context.Context) error
 	VerifyUserOnboarded(context.Context, any, string) error
+	InsertLoginChallenge(context.Context, *entities.LoginChallenge) error
+	ConsumeLoginChallenge(ctx context.Context, chain, address, nonce string) (*entities.LoginChallenge, error)
 }
 
 // NewRepo
@@ -51,3 +56,53 @@ func (repo *Repo) VerifyUserOnboarded(_ context.Context, address any, chain stri
 
 	return nil
 }
+
+// InsertLoginChallenge stores a sign-in challenge until it expires
+func (repo *Repo) InsertLoginChallenge(_ context.Context, challenge *entities.LoginChallenge) error {
+	ttl := int(time.Until(challenge.ExpiresAt).Seconds())
+	query := fmt.Sprintf(
+		"INSERT INTO %s.%s (chain, address, nonce, message, expires_at) VALUES %s USING TTL %d",
+		repo.conf.DB.Keyspace, consts.LoginNonce, utilities.DBMultiValuePlaceholders(5), ttl,
+	)
+
+	err := repo.db.Query(
+		query, challenge.Chain, challenge.Address, challenge.Nonce, challenge.Message, challenge.ExpiresAt,
+	).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to store login challenge: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLoginChallenge burns a sign-in challenge and returns it. A challenge can be consumed only once,
+// so concurrent attempts to reuse the same nonce fail.
+func (repo *Repo) ConsumeLoginChallenge(
+	_ context.Context, chain, address, nonce string,
+) (*entities.LoginChallenge, error) {
+	tbl := fmt.Sprintf("%s.%s", repo.conf.DB.Keyspace, consts.LoginNonce)
+
+	challenge := &entities.LoginChallenge{
+		Chain:   chain,
+		Address: address,
+		Nonce:   nonce,
+	}
+	query := fmt.Sprintf("SELECT message, expires_at FROM %s WHERE chain = ? AND address = ? AND nonce = ?", tbl)
+	if err := repo.db.Query(query, chain, address, nonce).Scan(&challenge.Message, &challenge.ExpiresAt); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, errors.New("unknown or expired nonce")
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE chain = ? AND address = ? AND nonce = ? IF EXISTS", tbl)
+	applied, err := repo.db.Query(query, chain, address, nonce).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to burn login challenge: %w", err)
+	}
+	if !applied {
+		return nil, errors.New("nonce already used")
+	}
+
+	return challenge, nil
+}
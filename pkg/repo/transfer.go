@@ -0,0 +1,396 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/cache"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+// addChannelAttempts is how often adding a channel is tried before giving up on channels changing concurrently
+const addChannelAttempts = 3
+
+type ChannelTransferRepo struct {
+	db   *gocql.Session
+	conf *config.NotiboyConfModel
+}
+
+// ChannelTransferRepoImply is an interface that defines the contract for changing the owner of a channel.
+type ChannelTransferRepoImply interface {
+	CreateTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error
+	GetTransfer(ctx context.Context, chain, appID string) (*entities.ChannelTransfer, error)
+	ListTransfers(ctx context.Context, chain, kind, to string) ([]entities.ChannelTransfer, error)
+	DeleteTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error
+	CompleteTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error
+}
+
+func NewChannelTransferRepo(db *gocql.Session, conf *config.NotiboyConfModel) ChannelTransferRepoImply {
+	return &ChannelTransferRepo{db: db, conf: conf}
+}
+
+// CreateTransfer stores a pending change of the owner of the channel, only one can be pending per channel.
+// It is withdrawn if it is not accepted or approved before its TTL runs out.
+func (repo *ChannelTransferRepo) CreateTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, kind, from_address, to_address, reason, created) VALUES %s
+		IF NOT EXISTS USING TTL %d`,
+		repo.conf.DB.Keyspace, consts.ChannelTransfers, utilities.DBMultiValuePlaceholders(7),
+		repo.conf.TTL.ChannelTransfer,
+	)
+	applied, err := repo.db.Query(
+		query, transfer.Chain, transfer.AppID, transfer.Kind, transfer.From, transfer.To, transfer.Reason,
+		transfer.Created,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to store channel transfer: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("a change of the owner of channel %s is already pending", transfer.AppID)
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, kind, to_address, app_id, from_address, reason, created) VALUES %s USING TTL %d`,
+		repo.conf.DB.Keyspace, consts.TransfersByTarget, utilities.DBMultiValuePlaceholders(7),
+		repo.conf.TTL.ChannelTransfer,
+	)
+	err = repo.db.Query(
+		query, transfer.Chain, transfer.Kind, transfer.To, transfer.AppID, transfer.From, transfer.Reason,
+		transfer.Created,
+	).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to index channel transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetTransfer retrieves the pending change of the owner of the channel.
+func (repo *ChannelTransferRepo) GetTransfer(
+	ctx context.Context, chain, appID string,
+) (*entities.ChannelTransfer, error) {
+	query := fmt.Sprintf(
+		`SELECT kind, from_address, to_address, reason, created FROM %s.%s WHERE chain = ? AND app_id = ?`,
+		repo.conf.DB.Keyspace, consts.ChannelTransfers,
+	)
+
+	transfer := entities.ChannelTransfer{Chain: chain, AppID: appID}
+	err := repo.db.Query(query, chain, appID).WithContext(ctx).Scan(
+		&transfer.Kind, &transfer.From, &transfer.To, &transfer.Reason, &transfer.Created,
+	)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, fmt.Errorf("no change of the owner of channel %s is pending", appID)
+		}
+		return nil, fmt.Errorf("failed to get channel transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// ListTransfers retrieves the pending changes of owner of the kind on chain, only those to the address to
+// unless it is empty.
+func (repo *ChannelTransferRepo) ListTransfers(
+	ctx context.Context, chain, kind, to string,
+) ([]entities.ChannelTransfer, error) {
+	query := fmt.Sprintf(
+		`SELECT app_id, from_address, to_address, reason, created FROM %s.%s WHERE chain = ? AND kind = ?`,
+		repo.conf.DB.Keyspace, consts.TransfersByTarget,
+	)
+	args := []interface{}{chain, kind}
+	if to != "" {
+		query += ` AND to_address = ?`
+		args = append(args, to)
+	}
+
+	transfers := make([]entities.ChannelTransfer, 0)
+	transfer := entities.ChannelTransfer{Chain: chain, Kind: kind}
+	iter := repo.db.Query(query, args...).WithContext(ctx).Iter()
+	for iter.Scan(&transfer.AppID, &transfer.From, &transfer.To, &transfer.Reason, &transfer.Created) {
+		transfers = append(transfers, transfer)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list channel transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// DeleteTransfer withdraws the pending change of the owner of the channel.
+func (repo *ChannelTransferRepo) DeleteTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error {
+	batch := repo.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, repo.conf.DB.Keyspace, consts.ChannelTransfers),
+		transfer.Chain, transfer.AppID,
+	)
+	deleteTransferTarget(batch, repo.conf.DB.Keyspace, transfer)
+	if err := repo.db.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to delete channel transfer: %w", err)
+	}
+
+	return nil
+}
+
+// deleteTransferTarget adds the deletion of the row indexing the transfer by its target to batch
+func deleteTransferTarget(batch *gocql.Batch, keyspace string, transfer *entities.ChannelTransfer) {
+	batch.Query(
+		fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE chain = ? AND kind = ? AND to_address = ? AND app_id = ?`,
+			keyspace, consts.TransfersByTarget,
+		), transfer.Chain, transfer.Kind, transfer.To, transfer.AppID,
+	)
+}
+
+// CompleteTransfer makes the new owner of the transfer own the channel. The channel is added to the channels of
+// the new owner first, unless its membership doesn't allow one more. The owner of the channel, the channels of
+// the previous owner and the status of its channels, whose channel limit may no longer be exceeded, are then
+// updated in one logged batch. The members and webhooks of the previous owner go along with it.
+func (repo *ChannelTransferRepo) CompleteTransfer(ctx context.Context, transfer *entities.ChannelTransfer) error {
+	log := utilities.NewLoggerWithFields(
+		"CompleteTransfer", map[string]interface{}{
+			"chain":  transfer.Chain,
+			"app_id": transfer.AppID,
+			"from":   transfer.From,
+			"to":     transfer.To,
+		},
+	)
+
+	keyspace := repo.conf.DB.Keyspace
+
+	var statuses map[string]channelStatus
+	if transfer.From != "" {
+		var err error
+		if statuses, err = repo.channelLimitStatuses(ctx, transfer.Chain, transfer.From, transfer.AppID); err != nil {
+			return err
+		}
+	}
+
+	members, err := repo.channelMembers(ctx, transfer.Chain, transfer.AppID)
+	if err != nil {
+		return err
+	}
+	webhooks, err := db.GetChannelWebhooks(ctx, transfer.Chain, transfer.AppID)
+	if err != nil {
+		return err
+	}
+
+	// claiming the transfer first, so it is completed once even if withdrawn or accepted concurrently
+	query := fmt.Sprintf(
+		`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? IF kind = ? AND from_address = ? AND to_address = ?`,
+		keyspace, consts.ChannelTransfers,
+	)
+	applied, err := repo.db.Query(
+		query, transfer.Chain, transfer.AppID, transfer.Kind, transfer.From, transfer.To,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to claim channel transfer: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("the change of the owner of channel %s is no longer pending", transfer.AppID)
+	}
+
+	if err = repo.addChannel(ctx, transfer.Chain, transfer.To, transfer.AppID); err != nil {
+		// the transfer stays pending, the new owner may still make room for the channel
+		if restoreErr := repo.CreateTransfer(ctx, transfer); restoreErr != nil {
+			log.WithError(restoreErr).Error("failed to restore channel transfer")
+		}
+		return err
+	}
+
+	batch := repo.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+
+	deleteTransferTarget(batch, keyspace, transfer)
+	batch.Query(
+		fmt.Sprintf(
+			`UPDATE %s.%s SET owner = ?, status = ? WHERE chain = ? AND app_id = ?`,
+			keyspace, channelInfoTable(transfer.Chain, transfer.AppID),
+		), transfer.To, consts.STATUS_ACTIVE, transfer.Chain, transfer.AppID,
+	)
+	if transfer.From != "" {
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.%s SET channels = channels - ? WHERE address = ? AND chain = ?`, keyspace, consts.UserInfo),
+			[]string{transfer.AppID}, transfer.From, transfer.Chain,
+		)
+	}
+
+	// the members were chosen by the previous owner, and the webhooks point to its servers, the new owner
+	// starts over. The new owner no longer needs the role it might have had as a member either.
+	for _, member := range members {
+		batch.Query(
+			fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND address = ? AND app_id = ?`, keyspace, consts.UserChannelRoles),
+			transfer.Chain, member, transfer.AppID,
+		)
+	}
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, keyspace, consts.ChannelMembers),
+		transfer.Chain, transfer.AppID,
+	)
+	deleteChannelWebhooks(batch, keyspace, transfer.Chain, transfer.AppID, webhooks)
+
+	for appID, status := range statuses {
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.%s SET status = ? WHERE chain = ? AND app_id = ?`, keyspace, status.table),
+			status.status, transfer.Chain, appID,
+		)
+	}
+
+	if err = repo.db.ExecuteBatch(batch); err != nil {
+		log.WithError(err).Error("failed to transfer channel")
+		return fmt.Errorf("failed to transfer channel: %w", err)
+	}
+
+	log.Info("Channel transferred")
+
+	return nil
+}
+
+// channelMembers returns the addresses of the members of the channel and of the users invited to it
+func (repo *ChannelTransferRepo) channelMembers(ctx context.Context, chain, appID string) ([]string, error) {
+	query := fmt.Sprintf(
+		`SELECT address FROM %s.%s WHERE chain = ? AND app_id = ?`, repo.conf.DB.Keyspace, consts.ChannelMembers,
+	)
+
+	var (
+		members []string
+		address string
+	)
+
+	iter := repo.db.Query(query, chain, appID).WithContext(ctx).Iter()
+	for iter.Scan(&address) {
+		members = append(members, address)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list channel members: %w", err)
+	}
+
+	return members, nil
+}
+
+// addChannel adds appID to the channels of address unless its membership doesn't allow one more channel. The
+// channels are compared and set, so that channels added concurrently can't exceed the limit.
+func (repo *ChannelTransferRepo) addChannel(ctx context.Context, chain, address, appID string) error {
+	query := fmt.Sprintf(
+		`UPDATE %s.%s SET channels = channels + ? WHERE address = ? AND chain = ? IF channels = ?`,
+		repo.conf.DB.Keyspace, consts.UserInfo,
+	)
+
+	for attempt := 0; attempt < addChannelAttempts; attempt++ {
+		userModel, err := db.GetUserModel(ctx, chain, address)
+		if err != nil {
+			return fmt.Errorf("getting user model failed: %w", err)
+		}
+		if err = userModel.VerifyChannelCapacity(); err != nil {
+			return err
+		}
+
+		// a user without channels has none stored
+		var channels interface{}
+		if len(userModel.Channels) > 0 {
+			channels = userModel.Channels
+		}
+
+		applied, err := repo.db.Query(query, []string{appID}, address, chain, channels).WithContext(ctx).
+			MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			return fmt.Errorf("failed to add channel: %w", err)
+		}
+		if applied {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the channels of %s keep changing, try again", address)
+}
+
+type channelStatus struct {
+	table  string
+	status string
+}
+
+// channelLimitStatuses returns the statuses to set on the channels address keeps once it no longer owns appID.
+// As on a change of membership, the oldest channels up to the limit of the membership are active and the
+// others exceed the limit. Orphaned channels are left as they are.
+func (repo *ChannelTransferRepo) channelLimitStatuses(
+	ctx context.Context, chain, address, appID string,
+) (map[string]channelStatus, error) {
+	userModel, err := db.GetUserModel(ctx, chain, address)
+	if err != nil {
+		return nil, fmt.Errorf("getting user model failed: %w", err)
+	}
+
+	channels := make([]string, 0, len(userModel.Channels))
+	for _, channel := range userModel.Channels {
+		if channel != appID {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	type channel struct {
+		appID   string
+		table   string
+		status  string
+		created time.Time
+	}
+
+	owned := make([]channel, 0, len(channels))
+	for _, tbl := range []string{consts.VerifiedChannelInfo, consts.UnverifiedChannelInfo} {
+		query := fmt.Sprintf(
+			`SELECT app_id, status, created FROM %s.%s WHERE chain = ? AND app_id IN ?`, repo.conf.DB.Keyspace, tbl,
+		)
+
+		c := channel{table: tbl}
+		iter := repo.db.Query(query, chain, channels).WithContext(ctx).Iter()
+		for iter.Scan(&c.appID, &c.status, &c.created) {
+			owned = append(owned, c)
+		}
+		if err = iter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to get channels: %w", err)
+		}
+	}
+
+	sort.Slice(
+		owned, func(i, j int) bool {
+			return owned[i].created.Before(owned[j].created)
+		},
+	)
+
+	limit := consts.ChannelCount[consts.MembershipStringToEnum(userModel.Membership)]
+	statuses := make(map[string]channelStatus)
+	for idx, c := range owned {
+		if c.status != consts.STATUS_ACTIVE && c.status != consts.STATUS_CHANNEL_LIMIT_EXCEEDED {
+			continue
+		}
+
+		status := consts.STATUS_ACTIVE
+		if idx >= limit {
+			status = consts.STATUS_CHANNEL_LIMIT_EXCEEDED
+		}
+		if status != c.status {
+			statuses[c.appID] = channelStatus{table: c.table, status: status}
+		}
+	}
+
+	return statuses, nil
+}
+
+// channelInfoTable returns the table the info of the channel is stored in
+func channelInfoTable(chain, appID string) string {
+	if cache.GetChannelVerifyCache().IsVerified(chain, appID) {
+		return consts.VerifiedChannelInfo
+	}
+
+	return consts.UnverifiedChannelInfo
+}
@@ -2,10 +2,15 @@ package repo
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cast"
@@ -38,14 +43,21 @@ type UserRepoImply interface {
 	GetMediumAddress(context.Context, string, string, string) (string, error)
 	UpdateMediumMetadata(context.Context, []string, string, string) error
 	GetUser(context.Context, entities.UserIdentifier) (*entities.Response, error)
-	Login(context.Context, entities.UserIdentifier) (string, error)
+	Login(context.Context, entities.UserIdentifier, entities.UserSession) (*entities.SessionTokens, error)
 	Logout(context.Context, entities.UserIdentifier) error
-	GeneratePAT(context.Context, string, string, string) (string, error)
+	RefreshSession(context.Context, entities.UserIdentifier, string, entities.UserSession) (*entities.SessionTokens, error)
+	ListSessions(context.Context, entities.UserIdentifier) ([]entities.UserSession, error)
+	RevokeSession(context.Context, entities.UserIdentifier, string) error
+	RevokeOtherSessions(context.Context, entities.UserIdentifier, string) error
+	GeneratePAT(context.Context, string, string, string, []string, time.Duration) (string, error)
+	RotatePAT(context.Context, string, string, time.Duration) (string, error)
 	GetPAT(context.Context, string) ([]entities.PATTokens, error)
 	RevokePAT(context.Context, string, string) error
 	GetUserSendMetricsForMonth(context.Context, string, string) (int, error)
 	StoreFCMToken(ctx context.Context, fcm entities.FCM) error
 	GetFCMTokens(ctx context.Context, userIdentifier entities.UserIdentifier) ([]string, error)
+	GetUserModel(ctx context.Context, chain, address string) (*entities.UserModel, error)
+	IsUserOnboarded(ctx context.Context, chain, address string) (bool, error)
 }
 
 // NewUserRepo
@@ -80,10 +92,14 @@ func (user *UserRepo) GetUser(ctx context.Context, data entities.UserIdentifier)
 	var allowedMediums, supportedMediums, channels, optins []string
 	var status, membership string
 	var mediumMetadataStr, logo string
+	var trackingOptOut bool
+	profile := new(entities.UserProfile)
 
-	infoQuery := "SELECT channels, optins, membership, logo, status, allowed_mediums, supported_mediums, medium_metadata FROM " + tblUserInfo + " WHERE chain = ? AND address = ?"
+	infoQuery := "SELECT channels, optins, membership, logo, status, allowed_mediums, supported_mediums, medium_metadata, tracking_opt_out, display_name, avatar, bio, locale, timezone, profile_privacy FROM " + tblUserInfo + " WHERE chain = ? AND address = ?"
 	if err := user.db.Query(infoQuery, data.Chain, data.Address).Scan(
 		&channels, &optins, &membership, &logo, &status, &allowedMediums, &supportedMediums, &mediumMetadataStr,
+		&trackingOptOut, &profile.DisplayName, &profile.Avatar, &profile.Bio, &profile.Locale, &profile.Timezone,
+		&profile.Privacy,
 	); err != nil {
 		return nil, fmt.Errorf("failed to query db: %w", err)
 	}
@@ -109,6 +125,8 @@ func (user *UserRepo) GetUser(ctx context.Context, data entities.UserIdentifier)
 		Channels:         channels,
 		Optins:           optins,
 		Privileges:       getUserLimit(ctx, membership),
+		TrackingOptOut:   trackingOptOut,
+		Profile:          withDefaultPrivacy(profile),
 	}
 
 	// construct the response object
@@ -143,6 +161,37 @@ func (user *UserRepo) ProfileUpdate(_ context.Context, data entities.UserInfo) e
 		args = append(args, data.Logo)
 	}
 
+	if data.TrackingOptOut != nil {
+		setClause = append(setClause, "tracking_opt_out = ?")
+		args = append(args, *data.TrackingOptOut)
+	}
+
+	if err := validateProfile(data); err != nil {
+		log.WithError(err).Error("Profile validation failed")
+		return err
+	}
+
+	for _, field := range []struct {
+		column string
+		value  *string
+	}{
+		{consts.ProfileDisplayName, data.DisplayName},
+		{consts.ProfileAvatar, data.Avatar},
+		{consts.ProfileBio, data.Bio},
+		{consts.ProfileLocale, data.Locale},
+		{consts.ProfileTimezone, data.Timezone},
+	} {
+		if field.value != nil {
+			setClause = append(setClause, field.column+" = ?")
+			args = append(args, strings.TrimSpace(*field.value))
+		}
+	}
+
+	if len(data.ProfilePrivacy) > 0 {
+		setClause = append(setClause, "profile_privacy = profile_privacy + ?")
+		args = append(args, data.ProfilePrivacy)
+	}
+
 	if len(setClause) == 0 {
 		return nil
 	}
@@ -162,6 +211,65 @@ func (user *UserRepo) ProfileUpdate(_ context.Context, data entities.UserInfo) e
 	return nil
 }
 
+// withDefaultPrivacy fills in the visibility of the fields the user hasn't chosen one for, so that the user
+// sees who gets to see each field of its profile
+func withDefaultPrivacy(profile *entities.UserProfile) *entities.UserProfile {
+	privacy := make(map[string]string, len(consts.DefaultProfilePrivacy))
+	for field := range consts.DefaultProfilePrivacy {
+		privacy[field] = profile.Visibility(field)
+	}
+	profile.Privacy = privacy
+
+	return profile
+}
+
+// localePattern matches BCP 47 language tags like en, pt-BR or zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// validateProfile checks the profile fields of data which are being updated
+func validateProfile(data entities.UserInfo) error {
+	if data.DisplayName != nil && utf8.RuneCountInString(strings.TrimSpace(*data.DisplayName)) > consts.MaxDisplayNameLength {
+		return fmt.Errorf("display name cannot be longer than %d characters", consts.MaxDisplayNameLength)
+	}
+
+	if data.Bio != nil && utf8.RuneCountInString(strings.TrimSpace(*data.Bio)) > consts.MaxBioLength {
+		return fmt.Errorf("bio cannot be longer than %d characters", consts.MaxBioLength)
+	}
+
+	if data.Avatar != nil && *data.Avatar != "" {
+		logoConfig := config.GetConfig().Logo
+		err := utilities.ValidateImage(
+			*data.Avatar, logoConfig.MaxX, logoConfig.MaxY,
+			logoConfig.MaxSize, logoConfig.SupportedTypes,
+		)
+		if err != nil {
+			return fmt.Errorf("avatar validation failed: %w", err)
+		}
+	}
+
+	if data.Locale != nil && *data.Locale != "" && !localePattern.MatchString(*data.Locale) {
+		return fmt.Errorf("invalid locale %s", *data.Locale)
+	}
+
+	if data.Timezone != nil && *data.Timezone != "" {
+		if _, err := time.LoadLocation(*data.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %s", *data.Timezone)
+		}
+	}
+
+	for field, visibility := range data.ProfilePrivacy {
+		if _, ok := consts.DefaultProfilePrivacy[field]; !ok {
+			return fmt.Errorf("unknown profile field %s", field)
+		}
+		if visibility != consts.ProfilePublic && visibility != consts.ProfileContacts &&
+			visibility != consts.ProfilePrivate {
+			return fmt.Errorf("invalid visibility %s of %s", visibility, field)
+		}
+	}
+
+	return nil
+}
+
 // Onboarding performs the onboarding process for a user based on the provided OnboardingRequest data.
 func (user *UserRepo) Onboarding(_ context.Context, data entities.OnboardingRequest) error {
 
@@ -186,6 +294,10 @@ func (user *UserRepo) Onboarding(_ context.Context, data entities.OnboardingRequ
 		log.Warn("User is already onboarded")
 		return fmt.Errorf("user is already onboarded")
 	}
+	if status == consts.STATUS_DELETING {
+		log.Warn("User account is being deleted")
+		return fmt.Errorf("user account is being deleted, try again once the deletion completed")
+	}
 
 	mediumMetadata := &entities.MediumMetadata{
 		Email:   &entities.EmailMedium{},
@@ -553,11 +665,13 @@ func (user *UserRepo) UpdateMediumMetadata(ctx context.Context, users []string,
 	return nil
 }
 
-// Login performs user login and generates a JWT token for the specified address and chain.
+// Login performs the login operation for a user.
 // If auto-onboarding is enabled, it automatically onboards the user if they are not already onboarded.
-// The JWT token is inserted into the login table with an expiration time.
-// The generated JWT token is returned.
-func (user *UserRepo) Login(ctx context.Context, request entities.UserIdentifier) (string, error) {
+// A new session is started for the client, the short-lived access token and the refresh token of
+// the session are returned.
+func (user *UserRepo) Login(
+	ctx context.Context, request entities.UserIdentifier, client entities.UserSession,
+) (*entities.SessionTokens, error) {
 	log := utilities.NewLogger("Login").WithFields(
 		logrus.Fields{
 			"chain":   request.Chain,
@@ -569,7 +683,7 @@ func (user *UserRepo) Login(ctx context.Context, request entities.UserIdentifier
 		onboarded, err := dbDriver.IsUserOnboarded(ctx, request.Chain, request.Address)
 		if err != nil {
 			log.WithError(err).Error("user onboard check failed")
-			return "", fmt.Errorf("user onboard check failed: %w", err)
+			return nil, fmt.Errorf("user onboard check failed: %w", err)
 		}
 
 		if !onboarded {
@@ -584,41 +698,52 @@ func (user *UserRepo) Login(ctx context.Context, request entities.UserIdentifier
 			)
 			if err != nil {
 				log.WithError(err).Error("user auto-onboarding failed")
-				return "", fmt.Errorf("user auto-onboarding failed for %s: %w", request.Address, err)
+				return nil, fmt.Errorf("user auto-onboarding failed for %s: %w", request.Address, err)
 			}
 		}
 	}
 
-	loginInfoTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.LoginTable)
-
-	ttl := cast.ToDuration(user.conf.LoginTokenExpiry)
-	jwtToken, tokenExpiry, err := jwt.GenerateJWT(request.Address, request.Chain, "", "", ttl)
+	sessionID := uuid.NewString()
+	tokens, refreshHash, err := user.issueSessionTokens(request, sessionID)
 	if err != nil {
-		return "", fmt.Errorf("token generation failed: %w", err)
+		return nil, err
 	}
 
+	sessionTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.Sessions)
+
 	query := fmt.Sprintf(
-		"INSERT INTO %s (address, chain, jwt, created) VALUES (?, ?, ?, ?) USING TTL ?",
-		loginInfoTable,
+		"INSERT INTO %s (chain, address, session_id, refresh_token, user_agent, ip, created, last_seen) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
+		sessionTable,
 	)
 
+	now := utilities.TimeNow()
 	if err = user.db.Query(
-		query, request.Address, request.Chain, jwtToken, time.Now(), tokenExpiry,
+		query, request.Chain, request.Address, sessionID, refreshHash, client.UserAgent, client.IP, now, now,
+		user.sessionTTL(),
 	).Exec(); err != nil {
-		log.WithError(err).Error("Failed to insert login token")
-		return "", fmt.Errorf("failed to insert login token: %w", err)
+		log.WithError(err).Error("Failed to insert login session")
+		return nil, fmt.Errorf("failed to insert login session: %w", err)
 	}
 
 	log.Info("Login succeeded")
 
-	return jwtToken, nil
+	return tokens, nil
 }
 
-// Logout removes the specified JWT token from the login table, effectively logging out the user.
-// The JWT token is matched against the address and chain in the login table, and if found, it is deleted.
+// Logout ends the session of the token used for the request, effectively logging out the user.
+// Tokens issued before sessions are matched against the address and chain in the login table instead.
 func (user *UserRepo) Logout(ctx context.Context, req entities.UserIdentifier) error {
-
 	log := utilities.NewLogger("Logout")
+
+	if sessionID := cast.ToString(ctx.Value(consts.UserSession)); sessionID != "" {
+		if err := user.RevokeSession(ctx, req, sessionID); err != nil {
+			log.WithError(err).Error("failed to logout")
+			return fmt.Errorf("failed to log user out: %w", err)
+		}
+		return nil
+	}
+
 	loginInfoTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.LoginTable)
 
 	queryProfile := fmt.Sprintf("DELETE FROM %s WHERE address = ? AND chain = ? AND jwt = ?", loginInfoTable)
@@ -630,7 +755,216 @@ func (user *UserRepo) Logout(ctx context.Context, req entities.UserIdentifier) e
 	return nil
 }
 
-func (user *UserRepo) GeneratePAT(ctx context.Context, name, kind, description string) (string, error) {
+// RefreshSession exchanges a refresh token for a new access token and rotates the refresh token.
+// A refresh token that was already rotated out is treated as stolen and revokes its session.
+func (user *UserRepo) RefreshSession(
+	ctx context.Context, req entities.UserIdentifier, refreshToken string, client entities.UserSession,
+) (*entities.SessionTokens, error) {
+	log := utilities.NewLogger("RefreshSession").WithFields(
+		logrus.Fields{
+			"chain":   req.Chain,
+			"address": req.Address,
+		},
+	)
+
+	sessionID, _, found := strings.Cut(refreshToken, ".")
+	if !found || sessionID == "" {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	var (
+		storedHash   string
+		previousHash string
+		created      time.Time
+	)
+
+	sessionTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.Sessions)
+
+	query := fmt.Sprintf(
+		"SELECT refresh_token, previous_refresh_token, created FROM %s WHERE chain = ? AND address = ? AND session_id = ?",
+		sessionTable,
+	)
+	if err := user.db.Query(query, req.Chain, req.Address, sessionID).Scan(
+		&storedHash, &previousHash, &created,
+	); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, errors.New("session expired or revoked")
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if storedHash == "" {
+		return nil, errors.New("session expired or revoked")
+	}
+
+	switch checkRefreshToken(hashRefreshToken(refreshToken), storedHash, previousHash) {
+	case refreshTokenReused:
+		// only a token this session already rotated away proves it was stolen, a random one is just rejected
+		log.Warnf("refresh token reuse detected, revoking session %s", sessionID)
+		if err := user.RevokeSession(ctx, req, sessionID); err != nil {
+			log.WithError(err).Error("failed to revoke session")
+		}
+		return nil, errors.New("invalid refresh token")
+	case refreshTokenInvalid:
+		return nil, errors.New("invalid refresh token")
+	}
+
+	tokens, refreshHash, err := user.issueSessionTokens(req, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// every column is rewritten so that the whole session gets the renewed lifetime
+	query = fmt.Sprintf(
+		"UPDATE %s USING TTL ? SET refresh_token = ?, previous_refresh_token = ?, user_agent = ?, ip = ?, "+
+			"created = ?, last_seen = ? WHERE chain = ? AND address = ? AND session_id = ? IF refresh_token = ?",
+		sessionTable,
+	)
+	applied, err := user.db.Query(
+		query, user.sessionTTL(), refreshHash, storedHash, client.UserAgent, client.IP, created, utilities.TimeNow(),
+		req.Chain, req.Address, sessionID, storedHash,
+	).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		log.WithError(err).Error("failed to rotate refresh token")
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !applied {
+		return nil, errors.New("refresh token already used")
+	}
+
+	return tokens, nil
+}
+
+// ListSessions lists the active login sessions of a user
+func (user *UserRepo) ListSessions(ctx context.Context, req entities.UserIdentifier) ([]entities.UserSession, error) {
+	sessionTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.Sessions)
+
+	query := fmt.Sprintf(
+		"SELECT session_id, refresh_token, user_agent, ip, created, last_seen FROM %s WHERE chain = ? AND address = ?",
+		sessionTable,
+	)
+	iter := user.db.Query(query, req.Chain, req.Address).WithContext(ctx).Iter()
+
+	var (
+		session     entities.UserSession
+		refreshHash string
+		sessions    = make([]entities.UserSession, 0)
+	)
+	for iter.Scan(
+		&session.SessionID, &refreshHash, &session.UserAgent, &session.IP, &session.Created, &session.LastSeen,
+	) {
+		if refreshHash != "" {
+			sessions = append(sessions, session)
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession ends a login session, its access and refresh tokens stop working immediately
+func (user *UserRepo) RevokeSession(ctx context.Context, req entities.UserIdentifier, sessionID string) error {
+	sessionTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.Sessions)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE chain = ? AND address = ? AND session_id = ?", sessionTable)
+	if err := user.db.Query(query, req.Chain, req.Address, sessionID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions ends every login session of a user except currentSessionID
+func (user *UserRepo) RevokeOtherSessions(
+	ctx context.Context, req entities.UserIdentifier, currentSessionID string,
+) error {
+	sessions, err := user.ListSessions(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.SessionID != currentSessionID {
+			sessionIDs = append(sessionIDs, session.SessionID)
+		}
+	}
+
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	sessionTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.Sessions)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE chain = ? AND address = ? AND session_id IN ?", sessionTable)
+	if err = user.db.Query(query, req.Chain, req.Address, sessionIDs).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// issueSessionTokens generates an access token and a new refresh token for sessionID.
+// The refresh token is returned to the client once, only its hash is stored.
+func (user *UserRepo) issueSessionTokens(
+	req entities.UserIdentifier, sessionID string,
+) (*entities.SessionTokens, string, error) {
+	accessToken, expiresIn, err := jwt.GenerateSessionJWT(
+		req.Address, req.Chain, sessionID, cast.ToDuration(user.conf.AccessTokenExpiry),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("token generation failed: %w", err)
+	}
+
+	secret, err := utilities.GenerateSecret(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("refresh token generation failed: %w", err)
+	}
+	refreshToken := sessionID + "." + secret
+
+	return &entities.SessionTokens{
+		Token:        accessToken,
+		ExpiresIn:    expiresIn,
+		RefreshToken: refreshToken,
+		SessionID:    sessionID,
+	}, hashRefreshToken(refreshToken), nil
+}
+
+// sessionTTL is the lifetime of a session in seconds, renewed on every refresh
+func (user *UserRepo) sessionTTL() int {
+	return int(cast.ToDuration(user.conf.LoginTokenExpiry).Seconds())
+}
+
+const (
+	refreshTokenValid = iota
+	refreshTokenReused
+	refreshTokenInvalid
+)
+
+// checkRefreshToken compares the hash of a presented refresh token with the current and the last rotated refresh
+// token of its session
+func checkRefreshToken(presentedHash, currentHash, previousHash string) int {
+	switch {
+	case subtle.ConstantTimeCompare([]byte(currentHash), []byte(presentedHash)) == 1:
+		return refreshTokenValid
+	case previousHash != "" && subtle.ConstantTimeCompare([]byte(previousHash), []byte(presentedHash)) == 1:
+		return refreshTokenReused
+	default:
+		return refreshTokenInvalid
+	}
+}
+
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func (user *UserRepo) GeneratePAT(
+	ctx context.Context, name, kind, description string, scopes []string, ttl time.Duration,
+) (string, error) {
 	chain := cast.ToString(ctx.Value(consts.UserChain))
 	address := cast.ToString(ctx.Value(consts.UserAddress))
 
@@ -659,31 +993,144 @@ func (user *UserRepo) GeneratePAT(ctx context.Context, name, kind, description s
 		}
 	}
 
-	ttl := cast.ToDuration(user.conf.TTL.PAToken)
 	id := uuid.New().String()
 
-	jwtToken, tokenExpiry, err := jwt.GenerateJWT(address, chain, kind, id, ttl)
+	jwtToken, tokenExpiry, err := jwt.GenerateScopedJWT(address, chain, kind, id, scopes, ttl)
 	if err != nil {
 		return "", fmt.Errorf("token generation failed: %w", err)
 	}
 
+	now := time.Now()
+	if err = user.insertPAT(
+		chain, address, jwtToken, entities.PATTokens{
+			Name:        name,
+			UUID:        id,
+			Created:     now,
+			Kind:        kind,
+			Description: description,
+			Scopes:      scopes,
+			ExpiresAt:   now.Add(ttl),
+		}, tokenExpiry,
+	); err != nil {
+		log.WithError(err).Error("failed to insert pa token")
+		return "", err
+	}
+
+	log.Info("Personal access token generated")
+
+	return jwtToken, nil
+}
+
+// RotatePAT issues a replacement for the token id with the same name, scopes and lifetime.
+// The replaced token keeps working for the grace period, or stops right away when it is zero.
+func (user *UserRepo) RotatePAT(ctx context.Context, id, kind string, grace time.Duration) (string, error) {
+	chain := cast.ToString(ctx.Value(consts.UserChain))
+	address := cast.ToString(ctx.Value(consts.UserAddress))
+
+	log := utilities.NewLogger("RotatePAT").WithFields(
+		logrus.Fields{
+			"chain":   chain,
+			"address": address,
+			"uuid":    id,
+		},
+	)
+
+	var (
+		old      = entities.PATTokens{UUID: id, Kind: kind}
+		oldJWT   string
+		lastUsed time.Time
+		ttl      *int
+	)
+
+	patTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.PATInfo)
+
 	query := fmt.Sprintf(
-		"INSERT INTO %s (address, chain, uuid, name, jwt, created, description, kind) VALUES %s USING TTL ?",
-		patTable, utilities.DBMultiValuePlaceholders(8),
+		"SELECT name, jwt, created, description, scopes, expires_at, last_used, last_used_ip, TTL(jwt) FROM %s "+
+			"WHERE address = ? AND chain = ? AND kind = ? AND uuid = ?",
+		patTable,
 	)
+	if err := user.db.Query(query, address, chain, kind, id).Scan(
+		&old.Name, &oldJWT, &old.Created, &old.Description, &old.Scopes, &old.ExpiresAt, &lastUsed,
+		&old.LastUsedIP, &ttl,
+	); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return "", fmt.Errorf("token %s not found", id)
+		}
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+	if !lastUsed.IsZero() {
+		old.LastUsed = &lastUsed
+	}
 
-	if err = user.db.Query(
-		query, address, chain, id, name, jwtToken, time.Now(), description, kind, tokenExpiry,
-	).Exec(); err != nil {
+	lifetime := old.ExpiresAt.Sub(old.Created)
+	if old.ExpiresAt.IsZero() {
+		lifetime = cast.ToDuration(user.conf.TTL.PAToken)
+	}
+
+	newID := uuid.New().String()
+
+	jwtToken, tokenExpiry, err := jwt.GenerateScopedJWT(address, chain, kind, newID, old.Scopes, lifetime)
+	if err != nil {
+		return "", fmt.Errorf("token generation failed: %w", err)
+	}
+
+	now := time.Now()
+	if err = user.insertPAT(
+		chain, address, jwtToken, entities.PATTokens{
+			Name:        old.Name,
+			UUID:        newID,
+			Created:     now,
+			Kind:        kind,
+			Description: old.Description,
+			Scopes:      old.Scopes,
+			ExpiresAt:   now.Add(lifetime),
+		}, tokenExpiry,
+	); err != nil {
 		log.WithError(err).Error("failed to insert pa token")
-		return "", fmt.Errorf("failed to insert pa token: %w", err)
+		return "", err
 	}
 
-	log.Info("Personal access token generated")
+	graceSecs := int(grace.Seconds())
+	switch {
+	case graceSecs <= 0:
+		query = fmt.Sprintf("DELETE FROM %s WHERE address = ? AND chain = ? AND kind = ? AND uuid = ?", patTable)
+		if err = user.db.Query(query, address, chain, kind, id).Exec(); err != nil {
+			log.WithError(err).Error("failed to revoke replaced token")
+			return "", fmt.Errorf("failed to revoke replaced token: %w", err)
+		}
+	case ttl != nil && graceSecs < *ttl:
+		// rewritten as a whole so that every column expires with the grace period
+		old.ExpiresAt = now.Add(grace)
+		if err = user.insertPAT(chain, address, oldJWT, old, graceSecs); err != nil {
+			log.WithError(err).Error("failed to shorten replaced token")
+			return "", err
+		}
+	}
+
+	log.Info("Personal access token rotated")
 
 	return jwtToken, nil
 }
 
+func (user *UserRepo) insertPAT(chain, address, jwtToken string, pat entities.PATTokens, ttl int) error {
+	patTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.PATInfo)
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (address, chain, uuid, name, jwt, created, description, kind, scopes, expires_at, "+
+			"last_used, last_used_ip) VALUES %s USING TTL ?",
+		patTable, utilities.DBMultiValuePlaceholders(12),
+	)
+
+	if err := user.db.Query(
+		query, address, chain, pat.UUID, pat.Name, jwtToken, pat.Created, pat.Description, pat.Kind, pat.Scopes,
+		pat.ExpiresAt, pat.LastUsed, pat.LastUsedIP, ttl,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to insert pa token: %w", err)
+	}
+
+	return nil
+}
+
 func (user *UserRepo) GetPAT(ctx context.Context, kind string) ([]entities.PATTokens, error) {
 	chain := cast.ToString(ctx.Value(consts.UserChain))
 	address := cast.ToString(ctx.Value(consts.UserAddress))
@@ -700,6 +1147,10 @@ func (user *UserRepo) GetPAT(ctx context.Context, kind string) ([]entities.PATTo
 		id          string
 		description string
 		created     time.Time
+		scopes      []string
+		expiresAt   time.Time
+		lastUsed    time.Time
+		lastUsedIP  string
 	)
 
 	if kind != "normal" && kind != "mobile" {
@@ -710,21 +1161,28 @@ func (user *UserRepo) GetPAT(ctx context.Context, kind string) ([]entities.PATTo
 
 	patTable := fmt.Sprintf("%s.%s", user.conf.DB.Keyspace, consts.PATInfo)
 	query := fmt.Sprintf(
-		"SELECT name, uuid, created, description FROM %s WHERE address = ? AND chain = ? AND kind = ?",
+		"SELECT name, uuid, created, description, scopes, expires_at, last_used, last_used_ip FROM %s "+
+			"WHERE address = ? AND chain = ? AND kind = ?",
 		patTable,
 	)
 
 	iter := user.db.Query(query, address, chain, kind).Iter()
-	for iter.Scan(&name, &id, &created, &description) {
-		data = append(
-			data, entities.PATTokens{
-				Name:        name,
-				UUID:        id,
-				Created:     created,
-				Kind:        kind,
-				Description: description,
-			},
-		)
+	for iter.Scan(&name, &id, &created, &description, &scopes, &expiresAt, &lastUsed, &lastUsedIP) {
+		pat := entities.PATTokens{
+			Name:        name,
+			UUID:        id,
+			Created:     created,
+			Kind:        kind,
+			Description: description,
+			Scopes:      scopes,
+			ExpiresAt:   expiresAt,
+			LastUsedIP:  lastUsedIP,
+		}
+		if !lastUsed.IsZero() {
+			used := lastUsed
+			pat.LastUsed = &used
+		}
+		data = append(data, pat)
 	}
 	if err := iter.Close(); err != nil {
 		if !errors.Is(err, gocql.ErrNotFound) {
@@ -845,3 +1303,13 @@ func (user *UserRepo) GetFCMTokens(_ context.Context, userIdentifier entities.Us
 
 	return deviceIDs, nil
 }
+
+// GetUserModel retrieves the user_info row of the user.
+func (user *UserRepo) GetUserModel(ctx context.Context, chain, address string) (*entities.UserModel, error) {
+	return dbDriver.GetUserModel(ctx, chain, address)
+}
+
+// IsUserOnboarded reports whether the user signed up.
+func (user *UserRepo) IsUserOnboarded(ctx context.Context, chain, address string) (bool, error) {
+	return dbDriver.IsUserOnboarded(ctx, chain, address)
+}
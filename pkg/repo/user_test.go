@@ -0,0 +1,48 @@
+package repo
+
+import "testing"
+
+func Test_checkRefreshToken(t *testing.T) {
+	current := hashRefreshToken("session.current")
+	previous := hashRefreshToken("session.previous")
+
+	tests := []struct {
+		name      string
+		presented string
+		previous  string
+		want      int
+	}{
+		{
+			name:      "current token",
+			presented: "session.current",
+			previous:  previous,
+			want:      refreshTokenValid,
+		},
+		{
+			name:      "rotated token",
+			presented: "session.previous",
+			previous:  previous,
+			want:      refreshTokenReused,
+		},
+		{
+			name:      "unknown token",
+			presented: "session.guessed",
+			previous:  previous,
+			want:      refreshTokenInvalid,
+		},
+		{
+			name:      "unknown token of a never rotated session",
+			presented: "session.guessed",
+			want:      refreshTokenInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := checkRefreshToken(hashRefreshToken(tt.presented), current, tt.previous); got != tt.want {
+					t.Errorf("checkRefreshToken() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -105,6 +105,11 @@ func (verify *VerifyRepo) Verify(
 		return err
 	}
 
+	if err := verify.indexVerification(user, token, verify.conf.TTL.VerifyToken); err != nil {
+		log.WithError(err).Error("Failed to index verification information")
+		return err
+	}
+
 	if mediumMetadata.Email == nil {
 		mediumMetadata.Email = new(entities.EmailMedium)
 	}
@@ -151,6 +156,11 @@ func (verify *VerifyRepo) VerifySent(_ context.Context, user entities.UserIdenti
 		return err
 	}
 
+	if err := verify.indexVerification(user, token, ttl); err != nil {
+		log.WithError(err).Error("Failed to index verification information")
+		return err
+	}
+
 	return nil
 
 }
@@ -193,6 +203,11 @@ func (verify *VerifyRepo) Callback(_ context.Context, user entities.UserIdentifi
 		log.WithError(err).Error("Failed to update verification status and deletion")
 		return mediumAddress, fmt.Errorf("failed to update callback: %w", err)
 	}
+
+	if err := verify.indexVerification(user, token, verify.conf.TTL.VerifyToken); err != nil {
+		log.WithError(err).Error("Failed to index verification information")
+		return mediumAddress, err
+	}
 	var verified bool
 
 	query = fmt.Sprintf(
@@ -257,6 +272,19 @@ func (verify *VerifyRepo) Callback(_ context.Context, user entities.UserIdentifi
 
 }
 
+// indexVerification adds token to the verifications of user for as long as its verify_info row lives
+func (verify *VerifyRepo) indexVerification(user entities.UserIdentifier, token string, ttl int64) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, address, "token") VALUES (?, ?, ?) USING TTL %d`,
+		verify.conf.DB.Keyspace, consts.UserVerifications, ttl,
+	)
+	if err := verify.db.Query(query, user.Chain, user.Address, token).Exec(); err != nil {
+		return fmt.Errorf("failed to index verification: %w", err)
+	}
+
+	return nil
+}
+
 func (verify *VerifyRepo) CallbackDiscord(ctx context.Context, address, discordId, chain string) error {
 
 	log := utilities.NewLogger("CallbackDiscord")
@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type WebhookRepo struct {
+	db   *gocql.Session
+	conf *config.NotiboyConfModel
+}
+
+// WebhookRepoImply is an interface that defines the contract for managing channel webhooks and their delivery log.
+type WebhookRepoImply interface {
+	CreateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	ListWebhooks(ctx context.Context, chain, appID string) ([]entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, chain, appID, webhookID string) error
+	ListWebhookDeliveries(ctx context.Context, chain, appID, webhookID string, limit int) ([]entities.WebhookDelivery, error)
+}
+
+func NewWebhookRepo(db *gocql.Session, conf *config.NotiboyConfModel) WebhookRepoImply {
+	return &WebhookRepo{db: db, conf: conf}
+}
+
+// CreateWebhook registers a webhook for a channel.
+func (repo *WebhookRepo) CreateWebhook(_ context.Context, webhook *entities.Webhook) error {
+	log := utilities.NewLogger("CreateWebhook")
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s.%s (chain, app_id, id, url, events, secret, created_time) VALUES %s`,
+		repo.conf.DB.Keyspace, consts.ChannelWebhooks, utilities.DBMultiValuePlaceholders(7),
+	)
+
+	err := repo.db.Query(
+		query, webhook.Chain, webhook.Channel, webhook.ID, webhook.URL, webhook.Events, webhook.Secret,
+		webhook.CreatedTime,
+	).Exec()
+	if err != nil {
+		log.WithError(err).Error("failed to create webhook")
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhooks retrieves the webhooks registered for a channel, including their signing secrets.
+func (repo *WebhookRepo) ListWebhooks(ctx context.Context, chain, appID string) ([]entities.Webhook, error) {
+	return db.GetChannelWebhooks(ctx, chain, appID)
+}
+
+// DeleteWebhook removes a webhook of a channel, its delivery log expires on its own.
+func (repo *WebhookRepo) DeleteWebhook(ctx context.Context, chain, appID, webhookID string) error {
+	log := utilities.NewLogger("DeleteWebhook")
+
+	// the delivery log goes along with the webhook
+	batch := repo.db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND id = ?`, repo.conf.DB.Keyspace, consts.ChannelWebhooks),
+		chain, appID, webhookID,
+	)
+	batch.Query(
+		fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND webhook_id = ?`,
+			repo.conf.DB.Keyspace, consts.ChannelWebhookDeliveries,
+		), chain, appID, webhookID,
+	)
+	if err := repo.db.ExecuteBatch(batch); err != nil {
+		log.WithError(err).Error("failed to delete webhook")
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the latest deliveries of a channel webhook, newest first.
+func (repo *WebhookRepo) ListWebhookDeliveries(
+	ctx context.Context, chain, appID, webhookID string, limit int,
+) ([]entities.WebhookDelivery, error) {
+	log := utilities.NewLogger("ListWebhookDeliveries")
+
+	var deliveries = make([]entities.WebhookDelivery, 0, limit)
+
+	query := fmt.Sprintf(
+		`SELECT id, event, payload, attempts, status_code, success, error, created_time, updated_time
+	FROM %s.%s WHERE chain = ? AND app_id = ? AND webhook_id = ? LIMIT ?`,
+		repo.conf.DB.Keyspace, consts.ChannelWebhookDeliveries,
+	)
+
+	delivery := entities.WebhookDelivery{
+		WebhookID: webhookID,
+		Chain:     chain,
+		Channel:   appID,
+	}
+	iter := repo.db.Query(query, chain, appID, webhookID, limit).WithContext(ctx).Iter()
+	for iter.Scan(
+		&delivery.ID, &delivery.Event, &delivery.Payload, &delivery.Attempts, &delivery.StatusCode,
+		&delivery.Success, &delivery.Error, &delivery.CreatedTime, &delivery.UpdatedTime,
+	) {
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := iter.Close(); err != nil {
+		log.WithError(err).Error("failed to retrieve webhook deliveries")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// deleteChannelWebhooks adds the deletion of the webhooks of a channel, and of their delivery logs, to batch
+func deleteChannelWebhooks(batch *gocql.Batch, keyspace, chain, appID string, webhooks []entities.Webhook) {
+	for _, webhook := range webhooks {
+		batch.Query(
+			fmt.Sprintf(
+				`DELETE FROM %s.%s WHERE chain = ? AND app_id = ? AND webhook_id = ?`,
+				keyspace, consts.ChannelWebhookDeliveries,
+			), chain, appID, webhook.ID,
+		)
+	}
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.%s WHERE chain = ? AND app_id = ?`, keyspace, consts.ChannelWebhooks), chain, appID,
+	)
+}
@@ -0,0 +1,363 @@
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+const week = 7 * 24 * time.Hour
+
+// exportColumns holds the column names of every exportable dataset in the order the repo writes them
+var exportColumns = map[string][]string{
+	consts.ExportTraction:      {"event_date", "event_time", "optin", "optout"},
+	consts.ExportSentRead:      {"event_date", "event_time", "medium", "sent", "read", "clicked", "recalled"},
+	consts.ExportNotifications: {"uuid", "created_time", "sender", "type", "message", "link", "receivers", "recalled"},
+	consts.ExportSubscribers:   {"address", "optin_time"},
+}
+
+type AnalyticsUseCases struct {
+	repo     repo.AnalyticsRepoImply
+	members  repo.ChannelMemberRepoImply
+	userRepo repo.UserRepoImply
+}
+
+type AnalyticsUseCaseImply interface {
+	ChannelAnalytics(ctx context.Context, chain, appID, user string, days, weeks int) (*entities.ChannelAnalytics, error)
+	ExportChannelData(
+		ctx context.Context, chain, appID, user, dataset, format, startDate, endDate string, w io.Writer,
+	) error
+}
+
+// NewAnalyticsUseCases
+func NewAnalyticsUseCases(
+	analyticsRepo repo.AnalyticsRepoImply, members repo.ChannelMemberRepoImply, userRepo repo.UserRepoImply,
+) AnalyticsUseCaseImply {
+	return &AnalyticsUseCases{
+		repo:     analyticsRepo,
+		members:  members,
+		userRepo: userRepo,
+	}
+}
+
+// ChannelAnalytics computes the subscriber retention, churn and read behaviour of a channel the user may see stats of.
+// Notification metrics cover the last `days` days and cohorts cover the last `weeks` weeks.
+func (auc *AnalyticsUseCases) ChannelAnalytics(
+	ctx context.Context, chain, appID, user string, days, weeks int,
+) (*entities.ChannelAnalytics, error) {
+	if err := verifyChannelPermission(ctx, auc.members, chain, appID, user, consts.ChannelPermStats); err != nil {
+		return nil, err
+	}
+
+	userModel, err := channelOwnerModel(ctx, auc.members, auc.userRepo, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := consts.MembershipStringToEnum(userModel.Membership)
+	if !consts.Analytics[consts.CHANNEL_ADVANCED_STATS][membership] {
+		return nil, fmt.Errorf("your membership doesn't allow advanced analytics for channels")
+	}
+
+	now := time.Now().UTC()
+
+	subscribers, err := auc.repo.ListChannelSubscribers(ctx, chain, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel subscribers: %w", err)
+	}
+
+	analytics := &entities.ChannelAnalytics{
+		Days:    days,
+		Weeks:   weeks,
+		Cohorts: cohortRetention(subscribers, now, weeks),
+		ReadRate: map[string]*entities.MediumReadRate{
+			consts.Inapp:   {},
+			consts.Email:   {},
+			consts.Discord: {},
+		},
+		SendHours: make([]entities.HourlyReadRate, 24),
+	}
+
+	since := now.AddDate(0, 0, -days)
+	analytics.ChurnRate = churnRate(subscribers, since, now)
+
+	notifications, err := auc.repo.ListSentNotifications(ctx, chain, appID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sent notifications: %w", err)
+	}
+
+	for hour := range analytics.SendHours {
+		analytics.SendHours[hour].Hour = hour
+	}
+
+	uuids := make([]string, len(notifications))
+	for i, notification := range notifications {
+		uuids[i] = notification.UUID
+	}
+
+	reach, err := auc.repo.ListNotificationReachEvents(ctx, chain, appID, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reach of notifications: %w", err)
+	}
+
+	var timeToRead []int64
+	for _, notification := range notifications {
+		firstRead := make(map[string]time.Time)
+		for _, event := range reach[notification.UUID] {
+			readRate, ok := analytics.ReadRate[event.Medium]
+			if ok {
+				switch event.Event {
+				case consts.ReachSent:
+					readRate.Sent++
+				case consts.ReachRead:
+					readRate.Read++
+				}
+			}
+
+			if event.Event != consts.ReachRead {
+				continue
+			}
+			if read, ok := firstRead[event.Receiver]; !ok || event.EventTime.Before(read) {
+				firstRead[event.Receiver] = event.EventTime
+			}
+		}
+
+		for _, read := range firstRead {
+			if elapsed := read.Sub(notification.CreatedTime); elapsed >= 0 {
+				timeToRead = append(timeToRead, int64(elapsed.Seconds()))
+			}
+		}
+
+		hour := &analytics.SendHours[notification.CreatedTime.UTC().Hour()]
+		hour.Sent += len(notification.Receivers)
+		hour.Read += len(firstRead)
+	}
+
+	for _, readRate := range analytics.ReadRate {
+		readRate.ReadRate = ratio(readRate.Read, readRate.Sent)
+	}
+
+	for i := range analytics.SendHours {
+		hour := &analytics.SendHours[i]
+		hour.ReadRate = ratio(hour.Read, hour.Sent)
+		if hour.Sent == 0 {
+			continue
+		}
+		if analytics.BestSendHour == nil || hour.ReadRate > analytics.SendHours[*analytics.BestSendHour].ReadRate {
+			analytics.BestSendHour = &hour.Hour
+		}
+	}
+
+	if len(timeToRead) > 0 {
+		sort.Slice(timeToRead, func(i, j int) bool { return timeToRead[i] < timeToRead[j] })
+		mid := len(timeToRead) / 2
+		analytics.MedianTimeToReadSecs = timeToRead[mid]
+		if len(timeToRead)%2 == 0 {
+			analytics.MedianTimeToReadSecs = (timeToRead[mid-1] + timeToRead[mid]) / 2
+		}
+	}
+
+	return analytics, nil
+}
+
+// ExportChannelData streams a dataset of a channel the user may see stats of to w as CSV or NDJSON.
+// Nothing is written to w when the request is rejected.
+func (auc *AnalyticsUseCases) ExportChannelData(
+	ctx context.Context, chain, appID, user, dataset, format, startDate, endDate string, w io.Writer,
+) error {
+	columns, ok := exportColumns[dataset]
+	if !ok {
+		return fmt.Errorf("unsupported export dataset %s", dataset)
+	}
+
+	if format != consts.ExportCSV && format != consts.ExportNDJSON {
+		return fmt.Errorf("unsupported export format %s", format)
+	}
+
+	for _, date := range []string{startDate, endDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid date %s, expected yyyy-mm-dd", date)
+		}
+	}
+	if startDate != "" && endDate != "" && startDate > endDate {
+		return fmt.Errorf("start_date must not be after end_date")
+	}
+
+	if err := verifyChannelPermission(ctx, auc.members, chain, appID, user, consts.ChannelPermStats); err != nil {
+		return err
+	}
+
+	userModel, err := channelOwnerModel(ctx, auc.members, auc.userRepo, chain, appID)
+	if err != nil {
+		return err
+	}
+
+	membership := consts.MembershipStringToEnum(userModel.Membership)
+	if !consts.Analytics[consts.CHANNEL_DATA_EXPORT][membership] {
+		return fmt.Errorf("your membership doesn't allow exporting channel data")
+	}
+
+	var (
+		write entities.ExportRecordWriter
+		flush = func() error { return nil }
+	)
+
+	switch format {
+	case consts.ExportCSV:
+		csvWriter := csv.NewWriter(w)
+		headerWritten := false
+		write = func(record []any) error {
+			if !headerWritten {
+				if err := csvWriter.Write(columns); err != nil {
+					return err
+				}
+				headerWritten = true
+			}
+
+			row := make([]string, len(record))
+			for i, value := range record {
+				row[i] = fmt.Sprint(exportValue(value))
+			}
+
+			return csvWriter.Write(row)
+		}
+		flush = func() error {
+			if !headerWritten {
+				if err := csvWriter.Write(columns); err != nil {
+					return err
+				}
+			}
+			csvWriter.Flush()
+
+			return csvWriter.Error()
+		}
+	case consts.ExportNDJSON:
+		encoder := json.NewEncoder(w)
+		write = func(record []any) error {
+			row := make(map[string]any, len(record))
+			for i, value := range record {
+				row[columns[i]] = exportValue(value)
+			}
+
+			return encoder.Encode(row)
+		}
+	}
+
+	switch dataset {
+	case consts.ExportTraction:
+		err = auc.repo.ExportTraction(ctx, chain, appID, startDate, endDate, write)
+	case consts.ExportSentRead:
+		err = auc.repo.ExportSentRead(ctx, chain, appID, startDate, endDate, write)
+	case consts.ExportNotifications:
+		err = auc.repo.ExportNotifications(ctx, chain, appID, startDate, endDate, write)
+	case consts.ExportSubscribers:
+		err = auc.repo.ExportSubscribers(ctx, chain, appID, startDate, endDate, write)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", dataset, err)
+	}
+
+	return flush()
+}
+
+// exportValue formats timestamps as RFC3339 in UTC, unset timestamps are exported as empty values.
+func exportValue(value any) any {
+	t, ok := value.(time.Time)
+	if !ok {
+		return value
+	}
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// cohortRetention groups subscribers by the week they opted in and computes, for every week since,
+// the fraction of the cohort that had not opted out by the end of that week.
+func cohortRetention(subscribers []entities.ChannelSubscriber, now time.Time, weeks int) []entities.CohortRetention {
+	currentWeek := startOfWeek(now)
+	firstWeek := currentWeek.Add(-time.Duration(weeks-1) * week)
+
+	cohorts := make([]entities.CohortRetention, weeks)
+	for i := range cohorts {
+		weekStart := firstWeek.Add(time.Duration(i) * week)
+		cohorts[i] = entities.CohortRetention{
+			Week:      weekStart.Format("2006-01-02"),
+			Retention: make([]float64, weeks-i),
+		}
+	}
+
+	for _, subscriber := range subscribers {
+		if subscriber.OptinTime.Before(firstWeek) {
+			continue
+		}
+
+		cohort := int(startOfWeek(subscriber.OptinTime).Sub(firstWeek) / week)
+		if cohort >= weeks {
+			continue
+		}
+		cohorts[cohort].Size++
+
+		for offset := range cohorts[cohort].Retention {
+			weekEnd := firstWeek.Add(time.Duration(cohort+offset+1) * week)
+			if subscriber.OptoutTime.IsZero() || !subscriber.OptoutTime.Before(weekEnd) {
+				cohorts[cohort].Retention[offset]++
+			}
+		}
+	}
+
+	for i := range cohorts {
+		for offset := range cohorts[i].Retention {
+			cohorts[i].Retention[offset] = ratio(int(cohorts[i].Retention[offset]), cohorts[i].Size)
+		}
+	}
+
+	return cohorts
+}
+
+// churnRate returns the fraction of users subscribed at `since` who opted out before `now`.
+func churnRate(subscribers []entities.ChannelSubscriber, since, now time.Time) float64 {
+	var base, churned int
+	for _, subscriber := range subscribers {
+		if subscriber.OptinTime.After(since) {
+			continue
+		}
+		if !subscriber.OptoutTime.IsZero() && subscriber.OptoutTime.Before(since) {
+			continue
+		}
+
+		base++
+		if !subscriber.OptoutTime.IsZero() && subscriber.OptoutTime.Before(now) {
+			churned++
+		}
+	}
+
+	return ratio(churned, base)
+}
+
+// startOfWeek returns the monday midnight (UTC) of the week t falls in.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+
+	return float64(numerator) / float64(denominator)
+}
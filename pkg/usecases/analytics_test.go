@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+// fakeAnalyticsRepo serves fixed subscribers, notifications and reach events and counts the reach lookups
+type fakeAnalyticsRepo struct {
+	repo.AnalyticsRepoImply
+
+	subscribers   []entities.ChannelSubscriber
+	notifications []entities.SentNotification
+	reach         map[string][]entities.NotificationReachEvent
+	reachLookups  int
+	traction      [][]any
+}
+
+func (f *fakeAnalyticsRepo) ListChannelSubscribers(context.Context, string, string) ([]entities.ChannelSubscriber, error) {
+	return f.subscribers, nil
+}
+
+func (f *fakeAnalyticsRepo) ListSentNotifications(
+	context.Context, string, string, time.Time,
+) ([]entities.SentNotification, error) {
+	return f.notifications, nil
+}
+
+func (f *fakeAnalyticsRepo) ListNotificationReachEvents(
+	_ context.Context, _, _ string, uuids []string,
+) (map[string][]entities.NotificationReachEvent, error) {
+	f.reachLookups++
+
+	reach := make(map[string][]entities.NotificationReachEvent, len(uuids))
+	for _, uuid := range uuids {
+		reach[uuid] = f.reach[uuid]
+	}
+
+	return reach, nil
+}
+
+func (f *fakeAnalyticsRepo) ExportTraction(
+	_ context.Context, _, _, _, _ string, write entities.ExportRecordWriter,
+) error {
+	for _, record := range f.traction {
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Test_cohortRetention(t *testing.T) {
+	// a wednesday, the current week starts on monday 2024-01-15
+	now := time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC)
+
+	subscribers := []entities.ChannelSubscriber{
+		// opted in before tracking began
+		{Address: "backfilled"},
+		{Address: "a", OptinTime: time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{
+			Address:    "b",
+			OptinTime:  time.Date(2024, 1, 9, 9, 0, 0, 0, time.UTC),
+			OptoutTime: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+		},
+		{Address: "c", OptinTime: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC)},
+	}
+
+	want := []entities.CohortRetention{
+		{Week: "2024-01-08", Size: 2, Retention: []float64{1, 0.5}},
+		{Week: "2024-01-15", Size: 1, Retention: []float64{1}},
+	}
+	if got := cohortRetention(subscribers, now, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("cohortRetention() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_churnRate(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -30)
+
+	tests := []struct {
+		name        string
+		subscribers []entities.ChannelSubscriber
+		want        float64
+	}{
+		{
+			name: "no subscribers",
+			want: 0,
+		},
+		{
+			name: "backfilled subscribers count towards the base",
+			subscribers: []entities.ChannelSubscriber{
+				{Address: "a"},
+				{Address: "b", OptoutTime: now.AddDate(0, 0, -1)},
+			},
+			want: 0.5,
+		},
+		{
+			name: "subscribers after since and churned before since are left out",
+			subscribers: []entities.ChannelSubscriber{
+				{Address: "a", OptinTime: since.AddDate(0, 0, -10)},
+				{Address: "b", OptinTime: since.AddDate(0, 0, 1), OptoutTime: now.AddDate(0, 0, -1)},
+				{Address: "c", OptinTime: since.AddDate(0, 0, -10), OptoutTime: since.AddDate(0, 0, -1)},
+				{Address: "d", OptinTime: since.AddDate(0, 0, -10), OptoutTime: since.AddDate(0, 0, 1)},
+			},
+			want: 0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := churnRate(tt.subscribers, since, now); got != tt.want {
+					t.Errorf("churnRate() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestAnalyticsUseCases_ChannelAnalytics(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{"owner": consts.ChannelRoleOwner, "analyst": consts.ChannelRoleAnalyst},
+	}
+
+	sent := time.Now().UTC().Add(-time.Hour).Truncate(time.Hour)
+	analyticsRepo := &fakeAnalyticsRepo{
+		notifications: []entities.SentNotification{
+			{UUID: "1", Receivers: []string{"a", "b"}, CreatedTime: sent},
+			{UUID: "2", Receivers: []string{"a", "b"}, CreatedTime: sent.Add(-time.Hour)},
+		},
+		reach: map[string][]entities.NotificationReachEvent{
+			"1": {
+				{Medium: consts.Inapp, Event: consts.ReachSent, Receiver: "a"},
+				{Medium: consts.Inapp, Event: consts.ReachSent, Receiver: "b"},
+				{Medium: consts.Inapp, Event: consts.ReachRead, Receiver: "a", EventTime: sent.Add(time.Minute)},
+				{Medium: consts.Email, Event: consts.ReachRead, Receiver: "b", EventTime: sent.Add(3 * time.Minute)},
+			},
+			"2": {
+				{Medium: consts.Inapp, Event: consts.ReachSent, Receiver: "a"},
+				{Medium: consts.Inapp, Event: consts.ReachSent, Receiver: "b"},
+			},
+		},
+	}
+	// the membership of the owner applies
+	usecase := func(membership string) *AnalyticsUseCases {
+		return &AnalyticsUseCases{repo: analyticsRepo, members: members, userRepo: fakeUserRepo{membership: membership}}
+	}
+
+	t.Run(
+		"membership without advanced analytics", func(t *testing.T) {
+			_, err := usecase("free").ChannelAnalytics(context.Background(), consts.Algorand, "1", "analyst", 30, 4)
+			if err == nil {
+				t.Error("ChannelAnalytics() error = nil, want an error")
+			}
+		},
+	)
+
+	t.Run(
+		"not a member", func(t *testing.T) {
+			_, err := usecase("gold").ChannelAnalytics(context.Background(), consts.Algorand, "1", "stranger", 30, 4)
+			if err == nil {
+				t.Error("ChannelAnalytics() error = nil, want an error")
+			}
+		},
+	)
+
+	t.Run(
+		"analytics", func(t *testing.T) {
+			analytics, err := usecase("gold").ChannelAnalytics(context.Background(), consts.Algorand, "1", "analyst", 30, 4)
+			if err != nil {
+				t.Fatalf("ChannelAnalytics() error = %v", err)
+			}
+
+			if analyticsRepo.reachLookups != 1 {
+				t.Errorf("reach looked up %d times, want once", analyticsRepo.reachLookups)
+			}
+			if got := analytics.ReadRate[consts.Inapp]; got.Sent != 4 || got.Read != 1 || got.ReadRate != 0.25 {
+				t.Errorf("inapp read rate = %+v, want 1 read of 4 sent", got)
+			}
+			if analytics.MedianTimeToReadSecs != 120 {
+				t.Errorf("median time to read = %d, want 120", analytics.MedianTimeToReadSecs)
+			}
+			if analytics.BestSendHour == nil || *analytics.BestSendHour != sent.Hour() {
+				t.Errorf("best send hour = %v, want %d", analytics.BestSendHour, sent.Hour())
+			}
+		},
+	)
+}
+
+func TestAnalyticsUseCases_ExportChannelData(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{"owner": consts.ChannelRoleOwner, "analyst": consts.ChannelRoleAnalyst},
+	}
+
+	eventTime := time.Date(2024, 1, 8, 9, 30, 0, 0, time.FixedZone("IST", 19800))
+	traction := [][]any{
+		{"2024-01-08", eventTime, 3, 1},
+		{"2024-01-09", time.Time{}, 0, 2},
+	}
+
+	tests := []struct {
+		name       string
+		user       string
+		membership string
+		dataset    string
+		format     string
+		startDate  string
+		endDate    string
+		traction   [][]any
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "unsupported dataset",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    "passwords",
+			format:     consts.ExportCSV,
+			wantErr:    true,
+		},
+		{
+			name:       "unsupported format",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     "xlsx",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid date",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			startDate:  "08/01/2024",
+			wantErr:    true,
+		},
+		{
+			name:       "start after end",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			startDate:  "2024-02-01",
+			endDate:    "2024-01-01",
+			wantErr:    true,
+		},
+		{
+			name:       "not a member",
+			user:       "stranger",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			wantErr:    true,
+		},
+		{
+			name:       "membership without exports",
+			user:       "analyst",
+			membership: "free",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			wantErr:    true,
+		},
+		{
+			name:       "empty csv has a header",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			want:       "event_date,event_time,optin,optout\n",
+		},
+		{
+			name:       "csv",
+			user:       "analyst",
+			membership: "gold",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportCSV,
+			traction:   traction,
+			want: "event_date,event_time,optin,optout\n" +
+				"2024-01-08,2024-01-08T04:00:00Z,3,1\n" +
+				"2024-01-09,,0,2\n",
+		},
+		{
+			name:       "ndjson",
+			user:       "analyst",
+			membership: "silver",
+			dataset:    consts.ExportTraction,
+			format:     consts.ExportNDJSON,
+			traction:   traction,
+			want: `{"event_date":"2024-01-08","event_time":"2024-01-08T04:00:00Z","optin":3,"optout":1}` + "\n" +
+				`{"event_date":"2024-01-09","event_time":"","optin":0,"optout":2}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				usecase := &AnalyticsUseCases{
+					repo:     &fakeAnalyticsRepo{traction: tt.traction},
+					members:  members,
+					userRepo: fakeUserRepo{membership: tt.membership},
+				}
+
+				var w bytes.Buffer
+				err := usecase.ExportChannelData(
+					context.Background(), consts.Algorand, "1", tt.user, tt.dataset, tt.format, tt.startDate,
+					tt.endDate, &w,
+				)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ExportChannelData() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr && w.Len() > 0 {
+					t.Errorf("ExportChannelData() wrote %q to a rejected export", w.String())
+				}
+				if got := w.String(); !tt.wantErr && got != tt.want {
+					t.Errorf("ExportChannelData() = %q, want %q", got, tt.want)
+				}
+			},
+		)
+	}
+}
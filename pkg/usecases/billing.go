@@ -3,13 +3,16 @@ package usecases
 import (
 	"context"
 
+	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/db"
 )
 
 type BillingUsecases struct {
 	repo     repo.BillingRepoImply
 	userRepo repo.UserRepoImply
+	members  repo.ChannelMemberRepoImply
 }
 
 type BillingUsecasesImply interface {
@@ -18,13 +21,17 @@ type BillingUsecasesImply interface {
 	ChangeMembership(context.Context, entities.BillingRequest) error
 	GetBillingDetails(context.Context, entities.BillingRequest) (*entities.BillingInfo, error)
 	GetMemershipTiers(context.Context) (map[string]map[string]interface{}, error)
+	ChannelBillingOwner(ctx context.Context, chain, appID, user string) (string, error)
 }
 
 // NewBillingUsecases creates a new instance of the BillingUsecases struct
-func NewBillingUsecases(billingRepo repo.BillingRepoImply, userRepo repo.UserRepoImply) BillingUsecasesImply {
+func NewBillingUsecases(
+	billingRepo repo.BillingRepoImply, userRepo repo.UserRepoImply, members repo.ChannelMemberRepoImply,
+) BillingUsecasesImply {
 	return &BillingUsecases{
 		repo:     billingRepo,
 		userRepo: userRepo,
+		members:  members,
 	}
 }
 
@@ -39,9 +46,23 @@ func (b *BillingUsecases) AddFund(ctx context.Context, req entities.BillingReque
 
 	membership := resp.Data.(*entities.UserModel).Membership
 
+	if err = withSharedBilling(ctx, &req); err != nil {
+		return err
+	}
+
 	return b.repo.AddFund(ctx, membership, req)
 }
 
+// ChannelBillingOwner returns the owner of the channel, whose billing applies to the channel, if user may
+// manage the billing of the channel
+func (b *BillingUsecases) ChannelBillingOwner(ctx context.Context, chain, appID, user string) (string, error) {
+	if err := verifyChannelPermission(ctx, b.members, chain, appID, user, consts.ChannelPermBilling); err != nil {
+		return "", err
+	}
+
+	return b.members.GetChannelOwner(ctx, chain, appID)
+}
+
 func (b *BillingUsecases) AdminChangeMembership(ctx context.Context, req entities.BillingRequest) error {
 	resp, err := b.userRepo.GetUser(ctx, entities.UserIdentifier{
 		Chain:   req.Chain,
@@ -53,6 +74,10 @@ func (b *BillingUsecases) AdminChangeMembership(ctx context.Context, req entitie
 
 	membership := resp.Data.(*entities.UserModel).Membership
 
+	if err = withSharedBilling(ctx, &req); err != nil {
+		return err
+	}
+
 	return b.repo.ChangeMembership(ctx, membership, req, true)
 }
 
@@ -67,6 +92,10 @@ func (b *BillingUsecases) ChangeMembership(ctx context.Context, req entities.Bil
 
 	membership := resp.Data.(*entities.UserModel).Membership
 
+	if err = withSharedBilling(ctx, &req); err != nil {
+		return err
+	}
+
 	return b.repo.ChangeMembership(ctx, membership, req, false)
 }
 
@@ -89,9 +118,28 @@ func (b *BillingUsecases) GetBillingDetails(ctx context.Context, req entities.Bi
 	}
 	req.TotalSent = totalSent
 
+	if err = withSharedBilling(ctx, &req); err != nil {
+		return nil, err
+	}
+
 	return b.repo.GetBillingDetails(ctx, membership, req)
 }
 
+// withSharedBilling points the request to the shared balance when the account shares billing with a linked account
+func withSharedBilling(ctx context.Context, req *entities.BillingRequest) error {
+	link, err := db.GetSharedBilling(ctx, req.Chain, req.Address)
+	if err != nil || link == nil {
+		return err
+	}
+
+	if *link.BillingAccount != (entities.UserIdentifier{Chain: req.Chain, Address: req.Address}) {
+		req.BillingAccount = link.BillingAccount
+	}
+	req.SharedWith = []entities.UserIdentifier{link.UserIdentifier}
+
+	return nil
+}
+
 func (b *BillingUsecases) GetMemershipTiers(ctx context.Context) (map[string]map[string]interface{}, error) {
 	return b.repo.GetMembershipTiers(ctx)
 }
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"notiboy/pkg/consts"
+)
+
+func TestBillingUsecases_ChannelBillingOwner(t *testing.T) {
+	usecase := &BillingUsecases{
+		members: channelMembers{
+			roles: map[string]string{
+				"owner": consts.ChannelRoleOwner, "admin": consts.ChannelRoleAdmin, "analyst": consts.ChannelRoleAnalyst,
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		wantErr bool
+	}{
+		{
+			name: "owner",
+			user: "owner",
+		},
+		{
+			name:    "admin",
+			user:    "admin",
+			wantErr: true,
+		},
+		{
+			name:    "analyst",
+			user:    "analyst",
+			wantErr: true,
+		},
+		{
+			name:    "not a member",
+			user:    "stranger",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				owner, err := usecase.ChannelBillingOwner(context.Background(), consts.Algorand, "1", tt.user)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ChannelBillingOwner() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && owner != "owner" {
+					t.Errorf("ChannelBillingOwner() = %s, want owner", owner)
+				}
+			},
+		)
+	}
+}
@@ -10,13 +10,13 @@ import (
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
-	"notiboy/pkg/repo/driver/db"
 	"notiboy/utilities"
 )
 
 type ChannelUseCases struct {
 	repo     repo.ChannelRepoImpl
 	userRepo repo.UserRepoImply
+	members  repo.ChannelMemberRepoImply
 }
 
 type ChannelUseCaseImply interface {
@@ -31,12 +31,32 @@ type ChannelUseCaseImply interface {
 	ChannelReadSentStatistics(ctx context.Context, r *http.Request, chain, channel, fetchKind, startDate, endDate string) ([]entities.ChannelReadSentResponse, error)
 	ChannelNotificationStatistics(ctx context.Context, r *http.Request, chain string, channel string, address string, typeStr, startDate, endDate string, limit int, offset int) (*repo.ChannelStats, error)
 	VerifyChannel(ctx context.Context, chain, appID string) error
+	RequestVerification(ctx context.Context, chain, appID, user string, request entities.ChannelVerificationRequest) (*entities.ChannelVerification, error)
+	GetVerificationRequest(ctx context.Context, chain, appID, user string) (*entities.ChannelVerification, error)
+	ListVerificationRequests(ctx context.Context, chain, status string) ([]entities.ChannelVerification, error)
+	RejectVerification(ctx context.Context, chain, appID, reason string) error
+	RevokeVerification(ctx context.Context, chain, appID, reason string) error
+	ClaimDomain(ctx context.Context, chain, appID, user string, request entities.ChannelDomainRequest) (*entities.ChannelDomain, error)
+	GetDomainClaim(ctx context.Context, chain, appID, user string) (*entities.ChannelDomain, error)
+	CheckDomain(ctx context.Context, chain, appID, user string) (*entities.ChannelDomain, error)
+	RemoveDomainClaim(ctx context.Context, chain, appID, user string) error
+	SetChannelTags(ctx context.Context, chain, appID, user string, request entities.ChannelTagsRequest) error
+	CurateChannelTags(ctx context.Context, chain, appID string, request entities.CurateChannelTagsRequest) error
+	ListCategories() []string
+	TrendingChannels(ctx context.Context, chain string, limit int, withLogo bool) ([]entities.RankedChannel, error)
+	ActiveSenders(ctx context.Context, chain string, limit int, withLogo bool) ([]entities.RankedChannel, error)
+	SimilarChannels(
+		ctx context.Context, chain, appID string, limit int, withLogo bool,
+	) ([]entities.RankedChannel, error)
 }
 
-func NewChannelUseCases(repo repo.ChannelRepoImpl, userRepo repo.UserRepoImply) ChannelUseCaseImply {
+func NewChannelUseCases(
+	repo repo.ChannelRepoImpl, userRepo repo.UserRepoImply, members repo.ChannelMemberRepoImply,
+) ChannelUseCaseImply {
 	return &ChannelUseCases{
 		repo:     repo,
 		userRepo: userRepo,
+		members:  members,
 	}
 }
 
@@ -62,7 +82,11 @@ func (cuc *ChannelUseCases) ChannelUsers(ctx context.Context, r *http.Request, r
 
 // ChannelCreate creates a new channel with the provided channel information and chain.
 func (cuc *ChannelUseCases) ChannelCreate(ctx context.Context, data entities.ChannelInfo, chain string) (*entities.Response, error) {
-	userModel, err := db.GetUserModel(ctx, chain, data.Address)
+	if data.Visibility != "" && !utilities.ContainsString(consts.ChannelVisibilities, data.Visibility) {
+		return nil, fmt.Errorf("invalid visibility %s", data.Visibility)
+	}
+
+	userModel, err := cuc.userRepo.GetUserModel(ctx, chain, data.Address)
 	if err != nil {
 		return nil, fmt.Errorf("getting user model failed")
 	}
@@ -77,6 +101,9 @@ func (cuc *ChannelUseCases) ChannelCreate(ctx context.Context, data entities.Cha
 
 // ChannelUpdate updates an existing channel with the provided channel information.
 func (cuc *ChannelUseCases) ChannelUpdate(ctx context.Context, data entities.ChannelInfo) error {
+	if data.Visibility != "" && !utilities.ContainsString(consts.ChannelVisibilities, data.Visibility) {
+		return fmt.Errorf("invalid visibility %s", data.Visibility)
+	}
 
 	return cuc.repo.ChannelUpdate(ctx, data)
 }
@@ -143,9 +170,13 @@ func (cuc *ChannelUseCases) DeleteChannel(ctx context.Context, chain string, app
 func (cuc *ChannelUseCases) ChannelReadSentStatistics(ctx context.Context, r *http.Request, chain, channel, fetchKind, startDate, endDate string) ([]entities.ChannelReadSentResponse, error) {
 	sender := cast.ToString(ctx.Value(consts.UserAddress))
 
-	userModel, err := db.GetUserModel(ctx, chain, sender)
+	if err := verifyChannelPermission(ctx, cuc.members, chain, channel, sender, consts.ChannelPermStats); err != nil {
+		return nil, err
+	}
+
+	userModel, err := channelOwnerModel(ctx, cuc.members, cuc.userRepo, chain, channel)
 	if err != nil {
-		return nil, fmt.Errorf("getting user model failed")
+		return nil, err
 	}
 
 	membership := consts.MembershipStringToEnum(userModel.Membership)
@@ -155,18 +186,6 @@ func (cuc *ChannelUseCases) ChannelReadSentStatistics(ctx context.Context, r *ht
 		return nil, fmt.Errorf("your membership doesn't allow analytics for channel read/sent metrics")
 	}
 
-	owned := false
-	for _, userChannel := range userModel.Channels {
-		if userChannel == channel {
-			owned = true
-			break
-		}
-	}
-
-	if !owned {
-		return nil, fmt.Errorf("channel %s is not owned by user %s", channel, sender)
-	}
-
 	data, err := cuc.repo.ChannelReadSentStatistics(ctx, r, chain, channel, fetchKind, startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -183,6 +202,24 @@ func (cuc *ChannelUseCases) ChannelStatistics(ctx context.Context, r *http.Reque
 	return data, nil
 }
 
+// VerifyChannel verifies the channel and approves the pending request to verify it if there is one, letting
+// the owner who requested it know.
 func (cuc *ChannelUseCases) VerifyChannel(ctx context.Context, chain, appID string) error {
-	return cuc.repo.VerifyChannel(ctx, chain, appID)
+	if err := cuc.repo.VerifyChannel(ctx, chain, appID); err != nil {
+		return err
+	}
+
+	// channels can still be verified without a request
+	admin := cast.ToString(ctx.Value(consts.UserAddress))
+	err := cuc.repo.UpdateVerificationStatus(
+		ctx, chain, appID, consts.VerificationPending, consts.VerificationApproved, "", admin,
+	)
+	if err != nil {
+		utilities.NewLogger("VerifyChannel").WithError(err).Warn("no pending verification request approved")
+		return nil
+	}
+
+	cuc.notifyVerificationDecision(ctx, chain, appID, "approved", "")
+
+	return nil
 }
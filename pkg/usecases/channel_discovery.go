@@ -0,0 +1,289 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/cache"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+)
+
+// tagPattern is what a tag looks like once normalised
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// SetChannelTags sets the category and tags the channel is discovered by, unless the admins curated them.
+func (cuc *ChannelUseCases) SetChannelTags(
+	ctx context.Context, chain, appID, user string, request entities.ChannelTagsRequest,
+) error {
+	if err := verifyChannelPermission(ctx, cuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return err
+	}
+
+	tags, err := validateChannelTags(&request)
+	if err != nil {
+		return err
+	}
+
+	return cuc.repo.SetChannelTags(ctx, chain, appID, request.Category, tags, nil)
+}
+
+// CurateChannelTags sets the category and tags of the channel on behalf of the admins, and whether its owner can
+// still change them.
+func (cuc *ChannelUseCases) CurateChannelTags(
+	ctx context.Context, chain, appID string, request entities.CurateChannelTagsRequest,
+) error {
+	tags, err := validateChannelTags(&request.ChannelTagsRequest)
+	if err != nil {
+		return err
+	}
+
+	return cuc.repo.SetChannelTags(ctx, chain, appID, request.Category, tags, &request.Curated)
+}
+
+// ListCategories retrieves the categories channels are listed under.
+func (cuc *ChannelUseCases) ListCategories() []string {
+	return consts.ChannelCategories
+}
+
+// TrendingChannels retrieves the channels gaining subscribers the fastest, scored by net opt-ins per day.
+func (cuc *ChannelUseCases) TrendingChannels(
+	ctx context.Context, chain string, limit int, withLogo bool,
+) ([]entities.RankedChannel, error) {
+	return cuc.repo.GetRanking(ctx, chain, consts.RankingTrending, rankingLimit(limit), withLogo)
+}
+
+// ActiveSenders retrieves the channels sending the most notifications, scored by notifications sent per day.
+func (cuc *ChannelUseCases) ActiveSenders(
+	ctx context.Context, chain string, limit int, withLogo bool,
+) ([]entities.RankedChannel, error) {
+	return cuc.repo.GetRanking(ctx, chain, consts.RankingActiveSenders, rankingLimit(limit), withLogo)
+}
+
+// SimilarChannels retrieves the channels sharing the most subscribers with the channel, scored by the share of
+// their subscribers in common.
+func (cuc *ChannelUseCases) SimilarChannels(
+	ctx context.Context, chain, appID string, limit int, withLogo bool,
+) ([]entities.RankedChannel, error) {
+	return cuc.repo.GetRanking(ctx, chain, consts.RankingSimilar+appID, rankingLimit(limit), withLogo)
+}
+
+// DiscoveryWorker periodically ranks the public channels of every chain by opt-in velocity, by notifications sent
+// and by subscriber overlap, over the last days. The rankings are computed once at start so discovery isn't empty
+// until the first tick.
+func DiscoveryWorker(ctx context.Context, channelRepo repo.ChannelRepoImpl) {
+	log := utilities.NewLogger("DiscoveryWorker")
+
+	ticker := time.NewTicker(cast.ToDuration(config.GetConfig().DiscoveryInterval))
+
+	go func() {
+		runOnce := make(chan struct{}, 1)
+
+		runOnce <- struct{}{}
+		go runDiscoveryRankings(ctx, channelRepo, runOnce)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("Terminating...")
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				select {
+				// at any point of time, no more than one go routine should run
+				case runOnce <- struct{}{}:
+					go runDiscoveryRankings(ctx, channelRepo, runOnce)
+				default:
+				}
+			}
+		}
+	}()
+}
+
+func runDiscoveryRankings(ctx context.Context, channelRepo repo.ChannelRepoImpl, runOnce chan struct{}) {
+	log := utilities.NewLogger("runDiscoveryRankings")
+
+	defer func() {
+		<-runOnce
+	}()
+
+	// channels created or changed through other instances are searchable once indexed again
+	if err := cache.GetChannelSearchIndex().Reload(); err != nil {
+		log.WithError(err).Error("failed to reload channel search index")
+	}
+
+	since := utilities.ToDate(utilities.TimeNow().AddDate(0, 0, -consts.DiscoveryWindowDays))
+
+	for _, chain := range config.GetConfig().Chain.Supported {
+		if err := rankChannels(ctx, channelRepo, chain, since); err != nil {
+			log.WithError(err).Errorf("failed to rank channels of %s", chain)
+		}
+	}
+}
+
+// rankChannels computes and stores the rankings of the public channels of chain since the date
+func rankChannels(ctx context.Context, channelRepo repo.ChannelRepoImpl, chain, since string) error {
+	log := utilities.NewLoggerWithFields("rankChannels", map[string]interface{}{"chain": chain})
+
+	channels, err := channelRepo.ListDiscoverableChannels(ctx, chain)
+	if err != nil {
+		return err
+	}
+
+	var (
+		trending = make([]entities.ChannelScore, 0)
+		active   = make([]entities.ChannelScore, 0)
+		// the channels every user subscribes to, and the number of subscribers of every channel
+		userChannels    = make(map[string][]string)
+		subscriberCount = make(map[string]int, len(channels))
+	)
+	for _, channel := range channels {
+		optins, optouts, err := channelRepo.ChannelTraction(ctx, chain, channel.AppID, since)
+		if err != nil {
+			log.WithError(err).Errorf("failed to get traction of %s", channel.AppID)
+		} else if net := optins - optouts; net > 0 {
+			trending = append(
+				trending, entities.ChannelScore{
+					AppID: channel.AppID, Score: float64(net) / consts.DiscoveryWindowDays,
+				},
+			)
+		}
+
+		sent, err := channelRepo.ChannelSentCount(ctx, chain, channel.AppID, since)
+		if err != nil {
+			log.WithError(err).Errorf("failed to get notifications sent by %s", channel.AppID)
+		} else if sent > 0 {
+			active = append(
+				active, entities.ChannelScore{AppID: channel.AppID, Score: float64(sent) / consts.DiscoveryWindowDays},
+			)
+		}
+
+		users, err := channelRepo.RetrieveChannelUsers(ctx, chain, channel.AppID)
+		if err != nil {
+			log.WithError(err).Errorf("failed to get subscribers of %s", channel.AppID)
+			continue
+		}
+		subscriberCount[channel.AppID] = len(users)
+		for _, user := range users {
+			userChannels[user] = append(userChannels[user], channel.AppID)
+		}
+	}
+
+	if err = channelRepo.StoreRanking(ctx, chain, consts.RankingTrending, topScores(trending)); err != nil {
+		return err
+	}
+	if err = channelRepo.StoreRanking(ctx, chain, consts.RankingActiveSenders, topScores(active)); err != nil {
+		return err
+	}
+
+	similar := similarChannels(userChannels, subscriberCount)
+	for _, channel := range channels {
+		// stored even when empty so that channels which stopped overlapping drop out
+		err = channelRepo.StoreRanking(
+			ctx, chain, consts.RankingSimilar+channel.AppID, topScores(similar[channel.AppID]),
+		)
+		if err != nil {
+			log.WithError(err).Errorf("failed to store channels similar to %s", channel.AppID)
+		}
+	}
+
+	return nil
+}
+
+type channelPair struct {
+	a, b string
+}
+
+// similarChannels scores every channel sharing subscribers with another one by the share of the subscribers of
+// either that subscribe to both. Only the channels of every subscriber are paired, so channels without common
+// subscribers cost nothing. Subscribers of more than consts.SimilarityMaxOptins channels are left out of the pairs.
+func similarChannels(
+	userChannels map[string][]string, subscriberCount map[string]int,
+) map[string][]entities.ChannelScore {
+	common := make(map[channelPair]int)
+	for _, appIDs := range userChannels {
+		if len(appIDs) > consts.SimilarityMaxOptins {
+			continue
+		}
+
+		for i := range appIDs {
+			for j := i + 1; j < len(appIDs); j++ {
+				pair := channelPair{a: appIDs[i], b: appIDs[j]}
+				if pair.b < pair.a {
+					pair.a, pair.b = pair.b, pair.a
+				}
+				common[pair]++
+			}
+		}
+	}
+
+	similar := make(map[string][]entities.ChannelScore)
+	for pair, count := range common {
+		overlap := float64(count) / float64(subscriberCount[pair.a]+subscriberCount[pair.b]-count)
+		similar[pair.a] = append(similar[pair.a], entities.ChannelScore{AppID: pair.b, Score: overlap})
+		similar[pair.b] = append(similar[pair.b], entities.ChannelScore{AppID: pair.a, Score: overlap})
+	}
+
+	return similar
+}
+
+// topScores orders scores best first and keeps the top of them
+func topScores(scores []entities.ChannelScore) []entities.ChannelScore {
+	sort.Slice(
+		scores, func(i, j int) bool {
+			if scores[i].Score != scores[j].Score {
+				return scores[i].Score > scores[j].Score
+			}
+			return scores[i].AppID < scores[j].AppID
+		},
+	)
+
+	if len(scores) > consts.RankingSize {
+		scores = scores[:consts.RankingSize]
+	}
+
+	return scores
+}
+
+// rankingLimit bounds the channels requested from a ranking to what it keeps
+func rankingLimit(limit int) int {
+	if limit <= 0 || limit > consts.RankingSize {
+		return consts.RankingSize
+	}
+
+	return limit
+}
+
+// validateChannelTags checks the category of request and returns its tags normalised and deduplicated
+func validateChannelTags(request *entities.ChannelTagsRequest) ([]string, error) {
+	request.Category = strings.ToLower(strings.TrimSpace(request.Category))
+	if request.Category != "" && !utilities.ContainsString(consts.ChannelCategories, request.Category) {
+		return nil, fmt.Errorf("invalid category %s", request.Category)
+	}
+
+	tags := make([]string, 0, len(request.Tags))
+	for _, tag := range request.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		if !utilities.ContainsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) > consts.MaxChannelTags {
+		return nil, fmt.Errorf("a channel can have at most %d tags", consts.MaxChannelTags)
+	}
+
+	return tags, nil
+}
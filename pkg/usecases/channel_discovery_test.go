@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+func Test_similarChannels(t *testing.T) {
+	// a heavy subscriber of more channels than counted towards similarity
+	heavy := make([]string, 0, consts.SimilarityMaxOptins+1)
+	for i := 0; i <= consts.SimilarityMaxOptins; i++ {
+		heavy = append(heavy, strconv.Itoa(i))
+	}
+
+	userChannels := map[string][]string{
+		"alice": {"news", "sports"},
+		"bob":   {"sports", "news"},
+		"carol": {"news", "music"},
+		"dave":  {"sports"},
+		"erin":  {"weather"},
+		"heavy": heavy,
+	}
+	subscriberCount := map[string]int{"news": 3, "sports": 3, "music": 1, "weather": 1}
+
+	got := topScoresOf(similarChannels(userChannels, subscriberCount))
+	want := map[string][]entities.ChannelScore{
+		// 2 common of 4 subscribers of either
+		"news":   {{AppID: "sports", Score: 0.5}, {AppID: "music", Score: 1.0 / 3}},
+		"sports": {{AppID: "news", Score: 0.5}},
+		"music":  {{AppID: "news", Score: 1.0 / 3}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("similarChannels() = %v, want %v", got, want)
+	}
+}
+
+func topScoresOf(similar map[string][]entities.ChannelScore) map[string][]entities.ChannelScore {
+	for appID, scores := range similar {
+		similar[appID] = topScores(scores)
+	}
+
+	return similar
+}
+
+func Test_topScores(t *testing.T) {
+	scores := make([]entities.ChannelScore, 0, consts.RankingSize+2)
+	for i := 0; i < consts.RankingSize+2; i++ {
+		scores = append(scores, entities.ChannelScore{AppID: strconv.Itoa(i), Score: float64(i % 3)})
+	}
+
+	got := topScores(scores)
+	if len(got) != consts.RankingSize {
+		t.Fatalf("topScores() kept %d scores, want %d", len(got), consts.RankingSize)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1].Score < got[i].Score || (got[i-1].Score == got[i].Score && got[i-1].AppID > got[i].AppID) {
+			t.Errorf("topScores() ordered %v before %v", got[i-1], got[i])
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/domain"
+	"notiboy/utilities"
+)
+
+var domainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// domainCheckTimeout bounds checking the proof of one domain
+const domainCheckTimeout = 30 * time.Second
+
+// ClaimDomain claims a domain for the channel and issues the token the domain proves it belongs to the channel
+// with, by a TXT record or its well-known file. Claiming another domain replaces the previous claim.
+func (cuc *ChannelUseCases) ClaimDomain(
+	ctx context.Context, chain, appID, user string, request entities.ChannelDomainRequest,
+) (*entities.ChannelDomain, error) {
+	if err := verifyChannelPermission(ctx, cuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeDomain(request.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utilities.GenerateSecret(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	claim := &entities.ChannelDomain{
+		Chain:   chain,
+		AppID:   appID,
+		Domain:  name,
+		Token:   token,
+		Status:  consts.DomainPending,
+		Created: utilities.TimeNow(),
+	}
+
+	if err = cuc.repo.ClaimDomain(ctx, claim); err != nil {
+		return nil, err
+	}
+
+	return withProofInstructions(claim), nil
+}
+
+// GetDomainClaim retrieves the domain claimed by the channel and how it proves it, it is visible to every member.
+func (cuc *ChannelUseCases) GetDomainClaim(
+	ctx context.Context, chain, appID, user string,
+) (*entities.ChannelDomain, error) {
+	role, err := cuc.members.GetChannelRole(ctx, chain, appID, user)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, fmt.Errorf("user %s is not a member of channel %s", user, appID)
+	}
+
+	claim, err := cuc.repo.GetDomainClaim(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withProofInstructions(claim), nil
+}
+
+// CheckDomain checks the proof of the domain claimed by the channel right away, instead of waiting for the
+// periodic check.
+func (cuc *ChannelUseCases) CheckDomain(
+	ctx context.Context, chain, appID, user string,
+) (*entities.ChannelDomain, error) {
+	if err := verifyChannelPermission(ctx, cuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	claim, err := cuc.repo.GetDomainClaim(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDomainClaim(ctx, cuc.repo, claim); err != nil {
+		return nil, err
+	}
+
+	return withProofInstructions(claim), nil
+}
+
+// RemoveDomainClaim removes the domain claimed by the channel.
+func (cuc *ChannelUseCases) RemoveDomainClaim(ctx context.Context, chain, appID, user string) error {
+	if err := verifyChannelPermission(ctx, cuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return err
+	}
+
+	return cuc.repo.DeleteDomainClaim(ctx, chain, appID)
+}
+
+// DomainProofWorker periodically checks that the domains claimed by channels still carry their proof. A pending
+// domain is verified once its proof shows up, a verified domain fails once it disappears.
+func DomainProofWorker(ctx context.Context, channelRepo repo.ChannelRepoImpl) {
+	log := utilities.NewLogger("DomainProofWorker")
+
+	ticker := time.NewTicker(cast.ToDuration(config.GetConfig().DomainCheckInterval))
+
+	go func() {
+		runOnce := make(chan struct{}, 1)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("Terminating...")
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				select {
+				// at any point of time, no more than one go routine should run
+				case runOnce <- struct{}{}:
+					go runDomainChecks(ctx, channelRepo, runOnce)
+				default:
+				}
+			}
+		}
+	}()
+}
+
+func runDomainChecks(ctx context.Context, channelRepo repo.ChannelRepoImpl, runOnce chan struct{}) {
+	log := utilities.NewLogger("runDomainChecks")
+
+	defer func() {
+		<-runOnce
+	}()
+
+	claims, err := channelRepo.ListDomainClaims(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to list domain claims")
+		return
+	}
+
+	for i := range claims {
+		if err = checkDomainClaim(ctx, channelRepo, &claims[i]); err != nil {
+			log.WithError(err).Errorf("failed to check domain %s", claims[i].Domain)
+		}
+	}
+}
+
+// checkDomainClaim checks the proof of the domain claimed and records the outcome on claim
+func checkDomainClaim(ctx context.Context, channelRepo repo.ChannelRepoImpl, claim *entities.ChannelDomain) error {
+	checkCtx, cancel := context.WithTimeout(ctx, domainCheckTimeout)
+	defer cancel()
+
+	method, proofErr := channelRepo.ProveDomain(checkCtx, claim.Domain, claim.Token)
+
+	now := utilities.TimeNow()
+	claim.Checked = &now
+
+	if proofErr == nil {
+		if claim.Status != consts.DomainVerified || claim.Verified == nil {
+			claim.Verified = &now
+		}
+		claim.Status, claim.Method, claim.Error = consts.DomainVerified, method, ""
+	} else {
+		if claim.Status == consts.DomainVerified {
+			claim.Status = consts.DomainFailed
+		}
+		claim.Method, claim.Error, claim.Verified = "", proofErr.Error(), nil
+	}
+
+	return channelRepo.UpdateDomainProof(ctx, claim)
+}
+
+// normalizeDomain returns the lowercase host name of domain, which may be given as a URL
+func normalizeDomain(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strings.Contains(name, "://") {
+		u, err := url.Parse(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid domain %s", name)
+		}
+		name = u.Hostname()
+	}
+	name = strings.TrimSuffix(name, ".")
+
+	if len(name) > 253 || !domainRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid domain %s", name)
+	}
+
+	return name, nil
+}
+
+// withProofInstructions fills in where the domain of claim carries its proof
+func withProofInstructions(claim *entities.ChannelDomain) *entities.ChannelDomain {
+	claim.TXTRecord = domain.TXTRecordName(claim.Domain)
+	claim.TXTValue = domain.TXTRecordPrefix + claim.Token
+	claim.WellKnownURL = "https://" + claim.Domain + domain.WellKnownPath
+
+	return claim
+}
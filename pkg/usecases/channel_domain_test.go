@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/domain"
+)
+
+// provenDomains proves the domains with prover and records the claims updated
+type provenDomains struct {
+	repo.ChannelRepoImpl
+	prover  *domain.Prover
+	updated []entities.ChannelDomain
+}
+
+func (p *provenDomains) ProveDomain(ctx context.Context, name, token string) (string, error) {
+	return p.prover.Prove(ctx, name, token)
+}
+
+func (p *provenDomains) UpdateDomainProof(_ context.Context, claim *entities.ChannelDomain) error {
+	p.updated = append(p.updated, *claim)
+	return nil
+}
+
+type txtRecords []string
+
+func (r txtRecords) LookupTXT(context.Context, string) ([]string, error) {
+	return r, nil
+}
+
+type unreachable struct{}
+
+func (unreachable) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func Test_checkDomainClaim(t *testing.T) {
+	verified := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		status       string
+		verified     *time.Time
+		records      []string
+		wantStatus   string
+		wantMethod   string
+		wantVerified bool
+	}{
+		{
+			name:         "pending domain proven",
+			status:       consts.DomainPending,
+			records:      []string{domain.TXTRecordPrefix + "token"},
+			wantStatus:   consts.DomainVerified,
+			wantMethod:   consts.DomainProofDNS,
+			wantVerified: true,
+		},
+		{
+			name:       "pending domain without proof",
+			status:     consts.DomainPending,
+			wantStatus: consts.DomainPending,
+		},
+		{
+			name:         "verified domain still proven",
+			status:       consts.DomainVerified,
+			verified:     &verified,
+			records:      []string{domain.TXTRecordPrefix + "token"},
+			wantStatus:   consts.DomainVerified,
+			wantMethod:   consts.DomainProofDNS,
+			wantVerified: true,
+		},
+		{
+			name:       "verified domain lost its proof",
+			status:     consts.DomainVerified,
+			verified:   &verified,
+			records:    []string{domain.TXTRecordPrefix + "other"},
+			wantStatus: consts.DomainFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				// the domains carry the TXT records only
+				domains := &provenDomains{
+					prover: &domain.Prover{Resolver: txtRecords(tt.records), Fetcher: unreachable{}},
+				}
+				claim := &entities.ChannelDomain{
+					Domain: "example.com", Token: "token", Status: tt.status, Verified: tt.verified,
+				}
+
+				if err := checkDomainClaim(context.Background(), domains, claim); err != nil {
+					t.Fatalf("checkDomainClaim() error = %v", err)
+				}
+				if len(domains.updated) != 1 {
+					t.Fatalf("checkDomainClaim() updated %d claims", len(domains.updated))
+				}
+				if claim.Status != tt.wantStatus || claim.Method != tt.wantMethod {
+					t.Errorf(
+						"checkDomainClaim() = %s by %q, want %s by %q", claim.Status, claim.Method, tt.wantStatus,
+						tt.wantMethod,
+					)
+				}
+				if (claim.Verified != nil) != tt.wantVerified || (claim.Error == "") != tt.wantVerified {
+					t.Errorf("checkDomainClaim() verified = %v, error = %q", claim.Verified, claim.Error)
+				}
+				if tt.verified != nil && tt.wantVerified && !claim.Verified.Equal(verified) {
+					t.Errorf("checkDomainClaim() moved verified from %v to %v", verified, claim.Verified)
+				}
+			},
+		)
+	}
+}
+
+func Test_normalizeDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{name: "domain", domain: "example.com", want: "example.com"},
+		{name: "uppercase and spaces", domain: " Example.COM ", want: "example.com"},
+		{name: "trailing dot", domain: "news.example.com.", want: "news.example.com"},
+		{name: "URL", domain: "https://Example.com:8443/path?q=1", want: "example.com"},
+		{name: "no TLD", domain: "localhost", wantErr: true},
+		{name: "IP address", domain: "127.0.0.1", wantErr: true},
+		{name: "underscore", domain: "ex_ample.com", wantErr: true},
+		{name: "empty", domain: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := normalizeDomain(tt.domain)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("normalizeDomain() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("normalizeDomain() = %s, want %s", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+type verifiedChannels struct {
+	repo.ChannelRepoImpl
+	pending  bool
+	notified int
+}
+
+func (v *verifiedChannels) VerifyChannel(context.Context, string, string) error {
+	return nil
+}
+
+func (v *verifiedChannels) UpdateVerificationStatus(context.Context, string, string, string, string, string, string) error {
+	if !v.pending {
+		return errors.New("verification of channel 1 is not PENDING")
+	}
+	return nil
+}
+
+func (v *verifiedChannels) GetChannel(context.Context, string, string, bool) (*entities.Response, error) {
+	v.notified++
+	return &entities.Response{Data: entities.ChannelModel{}}, nil
+}
+
+func TestChannelUseCases_VerifyChannel(t *testing.T) {
+	tests := []struct {
+		name         string
+		pending      bool
+		wantNotified int
+	}{
+		{
+			name:         "pending request",
+			pending:      true,
+			wantNotified: 1,
+		},
+		{
+			name: "verified without a request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				channels := &verifiedChannels{pending: tt.pending}
+				cuc := &ChannelUseCases{repo: channels}
+
+				if err := cuc.VerifyChannel(context.Background(), "algorand", "1"); err != nil {
+					t.Fatalf("VerifyChannel() error = %v", err)
+				}
+				if channels.notified != tt.wantNotified {
+					t.Errorf("VerifyChannel() notified %d times, want %d", channels.notified, tt.wantNotified)
+				}
+			},
+		)
+	}
+}
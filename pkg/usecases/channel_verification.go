@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cast"
+
+	"notiboy/pkg/cache"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/utilities"
+)
+
+// RequestVerification asks the admins to verify the channel, the website, socials and notes are the evidence
+// they review.
+func (cuc *ChannelUseCases) RequestVerification(
+	ctx context.Context, chain, appID, user string, request entities.ChannelVerificationRequest,
+) (*entities.ChannelVerification, error) {
+	if err := verifyChannelPermission(ctx, cuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	if cache.GetChannelVerifyCache().IsVerified(chain, appID) {
+		return nil, fmt.Errorf("channel %s is already verified", appID)
+	}
+
+	website, err := url.Parse(request.Website)
+	if err != nil || website.Host == "" || (website.Scheme != "https" && website.Scheme != "http") {
+		return nil, fmt.Errorf("invalid website %s", request.Website)
+	}
+
+	now := utilities.TimeNow()
+	verification := &entities.ChannelVerification{
+		Chain:       chain,
+		AppID:       appID,
+		RequestedBy: user,
+		Website:     request.Website,
+		Socials:     request.Socials,
+		Notes:       request.Notes,
+		Status:      consts.VerificationPending,
+		Created:     now,
+		Updated:     now,
+	}
+
+	if err = cuc.repo.RequestVerification(ctx, verification); err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+// GetVerificationRequest retrieves the latest request to verify the channel, it is visible to every member.
+func (cuc *ChannelUseCases) GetVerificationRequest(
+	ctx context.Context, chain, appID, user string,
+) (*entities.ChannelVerification, error) {
+	role, err := cuc.members.GetChannelRole(ctx, chain, appID, user)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, fmt.Errorf("user %s is not a member of channel %s", user, appID)
+	}
+
+	return cuc.repo.GetVerificationRequest(ctx, chain, appID)
+}
+
+// ListVerificationRequests retrieves the requests to verify channels for the admin review queue.
+func (cuc *ChannelUseCases) ListVerificationRequests(
+	ctx context.Context, chain, status string,
+) ([]entities.ChannelVerification, error) {
+	return cuc.repo.ListVerificationRequests(ctx, chain, status)
+}
+
+// RejectVerification rejects the pending request to verify the channel and lets its owner know why.
+func (cuc *ChannelUseCases) RejectVerification(ctx context.Context, chain, appID, reason string) error {
+	admin := cast.ToString(ctx.Value(consts.UserAddress))
+
+	err := cuc.repo.UpdateVerificationStatus(
+		ctx, chain, appID, consts.VerificationPending, consts.VerificationRejected, reason, admin,
+	)
+	if err != nil {
+		return err
+	}
+
+	cuc.notifyVerificationDecision(ctx, chain, appID, "rejected", reason)
+
+	return nil
+}
+
+// RevokeVerification unverifies the verified channel and lets its owner know why.
+func (cuc *ChannelUseCases) RevokeVerification(ctx context.Context, chain, appID, reason string) error {
+	if !cache.GetChannelVerifyCache().IsVerified(chain, appID) {
+		return fmt.Errorf("channel %s is not verified", appID)
+	}
+
+	admin := cast.ToString(ctx.Value(consts.UserAddress))
+	if err := cuc.repo.RevokeVerification(ctx, chain, appID, reason, admin); err != nil {
+		return err
+	}
+
+	cuc.notifyVerificationDecision(ctx, chain, appID, "revoked", reason)
+
+	return nil
+}
+
+// notifyVerificationDecision lets the owner of the channel know about the decision on its verification. Failing
+// to notify doesn't fail the decision.
+func (cuc *ChannelUseCases) notifyVerificationDecision(ctx context.Context, chain, appID, decision, reason string) {
+	log := utilities.NewLoggerWithFields(
+		"notifyVerificationDecision", map[string]interface{}{
+			"chain":  chain,
+			"app_id": appID,
+		},
+	)
+
+	channelInfo, err := cuc.repo.GetChannel(ctx, chain, appID, false)
+	if err != nil {
+		log.WithError(err).Error("failed to get channel")
+		return
+	}
+
+	channelData := channelInfo.Data.(entities.ChannelModel)
+	if channelData.Owner == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("Channel verification %s", decision)
+	message := fmt.Sprintf("The verification of your channel %s was %s", channelData.Name, decision)
+	if reason != "" {
+		message = fmt.Sprintf("%s: %s", message, reason)
+	}
+
+	notifyAccount(ctx, cuc.userRepo, chain, channelData.Owner, appID, channelData.Name, subject, message)
+}
@@ -18,6 +18,7 @@ import (
 	"notiboy/pkg/repo"
 	"notiboy/pkg/repo/driver/db"
 	"notiboy/pkg/repo/driver/medium"
+	"notiboy/utilities"
 )
 
 type ChatUseCases struct {
@@ -65,16 +66,56 @@ func (c *ChatUseCases) GetGroupChatByGroup(ctx context.Context, chain, user, gid
 	return c.repo.GetGroupChatByGroup(ctx, chain, user, gid, pageSize, pageState)
 }
 
+func (c *ChatUseCases) GetContactProfiles(
+	ctx context.Context, chain, originUser, lookupAddr string,
+) (map[string]*entities.UserProfile, error) {
+	return c.repo.GetContactProfiles(ctx, chain, originUser, lookupAddr)
+}
+
 func (c *ChatUseCases) GetPersonalChat(
 	ctx context.Context, chain, user string, from, until int64,
-) ([]*entities.UserChat, error) {
-	return c.repo.GetPersonalChat(ctx, chain, user, from, until)
+) ([]*entities.UserChat, map[string]*entities.UserProfile, error) {
+	chats, err := c.repo.GetPersonalChat(ctx, chain, user, from, until)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profiles, err := chatProfiles(ctx, chain, user, chats)
+
+	return chats, profiles, err
 }
 
 func (c *ChatUseCases) GetPersonalChatByUser(
 	ctx context.Context, chain, user, receiver string, numPageSize int, currPageState []byte,
-) ([]*entities.UserChat, []byte, error) {
-	return c.repo.GetPersonalChatByUser(ctx, chain, user, receiver, numPageSize, currPageState)
+) ([]*entities.UserChat, map[string]*entities.UserProfile, []byte, error) {
+	chats, nextPageState, err := c.repo.GetPersonalChatByUser(ctx, chain, user, receiver, numPageSize, currPageState)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	profiles, err := chatProfiles(ctx, chain, user, chats)
+
+	return chats, profiles, nextPageState, err
+}
+
+// chatProfiles retrieves the profiles of the other users of the chats, as user gets to see them, keyed by address.
+// Every user is looked up once, however many chats it has.
+func chatProfiles(
+	ctx context.Context, chain, user string, chats []*entities.UserChat,
+) (map[string]*entities.UserProfile, error) {
+	return db.GetUserProfiles(ctx, chain, user, chatParticipants(chats))
+}
+
+// chatParticipants returns the other users of the chats, once each
+func chatParticipants(chats []*entities.UserChat) []string {
+	var others []string
+	for _, chat := range chats {
+		if !utilities.ContainsString(others, chat.UserB) {
+			others = append(others, chat.UserB)
+		}
+	}
+
+	return others
 }
 
 func (c *ChatUseCases) BlockUser(ctx context.Context, chain, user, blockedUser string) (*entities.Response, error) {
@@ -365,15 +406,18 @@ func (c *ChatUseCases) ChatProcessor(ctx context.Context) {
 }
 
 type ChatUseCaseImply interface {
-	GetPersonalChat(ctx context.Context, chain, user string, from, until int64) ([]*entities.UserChat, error)
+	GetPersonalChat(ctx context.Context, chain, user string, from, until int64) (
+		[]*entities.UserChat, map[string]*entities.UserProfile, error,
+	)
 	GetPersonalChatByUser(
 		ctx context.Context, chain, user, receiver string, numPageSize int, currPageState []byte,
-	) ([]*entities.UserChat, []byte, error)
+	) ([]*entities.UserChat, map[string]*entities.UserProfile, []byte, error)
 	BlockUser(ctx context.Context, chain, user, blockedUser string) (*entities.Response, error)
 	UnblockUser(ctx context.Context, chain, user, blockedUser string) (*entities.Response, error)
 	IsBlockedUser(ctx context.Context, chain, user, blockedUser string) (bool, error)
 
 	GetDNSContactsList(ctx context.Context, chain, originUser, lookupAddr string) (map[string]string, error)
+	GetContactProfiles(ctx context.Context, chain, originUser, lookupAddr string) (map[string]*entities.UserProfile, error)
 
 	StoreGroupInfo(ctx context.Context, data *entities.GroupChatInfo) (
 		*entities.Response,
@@ -0,0 +1,24 @@
+package usecases
+
+import (
+	"reflect"
+	"testing"
+
+	"notiboy/pkg/entities"
+)
+
+func Test_chatParticipants(t *testing.T) {
+	chats := []*entities.UserChat{
+		{UserA: "alice", UserB: "bob", Message: "hi"},
+		{UserA: "alice", UserB: "bob", Message: "there"},
+		{UserA: "alice", UserB: "carol", Message: "hey"},
+		{UserA: "alice", UserB: "bob", Message: "again"},
+	}
+
+	if got, want := chatParticipants(chats), []string{"bob", "carol"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chatParticipants() = %v, want %v", got, want)
+	}
+	if got := chatParticipants(nil); len(got) != 0 {
+		t.Errorf("chatParticipants(nil) = %v, want none", got)
+	}
+}
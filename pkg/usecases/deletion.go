@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+)
+
+// deletionLease is how long an instance holds a deletion job for running one step of it
+const deletionLease = 10 * time.Minute
+
+// AccountDeletionWorker periodically picks up the pending account deletions and runs their remaining steps.
+// A job is claimed before each step, so that several instances never work on it at once and a job left
+// behind by a crashed instance is resumed once its lease runs out.
+func AccountDeletionWorker(ctx context.Context, deletionRepo repo.DeletionRepoImply) {
+	log := utilities.NewLogger("AccountDeletionWorker")
+
+	ticker := time.NewTicker(cast.ToDuration(config.GetConfig().AccountDeletionInterval))
+
+	go func() {
+		runOnce := make(chan struct{}, 1)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("Terminating...")
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				select {
+				// at any point of time, no more than one go routine should run
+				case runOnce <- struct{}{}:
+					go runAccountDeletions(ctx, deletionRepo, runOnce)
+				default:
+				}
+			}
+		}
+	}()
+}
+
+func runAccountDeletions(ctx context.Context, deletionRepo repo.DeletionRepoImply, runOnce chan struct{}) {
+	log := utilities.NewLogger("runAccountDeletions")
+
+	defer func() {
+		<-runOnce
+	}()
+
+	jobs, err := deletionRepo.ListPendingDeletionJobs(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to list pending account deletions")
+		return
+	}
+
+	for i := range jobs {
+		runAccountDeletion(ctx, deletionRepo, &jobs[i])
+	}
+}
+
+// runAccountDeletion runs the remaining steps of job. A failed step is recorded on the job and retried
+// once the lease on it runs out.
+func runAccountDeletion(ctx context.Context, deletionRepo repo.DeletionRepoImply, job *entities.AccountDeletion) {
+	log := utilities.NewLoggerWithFields(
+		"runAccountDeletion", map[string]interface{}{
+			"id":      job.ID,
+			"chain":   job.Chain,
+			"address": job.Address,
+		},
+	)
+
+	if job.LeaseUntil.After(utilities.TimeNow()) {
+		// another instance is working on it, or a failed step waits for the lease to run out before a retry
+		return
+	}
+
+	for job.StepsDone < len(consts.AccountDeletionSteps) {
+		// the lease is renewed for every step, the job is given up as soon as another instance took it over
+		claimed, err := deletionRepo.ClaimDeletionJob(ctx, job, utilities.TimeNow().Add(deletionLease))
+		if err != nil {
+			log.WithError(err).Error("failed to claim account deletion")
+			return
+		}
+		if !claimed {
+			return
+		}
+
+		step := consts.AccountDeletionSteps[job.StepsDone]
+		job.Error = ""
+		if err = deletionRepo.RunDeletionStep(ctx, job.UserIdentifier, step); err != nil {
+			log.WithError(err).Errorf("failed to run deletion step %s", step)
+			job.Error = err.Error()
+		} else {
+			job.StepsDone++
+		}
+
+		if job.StepsDone == len(consts.AccountDeletionSteps) {
+			job.Status = consts.DeletionCompleted
+		}
+		job.Updated = utilities.TimeNow()
+		if err = deletionRepo.UpdateDeletionJob(ctx, job); err != nil {
+			log.WithError(err).Error("failed to update account deletion")
+			return
+		}
+
+		if job.Error != "" {
+			return
+		}
+	}
+
+	log.Info("account deleted")
+}
+
+// withDeletionProgress fills in the progress of job derived from the steps it completed
+func withDeletionProgress(job *entities.AccountDeletion) *entities.AccountDeletion {
+	job.StepsTotal = len(consts.AccountDeletionSteps)
+	if job.StepsDone < job.StepsTotal {
+		job.CurrentStep = consts.AccountDeletionSteps[job.StepsDone]
+	}
+
+	return job
+}
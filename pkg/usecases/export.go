@@ -0,0 +1,280 @@
+package usecases
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+)
+
+type ExportUseCases struct {
+	repo repo.ExportRepoImply
+}
+
+type ExportUseCaseImply interface {
+	ExportAccount(ctx context.Context, user entities.UserIdentifier, format string) (*entities.AccountArchive, error)
+}
+
+// NewExportUseCases
+func NewExportUseCases(exportRepo repo.ExportRepoImply) ExportUseCaseImply {
+	return &ExportUseCases{
+		repo: exportRepo,
+	}
+}
+
+// ExportAccount prepares a downloadable archive of everything stored for the account of the user, a single
+// JSON document or a ZIP with a JSON file per kind of data. The archive is streamed as it is read.
+func (euc *ExportUseCases) ExportAccount(
+	ctx context.Context, user entities.UserIdentifier, format string,
+) (*entities.AccountArchive, error) {
+	if format != consts.ExportJSON && format != consts.ExportZIP {
+		return nil, fmt.Errorf("unsupported export format %s", format)
+	}
+
+	name := fmt.Sprintf("notiboy-%s-%s-%s", user.Chain, user.Address, utilities.TimeNow().Format("20060102"))
+
+	archive := &entities.AccountArchive{
+		Name:        name + ".json",
+		ContentType: "application/json",
+	}
+	if format == consts.ExportZIP {
+		archive.Name, archive.ContentType = name+".zip", "application/zip"
+	}
+
+	archive.Write = func(w io.Writer) error {
+		export := newExportArchive(format, w)
+		if err := euc.repo.ExportAccount(ctx, user, export); err != nil {
+			return err
+		}
+
+		return export.Close()
+	}
+
+	return archive, nil
+}
+
+// exportArchive is an export writer that has to be closed once every section is written
+type exportArchive interface {
+	repo.ExportWriter
+	Close() error
+}
+
+func newExportArchive(format string, w io.Writer) exportArchive {
+	if format == consts.ExportZIP {
+		return &zipExport{archive: zip.NewWriter(w)}
+	}
+
+	return &jsonExport{w: w}
+}
+
+// jsonList writes the items of a JSON array as they come
+type jsonList struct {
+	w      io.Writer
+	indent string
+	items  int
+}
+
+func (l *jsonList) item(v interface{}) error {
+	data, err := json.MarshalIndent(v, l.indent+"  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export: %w", err)
+	}
+
+	sep := ",\n"
+	if l.items == 0 {
+		sep = "\n"
+	}
+	l.items++
+
+	_, err = fmt.Fprintf(l.w, "%s%s  %s", sep, l.indent, data)
+
+	return err
+}
+
+func (l *jsonList) close() error {
+	var err error
+	if l.items == 0 {
+		_, err = io.WriteString(l.w, "]")
+	} else {
+		_, err = fmt.Fprintf(l.w, "\n%s]", l.indent)
+	}
+
+	return err
+}
+
+// jsonExport writes the export as a single JSON document, an object holding every section
+type jsonExport struct {
+	w        io.Writer
+	sections int
+	list     *jsonList
+}
+
+func (e *jsonExport) section(name string) error {
+	if err := e.endList(); err != nil {
+		return err
+	}
+
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if e.sections == 0 {
+		sep = "{\n"
+	}
+	e.sections++
+
+	_, err = fmt.Fprintf(e.w, "%s  %s: ", sep, key)
+
+	return err
+}
+
+func (e *jsonExport) Value(name string, v interface{}) error {
+	if err := e.section(name); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+func (e *jsonExport) List(name string) error {
+	if err := e.section(name); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(e.w, "["); err != nil {
+		return err
+	}
+	e.list = &jsonList{w: e.w, indent: "  "}
+
+	return nil
+}
+
+func (e *jsonExport) Item(v interface{}) error {
+	if e.list == nil {
+		return fmt.Errorf("export item outside of a list")
+	}
+
+	return e.list.item(v)
+}
+
+func (e *jsonExport) endList() error {
+	if e.list == nil {
+		return nil
+	}
+
+	list := e.list
+	e.list = nil
+
+	return list.close()
+}
+
+func (e *jsonExport) Close() error {
+	if err := e.endList(); err != nil {
+		return err
+	}
+
+	end := "\n}\n"
+	if e.sections == 0 {
+		end = "{}\n"
+	}
+	_, err := io.WriteString(e.w, end)
+
+	return err
+}
+
+// zipExport writes every section of the export to a JSON file of its own
+type zipExport struct {
+	archive *zip.Writer
+	list    *jsonList
+}
+
+func (e *zipExport) section(name string) (io.Writer, error) {
+	if err := e.endList(); err != nil {
+		return nil, err
+	}
+
+	file, err := e.archive.Create(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	return file, nil
+}
+
+func (e *zipExport) Value(name string, v interface{}) error {
+	file, err := e.section(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	if _, err = file.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+
+	return nil
+}
+
+func (e *zipExport) List(name string) error {
+	file, err := e.section(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(file, "["); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	e.list = &jsonList{w: file}
+
+	return nil
+}
+
+func (e *zipExport) Item(v interface{}) error {
+	if e.list == nil {
+		return fmt.Errorf("export item outside of a list")
+	}
+
+	return e.list.item(v)
+}
+
+func (e *zipExport) endList() error {
+	if e.list == nil {
+		return nil
+	}
+
+	list := e.list
+	e.list = nil
+
+	return list.close()
+}
+
+func (e *zipExport) Close() error {
+	if err := e.endList(); err != nil {
+		return err
+	}
+
+	if err := e.archive.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+// fakeExportRepo writes an account, a list with two items and an empty list
+type fakeExportRepo struct {
+	repo.ExportRepoImply
+
+	err error
+}
+
+func (f *fakeExportRepo) ExportAccount(_ context.Context, user entities.UserIdentifier, w repo.ExportWriter) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if err := w.Value("account", user); err != nil {
+		return err
+	}
+	if err := w.List("inbox"); err != nil {
+		return err
+	}
+	for _, uuid := range []string{"a", "b"} {
+		if err := w.Item(map[string]string{"uuid": uuid}); err != nil {
+			return err
+		}
+	}
+	if err := w.List("fcm_devices"); err != nil {
+		return err
+	}
+
+	return w.Value("contacts", []string{"friend"})
+}
+
+func TestExportUseCases_ExportAccount(t *testing.T) {
+	user := entities.UserIdentifier{Chain: consts.Algorand, Address: "ADDRESS"}
+
+	want := map[string]interface{}{
+		"account":     map[string]interface{}{"chain": consts.Algorand, "address": "ADDRESS"},
+		"inbox":       []interface{}{map[string]interface{}{"uuid": "a"}, map[string]interface{}{"uuid": "b"}},
+		"fcm_devices": []interface{}{},
+		"contacts":    []interface{}{"friend"},
+	}
+
+	// sections reads the sections of an archive
+	sections := func(t *testing.T, format string, data []byte) map[string]interface{} {
+		t.Helper()
+
+		got := make(map[string]interface{})
+		if format == consts.ExportJSON {
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("invalid json export %s: %v", data, err)
+			}
+			return got
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("invalid zip export: %v", err)
+		}
+		for _, file := range archive.File {
+			r, err := file.Open()
+			if err != nil {
+				t.Fatalf("failed to open %s: %v", file.Name, err)
+			}
+			raw, _ := io.ReadAll(r)
+			r.Close()
+
+			var section interface{}
+			if err = json.Unmarshal(raw, &section); err != nil {
+				t.Fatalf("invalid json in %s %s: %v", file.Name, raw, err)
+			}
+			got[strings.TrimSuffix(file.Name, ".json")] = section
+		}
+		return got
+	}
+
+	tests := []struct {
+		name            string
+		format          string
+		wantContentType string
+		wantExt         string
+	}{
+		{
+			name:            "json document",
+			format:          consts.ExportJSON,
+			wantContentType: "application/json",
+			wantExt:         ".json",
+		},
+		{
+			name:            "zip of sections",
+			format:          consts.ExportZIP,
+			wantContentType: "application/zip",
+			wantExt:         ".zip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				usecase := NewExportUseCases(&fakeExportRepo{})
+
+				archive, err := usecase.ExportAccount(context.Background(), user, tt.format)
+				if err != nil {
+					t.Fatalf("ExportAccount() error = %v", err)
+				}
+				if archive.ContentType != tt.wantContentType || !strings.HasSuffix(archive.Name, tt.wantExt) {
+					t.Errorf("archive %s of %s, want %s", archive.Name, archive.ContentType, tt.wantContentType)
+				}
+
+				var buf bytes.Buffer
+				if err = archive.Write(&buf); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+
+				got := sections(t, tt.format, buf.Bytes())
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("sections = %v, want %v", got, want)
+				}
+			},
+		)
+	}
+}
+
+func TestExportUseCases_ExportAccount_errors(t *testing.T) {
+	user := entities.UserIdentifier{Chain: consts.Algorand, Address: "ADDRESS"}
+
+	if _, err := NewExportUseCases(&fakeExportRepo{}).ExportAccount(context.Background(), user, "csv"); err == nil {
+		t.Error("ExportAccount() of an unsupported format succeeded")
+	}
+
+	failed := errors.New("failed")
+	archive, err := NewExportUseCases(&fakeExportRepo{err: failed}).ExportAccount(
+		context.Background(), user, consts.ExportJSON,
+	)
+	if err != nil {
+		t.Fatalf("ExportAccount() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = archive.Write(&buf); !errors.Is(err, failed) {
+		t.Errorf("Write() error = %v, want %v", err, failed)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Write() wrote %q before failing", buf.String())
+	}
+}
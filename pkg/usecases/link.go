@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	chainDriver "notiboy/pkg/repo/driver/chain"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type LinkUseCases struct {
+	repo     repo.LinkRepoImply
+	userRepo repo.UserRepoImply
+	useCases UseCaseImply
+}
+
+type LinkUseCaseImply interface {
+	LinkAccount(ctx context.Context, user entities.UserIdentifier, request entities.LinkAccountRequest) (*entities.LinkedAccount, error)
+	ListLinkedAccounts(ctx context.Context, user entities.UserIdentifier) ([]entities.LinkedAccount, error)
+	UnlinkAccount(ctx context.Context, user entities.UserIdentifier, linkedChain string) error
+}
+
+// NewLinkUseCases
+func NewLinkUseCases(linkRepo repo.LinkRepoImply, userRepo repo.UserRepoImply, useCases UseCaseImply) LinkUseCaseImply {
+	return &LinkUseCases{
+		repo:     linkRepo,
+		userRepo: userRepo,
+		useCases: useCases,
+	}
+}
+
+// LinkAccount links the account of the user to its onboarded account on another chain. The wallet of that
+// account proves its control by signing a login challenge issued for it. Once linked, the accounts share
+// their verified mediums and inbox, with shared billing the balance of the user pays for both.
+func (luc *LinkUseCases) LinkAccount(
+	ctx context.Context, user entities.UserIdentifier, request entities.LinkAccountRequest,
+) (*entities.LinkedAccount, error) {
+	log := utilities.NewLogger("LinkAccount")
+
+	linked := entities.UserIdentifier{
+		Chain:   strings.ToLower(request.Chain),
+		Address: request.Address,
+	}
+
+	if !chainDriver.IsChainSupported(linked.Chain) {
+		return nil, fmt.Errorf("unsupported chain %s", request.Chain)
+	}
+	if linked.Chain == user.Chain {
+		return nil, errors.New("only accounts on different chains can be linked")
+	}
+
+	err := luc.useCases.VerifyLoginChallenge(
+		ctx, linked.Chain, linked.Address, request.Nonce, request.Signature, request.PublicKey,
+	)
+	if err != nil {
+		log.WithError(err).Errorf("challenge verification failed for %s", linked.Address)
+		return nil, errors.New("failed to verify the signature of the linked account")
+	}
+
+	onboarded, err := luc.userRepo.IsUserOnboarded(ctx, linked.Chain, linked.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !onboarded {
+		return nil, fmt.Errorf("account %s is not onboarded", linked.Address)
+	}
+
+	for _, account := range []entities.UserIdentifier{user, linked} {
+		links, err := luc.repo.ListLinkedAccounts(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		if len(links) > 0 {
+			return nil, fmt.Errorf("account %s is already linked", account.Address)
+		}
+	}
+
+	link, err := luc.repo.LinkAccounts(ctx, user, linked, request.SharedBilling)
+	if err != nil {
+		return nil, err
+	}
+
+	// mediums verified on either account are shared both ways
+	if err = db.ShareVerifiedMediums(ctx, user, linked); err != nil {
+		log.WithError(err).Error("failed to share verified mediums")
+	}
+	if err = db.ShareVerifiedMediums(ctx, linked, user); err != nil {
+		log.WithError(err).Error("failed to share verified mediums")
+	}
+
+	return link, nil
+}
+
+// ListLinkedAccounts retrieves the accounts linked to the account of the user.
+func (luc *LinkUseCases) ListLinkedAccounts(
+	ctx context.Context, user entities.UserIdentifier,
+) ([]entities.LinkedAccount, error) {
+	return luc.repo.ListLinkedAccounts(ctx, user)
+}
+
+// UnlinkAccount removes the link to the account of the user on linkedChain. Shared mediums stay verified
+// on both accounts, the inboxes and billing are separate again.
+func (luc *LinkUseCases) UnlinkAccount(ctx context.Context, user entities.UserIdentifier, linkedChain string) error {
+	return luc.repo.UnlinkAccount(ctx, user, strings.ToLower(linkedChain))
+}
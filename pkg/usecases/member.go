@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
+)
+
+type ChannelMemberUseCases struct {
+	repo     repo.ChannelMemberRepoImply
+	userRepo repo.UserRepoImply
+}
+
+type ChannelMemberUseCaseImply interface {
+	InviteMember(ctx context.Context, chain, appID, user string, request entities.ChannelMemberRequest) (*entities.ChannelMember, error)
+	AcceptInvite(ctx context.Context, chain, appID, user string) error
+	UpdateMemberRole(ctx context.Context, chain, appID, user, address, role string) error
+	RemoveMember(ctx context.Context, chain, appID, user, address string) error
+	ListMembers(ctx context.Context, chain, appID, user string) ([]entities.ChannelMember, error)
+	ListUserChannelRoles(ctx context.Context, chain, user string) ([]entities.ChannelMember, error)
+}
+
+// NewChannelMemberUseCases
+func NewChannelMemberUseCases(
+	memberRepo repo.ChannelMemberRepoImply, userRepo repo.UserRepoImply,
+) ChannelMemberUseCaseImply {
+	return &ChannelMemberUseCases{
+		repo:     memberRepo,
+		userRepo: userRepo,
+	}
+}
+
+// InviteMember invites a user to share the management of the channel with the given role, it gets the role
+// once it accepts the invite.
+func (muc *ChannelMemberUseCases) InviteMember(
+	ctx context.Context, chain, appID, user string, request entities.ChannelMemberRequest,
+) (*entities.ChannelMember, error) {
+	if err := verifyChannelPermission(ctx, muc.repo, chain, appID, user, consts.ChannelPermManageMembers); err != nil {
+		return nil, err
+	}
+
+	if err := validateMemberRole(request.Role); err != nil {
+		return nil, err
+	}
+
+	onboarded, err := muc.userRepo.IsUserOnboarded(ctx, chain, request.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !onboarded {
+		return nil, fmt.Errorf("user %s is not onboarded", request.Address)
+	}
+
+	role, err := muc.repo.GetChannelRole(ctx, chain, appID, request.Address)
+	if err != nil {
+		return nil, err
+	}
+	if role == consts.ChannelRoleOwner {
+		return nil, errors.New("the owner of the channel can't be invited to it")
+	}
+
+	now := utilities.TimeNow()
+	member := &entities.ChannelMember{
+		Chain:     chain,
+		AppID:     appID,
+		Address:   request.Address,
+		Role:      request.Role,
+		Status:    consts.MemberInvited,
+		InvitedBy: user,
+		Created:   now,
+		Updated:   now,
+	}
+
+	if err = muc.repo.InviteMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// validateMemberRole returns an error unless role can be given to a member. A channel has a single owner, it
+// is handed over by transferring the channel.
+func validateMemberRole(role string) error {
+	if role == consts.ChannelRoleOwner {
+		return errors.New("the owner role can't be given to a member, transfer the channel instead")
+	}
+	if _, ok := consts.ChannelRolePermissions[role]; !ok {
+		return fmt.Errorf("unknown role %s", role)
+	}
+
+	return nil
+}
+
+// AcceptInvite makes the user a member of the channel it was invited to.
+func (muc *ChannelMemberUseCases) AcceptInvite(ctx context.Context, chain, appID, user string) error {
+	return muc.repo.AcceptInvite(ctx, chain, appID, user)
+}
+
+// UpdateMemberRole changes the role of a member of the channel.
+func (muc *ChannelMemberUseCases) UpdateMemberRole(ctx context.Context, chain, appID, user, address, role string) error {
+	if err := verifyChannelPermission(ctx, muc.repo, chain, appID, user, consts.ChannelPermManageMembers); err != nil {
+		return err
+	}
+
+	if err := validateMemberRole(role); err != nil {
+		return err
+	}
+
+	return muc.repo.UpdateMemberRole(ctx, chain, appID, address, role)
+}
+
+// RemoveMember removes a member of the channel or withdraws an invite. Members can leave, and invited users
+// decline, on their own.
+func (muc *ChannelMemberUseCases) RemoveMember(ctx context.Context, chain, appID, user, address string) error {
+	if user != address {
+		if err := verifyChannelPermission(ctx, muc.repo, chain, appID, user, consts.ChannelPermManageMembers); err != nil {
+			return err
+		}
+	}
+
+	return muc.repo.RemoveMember(ctx, chain, appID, address)
+}
+
+// ListMembers retrieves the members of the channel, they are visible to every member.
+func (muc *ChannelMemberUseCases) ListMembers(ctx context.Context, chain, appID, user string) ([]entities.ChannelMember, error) {
+	role, err := muc.repo.GetChannelRole(ctx, chain, appID, user)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, fmt.Errorf("user %s is not a member of channel %s", user, appID)
+	}
+
+	return muc.repo.ListMembers(ctx, chain, appID)
+}
+
+// ListUserChannelRoles retrieves the channels the user is a member of, along with its pending invites.
+func (muc *ChannelMemberUseCases) ListUserChannelRoles(ctx context.Context, chain, user string) ([]entities.ChannelMember, error) {
+	return muc.repo.ListUserChannelRoles(ctx, chain, user)
+}
+
+// verifyChannelPermission returns an error unless the role of address in the channel grants permission
+func verifyChannelPermission(
+	ctx context.Context, members repo.ChannelMemberRepoImply, chain, appID, address, permission string,
+) error {
+	role, err := members.GetChannelRole(ctx, chain, appID, address)
+	if err != nil {
+		return err
+	}
+
+	return db.CheckChannelPermission(role, appID, address, permission)
+}
+
+// channelOwnerModel returns the user model of the owner of the channel, its membership applies to whatever
+// the members of the channel do
+func channelOwnerModel(
+	ctx context.Context, members repo.ChannelMemberRepoImply, userRepo repo.UserRepoImply, chain, appID string,
+) (*entities.UserModel, error) {
+	owner, err := members.GetChannelOwner(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	userModel, err := userRepo.GetUserModel(ctx, chain, owner)
+	if err != nil {
+		return nil, fmt.Errorf("getting user model failed: %w", err)
+	}
+
+	return userModel, nil
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+// memberRoles records the roles given to the members
+type memberRoles struct {
+	channelMembers
+	updated map[string]string
+}
+
+func (m memberRoles) UpdateMemberRole(_ context.Context, _, _, address, role string) error {
+	m.updated[address] = role
+	return nil
+}
+
+func TestChannelMemberUseCases_UpdateMemberRole(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{"owner": consts.ChannelRoleOwner, "admin": consts.ChannelRoleAdmin},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		role    string
+		wantErr bool
+	}{
+		{
+			name: "role given by the owner",
+			user: "owner",
+			role: consts.ChannelRoleSender,
+		},
+		{
+			name:    "role given by a member who can't manage members",
+			user:    "admin",
+			role:    consts.ChannelRoleSender,
+			wantErr: true,
+		},
+		{
+			name:    "owner role",
+			user:    "owner",
+			role:    consts.ChannelRoleOwner,
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			user:    "owner",
+			role:    "moderator",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				roles := make(map[string]string)
+				muc := &ChannelMemberUseCases{repo: memberRoles{channelMembers: members, updated: roles}}
+
+				err := muc.UpdateMemberRole(context.Background(), consts.Algorand, "1", tt.user, "member", tt.role)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("UpdateMemberRole() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && roles["member"] != tt.role {
+					t.Errorf("role of member = %q, want %q", roles["member"], tt.role)
+				}
+				if tt.wantErr && len(roles) != 0 {
+					t.Errorf("roles = %v, want none changed", roles)
+				}
+			},
+		)
+	}
+}
+
+func TestChannelMemberUseCases_InviteMember_owner(t *testing.T) {
+	muc := &ChannelMemberUseCases{
+		repo:     channelMembers{roles: map[string]string{"owner": consts.ChannelRoleOwner}},
+		userRepo: fakeUserRepo{},
+	}
+	_, err := muc.InviteMember(
+		context.Background(), consts.Algorand, "1", "owner",
+		entities.ChannelMemberRequest{Address: "member", Role: consts.ChannelRoleOwner},
+	)
+	if err == nil {
+		t.Error("InviteMember() with the owner role succeeded, want an error")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"notiboy/pkg/repo/driver/db"
 	"notiboy/pkg/repo/driver/medium"
 	"notiboy/utilities"
+	"notiboy/utilities/tracking"
 
 	uuidLib "github.com/google/uuid"
 )
@@ -28,6 +29,7 @@ type NotificationUsecases struct {
 	verify   repo.VerifyRepoImply
 	channel  repo.ChannelRepoImpl
 	optin    repo.OptinRepoImply
+	members  repo.ChannelMemberRepoImply
 	ws       *medium.Socket
 }
 
@@ -61,13 +63,19 @@ type NotificationUsecaseImply interface {
 	GetScheduledNotificationsBySender(context.Context, string, string) ([]entities.NotificationRequest, error)
 	DeleteScheduledNotificationInfo(context.Context, string, string, time.Time) error
 	UpdateScheduledNotificationInfo(context.Context, *entities.ScheduleNotificationRequest) error
-	NotificationReachCount(context.Context, string, int, []byte) ([]entities.NotificationReach, []byte, error)
+	GetNotificationReach(context.Context, string, string, string, string) (*entities.NotificationReach, error)
+	TrackClick(context.Context, string) (string, error)
+	TrackOpen(context.Context, string) error
+	RecallNotification(context.Context, string, string, string, string) error
+	EditNotification(context.Context, entities.EditNotificationRequest) error
+	GetNotificationRevisions(context.Context, string, string, string, string) ([]entities.NotificationRevision, error)
 }
 
 // NewNotificationUsecases creates a new instance of the NotificationUsecases struct
 func NewNotificationUsecases(
 	notificationRepo repo.NotificationRepoImply, userRepo repo.UserRepoImply, verify repo.VerifyRepoImply,
-	channel repo.ChannelRepoImpl, optin repo.OptinRepoImply, ws *medium.Socket,
+	channel repo.ChannelRepoImpl, optin repo.OptinRepoImply, members repo.ChannelMemberRepoImply,
+	ws *medium.Socket,
 ) NotificationUsecaseImply {
 	nuc = &NotificationUsecases{
 		repo:     notificationRepo,
@@ -75,6 +83,7 @@ func NewNotificationUsecases(
 		verify:   verify,
 		channel:  channel,
 		optin:    optin,
+		members:  members,
 		ws:       ws,
 	}
 
@@ -190,7 +199,21 @@ func (usecase *NotificationUsecases) SendNotifications(
 	message := request.Message
 	schedule := request.Schedule
 
-	userModel, err := db.GetUserModel(ctx, chain, sender)
+	if err := verifyChannelPermission(ctx, usecase.members, chain, channel, sender, consts.ChannelPermSend); err != nil {
+		log.WithError(err).Warnf("Sender %s may not send from the channel", sender)
+		return err
+	}
+
+	channelInfo, err := usecase.channel.GetChannel(ctx, chain, channel, true)
+	if err != nil {
+		log.WithError(err).Error("failed to get channel")
+		return err
+	}
+
+	channelData := channelInfo.Data.(entities.ChannelModel)
+
+	// the membership of the owner applies, whoever of the members sends
+	userModel, err := usecase.userRepo.GetUserModel(ctx, chain, channelData.Owner)
 	if err != nil {
 		return fmt.Errorf("getting user model failed: %w", err)
 	}
@@ -233,32 +256,10 @@ func (usecase *NotificationUsecases) SendNotifications(
 	uuid := uuidLib.NewString()
 	now := utilities.TimeNow()
 
-	permittedCharCount := consts.NotificationCharacterCount[membership]
-	curMsgCharCount := len(message)
-	curLinkCharCount := len(link)
-
-	if curMsgCharCount > permittedCharCount {
-		return fmt.Errorf(
-			"cannot send notification as your membership allows only %d notification text character count",
-			permittedCharCount,
-		)
-	}
-
-	if curLinkCharCount > permittedCharCount {
-		return fmt.Errorf(
-			"cannot send notification as your membership allows only %d notification link character count",
-			permittedCharCount,
-		)
-	}
-
-	channelInfo, err := usecase.channel.GetChannel(ctx, chain, channel, true)
-	if err != nil {
-		log.WithError(err).Error("failed to get channel")
+	if err = validateCharacterCount(membership, message, link); err != nil {
 		return err
 	}
 
-	channelData := channelInfo.Data.(entities.ChannelModel)
-
 	if channelData.Status == consts.STATUS_CHANNEL_LIMIT_EXCEEDED {
 		msg := fmt.Sprintf("cannot send notification - limit exceeded. Delete a channel or upgrade membership to continue")
 		log.Warnf(msg)
@@ -271,11 +272,6 @@ func (usecase *NotificationUsecases) SendNotifications(
 		return fmt.Errorf(msg)
 	}
 
-	if sender != channelData.Owner {
-		log.Warnf("Sender %s is not the owner %s of the channel", sender, channelData.Owner)
-		return fmt.Errorf("sender is not the owner of the channel")
-	}
-
 	if kind == "public" {
 		request.Receivers, err = usecase.channel.RetrieveChannelUsers(ctx, chain, channel)
 		if err != nil {
@@ -289,7 +285,7 @@ func (usecase *NotificationUsecases) SendNotifications(
 		return nil
 	}
 
-	totalSent, err := usecase.userRepo.GetUserSendMetricsForMonth(ctx, chain, sender)
+	totalSent, err := usecase.userRepo.GetUserSendMetricsForMonth(ctx, chain, channelData.Owner)
 	if err != nil {
 		log.WithError(err).Errorf("failed to get user send metrics")
 		return err
@@ -306,6 +302,7 @@ func (usecase *NotificationUsecases) SendNotifications(
 	}
 
 	sent := 0
+	sentReceivers := make([]string, 0, toSend)
 	for _, receiver := range request.Receivers {
 		receiverInfo, err := usecase.userRepo.GetUser(
 			ctx, entities.UserIdentifier{
@@ -368,11 +365,31 @@ func (usecase *NotificationUsecases) SendNotifications(
 
 		go func(notification *entities.Notification) {
 			identifier := medium.FormatIdentifier(chain, notification.Receiver)
+			reach := entities.NotificationReachEvent{
+				Chain:    chain,
+				Channel:  notification.Channel,
+				UUID:     notification.UUID,
+				Medium:   consts.Inapp,
+				Event:    consts.ReachSent,
+				Receiver: notification.Receiver,
+			}
+			if _, err := usecase.repo.InsertNotificationReach(ctx, reach); err != nil {
+				log.WithError(err).Error("failed to insert notification reach")
+			}
+			reach.Event = consts.ReachDelivered
 
 			n := entities.ReadNotification{
-				Message:     notification.Message,
-				Seen:        false,
-				Link:        notification.Link,
+				Message: notification.Message,
+				Seen:    false,
+				Link: tracking.TrackedLink(
+					notification.Link, tracking.Claims{
+						Chain:    chain,
+						AppID:    notification.Channel,
+						UUID:     notification.UUID,
+						Receiver: notification.Receiver,
+						Medium:   consts.Inapp,
+					}, notification.ReceiverInfo.TrackingOptOut,
+				),
 				CreatedTime: notification.CreatedTime,
 				AppID:       notification.Channel,
 				ChannelName: notification.ChannelName,
@@ -388,6 +405,8 @@ func (usecase *NotificationUsecases) SendNotifications(
 			} else {
 				if err = usecase.ws.PushMessage(identifier, data, false); err != nil {
 					log.WithError(err).Error("failed to push websocket notification")
+				} else if _, err = usecase.repo.InsertNotificationReach(ctx, reach); err != nil {
+					log.WithError(err).Error("failed to insert notification reach")
 				}
 			}
 
@@ -399,7 +418,15 @@ func (usecase *NotificationUsecases) SendNotifications(
 				Data: map[string]string{
 					"click_action": "FLUTTER_NOTIFICATION_CLICK",
 					"seen":         "false",
-					"link":         notification.Link,
+					"link": tracking.TrackedLink(
+						notification.Link, tracking.Claims{
+							Chain:    chain,
+							AppID:    notification.Channel,
+							UUID:     notification.UUID,
+							Receiver: notification.Receiver,
+							Medium:   consts.Push,
+						}, notification.ReceiverInfo.TrackingOptOut,
+					),
 					"created_time": notification.CreatedTime.Format("2006-01-02T15:04:05Z"),
 					"app_id":       notification.Channel,
 					"channel_name": notification.ChannelName,
@@ -412,15 +439,31 @@ func (usecase *NotificationUsecases) SendNotifications(
 			tokens, err := usecase.userRepo.GetFCMTokens(
 				ctx, entities.UserIdentifier{
 					Chain:   chain,
-					Address: receiver,
+					Address: notification.Receiver,
 				},
 			)
 			if err != nil {
 				log.WithError(err).Error("failed to get fcm tokens")
 			}
 			if len(tokens) > 0 {
+				reach.Medium = consts.Push
+				reach.Event = consts.ReachSent
+				if _, err = usecase.repo.InsertNotificationReach(ctx, reach); err != nil {
+					log.WithError(err).Error("failed to insert notification reach")
+				}
+
+				reach.Event = consts.ReachDelivered
 				if err = medium.GetFirebaseClient().PushMessageToClient(ctx, notification.Chain, notification.Receiver, msg, tokens); err != nil {
 					log.WithError(err).Errorf("failed to push notification")
+					medium.EmitWebhookEvent(
+						ctx, chain, notification.Channel, consts.WebhookDeliveryFailed,
+						entities.NotificationWebhookData{
+							UUID: notification.UUID, Receiver: notification.Receiver, Medium: consts.Push,
+							Error: err.Error(),
+						},
+					)
+				} else if _, err = usecase.repo.InsertNotificationReach(ctx, reach); err != nil {
+					log.WithError(err).Error("failed to insert notification reach")
 				}
 			}
 
@@ -430,9 +473,30 @@ func (usecase *NotificationUsecases) SendNotifications(
 			}
 		}(notification)
 
+		sentReceivers = append(sentReceivers, receiver)
 		sent++
 	}
 
+	if sent > 0 {
+		err = usecase.repo.InsertSentNotificationInfo(
+			ctx, &entities.SentNotification{
+				Chain:       chain,
+				Channel:     channel,
+				UUID:        uuid,
+				Sender:      sender,
+				Receivers:   sentReceivers,
+				Message:     message,
+				Link:        link,
+				Type:        kind,
+				CreatedTime: now,
+				TTL:         ttl,
+			},
+		)
+		if err != nil {
+			log.Errorf("failed to insert sent notification info: %v", err)
+		}
+	}
+
 	err = usecase.repo.InsertGlobalStats(ctx, request, sent)
 	if err != nil {
 		log.Errorf("failed to insert global stats: %v", err)
@@ -448,12 +512,7 @@ func (usecase *NotificationUsecases) SendNotifications(
 		log.Errorf("failed to insert notification channel send metrics: %v", err)
 	}
 
-	err = usecase.repo.InsertNotificationSentCountForReach(ctx, request, sent)
-	if err != nil {
-		log.Errorf("failed to insert notification sent count for reach: %v", err)
-	}
-
-	err = usecase.repo.InsertUserSendMetrics(ctx, request, now, sent)
+	err = usecase.repo.InsertUserSendMetrics(ctx, chain, channelData.Owner, now, sent)
 	if err != nil {
 		log.Errorf("failed to insert total user sent count: %v", err)
 	}
@@ -470,15 +529,441 @@ func (usecase *NotificationUsecases) SendNotifications(
 }
 
 // GetNotifications retrieves the notification information based on the provided criteria.
+// The inboxes of linked accounts are merged into one.
 func (usecase *NotificationUsecases) GetNotifications(
 	ctx context.Context, request entities.RequestNotification, pageSize int, pageState []byte,
 ) ([]entities.ReadNotification, []byte, error) {
-	return usecase.repo.GetNotificationInfo(ctx, request, pageSize, pageState)
+	links, err := db.GetLinkedAccounts(ctx, request.Chain, request.User)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(links) > 0 {
+		return usecase.getLinkedNotifications(ctx, request, links, pageSize, pageState)
+	}
+
+	notifications, nextPageState, err := usecase.repo.GetNotificationInfo(ctx, request, pageSize, pageState)
+	if err != nil {
+		return notifications, nextPageState, err
+	}
+
+	optOut := usecase.trackingOptOut(ctx, request.Chain, request.User)
+	for i := range notifications {
+		notifications[i].Link = tracking.TrackedLink(
+			notifications[i].Link, tracking.Claims{
+				Chain:    request.Chain,
+				AppID:    notifications[i].AppID,
+				UUID:     notifications[i].Uuid,
+				Receiver: request.User,
+				Medium:   consts.Inapp,
+			}, optOut,
+		)
+	}
+
+	return notifications, nextPageState, nil
+}
+
+// getLinkedNotifications retrieves a page of the merged inbox of linked accounts. The page state of the
+// merged inbox is the cursor of the last notification of the previous page.
+func (usecase *NotificationUsecases) getLinkedNotifications(
+	ctx context.Context, request entities.RequestNotification, links []entities.LinkedAccount, pageSize int,
+	pageState []byte,
+) ([]entities.ReadNotification, []byte, error) {
+	var after *entities.InboxCursor
+	if len(pageState) > 0 {
+		after = &entities.InboxCursor{}
+		if err := json.Unmarshal(pageState, after); err != nil {
+			return nil, nil, fmt.Errorf("invalid page state: %w", err)
+		}
+	}
+
+	requests := []entities.RequestNotification{request}
+	for _, link := range links {
+		requests = append(requests, entities.RequestNotification{Chain: link.Chain, User: link.Address})
+	}
+
+	notifications, err := usecase.repo.GetLinkedNotificationInfo(ctx, requests, pageSize, after)
+	if err != nil {
+		return notifications, nil, err
+	}
+
+	// the preference of each linked account applies to its own notifications
+	optOuts := make(map[string]bool, len(requests))
+	for _, request := range requests {
+		optOuts[request.Chain+"/"+request.User] = usecase.trackingOptOut(ctx, request.Chain, request.User)
+	}
+	for i := range notifications {
+		notifications[i].Link = tracking.TrackedLink(
+			notifications[i].Link, tracking.Claims{
+				Chain:    notifications[i].Chain,
+				AppID:    notifications[i].AppID,
+				UUID:     notifications[i].Uuid,
+				Receiver: notifications[i].Receiver,
+				Medium:   consts.Inapp,
+			}, optOuts[notifications[i].Chain+"/"+notifications[i].Receiver],
+		)
+	}
+
+	var nextPageState []byte
+	if len(notifications) == pageSize {
+		last := notifications[len(notifications)-1]
+		nextPageState, err = json.Marshal(
+			entities.InboxCursor{
+				CreatedTime: last.CreatedTime,
+				Chain:       last.Chain,
+				Receiver:    last.Receiver,
+				Uuid:        last.Uuid,
+			},
+		)
+		if err != nil {
+			return notifications, nil, err
+		}
+	}
+
+	return notifications, nextPageState, nil
+}
+
+// GetNotificationReach retrieves the per medium reach of a notification sent from a channel whose stats the
+// user may read.
+func (usecase *NotificationUsecases) GetNotificationReach(
+	ctx context.Context, chain, appID, uuid, user string,
+) (*entities.NotificationReach, error) {
+	if err := verifyChannelPermission(ctx, usecase.members, chain, appID, user, consts.ChannelPermStats); err != nil {
+		return nil, err
+	}
+
+	notification, err := usecase.repo.GetSentNotificationInfo(ctx, chain, appID, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	return usecase.repo.GetNotificationReach(ctx, notification)
+}
+
+// TrackClick records a click on a notification link for the receiver and medium embedded in the tracking token
+// and returns the link to redirect to.
+func (usecase *NotificationUsecases) TrackClick(ctx context.Context, token string) (string, error) {
+	log := utilities.NewLogger("TrackClick")
+
+	claims, err := tracking.Verify(config.GetConfig().Tracking.Secret, token)
+	if err != nil {
+		return "", err
+	}
+
+	notification, err := usecase.repo.GetSentNotificationInfo(ctx, claims.Chain, claims.AppID, claims.UUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	if notification.Recalled || notification.Link == "" {
+		return "", fmt.Errorf("notification %s has no link", claims.UUID)
+	}
+
+	first, err := usecase.repo.InsertNotificationReach(
+		ctx, entities.NotificationReachEvent{
+			Chain:    claims.Chain,
+			Channel:  claims.AppID,
+			UUID:     claims.UUID,
+			Medium:   claims.Medium,
+			Event:    consts.ReachClicked,
+			Receiver: claims.Receiver,
+		},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to insert notification click reach")
+		return notification.Link, nil
+	}
+
+	// only unique clicks are counted towards channel click-through rate
+	if first {
+		medium.EmitWebhookEvent(
+			ctx, claims.Chain, claims.AppID, consts.WebhookNotificationClicked,
+			entities.NotificationWebhookData{UUID: claims.UUID, Receiver: claims.Receiver, Medium: claims.Medium},
+		)
+
+		err = usecase.repo.InsertChannelClickMetrics(ctx, claims.Chain, claims.AppID, claims.Medium, utilities.TimeNow())
+		if err != nil {
+			log.Errorf("failed to insert notification channel click metrics: %v", err)
+		}
+	}
+
+	return notification.Link, nil
+}
+
+// TrackOpen records an email read for the receiver embedded in the open tracking token.
+func (usecase *NotificationUsecases) TrackOpen(ctx context.Context, token string) error {
+	claims, err := tracking.Verify(config.GetConfig().Tracking.Secret, token)
+	if err != nil {
+		return err
+	}
+
+	if claims.Medium != consts.Email {
+		return fmt.Errorf("open tracking is not supported for medium %s", claims.Medium)
+	}
+
+	first, err := usecase.repo.InsertNotificationReach(
+		ctx, entities.NotificationReachEvent{
+			Chain:    claims.Chain,
+			Channel:  claims.AppID,
+			UUID:     claims.UUID,
+			Medium:   consts.Email,
+			Event:    consts.ReachRead,
+			Receiver: claims.Receiver,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert email read reach: %w", err)
+	}
+
+	// email clients fetch the pixel on every open, only the first one is a read
+	if !first {
+		return nil
+	}
+
+	medium.EmitWebhookEvent(
+		ctx, claims.Chain, claims.AppID, consts.WebhookNotificationRead,
+		entities.NotificationWebhookData{UUID: claims.UUID, Receiver: claims.Receiver, Medium: consts.Email},
+	)
+
+	return usecase.repo.InsertChannelReadMetrics(ctx, claims.Chain, claims.AppID, consts.Email, utilities.TimeNow())
+}
+
+// trackingOptOut tells whether the user opted out of tracking. Users whose preference cannot be read are not tracked.
+func (usecase *NotificationUsecases) trackingOptOut(ctx context.Context, chain, address string) bool {
+	userModel, err := usecase.userRepo.GetUserModel(ctx, chain, address)
+	if err != nil {
+		utilities.NewLogger("trackingOptOut").WithError(err).Errorf("failed to get tracking preference of %s", address)
+		return true
+	}
+
+	return userModel.TrackingOptOut
+}
+
+// validateCharacterCount checks the notification message and link against the character limit of the membership tier.
+func validateCharacterCount(membership consts.MembershipTier, message, link string) error {
+	permittedCharCount := consts.NotificationCharacterCount[membership]
+
+	if len(message) > permittedCharCount {
+		return fmt.Errorf(
+			"cannot send notification as your membership allows only %d notification text character count",
+			permittedCharCount,
+		)
+	}
+
+	if len(link) > permittedCharCount {
+		return fmt.Errorf(
+			"cannot send notification as your membership allows only %d notification link character count",
+			permittedCharCount,
+		)
+	}
+
+	return nil
+}
+
+// RecallNotification removes an already sent notification from the inbox of all its receivers.
+// Receivers are informed of the retraction over websocket and FCM, and discord messages are deleted on a best effort basis.
+func (usecase *NotificationUsecases) RecallNotification(
+	ctx context.Context, chain, appID, uuid, sender string,
+) error {
+	log := utilities.NewLoggerWithFields(
+		"RecallNotification", map[string]interface{}{
+			"chain":  chain,
+			"app_id": appID,
+			"uuid":   uuid,
+		},
+	)
+
+	if err := verifyChannelPermission(ctx, usecase.members, chain, appID, sender, consts.ChannelPermSend); err != nil {
+		log.WithError(err).Warnf("Sender %s may not send from the channel", sender)
+		return err
+	}
+
+	notification, err := usecase.repo.GetSentNotificationInfo(ctx, chain, appID, uuid)
+	if err != nil {
+		return fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	if notification.Recalled {
+		return fmt.Errorf("notification %s is already recalled", uuid)
+	}
+
+	if err = usecase.repo.RecallNotificationInfo(ctx, notification); err != nil {
+		return fmt.Errorf("failed to recall notification: %w", err)
+	}
+
+	event := entities.NotificationEvent{
+		Event: consts.NotificationRecalled,
+		Uuid:  uuid,
+		AppID: appID,
+	}
+
+	go func() {
+		for _, receiver := range notification.Receivers {
+			usecase.pushNotificationEvent(ctx, chain, receiver, event)
+		}
+
+		if config.GetConfig().Mode == "local" {
+			return
+		}
+
+		for receiver, ref := range notification.DiscordMessages {
+			if err := medium.GetDiscordMessenger().DeleteMessage(ref); err != nil {
+				log.WithError(err).Warnf("failed to delete discord message of %s", receiver)
+			}
+		}
+	}()
+
+	err = usecase.repo.InsertChannelRecallMetrics(ctx, chain, appID, utilities.TimeNow(), len(notification.Receivers))
+	if err != nil {
+		log.Errorf("failed to insert notification channel recall metrics: %v", err)
+	}
+
+	return nil
 }
 
-// NotificationReachCount retrieves the reach count information for a notification with the given UUID.
-func (usecase *NotificationUsecases) NotificationReachCount(
-	ctx context.Context, uuid string, pageSize int, pageState []byte,
-) ([]entities.NotificationReach, []byte, error) {
-	return usecase.repo.NotificationReachCount(ctx, uuid, pageSize, pageState)
+// EditNotification updates the message and link of an already sent notification for all its receivers.
+// The previous content is kept as a revision and receivers are informed of the edit over websocket and FCM.
+func (usecase *NotificationUsecases) EditNotification(
+	ctx context.Context, request entities.EditNotificationRequest,
+) error {
+	log := utilities.NewLoggerWithFields(
+		"EditNotification", map[string]interface{}{
+			"chain":  request.Chain,
+			"app_id": request.Channel,
+			"uuid":   request.UUID,
+		},
+	)
+
+	err := verifyChannelPermission(
+		ctx, usecase.members, request.Chain, request.Channel, request.Sender, consts.ChannelPermSend,
+	)
+	if err != nil {
+		log.WithError(err).Warnf("Sender %s may not send from the channel", request.Sender)
+		return err
+	}
+
+	userModel, err := channelOwnerModel(ctx, usecase.members, usecase.userRepo, request.Chain, request.Channel)
+	if err != nil {
+		return err
+	}
+	membership := consts.MembershipStringToEnum(userModel.Membership)
+
+	if err = validateCharacterCount(membership, request.Message, request.Link); err != nil {
+		return err
+	}
+
+	notification, err := usecase.repo.GetSentNotificationInfo(ctx, request.Chain, request.Channel, request.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	if notification.Recalled {
+		return fmt.Errorf("notification %s is recalled and cannot be edited", request.UUID)
+	}
+
+	if notification.Message == request.Message && notification.Link == request.Link {
+		return nil
+	}
+
+	now := utilities.TimeNow()
+	err = usecase.repo.EditNotificationInfo(ctx, notification, request.Message, request.Link, now)
+	if err != nil {
+		return fmt.Errorf("failed to edit notification: %w", err)
+	}
+
+	event := entities.NotificationEvent{
+		Event:      consts.NotificationEdited,
+		Uuid:       request.UUID,
+		AppID:      request.Channel,
+		Message:    request.Message,
+		Link:       request.Link,
+		EditedTime: &now,
+	}
+
+	go func() {
+		for _, receiver := range notification.Receivers {
+			usecase.pushNotificationEvent(ctx, request.Chain, receiver, event)
+		}
+	}()
+
+	return nil
+}
+
+// GetNotificationRevisions retrieves the previous revisions of a notification.
+// Only the sender and the receivers of the notification are allowed to view them.
+func (usecase *NotificationUsecases) GetNotificationRevisions(
+	ctx context.Context, chain, appID, uuid, user string,
+) ([]entities.NotificationRevision, error) {
+	notification, err := usecase.repo.GetSentNotificationInfo(ctx, chain, appID, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	// the members who may send from the channel, or see its stats, follow its notifications along with the sender
+	if notification.Sender != user && !utilities.ContainsString(notification.Receivers, user) {
+		if verifyChannelPermission(ctx, usecase.members, chain, appID, user, consts.ChannelPermSend) != nil {
+			if err = verifyChannelPermission(ctx, usecase.members, chain, appID, user, consts.ChannelPermStats); err != nil {
+				return nil, fmt.Errorf("user is neither the sender, a receiver nor a member of the channel: %w", err)
+			}
+		}
+	}
+
+	return usecase.repo.GetNotificationRevisions(ctx, chain, appID, uuid)
+}
+
+// pushNotificationEvent informs the receiver about a change to an already delivered notification
+// over websocket and FCM data message.
+func (usecase *NotificationUsecases) pushNotificationEvent(
+	ctx context.Context, chain, receiver string, event entities.NotificationEvent,
+) {
+	log := utilities.NewLogger("pushNotificationEvent")
+
+	claims := tracking.Claims{Chain: chain, AppID: event.AppID, UUID: event.Uuid, Receiver: receiver, Medium: consts.Inapp}
+	optOut := usecase.trackingOptOut(ctx, chain, receiver)
+
+	link := event.Link
+	event.Link = tracking.TrackedLink(link, claims, optOut)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.WithError(err).Errorf("failed to marshal notification event %+v", event)
+		return
+	}
+
+	if err = usecase.ws.PushMessage(medium.FormatIdentifier(chain, receiver), data, false); err != nil {
+		log.WithError(err).Error("failed to push websocket notification event")
+	}
+
+	tokens, err := usecase.userRepo.GetFCMTokens(
+		ctx, entities.UserIdentifier{
+			Chain:   chain,
+			Address: receiver,
+		},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to get fcm tokens")
+		return
+	}
+
+	if len(tokens) == 0 {
+		return
+	}
+
+	msg := messaging.Message{
+		Data: map[string]string{
+			"event":  event.Event,
+			"uuid":   event.Uuid,
+			"app_id": event.AppID,
+		},
+	}
+	if event.Message != "" {
+		msg.Data["message"] = event.Message
+		claims.Medium = consts.Push
+		msg.Data["link"] = tracking.TrackedLink(link, claims, optOut)
+	}
+	if event.EditedTime != nil {
+		msg.Data["edited_time"] = event.EditedTime.Format("2006-01-02T15:04:05Z")
+	}
+	if err = medium.GetFirebaseClient().PushMessageToClient(ctx, chain, receiver, msg, tokens); err != nil {
+		log.WithError(err).Errorf("failed to push notification event")
+	}
 }
@@ -0,0 +1,439 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities/tracking"
+)
+
+// fakeNotificationRepo serves a single sent notification and records what is done to it
+type fakeNotificationRepo struct {
+	repo.NotificationRepoImply
+
+	notification  *entities.SentNotification
+	recalled      bool
+	recallMetrics int
+	edited        *entities.EditNotificationRequest
+	clicked       map[string]bool
+	clickMetrics  int
+}
+
+func (f *fakeNotificationRepo) GetSentNotificationInfo(
+	_ context.Context, _, _, _ string,
+) (*entities.SentNotification, error) {
+	return f.notification, nil
+}
+
+func (f *fakeNotificationRepo) RecallNotificationInfo(context.Context, *entities.SentNotification) error {
+	f.recalled = true
+	return nil
+}
+
+func (f *fakeNotificationRepo) InsertChannelRecallMetrics(
+	_ context.Context, _, _ string, _ time.Time, count int,
+) error {
+	f.recallMetrics += count
+	return nil
+}
+
+func (f *fakeNotificationRepo) EditNotificationInfo(
+	_ context.Context, _ *entities.SentNotification, message, link string, _ time.Time,
+) error {
+	f.edited = &entities.EditNotificationRequest{Message: message, Link: link}
+	return nil
+}
+
+func (f *fakeNotificationRepo) GetNotificationReach(
+	_ context.Context, notification *entities.SentNotification,
+) (*entities.NotificationReach, error) {
+	return &entities.NotificationReach{UUID: notification.UUID}, nil
+}
+
+func (f *fakeNotificationRepo) InsertNotificationReach(
+	_ context.Context, reach entities.NotificationReachEvent,
+) (bool, error) {
+	if f.clicked == nil {
+		f.clicked = make(map[string]bool)
+	}
+	key := reach.Receiver + "/" + reach.Medium + "/" + reach.Event
+	first := !f.clicked[key]
+	f.clicked[key] = true
+
+	return first, nil
+}
+
+func (f *fakeNotificationRepo) GetNotificationRevisions(
+	_ context.Context, _, _, _ string,
+) ([]entities.NotificationRevision, error) {
+	return []entities.NotificationRevision{{Message: "first"}}, nil
+}
+
+func (f *fakeNotificationRepo) InsertChannelClickMetrics(context.Context, string, string, string, time.Time) error {
+	f.clickMetrics++
+	return nil
+}
+
+func TestNotificationUsecases_RecallNotification(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{
+			"owner":   consts.ChannelRoleOwner,
+			"analyst": consts.ChannelRoleAnalyst,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		sender      string
+		recalled    bool
+		wantErr     bool
+		wantRecall  bool
+		wantMetrics int
+	}{
+		{
+			name:    "not a member",
+			sender:  "stranger",
+			wantErr: true,
+		},
+		{
+			name:    "without send permission",
+			sender:  "analyst",
+			wantErr: true,
+		},
+		{
+			name:     "already recalled",
+			sender:   "owner",
+			recalled: true,
+			wantErr:  true,
+		},
+		{
+			name:        "recalled",
+			sender:      "owner",
+			wantRecall:  true,
+			wantMetrics: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				notificationRepo := &fakeNotificationRepo{
+					notification: &entities.SentNotification{
+						Chain:     consts.Algorand,
+						Channel:   "1",
+						UUID:      "uuid",
+						Receivers: []string{"alice", "bob"},
+						Recalled:  tt.recalled,
+					},
+				}
+				usecase := &NotificationUsecases{
+					repo:     notificationRepo,
+					userRepo: fakeUserRepo{},
+					members:  members,
+					ws:       newTestSocket(),
+				}
+
+				err := usecase.RecallNotification(context.Background(), consts.Algorand, "1", "uuid", tt.sender)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("RecallNotification() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if notificationRepo.recalled != tt.wantRecall {
+					t.Errorf("RecallNotificationInfo called = %v, want %v", notificationRepo.recalled, tt.wantRecall)
+				}
+				if notificationRepo.recallMetrics != tt.wantMetrics {
+					t.Errorf("recall metrics = %d, want %d", notificationRepo.recallMetrics, tt.wantMetrics)
+				}
+			},
+		)
+	}
+}
+
+func TestNotificationUsecases_EditNotification(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{
+			"owner":   consts.ChannelRoleOwner,
+			"analyst": consts.ChannelRoleAnalyst,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		sender     string
+		message    string
+		recalled   bool
+		wantErr    bool
+		wantEdited bool
+	}{
+		{
+			name:    "without send permission",
+			sender:  "analyst",
+			message: "edited",
+			wantErr: true,
+		},
+		{
+			name:    "message too long for the membership",
+			sender:  "owner",
+			message: strings.Repeat("a", consts.NotificationCharacterCount[consts.FreeTier]+1),
+			wantErr: true,
+		},
+		{
+			name:     "recalled",
+			sender:   "owner",
+			message:  "edited",
+			recalled: true,
+			wantErr:  true,
+		},
+		{
+			name:    "unchanged",
+			sender:  "owner",
+			message: "original",
+		},
+		{
+			name:       "edited",
+			sender:     "owner",
+			message:    "edited",
+			wantEdited: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				notificationRepo := &fakeNotificationRepo{
+					notification: &entities.SentNotification{
+						Chain:     consts.Algorand,
+						Channel:   "1",
+						UUID:      "uuid",
+						Message:   "original",
+						Receivers: []string{"alice", "bob"},
+						Recalled:  tt.recalled,
+					},
+				}
+				usecase := &NotificationUsecases{
+					repo:     notificationRepo,
+					userRepo: fakeUserRepo{membership: "free"},
+					members:  members,
+					ws:       newTestSocket(),
+				}
+
+				err := usecase.EditNotification(
+					context.Background(), entities.EditNotificationRequest{
+						Message: tt.message,
+						Chain:   consts.Algorand,
+						Channel: "1",
+						UUID:    "uuid",
+						Sender:  tt.sender,
+					},
+				)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("EditNotification() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if (notificationRepo.edited != nil) != tt.wantEdited {
+					t.Fatalf("EditNotificationInfo called = %v, want %v", notificationRepo.edited != nil, tt.wantEdited)
+				}
+				if tt.wantEdited && notificationRepo.edited.Message != tt.message {
+					t.Errorf("edited message = %q, want %q", notificationRepo.edited.Message, tt.message)
+				}
+			},
+		)
+	}
+}
+
+func TestNotificationUsecases_GetNotificationReach(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{
+			"analyst": consts.ChannelRoleAnalyst,
+			"sender":  consts.ChannelRoleSender,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		wantErr bool
+	}{
+		{
+			name:    "not a member",
+			user:    "stranger",
+			wantErr: true,
+		},
+		{
+			name:    "without stats permission",
+			user:    "sender",
+			wantErr: true,
+		},
+		{
+			name: "with stats permission",
+			user: "analyst",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				usecase := &NotificationUsecases{
+					repo:    &fakeNotificationRepo{notification: &entities.SentNotification{UUID: "uuid"}},
+					members: members,
+				}
+
+				reach, err := usecase.GetNotificationReach(context.Background(), consts.Algorand, "1", "uuid", tt.user)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GetNotificationReach() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && reach.UUID != "uuid" {
+					t.Errorf("GetNotificationReach() uuid = %s, want uuid", reach.UUID)
+				}
+			},
+		)
+	}
+}
+
+func TestNotificationUsecases_GetNotificationRevisions(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{
+			"admin":   consts.ChannelRoleAdmin,
+			"analyst": consts.ChannelRoleAnalyst,
+			"sender":  consts.ChannelRoleSender,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		wantErr bool
+	}{
+		{
+			name: "original sender",
+			user: "owner",
+		},
+		{
+			name: "receiver",
+			user: "alice",
+		},
+		{
+			name: "member who may send",
+			user: "sender",
+		},
+		{
+			name: "member who may see stats",
+			user: "analyst",
+		},
+		{
+			name: "member who may edit",
+			user: "admin",
+		},
+		{
+			name:    "stranger",
+			user:    "stranger",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				usecase := &NotificationUsecases{
+					repo: &fakeNotificationRepo{
+						notification: &entities.SentNotification{
+							UUID: "uuid", Sender: "owner", Receivers: []string{"alice"},
+						},
+					},
+					members: members,
+				}
+
+				revisions, err := usecase.GetNotificationRevisions(
+					context.Background(), consts.Algorand, "1", "uuid", tt.user,
+				)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GetNotificationRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && len(revisions) != 1 {
+					t.Errorf("GetNotificationRevisions() = %v, want a revision", revisions)
+				}
+			},
+		)
+	}
+}
+
+func TestNotificationUsecases_TrackClick(t *testing.T) {
+	claims := tracking.Claims{
+		Chain:    consts.Algorand,
+		AppID:    "1",
+		UUID:     "uuid",
+		Receiver: "alice",
+		Medium:   consts.Email,
+	}
+	token, err := tracking.Sign(config.GetConfig().Tracking.Secret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := tracking.Sign("another secret of thirty two chars", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		tokens      []string
+		link        string
+		recalled    bool
+		wantErr     bool
+		wantMetrics int
+	}{
+		{
+			name:    "forged token",
+			tokens:  []string{forged},
+			link:    "https://notiboy.com",
+			wantErr: true,
+		},
+		{
+			name:     "recalled notification",
+			tokens:   []string{token},
+			link:     "https://notiboy.com",
+			recalled: true,
+			wantErr:  true,
+		},
+		{
+			name:    "notification without link",
+			tokens:  []string{token},
+			wantErr: true,
+		},
+		{
+			name:        "clicks counted once",
+			tokens:      []string{token, token},
+			link:        "https://notiboy.com",
+			wantMetrics: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				notificationRepo := &fakeNotificationRepo{
+					notification: &entities.SentNotification{
+						Chain:    consts.Algorand,
+						Channel:  "1",
+						UUID:     "uuid",
+						Link:     tt.link,
+						Recalled: tt.recalled,
+					},
+				}
+				usecase := &NotificationUsecases{repo: notificationRepo}
+
+				for _, token := range tt.tokens {
+					link, err := usecase.TrackClick(context.Background(), token)
+					if (err != nil) != tt.wantErr {
+						t.Fatalf("TrackClick() error = %v, wantErr %v", err, tt.wantErr)
+					}
+					if !tt.wantErr && link != tt.link {
+						t.Errorf("TrackClick() = %s, want %s", link, tt.link)
+					}
+				}
+				if notificationRepo.clickMetrics != tt.wantMetrics {
+					t.Errorf("click metrics = %d, want %d", notificationRepo.clickMetrics, tt.wantMetrics)
+				}
+			},
+		)
+	}
+}
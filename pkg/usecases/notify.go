@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+
+	"firebase.google.com/go/v4/messaging"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	mediumLib "notiboy/pkg/repo/driver/medium"
+	"notiboy/ui/templates"
+	"notiboy/utilities"
+)
+
+// notifyAccount lets the user of address know about something that happened to the channel, by email if it
+// verified one and by push to its devices. Failures are only logged.
+func notifyAccount(
+	ctx context.Context, userRepo repo.UserRepoImply, chain, address, appID, channelName, subject, message string,
+) {
+	log := utilities.NewLoggerWithFields(
+		"notifyAccount", map[string]interface{}{
+			"chain":   chain,
+			"address": address,
+			"app_id":  appID,
+		},
+	)
+
+	tokens, err := userRepo.GetFCMTokens(ctx, entities.UserIdentifier{Chain: chain, Address: address})
+	if err != nil {
+		log.WithError(err).Error("failed to get fcm tokens")
+	}
+	if len(tokens) > 0 {
+		msg := messaging.Message{
+			Notification: &messaging.Notification{
+				Title: subject,
+				Body:  message,
+			},
+			Data: map[string]string{
+				"click_action": "FLUTTER_NOTIFICATION_CLICK",
+				"app_id":       appID,
+				"channel_name": channelName,
+			},
+		}
+		err = mediumLib.GetFirebaseClient().PushMessageToClient(ctx, chain, address, msg, tokens)
+		if err != nil {
+			log.WithError(err).Error("failed to push notification")
+		}
+	}
+
+	if config.GetConfig().Mode == "local" {
+		return
+	}
+
+	email, err := userRepo.GetMediumAddress(ctx, address, consts.Email, chain)
+	if err != nil || email == "" {
+		return
+	}
+
+	var renderData entities.TplRenderData
+	renderData.Message = message
+	renderData.ButtonDescription = "VIEW CHANNEL"
+	if renderData.CallbackUrl, err = url.JoinPath(config.GetConfig().Server.RedirectPrefix, "/channels"); err != nil {
+		log.WithError(err).Error("failed to join path")
+		return
+	}
+
+	body, err := utilities.TemplateRendering(templates.VerificationTemplate, renderData)
+	if err != nil {
+		log.WithError(err).Error("failed to render email template")
+		return
+	}
+
+	if err = mediumLib.GetEmailClient().SendMail(
+		ctx, config.GetConfig().Email.Notification.From, email, subject, body.String(),
+	); err != nil {
+		log.WithError(err).Error("failed to send email")
+	}
+}
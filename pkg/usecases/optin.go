@@ -2,36 +2,110 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/spf13/cast"
 
 	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
-	"notiboy/pkg/repo/driver/db"
+	"notiboy/utilities"
 )
 
 type OptinUseCases struct {
-	repo repo.OptinRepoImply
+	repo     repo.OptinRepoImply
+	members  repo.ChannelMemberRepoImply
+	userRepo repo.UserRepoImply
 }
 
 type OptinUseCaseImply interface {
-	Optin(context.Context, string, string, string) error
+	Optin(ctx context.Context, chain, appId, userAddr, invite, source string) (bool, error)
 	Optout(context.Context, string, string, string) error
 	OptinoutStatistics(ctx context.Context, chain, appId, statType, startDate, endDate string) (*entities.ChannelOptInOutStats, error)
+	CreateInvite(
+		ctx context.Context, chain, appID, user string, request entities.ChannelInviteRequest,
+	) (*entities.ChannelInvite, error)
+	ListInvites(ctx context.Context, chain, appID, user string) ([]entities.ChannelInvite, error)
+	RevokeInvite(ctx context.Context, chain, appID, user, code string) error
+	ListOptinRequests(ctx context.Context, chain, appID, user string) ([]entities.OptinRequest, error)
+	ApproveOptinRequest(ctx context.Context, chain, appID, user, address string) error
+	RejectOptinRequest(ctx context.Context, chain, appID, user, address string) error
+	SetTokenGate(
+		ctx context.Context, chain, appID, user string, request entities.ChannelTokenGateRequest,
+	) (*entities.ChannelTokenGate, error)
+	GetTokenGate(ctx context.Context, chain, appID string) (*entities.ChannelTokenGate, error)
+	RemoveTokenGate(ctx context.Context, chain, appID, user string) error
+	OptinLink(ctx context.Context, chain, appID, source, invite string) (*entities.OptinLink, error)
+	OptinQRCode(ctx context.Context, chain, appID, source, invite string, size int) ([]byte, error)
 }
 
 // NewOptinUseCases
-func NewOptinUseCases(OptinRepo repo.OptinRepoImply) OptinUseCaseImply {
+func NewOptinUseCases(
+	OptinRepo repo.OptinRepoImply, members repo.ChannelMemberRepoImply, userRepo repo.UserRepoImply,
+) OptinUseCaseImply {
 	return &OptinUseCases{
-		repo: OptinRepo,
+		repo:     OptinRepo,
+		members:  members,
+		userRepo: userRepo,
 	}
 }
 
-// Optin enables opt-in for a user with the provided user address, chain, and app ID.
-func (Optin *OptinUseCases) Optin(ctx context.Context, chain, appId, userAddr string) error {
-	return Optin.repo.Optin(ctx, chain, appId, userAddr)
+// Optin enables opt-in for a user with the provided user address, chain, and app ID. Members of a channel opt in
+// right away, anyone else needs to hold what a token gated channel requires. Users with an invite to a private
+// channel opt in right away too, anyone else requests the approval of its owner, in which case Optin returns false.
+// The opt-in is attributed to the campaign or referral source of the opt-in link the user followed, if any.
+func (Optin *OptinUseCases) Optin(ctx context.Context, chain, appId, userAddr, invite, source string) (bool, error) {
+	source, err := optinSource(source)
+	if err != nil {
+		return false, err
+	}
+
+	visibility, err := Optin.repo.GetChannelVisibility(ctx, chain, appId)
+	if err != nil {
+		return false, err
+	}
+
+	role, err := Optin.members.GetChannelRole(ctx, chain, appId, userAddr)
+	if err != nil {
+		return false, err
+	}
+
+	if role == "" {
+		if err = Optin.verifyTokenGate(ctx, chain, appId, userAddr); err != nil {
+			return false, err
+		}
+
+		if visibility == consts.ChannelPrivate {
+			if _, err = Optin.repo.VerifyUserOptin(ctx, chain, appId, userAddr); err == nil {
+				return false, errors.New("you have already opted in to the channel")
+			}
+
+			if invite == "" {
+				return false, Optin.repo.CreateOptinRequest(
+					ctx, &entities.OptinRequest{
+						Chain:   chain,
+						AppID:   appId,
+						Address: userAddr,
+						Source:  source,
+						Created: utilities.TimeNow(),
+					},
+				)
+			}
+
+			if err = Optin.repo.RedeemInvite(ctx, chain, appId, invite); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err = Optin.repo.Optin(ctx, chain, appId, userAddr, source); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // Optout disables opt-in for a user with the provided user address, chain, and app ID.
@@ -43,7 +117,7 @@ func (Optin *OptinUseCases) Optout(ctx context.Context, chain, appId, userAddr s
 func (Optin *OptinUseCases) OptinoutStatistics(ctx context.Context, chain, appId, statType, startDate, endDate string) (*entities.ChannelOptInOutStats, error) {
 	sender := cast.ToString(ctx.Value(consts.UserAddress))
 
-	userModel, err := db.GetUserModel(ctx, chain, sender)
+	userModel, err := Optin.userRepo.GetUserModel(ctx, chain, sender)
 	if err != nil {
 		return nil, fmt.Errorf("getting user model failed")
 	}
@@ -62,3 +136,139 @@ func (Optin *OptinUseCases) OptinoutStatistics(ctx context.Context, chain, appId
 
 	return data, nil
 }
+
+// CreateInvite creates an invite letting users opt in to the channel without approval, until it expires, is used
+// up or is revoked.
+func (Optin *OptinUseCases) CreateInvite(
+	ctx context.Context, chain, appID, user string, request entities.ChannelInviteRequest,
+) (*entities.ChannelInvite, error) {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return nil, err
+	}
+
+	if request.MaxUses < 0 {
+		return nil, fmt.Errorf("invalid max uses %d", request.MaxUses)
+	}
+
+	now := utilities.TimeNow()
+	invite := &entities.ChannelInvite{
+		Chain:     chain,
+		AppID:     appID,
+		MaxUses:   request.MaxUses,
+		CreatedBy: user,
+		Created:   now,
+	}
+
+	if request.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(request.ExpiresIn)
+		if err != nil || expiresIn <= 0 {
+			return nil, fmt.Errorf("invalid expiry %s", request.ExpiresIn)
+		}
+		expires := now.Add(expiresIn)
+		invite.Expires = &expires
+	}
+
+	var err error
+	if invite.Code, err = utilities.GenerateSecret(16); err != nil {
+		return nil, fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	if err = Optin.repo.CreateInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	return withInviteLink(invite)
+}
+
+// ListInvites retrieves the invites to the channel.
+func (Optin *OptinUseCases) ListInvites(
+	ctx context.Context, chain, appID, user string,
+) ([]entities.ChannelInvite, error) {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return nil, err
+	}
+
+	invites, err := Optin.repo.ListInvites(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range invites {
+		if _, err = withInviteLink(&invites[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return invites, nil
+}
+
+// RevokeInvite revokes the invite to the channel, users who redeemed it stay opted in.
+func (Optin *OptinUseCases) RevokeInvite(ctx context.Context, chain, appID, user, code string) error {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return err
+	}
+
+	return Optin.repo.RevokeInvite(ctx, chain, appID, code)
+}
+
+// ListOptinRequests retrieves the requests to opt in to the channel waiting for approval.
+func (Optin *OptinUseCases) ListOptinRequests(
+	ctx context.Context, chain, appID, user string,
+) ([]entities.OptinRequest, error) {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return nil, err
+	}
+
+	return Optin.repo.ListOptinRequests(ctx, chain, appID)
+}
+
+// ApproveOptinRequest opts the user in to the channel it requested to opt in to.
+func (Optin *OptinUseCases) ApproveOptinRequest(ctx context.Context, chain, appID, user, address string) error {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return err
+	}
+
+	request, err := Optin.repo.GetOptinRequest(ctx, chain, appID, address)
+	if err != nil {
+		return err
+	}
+
+	if err = Optin.verifyTokenGate(ctx, chain, appID, address); err != nil {
+		return err
+	}
+
+	source := request.Source
+	if source == "" {
+		source = consts.OptinSourceDirect
+	}
+
+	if err = Optin.repo.Optin(ctx, chain, appID, address, source); err != nil {
+		return err
+	}
+
+	return Optin.repo.DeleteOptinRequest(ctx, chain, appID, address)
+}
+
+// RejectOptinRequest rejects the request of the user to opt in to the channel.
+func (Optin *OptinUseCases) RejectOptinRequest(ctx context.Context, chain, appID, user, address string) error {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return err
+	}
+
+	if _, err := Optin.repo.GetOptinRequest(ctx, chain, appID, address); err != nil {
+		return err
+	}
+
+	return Optin.repo.DeleteOptinRequest(ctx, chain, appID, address)
+}
+
+// withInviteLink fills in the link users follow to opt in to the channel with invite
+func withInviteLink(invite *entities.ChannelInvite) (*entities.ChannelInvite, error) {
+	link, err := optinLink(invite.Chain, invite.AppID, url.Values{"invite": {invite.Code}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build invite link: %w", err)
+	}
+	invite.Link = link
+
+	return invite, nil
+}
@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+)
+
+// holdingCheckTimeout bounds checking the holding of one user
+const holdingCheckTimeout = 30 * time.Second
+
+// The holdings of the subscribers of a channel are checked holdingCheckConcurrency at a time, and a check starts
+// every holdingCheckPace at most so that the node of the chain isn't flooded
+const (
+	holdingCheckConcurrency = 8
+	holdingCheckPace        = 100 * time.Millisecond
+)
+
+// SetTokenGate sets the holding users need to opt in to the channel. Users already opted in who don't hold it are
+// opted out by the next periodic check.
+func (Optin *OptinUseCases) SetTokenGate(
+	ctx context.Context, chain, appID, user string, request entities.ChannelTokenGateRequest,
+) (*entities.ChannelTokenGate, error) {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return nil, err
+	}
+
+	gate := &entities.ChannelTokenGate{
+		Chain:      chain,
+		AppID:      appID,
+		Kind:       request.Kind,
+		Asset:      request.Asset,
+		Issuer:     request.Issuer,
+		MinBalance: request.MinBalance,
+		UpdatedBy:  user,
+		Updated:    utilities.TimeNow(),
+	}
+
+	if err := validateTokenGate(gate); err != nil {
+		return nil, err
+	}
+
+	// querying the holding of the user setting the gate tells whether the chain knows the token
+	if _, err := holding(ctx, Optin.repo, gate, user); err != nil {
+		return nil, fmt.Errorf("failed to query the token: %w", err)
+	}
+
+	if err := Optin.repo.SetTokenGate(ctx, gate); err != nil {
+		return nil, err
+	}
+
+	return gate, nil
+}
+
+// GetTokenGate retrieves the holding users need to opt in to the channel.
+func (Optin *OptinUseCases) GetTokenGate(ctx context.Context, chain, appID string) (*entities.ChannelTokenGate, error) {
+	gate, err := Optin.repo.GetTokenGate(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+	if gate == nil {
+		return nil, fmt.Errorf("channel %s is not token gated", appID)
+	}
+
+	return gate, nil
+}
+
+// RemoveTokenGate lets anyone opt in to the channel again, whatever they hold.
+func (Optin *OptinUseCases) RemoveTokenGate(ctx context.Context, chain, appID, user string) error {
+	if err := verifyChannelPermission(ctx, Optin.members, chain, appID, user, consts.ChannelPermSubscribers); err != nil {
+		return err
+	}
+
+	return Optin.repo.DeleteTokenGate(ctx, chain, appID)
+}
+
+// verifyTokenGate checks that the user holds what the channel requires to opt in, if it is token gated
+func (Optin *OptinUseCases) verifyTokenGate(ctx context.Context, chain, appID, address string) error {
+	gate, err := Optin.repo.GetTokenGate(ctx, chain, appID)
+	if err != nil || gate == nil {
+		return err
+	}
+
+	qualified, reason, err := qualifies(ctx, Optin.repo, gate, address)
+	if err != nil {
+		return fmt.Errorf("failed to check your holding: %w", err)
+	}
+	if !qualified {
+		return fmt.Errorf("channel is token gated: %s", reason)
+	}
+
+	return nil
+}
+
+// TokenGateWorker periodically checks that the subscribers of token gated channels still hold what the channel
+// requires, and opts out those who don't anymore, letting them know why. Members of a channel are never opted
+// out, and a subscriber whose holding can't be queried is given the benefit of the doubt.
+func TokenGateWorker(
+	ctx context.Context, optinRepo repo.OptinRepoImply, channelRepo repo.ChannelRepoImpl, userRepo repo.UserRepoImply,
+	members repo.ChannelMemberRepoImply,
+) {
+	log := utilities.NewLogger("TokenGateWorker")
+
+	ticker := time.NewTicker(cast.ToDuration(config.GetConfig().TokenGateCheckInterval))
+
+	go func() {
+		runOnce := make(chan struct{}, 1)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("Terminating...")
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				select {
+				// at any point of time, no more than one go routine should run
+				case runOnce <- struct{}{}:
+					go runTokenGateChecks(ctx, optinRepo, channelRepo, userRepo, members, runOnce)
+				default:
+				}
+			}
+		}
+	}()
+}
+
+func runTokenGateChecks(
+	ctx context.Context, optinRepo repo.OptinRepoImply, channelRepo repo.ChannelRepoImpl,
+	userRepo repo.UserRepoImply, members repo.ChannelMemberRepoImply, runOnce chan struct{},
+) {
+	log := utilities.NewLogger("runTokenGateChecks")
+
+	defer func() {
+		<-runOnce
+	}()
+
+	gates, err := optinRepo.ListTokenGates(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to list token gates")
+		return
+	}
+
+	for i := range gates {
+		checkTokenGate(ctx, optinRepo, channelRepo, userRepo, members, &gates[i])
+	}
+}
+
+// checkTokenGate opts out the subscribers of the channel of gate who don't hold what it requires anymore
+func checkTokenGate(
+	ctx context.Context, optinRepo repo.OptinRepoImply, channelRepo repo.ChannelRepoImpl,
+	userRepo repo.UserRepoImply, members repo.ChannelMemberRepoImply, gate *entities.ChannelTokenGate,
+) {
+	log := utilities.NewLoggerWithFields(
+		"checkTokenGate", map[string]interface{}{
+			"chain":  gate.Chain,
+			"app_id": gate.AppID,
+		},
+	)
+
+	channelInfo, err := channelRepo.GetChannel(ctx, gate.Chain, gate.AppID, false)
+	if err != nil {
+		log.WithError(err).Error("failed to get channel")
+		return
+	}
+	channelName := channelInfo.Data.(entities.ChannelModel).Name
+
+	users, err := optinRepo.OptinUsers(ctx, gate.Chain, gate.AppID)
+	if err != nil {
+		log.WithError(err).Error("failed to get subscribers")
+		return
+	}
+
+	pace := time.NewTicker(holdingCheckPace)
+	defer pace.Stop()
+
+	throttler := make(chan struct{}, holdingCheckConcurrency)
+	wg := new(sync.WaitGroup)
+
+	for _, address := range users {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-pace.C:
+		}
+
+		throttler <- struct{}{}
+		wg.Add(1)
+
+		go func(address string) {
+			defer func() {
+				wg.Done()
+				<-throttler
+			}()
+
+			checkSubscriberHolding(ctx, optinRepo, userRepo, members, gate, channelName, address)
+		}(address)
+	}
+	wg.Wait()
+}
+
+// checkSubscriberHolding opts out the subscriber of the channel of gate unless it is a member of the channel or
+// holds what gate requires
+func checkSubscriberHolding(
+	ctx context.Context, optinRepo repo.OptinRepoImply, userRepo repo.UserRepoImply,
+	members repo.ChannelMemberRepoImply, gate *entities.ChannelTokenGate, channelName, address string,
+) {
+	log := utilities.NewLoggerWithFields(
+		"checkSubscriberHolding", map[string]interface{}{
+			"chain":   gate.Chain,
+			"app_id":  gate.AppID,
+			"address": address,
+		},
+	)
+
+	role, err := members.GetChannelRole(ctx, gate.Chain, gate.AppID, address)
+	if err != nil {
+		log.WithError(err).Error("failed to get role")
+		return
+	}
+	if role != "" {
+		return
+	}
+
+	qualified, reason, err := qualifies(ctx, optinRepo, gate, address)
+	if err != nil {
+		log.WithError(err).Error("failed to check holding")
+		return
+	}
+	if qualified {
+		return
+	}
+
+	if err = optinRepo.Optout(ctx, gate.Chain, gate.AppID, address); err != nil {
+		log.WithError(err).Error("failed to opt out")
+		return
+	}
+
+	notifyAccount(
+		ctx, userRepo, gate.Chain, address, gate.AppID, channelName, "Opted out of channel",
+		fmt.Sprintf("You were opted out of the channel %s as %s", channelName, reason),
+	)
+}
+
+// qualifies tells whether address holds what gate requires, and if not why
+func qualifies(
+	ctx context.Context, optinRepo repo.OptinRepoImply, gate *entities.ChannelTokenGate, address string,
+) (bool, string, error) {
+	balance, err := holding(ctx, optinRepo, gate, address)
+	if err != nil {
+		return false, "", err
+	}
+
+	if balance > 0 && balance >= gate.MinBalance {
+		return true, "", nil
+	}
+
+	what := "the token"
+	if gate.Kind == consts.TokenGateNFT {
+		what = "NFTs"
+	}
+	if gate.Asset != "" {
+		what = fmt.Sprintf("%s %s", what, gate.Asset)
+	}
+
+	if gate.MinBalance <= 0 {
+		return false, fmt.Sprintf("you hold none of %s", what), nil
+	}
+
+	return false, fmt.Sprintf(
+		"you hold %s of %s while at least %s are required", formatAmount(balance), what,
+		formatAmount(gate.MinBalance),
+	), nil
+}
+
+// holding returns how much of the token or how many of the NFTs of gate address holds
+func holding(
+	ctx context.Context, optinRepo repo.OptinRepoImply, gate *entities.ChannelTokenGate, address string,
+) (float64, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, holdingCheckTimeout)
+	defer cancel()
+
+	return optinRepo.TokenHolding(checkCtx, gate, address)
+}
+
+// validateTokenGate checks that gate identifies a token the way the chain of the channel does
+func validateTokenGate(gate *entities.ChannelTokenGate) error {
+	if !utilities.ContainsString(consts.TokenGateKinds, gate.Kind) {
+		return fmt.Errorf("invalid kind %s", gate.Kind)
+	}
+	if gate.MinBalance < 0 {
+		return fmt.Errorf("invalid min balance %v", gate.MinBalance)
+	}
+	if gate.Kind == consts.TokenGateNFT && gate.MinBalance < 1 {
+		gate.MinBalance = 1
+	}
+
+	switch gate.Chain {
+	case consts.Algorand:
+		if _, err := strconv.ParseUint(gate.Asset, 10, 64); err != nil {
+			return fmt.Errorf("asset must be the ID of an ASA")
+		}
+		gate.Issuer = ""
+	case consts.Xrpl:
+		if gate.Issuer == "" {
+			return fmt.Errorf("issuer is required")
+		}
+		if gate.Kind == consts.TokenGateFungible && gate.Asset == "" {
+			return fmt.Errorf("asset must be the currency code of the token")
+		}
+	}
+
+	return nil
+}
+
+// formatAmount formats an amount without trailing zeroes
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+// heldTokens holds balance of every token and nfts NFTs of every issuer
+type heldTokens struct {
+	repo.OptinRepoImply
+	balance float64
+	nfts    int
+}
+
+func (h heldTokens) TokenHolding(_ context.Context, gate *entities.ChannelTokenGate, _ string) (float64, error) {
+	if gate.Kind == consts.TokenGateNFT {
+		return float64(h.nfts), nil
+	}
+
+	return h.balance, nil
+}
+
+func Test_qualifies(t *testing.T) {
+	tests := []struct {
+		name       string
+		gate       entities.ChannelTokenGate
+		held       heldTokens
+		want       bool
+		wantReason string
+	}{
+		{
+			name: "holds the token",
+			gate: entities.ChannelTokenGate{Kind: consts.TokenGateFungible, Asset: "31566704"},
+			held: heldTokens{balance: 0.5},
+			want: true,
+		},
+		{
+			name:       "holds none of the token",
+			gate:       entities.ChannelTokenGate{Kind: consts.TokenGateFungible, Asset: "31566704"},
+			wantReason: "you hold none of the token 31566704",
+		},
+		{
+			name:       "holds less than the min balance",
+			gate:       entities.ChannelTokenGate{Kind: consts.TokenGateFungible, Asset: "31566704", MinBalance: 100},
+			held:       heldTokens{balance: 12.5},
+			wantReason: "you hold 12.5 of the token 31566704 while at least 100 are required",
+		},
+		{
+			name: "holds the min balance",
+			gate: entities.ChannelTokenGate{Kind: consts.TokenGateFungible, Asset: "31566704", MinBalance: 100},
+			held: heldTokens{balance: 100},
+			want: true,
+		},
+		{
+			name:       "holds too few NFTs",
+			gate:       entities.ChannelTokenGate{Kind: consts.TokenGateNFT, MinBalance: 2},
+			held:       heldTokens{nfts: 1},
+			wantReason: "you hold 1 of NFTs while at least 2 are required",
+		},
+		{
+			name: "holds the NFTs",
+			gate: entities.ChannelTokenGate{Kind: consts.TokenGateNFT, MinBalance: 2},
+			held: heldTokens{nfts: 3},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tt.gate.Chain = consts.Algorand
+
+				got, reason, err := qualifies(context.Background(), tt.held, &tt.gate, "address")
+				if err != nil {
+					t.Fatalf("qualifies() error = %v", err)
+				}
+				if got != tt.want || reason != tt.wantReason {
+					t.Errorf("qualifies() = %v, %q, want %v, %q", got, reason, tt.want, tt.wantReason)
+				}
+			},
+		)
+	}
+}
+
+func Test_validateTokenGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    entities.ChannelTokenGate
+		want    entities.ChannelTokenGate
+		wantErr bool
+	}{
+		{
+			name: "ASA",
+			gate: entities.ChannelTokenGate{
+				Chain: consts.Algorand, Kind: consts.TokenGateFungible, Asset: "31566704", Issuer: "ignored",
+			},
+			want: entities.ChannelTokenGate{Chain: consts.Algorand, Kind: consts.TokenGateFungible, Asset: "31566704"},
+		},
+		{
+			name:    "ASA without ID",
+			gate:    entities.ChannelTokenGate{Chain: consts.Algorand, Kind: consts.TokenGateFungible, Asset: "USDC"},
+			wantErr: true,
+		},
+		{
+			name: "NFT requires one at least",
+			gate: entities.ChannelTokenGate{Chain: consts.Algorand, Kind: consts.TokenGateNFT, Asset: "1"},
+			want: entities.ChannelTokenGate{
+				Chain: consts.Algorand, Kind: consts.TokenGateNFT, Asset: "1", MinBalance: 1,
+			},
+		},
+		{
+			name:    "unknown kind",
+			gate:    entities.ChannelTokenGate{Chain: consts.Algorand, Kind: "coin", Asset: "1"},
+			wantErr: true,
+		},
+		{
+			name: "negative min balance",
+			gate: entities.ChannelTokenGate{
+				Chain: consts.Algorand, Kind: consts.TokenGateFungible, Asset: "1", MinBalance: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "issued currency",
+			gate: entities.ChannelTokenGate{
+				Chain: consts.Xrpl, Kind: consts.TokenGateFungible, Asset: "USD", Issuer: "rIssuer",
+			},
+			want: entities.ChannelTokenGate{
+				Chain: consts.Xrpl, Kind: consts.TokenGateFungible, Asset: "USD", Issuer: "rIssuer",
+			},
+		},
+		{
+			name:    "issued currency without issuer",
+			gate:    entities.ChannelTokenGate{Chain: consts.Xrpl, Kind: consts.TokenGateFungible, Asset: "USD"},
+			wantErr: true,
+		},
+		{
+			name:    "issued currency without code",
+			gate:    entities.ChannelTokenGate{Chain: consts.Xrpl, Kind: consts.TokenGateFungible, Issuer: "rIssuer"},
+			wantErr: true,
+		},
+		{
+			name: "NFTs of any taxon",
+			gate: entities.ChannelTokenGate{Chain: consts.Xrpl, Kind: consts.TokenGateNFT, Issuer: "rIssuer"},
+			want: entities.ChannelTokenGate{
+				Chain: consts.Xrpl, Kind: consts.TokenGateNFT, Issuer: "rIssuer", MinBalance: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				gate := tt.gate
+				err := validateTokenGate(&gate)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("validateTokenGate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && gate != tt.want {
+					t.Errorf("validateTokenGate() = %+v, want %+v", gate, tt.want)
+				}
+			},
+		)
+	}
+}
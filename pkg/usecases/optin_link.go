@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+)
+
+// sourcePattern is what the campaign or referral source of an opt-in looks like once normalised
+var sourcePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// OptinLink builds the link users follow to opt in to the channel, attributing their opt-ins to source. A private
+// channel is opted in to right away with the link of an invite.
+func (Optin *OptinUseCases) OptinLink(
+	ctx context.Context, chain, appID, source, invite string,
+) (*entities.OptinLink, error) {
+	source, err := optinSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// tells whether the channel exists
+	if _, err = Optin.repo.GetChannelVisibility(ctx, chain, appID); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"source": {source}}
+	if invite != "" {
+		query.Set("invite", invite)
+	}
+
+	link, err := optinLink(chain, appID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build optin link: %w", err)
+	}
+
+	return &entities.OptinLink{
+		Chain:  chain,
+		AppID:  appID,
+		Source: source,
+		Invite: invite,
+		Link:   link,
+	}, nil
+}
+
+// OptinQRCode renders the link users follow to opt in to the channel as a PNG QR code of size pixels a side.
+func (Optin *OptinUseCases) OptinQRCode(
+	ctx context.Context, chain, appID, source, invite string, size int,
+) ([]byte, error) {
+	if size == 0 {
+		size = consts.OptinQRDefaultSize
+	}
+	if size < consts.OptinQRMinSize || size > consts.OptinQRMaxSize {
+		return nil, fmt.Errorf(
+			"size must be between %d and %d pixels", consts.OptinQRMinSize, consts.OptinQRMaxSize,
+		)
+	}
+
+	link, err := Optin.OptinLink(ctx, chain, appID, source, invite)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := qrcode.Encode(link.Link, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	return png, nil
+}
+
+// optinLink builds the link to the page of the channel users opt in from, with query
+func optinLink(chain, appID string, query url.Values) (string, error) {
+	link, err := url.JoinPath(config.GetConfig().Server.RedirectPrefix, "chains", chain, "channels", appID)
+	if err != nil {
+		return "", err
+	}
+
+	return link + "?" + query.Encode(), nil
+}
+
+// optinSource normalises the campaign or referral source of an opt-in, opt-ins without one are direct
+func optinSource(source string) (string, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		return consts.OptinSourceDirect, nil
+	}
+
+	if !sourcePattern.MatchString(source) {
+		return "", fmt.Errorf("invalid source %q", source)
+	}
+
+	return source, nil
+}
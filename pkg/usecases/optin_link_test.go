@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/repo"
+)
+
+// visibleChannels is a repo where every channel is public
+type visibleChannels struct {
+	repo.OptinRepoImply
+}
+
+func (visibleChannels) GetChannelVisibility(context.Context, string, string) (string, error) {
+	return consts.ChannelPublic, nil
+}
+
+func Test_optinSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{name: "none is direct", want: consts.OptinSourceDirect},
+		{name: "blank is direct", source: "   ", want: consts.OptinSourceDirect},
+		{name: "normalised", source: " Twitter_Spring.2024 ", want: "twitter_spring.2024"},
+		{name: "leading punctuation", source: "-twitter", wantErr: true},
+		{name: "spaces", source: "spring sale", wantErr: true},
+		{name: "query characters", source: "twitter&invite=x", wantErr: true},
+		{name: "too long", source: string(bytes.Repeat([]byte("a"), 65)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := optinSource(tt.source)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("optinSource() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("optinSource() = %q, want %q", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestOptinUseCases_OptinQRCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		wantSize int
+		wantErr  bool
+	}{
+		{name: "default", wantSize: consts.OptinQRDefaultSize},
+		{name: "smallest", size: consts.OptinQRMinSize, wantSize: consts.OptinQRMinSize},
+		{name: "largest", size: consts.OptinQRMaxSize, wantSize: consts.OptinQRMaxSize},
+		{name: "too small", size: consts.OptinQRMinSize - 1, wantErr: true},
+		{name: "too large", size: consts.OptinQRMaxSize + 1, wantErr: true},
+		{name: "negative", size: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				optin := &OptinUseCases{repo: visibleChannels{}}
+
+				got, err := optin.OptinQRCode(context.Background(), consts.Algorand, "1", "twitter", "", tt.size)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("OptinQRCode() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+
+				img, err := png.Decode(bytes.NewReader(got))
+				if err != nil {
+					t.Fatalf("OptinQRCode() is not a PNG: %v", err)
+				}
+				if bounds := img.Bounds(); bounds.Dx() != tt.wantSize || bounds.Dy() != tt.wantSize {
+					t.Errorf("OptinQRCode() is %dx%d, want %d a side", bounds.Dx(), bounds.Dy(), tt.wantSize)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,246 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+)
+
+type ChannelTransferUseCases struct {
+	repo        repo.ChannelTransferRepoImply
+	channelRepo repo.ChannelRepoImpl
+	members     repo.ChannelMemberRepoImply
+	userRepo    repo.UserRepoImply
+	useCases    UseCaseImply
+}
+
+type ChannelTransferUseCaseImply interface {
+	InitiateTransfer(ctx context.Context, chain, appID, user string, request entities.ChannelTransferRequest) (*entities.ChannelTransfer, error)
+	AcceptTransfer(ctx context.Context, chain, appID, user string, request entities.AcceptChannelTransferRequest) error
+	CancelTransfer(ctx context.Context, chain, appID, user string) error
+	GetTransfer(ctx context.Context, chain, appID, user string) (*entities.ChannelTransfer, error)
+	ListIncomingTransfers(ctx context.Context, chain, user string) ([]entities.ChannelTransfer, error)
+	RequestAdoption(ctx context.Context, chain, appID, user string, request entities.ChannelAdoptionRequest) (*entities.ChannelTransfer, error)
+	ListAdoptions(ctx context.Context, chain string) ([]entities.ChannelTransfer, error)
+	ApproveAdoption(ctx context.Context, chain, appID string) error
+	RejectAdoption(ctx context.Context, chain, appID string) error
+}
+
+// NewChannelTransferUseCases
+func NewChannelTransferUseCases(
+	transferRepo repo.ChannelTransferRepoImply, channelRepo repo.ChannelRepoImpl,
+	members repo.ChannelMemberRepoImply, userRepo repo.UserRepoImply, useCases UseCaseImply,
+) ChannelTransferUseCaseImply {
+	return &ChannelTransferUseCases{
+		repo:        transferRepo,
+		channelRepo: channelRepo,
+		members:     members,
+		userRepo:    userRepo,
+		useCases:    useCases,
+	}
+}
+
+// InitiateTransfer offers the channel to another user, the channel changes hands once that user accepts.
+func (tuc *ChannelTransferUseCases) InitiateTransfer(
+	ctx context.Context, chain, appID, user string, request entities.ChannelTransferRequest,
+) (*entities.ChannelTransfer, error) {
+	if err := verifyChannelPermission(ctx, tuc.members, chain, appID, user, consts.ChannelPermTransfer); err != nil {
+		return nil, err
+	}
+
+	// the channel is transferred from its owner, whoever of the members with the permission offers it
+	owner, err := tuc.members.GetChannelOwner(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Address == owner {
+		return nil, fmt.Errorf("the channel is already owned by %s", owner)
+	}
+
+	onboarded, err := tuc.userRepo.IsUserOnboarded(ctx, chain, request.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !onboarded {
+		return nil, fmt.Errorf("user %s is not onboarded", request.Address)
+	}
+
+	if err = tuc.verifyChannelCapacity(ctx, chain, request.Address); err != nil {
+		return nil, err
+	}
+
+	transfer := &entities.ChannelTransfer{
+		Chain:   chain,
+		AppID:   appID,
+		Kind:    consts.ChannelTransfer,
+		From:    owner,
+		To:      request.Address,
+		Created: utilities.TimeNow(),
+	}
+
+	if err = tuc.repo.CreateTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// AcceptTransfer makes the user the owner of the channel offered to it. The wallet of the user signs a login
+// challenge issued for it to confirm.
+func (tuc *ChannelTransferUseCases) AcceptTransfer(
+	ctx context.Context, chain, appID, user string, request entities.AcceptChannelTransferRequest,
+) error {
+	log := utilities.NewLogger("AcceptTransfer")
+
+	transfer, err := tuc.repo.GetTransfer(ctx, chain, appID)
+	if err != nil {
+		return err
+	}
+	if transfer.Kind != consts.ChannelTransfer || transfer.To != user {
+		return fmt.Errorf("channel %s is not offered to you", appID)
+	}
+
+	err = tuc.useCases.VerifyLoginChallenge(ctx, chain, user, request.Nonce, request.Signature, request.PublicKey)
+	if err != nil {
+		log.WithError(err).Errorf("challenge verification failed for %s", user)
+		return errors.New("failed to verify the signature of the new owner")
+	}
+
+	// the owner might have changed since the transfer was offered
+	owner, err := tuc.members.GetChannelOwner(ctx, chain, appID)
+	if err != nil {
+		return err
+	}
+	if owner != transfer.From {
+		_ = tuc.repo.DeleteTransfer(ctx, transfer)
+		return fmt.Errorf("channel %s is no longer owned by %s", appID, transfer.From)
+	}
+
+	if err = tuc.verifyChannelCapacity(ctx, chain, user); err != nil {
+		return err
+	}
+
+	return tuc.repo.CompleteTransfer(ctx, transfer)
+}
+
+// CancelTransfer withdraws the pending change of owner of the channel. The owner withdraws a transfer, the
+// user it is offered to declines it, an adoption is withdrawn by the user who requested it.
+func (tuc *ChannelTransferUseCases) CancelTransfer(ctx context.Context, chain, appID, user string) error {
+	transfer, err := tuc.GetTransfer(ctx, chain, appID, user)
+	if err != nil {
+		return err
+	}
+	if transfer.Kind == consts.ChannelAdoption && transfer.To != user {
+		return fmt.Errorf("the adoption of channel %s was not requested by you", appID)
+	}
+
+	return tuc.repo.DeleteTransfer(ctx, transfer)
+}
+
+// GetTransfer retrieves the pending change of owner of the channel, it is visible to both sides.
+func (tuc *ChannelTransferUseCases) GetTransfer(
+	ctx context.Context, chain, appID, user string,
+) (*entities.ChannelTransfer, error) {
+	transfer, err := tuc.repo.GetTransfer(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.From != user && transfer.To != user {
+		return nil, fmt.Errorf("no change of the owner of channel %s involves you", appID)
+	}
+
+	return transfer, nil
+}
+
+// ListIncomingTransfers retrieves the channels offered to the user.
+func (tuc *ChannelTransferUseCases) ListIncomingTransfers(
+	ctx context.Context, chain, user string,
+) ([]entities.ChannelTransfer, error) {
+	return tuc.repo.ListTransfers(ctx, chain, consts.ChannelTransfer, user)
+}
+
+// RequestAdoption asks to become the owner of an orphaned channel, an admin approves or rejects the request.
+func (tuc *ChannelTransferUseCases) RequestAdoption(
+	ctx context.Context, chain, appID, user string, request entities.ChannelAdoptionRequest,
+) (*entities.ChannelTransfer, error) {
+	channelInfo, err := tuc.channelRepo.GetChannel(ctx, chain, appID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	channelData := channelInfo.Data.(entities.ChannelModel)
+	if channelData.Status != consts.STATUS_CHANNEL_ORPHANED {
+		return nil, fmt.Errorf("channel %s is not orphaned", appID)
+	}
+
+	if err = tuc.verifyChannelCapacity(ctx, chain, user); err != nil {
+		return nil, err
+	}
+
+	transfer := &entities.ChannelTransfer{
+		Chain:   chain,
+		AppID:   appID,
+		Kind:    consts.ChannelAdoption,
+		From:    channelData.Owner,
+		To:      user,
+		Reason:  request.Reason,
+		Created: utilities.TimeNow(),
+	}
+
+	if err = tuc.repo.CreateTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// ListAdoptions retrieves the adoptions of orphaned channels waiting for an admin.
+func (tuc *ChannelTransferUseCases) ListAdoptions(ctx context.Context, chain string) ([]entities.ChannelTransfer, error) {
+	return tuc.repo.ListTransfers(ctx, chain, consts.ChannelAdoption, "")
+}
+
+// ApproveAdoption makes the user who requested the adoption of the orphaned channel its owner.
+func (tuc *ChannelTransferUseCases) ApproveAdoption(ctx context.Context, chain, appID string) error {
+	transfer, err := tuc.repo.GetTransfer(ctx, chain, appID)
+	if err != nil {
+		return err
+	}
+	if transfer.Kind != consts.ChannelAdoption {
+		return fmt.Errorf("no adoption of channel %s is pending", appID)
+	}
+
+	if err = tuc.verifyChannelCapacity(ctx, chain, transfer.To); err != nil {
+		return err
+	}
+
+	return tuc.repo.CompleteTransfer(ctx, transfer)
+}
+
+// RejectAdoption rejects the adoption of the orphaned channel.
+func (tuc *ChannelTransferUseCases) RejectAdoption(ctx context.Context, chain, appID string) error {
+	transfer, err := tuc.repo.GetTransfer(ctx, chain, appID)
+	if err != nil {
+		return err
+	}
+	if transfer.Kind != consts.ChannelAdoption {
+		return fmt.Errorf("no adoption of channel %s is pending", appID)
+	}
+
+	return tuc.repo.DeleteTransfer(ctx, transfer)
+}
+
+// verifyChannelCapacity returns an error unless the membership of address allows it to own one more channel.
+// It rejects a change of owner early, CompleteTransfer checks again as it adds the channel.
+func (tuc *ChannelTransferUseCases) verifyChannelCapacity(ctx context.Context, chain, address string) error {
+	userModel, err := tuc.userRepo.GetUserModel(ctx, chain, address)
+	if err != nil {
+		return fmt.Errorf("getting user model failed: %w", err)
+	}
+
+	return userModel.VerifyChannelCapacity()
+}
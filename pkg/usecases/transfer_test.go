@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+type createdTransfers struct {
+	repo.ChannelTransferRepoImply
+	created []*entities.ChannelTransfer
+}
+
+func (c *createdTransfers) CreateTransfer(_ context.Context, transfer *entities.ChannelTransfer) error {
+	c.created = append(c.created, transfer)
+	return nil
+}
+
+func TestChannelTransferUseCases_InitiateTransfer(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{"owner": consts.ChannelRoleOwner, "admin": consts.ChannelRoleAdmin},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		to      string
+		wantErr bool
+	}{
+		{
+			name: "owner offers the channel",
+			user: "owner",
+			to:   "bob",
+		},
+		{
+			name:    "offered to the owner",
+			user:    "owner",
+			to:      "owner",
+			wantErr: true,
+		},
+		{
+			name:    "member without the permission",
+			user:    "admin",
+			to:      "bob",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				transfers := &createdTransfers{}
+				tuc := &ChannelTransferUseCases{repo: transfers, members: members, userRepo: fakeUserRepo{}}
+
+				transfer, err := tuc.InitiateTransfer(
+					context.Background(), "algorand", "1", tt.user, entities.ChannelTransferRequest{Address: tt.to},
+				)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("InitiateTransfer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					if len(transfers.created) != 0 {
+						t.Errorf("InitiateTransfer() stored %v", transfers.created)
+					}
+					return
+				}
+				if transfer.From != "owner" || transfer.To != tt.to || len(transfers.created) != 1 {
+					t.Errorf("InitiateTransfer() = %+v, stored %d", transfer, len(transfers.created))
+				}
+			},
+		)
+	}
+}
@@ -2,8 +2,17 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	uuidLib "github.com/google/uuid"
+
+	"notiboy/config"
+	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
+	chainDriver "notiboy/pkg/repo/driver/chain"
+	"notiboy/utilities"
 )
 
 type UseCases struct {
@@ -12,6 +21,8 @@ type UseCases struct {
 type UseCaseImply interface {
 	DBHealthHandler(context.Context) error
 	VerifyUserOnboarded(context.Context, any, string) error
+	CreateLoginChallenge(ctx context.Context, chain, address string) (*entities.LoginChallenge, error)
+	VerifyLoginChallenge(ctx context.Context, chain, address, nonce, signature, publicKey string) error
 }
 
 func NewUseCases(repo repo.Imply) UseCaseImply {
@@ -29,3 +40,50 @@ func (usecase *UseCases) DBHealthHandler(ctx context.Context) error {
 func (usecase *UseCases) VerifyUserOnboarded(ctx context.Context, address any, chain string) error {
 	return usecase.repo.VerifyUserOnboarded(ctx, address, chain)
 }
+
+// CreateLoginChallenge issues a one-time sign-in message for the wallet of address to sign.
+func (usecase *UseCases) CreateLoginChallenge(
+	ctx context.Context, chain, address string,
+) (*entities.LoginChallenge, error) {
+	conf := config.GetConfig()
+
+	now := utilities.TimeNow()
+	challenge := &entities.LoginChallenge{
+		Chain:     chain,
+		Address:   address,
+		Nonce:     uuidLib.NewString(),
+		ExpiresAt: now.Add(time.Duration(conf.TTL.LoginNonce) * time.Second),
+	}
+
+	challenge.Message = fmt.Sprintf(
+		"%s wants you to sign in with your %s account:\n%s\n\nSign in to Notiboy\n\n"+
+			"URI: %s\nChain: %s\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		conf.Server.Host, chain, address, conf.Server.RedirectPrefix, chain, challenge.Nonce,
+		now.Format(time.RFC3339), challenge.ExpiresAt.Format(time.RFC3339),
+	)
+
+	if err := usecase.repo.InsertLoginChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// VerifyLoginChallenge burns the challenge of nonce and verifies that the wallet of address signed its message.
+// A nonce is burnt even if the signature turns out to be invalid, so every challenge can be tried only once.
+func (usecase *UseCases) VerifyLoginChallenge(
+	ctx context.Context, chain, address, nonce, signature, publicKey string,
+) error {
+	challenge, err := usecase.repo.ConsumeLoginChallenge(ctx, chain, address, nonce)
+	if err != nil {
+		return err
+	}
+
+	if utilities.TimeNow().After(challenge.ExpiresAt) {
+		return errors.New("nonce expired")
+	}
+
+	return chainDriver.GetBlockchainClient(chain).VerifySignedMessage(
+		ctx, address, []byte(challenge.Message), signature, publicKey,
+	)
+}
@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/medium"
+)
+
+func TestMain(m *testing.M) {
+	config.SetConfig(
+		&config.NotiboyConfModel{
+			Mode: "local",
+			Server: config.Server{
+				RedirectPrefix: "https://app.notiboy.com",
+			},
+			Chain: config.Chain{
+				Supported: []string{consts.Algorand, consts.Xrpl},
+			},
+			Tracking: config.Tracking{
+				Secret: "0123456789abcdef0123456789abcdef",
+			},
+		},
+	)
+
+	os.Exit(m.Run())
+}
+
+// channelMembers gives every channel the members of roles, by address, the member with the owner role owns it
+type channelMembers struct {
+	repo.ChannelMemberRepoImply
+	roles map[string]string
+}
+
+func (m channelMembers) GetChannelRole(_ context.Context, _, _, address string) (string, error) {
+	return m.roles[address], nil
+}
+
+func (m channelMembers) GetChannelOwner(_ context.Context, _, appID string) (string, error) {
+	for address, role := range m.roles {
+		if role == consts.ChannelRoleOwner {
+			return address, nil
+		}
+	}
+
+	return "", fmt.Errorf("channel %s has no owner", appID)
+}
+
+// fakeUserRepo has every user onboarded with the membership and channels, without FCM tokens
+type fakeUserRepo struct {
+	repo.UserRepoImply
+	membership string
+	channels   []string
+}
+
+func (f fakeUserRepo) GetUserModel(_ context.Context, chain, address string) (*entities.UserModel, error) {
+	return &entities.UserModel{
+		UserIdentifier: entities.UserIdentifier{Chain: chain, Address: address},
+		Membership:     f.membership,
+		Channels:       f.channels,
+	}, nil
+}
+
+func (fakeUserRepo) IsUserOnboarded(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
+func (fakeUserRepo) GetFCMTokens(context.Context, entities.UserIdentifier) ([]string, error) {
+	return nil, nil
+}
+
+// setMode switches the configured mode for the duration of the test
+func setMode(t *testing.T, mode string) {
+	t.Helper()
+
+	previous := config.GetConfig().Mode
+	config.GetConfig().Mode = mode
+	t.Cleanup(
+		func() {
+			config.GetConfig().Mode = previous
+		},
+	)
+}
+
+// newTestSocket returns a websocket hub without connections, pushing to it fails as if nobody was connected
+func newTestSocket() *medium.Socket {
+	return medium.NewWebSocket(false)
+}
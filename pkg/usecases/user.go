@@ -3,13 +3,22 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	uuidLib "github.com/google/uuid"
+	"github.com/spf13/cast"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
+	"notiboy/utilities"
 )
 
 type UserUseCases struct {
-	repo repo.UserRepoImply
+	repo         repo.UserRepoImply
+	deletionRepo repo.DeletionRepoImply
 }
 
 type UserUseCaseImply interface {
@@ -19,18 +28,26 @@ type UserUseCaseImply interface {
 	GlobalStatistics(context.Context) ([]entities.GlobalStatistics, error)
 	UserStatistics(ctx context.Context, chain, statType, startDate, endDate string) ([]entities.UserActivity, error)
 	GetUser(context.Context, entities.UserIdentifier) (*entities.Response, error)
-	Login(context.Context, entities.UserIdentifier) (string, error)
+	Login(context.Context, entities.UserIdentifier, entities.UserSession) (*entities.SessionTokens, error)
 	Logout(context.Context, entities.UserIdentifier) error
-	GeneratePAT(context.Context, string, string, string) (string, error)
+	RefreshSession(context.Context, entities.UserIdentifier, string, entities.UserSession) (*entities.SessionTokens, error)
+	ListSessions(context.Context, entities.UserIdentifier) ([]entities.UserSession, error)
+	RevokeSession(context.Context, entities.UserIdentifier, string) error
+	RevokeOtherSessions(context.Context, entities.UserIdentifier) error
+	GeneratePAT(context.Context, string, string, string, []string, int) (string, error)
+	RotatePAT(context.Context, string, string, time.Duration) (string, error)
 	GetPAT(context.Context, string) ([]entities.PATTokens, error)
 	RevokePAT(context.Context, string, string) error
 	StoreFCMToken(ctx context.Context, fcm entities.FCM) error
+	EraseAccount(context.Context, entities.UserIdentifier) (*entities.AccountDeletion, error)
+	GetAccountDeletion(context.Context, entities.UserIdentifier, string) (*entities.AccountDeletion, error)
 }
 
 // NewUserUseCases
-func NewUserUseCases(userRepo repo.UserRepoImply) UserUseCaseImply {
+func NewUserUseCases(userRepo repo.UserRepoImply, deletionRepo repo.DeletionRepoImply) UserUseCaseImply {
 	return &UserUseCases{
-		repo: userRepo,
+		repo:         userRepo,
+		deletionRepo: deletionRepo,
 	}
 }
 
@@ -49,6 +66,52 @@ func (user *UserUseCases) Offboarding(ctx context.Context, address string, chain
 	return user.repo.Offboarding(ctx, address, chain)
 }
 
+// EraseAccount schedules the erasure of all the data of the user's account. The account is locked right
+// away, the data is deleted in the background by AccountDeletionWorker.
+func (user *UserUseCases) EraseAccount(
+	ctx context.Context, identifier entities.UserIdentifier,
+) (*entities.AccountDeletion, error) {
+	userModel, err := user.repo.GetUserModel(ctx, identifier.Chain, identifier.Address)
+	if err != nil {
+		return nil, err
+	}
+	if userModel.Status == consts.STATUS_DELETING {
+		return nil, fmt.Errorf("account is already being deleted")
+	}
+
+	now := utilities.TimeNow()
+	job := &entities.AccountDeletion{
+		ID:             uuidLib.NewString(),
+		UserIdentifier: identifier,
+		Status:         consts.DeletionPending,
+		Requested:      now,
+		Updated:        now,
+		LeaseUntil:     now,
+	}
+
+	if err = user.deletionRepo.CreateDeletionJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return withDeletionProgress(job), nil
+}
+
+// GetAccountDeletion retrieves the progress of an account deletion of identifier. The deletion of another
+// account is reported as not found.
+func (user *UserUseCases) GetAccountDeletion(
+	ctx context.Context, identifier entities.UserIdentifier, id string,
+) (*entities.AccountDeletion, error) {
+	job, err := user.deletionRepo.GetDeletionJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserIdentifier != identifier {
+		return nil, fmt.Errorf("deletion job %s not found", id)
+	}
+
+	return withDeletionProgress(job), nil
+}
+
 // GlobalStatistics retrieves global statistics from the repository.
 func (user *UserUseCases) GlobalStatistics(ctx context.Context) ([]entities.GlobalStatistics, error) {
 	data, err := user.repo.GlobalStatistics(ctx)
@@ -77,9 +140,11 @@ func (user *UserUseCases) GetUser(ctx context.Context, request entities.UserIden
 	return user.repo.GetUser(ctx, request)
 }
 
-// Login performs user login based on the provided login request.
-func (user *UserUseCases) Login(ctx context.Context, request entities.UserIdentifier) (string, error) {
-	return user.repo.Login(ctx, request)
+// Login performs user login based on the provided login request, starting a new session for the client.
+func (user *UserUseCases) Login(
+	ctx context.Context, request entities.UserIdentifier, client entities.UserSession,
+) (*entities.SessionTokens, error) {
+	return user.repo.Login(ctx, request, client)
 }
 
 // Logout performs user logout based on the provided logout request.
@@ -87,8 +152,121 @@ func (user *UserUseCases) Logout(ctx context.Context, request entities.UserIdent
 	return user.repo.Logout(ctx, request)
 }
 
-func (user *UserUseCases) GeneratePAT(ctx context.Context, name, kind, description string) (string, error) {
-	return user.repo.GeneratePAT(ctx, name, kind, description)
+// RefreshSession issues a new access token for a session and rotates its refresh token.
+func (user *UserUseCases) RefreshSession(
+	ctx context.Context, request entities.UserIdentifier, refreshToken string, client entities.UserSession,
+) (*entities.SessionTokens, error) {
+	return user.repo.RefreshSession(ctx, request, refreshToken, client)
+}
+
+// ListSessions lists the active sessions of a user, marking the one the request was made with.
+func (user *UserUseCases) ListSessions(
+	ctx context.Context, request entities.UserIdentifier,
+) ([]entities.UserSession, error) {
+	sessions, err := user.repo.ListSessions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	current := cast.ToString(ctx.Value(consts.UserSession))
+	for i := range sessions {
+		sessions[i].Current = current != "" && sessions[i].SessionID == current
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a user out of one of their sessions.
+func (user *UserUseCases) RevokeSession(ctx context.Context, request entities.UserIdentifier, sessionID string) error {
+	sessions, err := user.repo.ListSessions(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.SessionID == sessionID {
+			return user.repo.RevokeSession(ctx, request, sessionID)
+		}
+	}
+
+	return fmt.Errorf("session %s not found", sessionID)
+}
+
+// RevokeOtherSessions signs a user out everywhere except the session the request was made with.
+func (user *UserUseCases) RevokeOtherSessions(ctx context.Context, request entities.UserIdentifier) error {
+	return user.repo.RevokeOtherSessions(ctx, request, cast.ToString(ctx.Value(consts.UserSession)))
+}
+
+// GeneratePAT issues a personal access token, restricted to scopes when any are given.
+// A scope narrowed down to a channel is only accepted for channels owned by the user.
+// The token expires after expiryDays, or after the longest allowed lifetime when it is zero.
+func (user *UserUseCases) GeneratePAT(
+	ctx context.Context, name, kind, description string, scopes []string, expiryDays int,
+) (string, error) {
+	// the mobile token is shared by the devices of the user, it can't be narrowed
+	if kind != "normal" && (len(scopes) != 0 || expiryDays != 0) {
+		return "", fmt.Errorf("scopes and expiry are only supported for normal tokens")
+	}
+
+	ttl := cast.ToDuration(config.GetConfig().TTL.PAToken)
+	if expiryDays < 0 {
+		return "", fmt.Errorf("expiry must be a positive number of days")
+	}
+	if expiryDays > 0 {
+		expiry := time.Duration(expiryDays) * 24 * time.Hour
+		if expiry > ttl {
+			return "", fmt.Errorf("expiry can be at most %d days", int(ttl.Hours()/24))
+		}
+		ttl = expiry
+	}
+
+	if len(scopes) != 0 {
+		if err := user.validatePATScopes(ctx, scopes); err != nil {
+			return "", err
+		}
+	}
+
+	return user.repo.GeneratePAT(ctx, name, kind, description, scopes, ttl)
+}
+
+// RotatePAT replaces a personal access token, the old one keeps working for the grace period.
+func (user *UserUseCases) RotatePAT(ctx context.Context, id, kind string, grace time.Duration) (string, error) {
+	if kind != "normal" {
+		return "", fmt.Errorf("only normal tokens can be rotated")
+	}
+
+	return user.repo.RotatePAT(ctx, id, kind, grace)
+}
+
+func (user *UserUseCases) validatePATScopes(ctx context.Context, scopes []string) error {
+	var userModel *entities.UserModel
+
+	for _, scope := range scopes {
+		if utilities.ContainsString(consts.PATScopes, scope) {
+			continue
+		}
+
+		idx := strings.LastIndex(scope, ":")
+		if idx < 0 || !utilities.ContainsString(consts.ChannelScopes, scope[:idx]) {
+			return fmt.Errorf("invalid scope %s", scope)
+		}
+
+		if userModel == nil {
+			var err error
+			userModel, err = user.repo.GetUserModel(
+				ctx, cast.ToString(ctx.Value(consts.UserChain)), cast.ToString(ctx.Value(consts.UserAddress)),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to get user: %w", err)
+			}
+		}
+
+		if !utilities.ContainsString(userModel.Channels, scope[idx+1:]) {
+			return fmt.Errorf("scope %s is for a channel you don't own", scope)
+		}
+	}
+
+	return nil
 }
 
 func (user *UserUseCases) GetPAT(ctx context.Context, kind string) ([]entities.PATTokens, error) {
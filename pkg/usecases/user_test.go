@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+func TestUserUseCases_validatePATScopes(t *testing.T) {
+	usecase := &UserUseCases{repo: fakeUserRepo{channels: []string{"owned"}}}
+
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{
+			name:   "scopes",
+			scopes: []string{consts.ScopeNotificationsRead, consts.ScopeBillingRead},
+		},
+		{
+			name:   "scope of an owned channel",
+			scopes: []string{consts.ScopeNotificationsSend + ":owned"},
+		},
+		{
+			name:    "scope of another channel",
+			scopes:  []string{consts.ScopeNotificationsSend + ":other"},
+			wantErr: true,
+		},
+		{
+			name:    "scope that can't be narrowed to a channel",
+			scopes:  []string{consts.ScopeBillingWrite + ":owned"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown scope",
+			scopes:  []string{"channels:delete"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+				ctx.Set(consts.UserChain, consts.Algorand)
+				ctx.Set(consts.UserAddress, "owner")
+
+				if err := usecase.validatePATScopes(ctx, tt.scopes); (err != nil) != tt.wantErr {
+					t.Errorf("validatePATScopes() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+type deletionJobs struct {
+	repo.DeletionRepoImply
+	jobs map[string]entities.AccountDeletion
+}
+
+func (d deletionJobs) GetDeletionJob(_ context.Context, id string) (*entities.AccountDeletion, error) {
+	job, ok := d.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("deletion job %s not found", id)
+	}
+
+	return &job, nil
+}
+
+func TestUserUseCases_GetAccountDeletion(t *testing.T) {
+	alice := entities.UserIdentifier{Chain: "algorand", Address: "alice"}
+	usecase := &UserUseCases{
+		deletionRepo: deletionJobs{
+			jobs: map[string]entities.AccountDeletion{
+				"1": {ID: "1", UserIdentifier: alice, Status: consts.DeletionPending, StepsDone: 1},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		user    entities.UserIdentifier
+		id      string
+		wantErr bool
+	}{
+		{
+			name: "deletion of the user",
+			user: alice,
+			id:   "1",
+		},
+		{
+			name:    "deletion of another user",
+			user:    entities.UserIdentifier{Chain: "algorand", Address: "bob"},
+			id:      "1",
+			wantErr: true,
+		},
+		{
+			name:    "deletion of the address on another chain",
+			user:    entities.UserIdentifier{Chain: "xrpl", Address: "alice"},
+			id:      "1",
+			wantErr: true,
+		},
+		{
+			name:    "unknown deletion",
+			user:    alice,
+			id:      "2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				job, err := usecase.GetAccountDeletion(context.Background(), tt.user, tt.id)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GetAccountDeletion() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if job.StepsTotal != len(consts.AccountDeletionSteps) ||
+					job.CurrentStep != consts.AccountDeletionSteps[1] {
+					t.Errorf("GetAccountDeletion() progress = %d/%d %s", job.StepsDone, job.StepsTotal, job.CurrentStep)
+				}
+			},
+		)
+	}
+}
+
+// issuedPATs records the kinds of the tokens generated
+type issuedPATs struct {
+	repo.UserRepoImply
+	kinds []string
+}
+
+func (i *issuedPATs) GeneratePAT(
+	_ context.Context, _, kind, _ string, _ []string, _ time.Duration,
+) (string, error) {
+	i.kinds = append(i.kinds, kind)
+
+	return "token", nil
+}
+
+func TestUserUseCases_GeneratePAT(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       string
+		scopes     []string
+		expiryDays int
+		wantErr    bool
+	}{
+		{
+			name: "normal token",
+			kind: "normal",
+		},
+		{
+			name: "mobile token",
+			kind: "mobile",
+		},
+		{
+			name:    "scoped mobile token",
+			kind:    "mobile",
+			scopes:  []string{consts.ScopeNotificationsRead},
+			wantErr: true,
+		},
+		{
+			name:       "expiring mobile token",
+			kind:       "mobile",
+			expiryDays: 7,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				issued := &issuedPATs{}
+				usecase := &UserUseCases{repo: issued}
+
+				_, err := usecase.GeneratePAT(context.Background(), "ci", tt.kind, "", tt.scopes, tt.expiryDays)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GeneratePAT() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr && len(issued.kinds) != 0 {
+					t.Errorf("GeneratePAT() issued a %v token", issued.kinds)
+				}
+			},
+		)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"notiboy/config"
 	"notiboy/pkg/entities"
 	"notiboy/pkg/repo"
+	"notiboy/pkg/repo/driver/db"
 	mediumLib "notiboy/pkg/repo/driver/medium"
 	"notiboy/ui/templates"
 	"notiboy/utilities"
@@ -122,7 +123,13 @@ func (verify *VerifyUseCases) Callback(ctx context.Context, user entities.UserId
 			log.Error("Failed to add Discord guild member:", err)
 			return fmt.Errorf("failed to add Discord guild member: %w", err)
 		}
-		return verify.repo.CallbackDiscord(ctx, chainAddress[1], userId, chainAddress[0])
+		if err := verify.repo.CallbackDiscord(ctx, chainAddress[1], userId, chainAddress[0]); err != nil {
+			return err
+		}
+
+		shareVerifiedMediums(ctx, entities.UserIdentifier{Chain: chainAddress[0], Address: chainAddress[1]})
+
+		return nil
 	}
 
 	if medium == "email" {
@@ -132,6 +139,8 @@ func (verify *VerifyUseCases) Callback(ctx context.Context, user entities.UserId
 			return err
 		}
 
+		shareVerifiedMediums(ctx, user)
+
 		var renderData entities.TplRenderData
 
 		emailConfirmationSubject := "Email Confirmation"
@@ -159,3 +168,21 @@ func (verify *VerifyUseCases) Callback(ctx context.Context, user entities.UserId
 
 	return errors.New("please enter a valid medium")
 }
+
+// shareVerifiedMediums shares the mediums the user just verified with its linked accounts. A failure
+// doesn't fail the verification, the mediums are shared again whenever another medium is verified.
+func shareVerifiedMediums(ctx context.Context, user entities.UserIdentifier) {
+	log := utilities.NewLogger("shareVerifiedMediums")
+
+	links, err := db.GetLinkedAccounts(ctx, user.Chain, user.Address)
+	if err != nil {
+		log.WithError(err).Error("failed to get linked accounts")
+		return
+	}
+
+	for _, link := range links {
+		if err = db.ShareVerifiedMediums(ctx, user, link.UserIdentifier); err != nil {
+			log.WithError(err).Errorf("failed to share verified mediums with %s:%s", link.Chain, link.Address)
+		}
+	}
+}
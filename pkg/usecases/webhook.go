@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	uuidLib "github.com/google/uuid"
+
+	"notiboy/config"
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+	"notiboy/utilities"
+	"notiboy/utilities/http_client"
+)
+
+type WebhookUseCases struct {
+	repo    repo.WebhookRepoImply
+	members repo.ChannelMemberRepoImply
+}
+
+type WebhookUseCaseImply interface {
+	CreateWebhook(ctx context.Context, chain, appID, user string, request entities.WebhookRequest) (*entities.Webhook, error)
+	ListWebhooks(ctx context.Context, chain, appID, user string) ([]entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, chain, appID, user, webhookID string) error
+	ListWebhookDeliveries(ctx context.Context, chain, appID, user, webhookID string, limit int) ([]entities.WebhookDelivery, error)
+}
+
+// NewWebhookUseCases
+func NewWebhookUseCases(webhookRepo repo.WebhookRepoImply, members repo.ChannelMemberRepoImply) WebhookUseCaseImply {
+	return &WebhookUseCases{
+		repo:    webhookRepo,
+		members: members,
+	}
+}
+
+// CreateWebhook registers a webhook for a channel the user may edit.
+// The returned webhook holds the signing secret, which is not shown again afterwards.
+func (wuc *WebhookUseCases) CreateWebhook(
+	ctx context.Context, chain, appID, user string, request entities.WebhookRequest,
+) (*entities.Webhook, error) {
+	if err := verifyChannelPermission(ctx, wuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	webhookURL, err := url.Parse(request.URL)
+	if err != nil || webhookURL.Host == "" {
+		return nil, fmt.Errorf("invalid webhook url %s", request.URL)
+	}
+	local := config.GetConfig().Mode == "local"
+	if webhookURL.Scheme != "https" && !(webhookURL.Scheme == "http" && local) {
+		return nil, fmt.Errorf("webhook url must use https")
+	}
+	// checked again whenever a delivery connects, the host may resolve differently by then
+	if err = http_client.CheckPublicHost(ctx, webhookURL.Hostname()); err != nil && !local {
+		return nil, fmt.Errorf("webhook url must point to a public host: %w", err)
+	}
+
+	if len(request.Events) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+	events := make([]string, 0, len(request.Events))
+	for _, event := range request.Events {
+		if !utilities.ContainsString(consts.WebhookEvents, event) {
+			return nil, fmt.Errorf("unsupported webhook event %s", event)
+		}
+		if !utilities.ContainsString(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	webhooks, err := wuc.repo.ListWebhooks(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) >= consts.MaxChannelWebhooks {
+		return nil, fmt.Errorf("a channel can have at most %d webhooks", consts.MaxChannelWebhooks)
+	}
+
+	secret, err := utilities.GenerateSecret(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := &entities.Webhook{
+		ID:          uuidLib.NewString(),
+		Chain:       chain,
+		Channel:     appID,
+		URL:         webhookURL.String(),
+		Events:      events,
+		Secret:      secret,
+		CreatedTime: utilities.TimeNow(),
+	}
+	if err = wuc.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks retrieves the webhooks of a channel the user may edit, without their secrets.
+func (wuc *WebhookUseCases) ListWebhooks(ctx context.Context, chain, appID, user string) ([]entities.Webhook, error) {
+	if err := verifyChannelPermission(ctx, wuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := wuc.repo.ListWebhooks(ctx, chain, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook of a channel the user may edit.
+func (wuc *WebhookUseCases) DeleteWebhook(ctx context.Context, chain, appID, user, webhookID string) error {
+	if err := verifyChannelPermission(ctx, wuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return err
+	}
+
+	return wuc.repo.DeleteWebhook(ctx, chain, appID, webhookID)
+}
+
+// ListWebhookDeliveries retrieves the delivery log of a webhook of a channel the user may edit.
+func (wuc *WebhookUseCases) ListWebhookDeliveries(
+	ctx context.Context, chain, appID, user, webhookID string, limit int,
+) ([]entities.WebhookDelivery, error) {
+	if err := verifyChannelPermission(ctx, wuc.members, chain, appID, user, consts.ChannelPermEdit); err != nil {
+		return nil, err
+	}
+
+	return wuc.repo.ListWebhookDeliveries(ctx, chain, appID, webhookID, limit)
+}
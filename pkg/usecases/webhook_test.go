@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"notiboy/pkg/consts"
+	"notiboy/pkg/entities"
+	"notiboy/pkg/repo"
+)
+
+// fakeWebhookRepo holds the webhooks of a single channel
+type fakeWebhookRepo struct {
+	repo.WebhookRepoImply
+
+	webhooks []entities.Webhook
+}
+
+func (f *fakeWebhookRepo) ListWebhooks(context.Context, string, string) ([]entities.Webhook, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeWebhookRepo) CreateWebhook(_ context.Context, webhook *entities.Webhook) error {
+	f.webhooks = append(f.webhooks, *webhook)
+	return nil
+}
+
+func TestWebhookUseCases_CreateWebhook(t *testing.T) {
+	members := channelMembers{
+		roles: map[string]string{
+			"owner":  consts.ChannelRoleOwner,
+			"sender": consts.ChannelRoleSender,
+		},
+	}
+	setMode(t, "stage")
+
+	events := []string{consts.WebhookOptin}
+
+	tests := []struct {
+		name     string
+		user     string
+		url      string
+		events   []string
+		existing int
+		wantErr  bool
+	}{
+		{
+			name:    "without edit permission",
+			user:    "sender",
+			url:     "https://93.184.216.34/hook",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "plain http",
+			user:    "owner",
+			url:     "http://93.184.216.34/hook",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "loopback",
+			user:    "owner",
+			url:     "https://127.0.0.1/hook",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "localhost",
+			user:    "owner",
+			url:     "https://localhost:8443/hook",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "private network",
+			user:    "owner",
+			url:     "https://10.0.0.5/hook",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "cloud metadata",
+			user:    "owner",
+			url:     "https://169.254.169.254/latest/meta-data",
+			events:  events,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported event",
+			user:    "owner",
+			url:     "https://93.184.216.34/hook",
+			events:  []string{"notification.sent"},
+			wantErr: true,
+		},
+		{
+			name:     "too many webhooks",
+			user:     "owner",
+			url:      "https://93.184.216.34/hook",
+			events:   events,
+			existing: consts.MaxChannelWebhooks,
+			wantErr:  true,
+		},
+		{
+			name:   "public host",
+			user:   "owner",
+			url:    "https://93.184.216.34/hook",
+			events: []string{consts.WebhookOptin, consts.WebhookOptout, consts.WebhookOptin},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				webhookRepo := &fakeWebhookRepo{webhooks: make([]entities.Webhook, tt.existing)}
+				usecase := &WebhookUseCases{repo: webhookRepo, members: members}
+
+				webhook, err := usecase.CreateWebhook(
+					context.Background(), consts.Algorand, "1", tt.user,
+					entities.WebhookRequest{URL: tt.url, Events: tt.events},
+				)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("CreateWebhook() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+
+				if webhook.Secret == "" {
+					t.Error("CreateWebhook() returned no secret")
+				}
+				if len(webhook.Events) != 2 {
+					t.Errorf("CreateWebhook() events = %v, want them deduplicated", webhook.Events)
+				}
+			},
+		)
+	}
+}
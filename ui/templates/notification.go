@@ -224,6 +224,7 @@ var notificationHTMLTemplate = `
   </table>
   <!--[if mso]></div><![endif]-->
   <!--[if IE]></div><![endif]-->
+{{if .Pixel}}<img src="{{.Pixel}}" width="1" height="1" alt="" style="display:block;border:0;width:1px;height:1px;" />{{end}}
 </body>
 
 </html>
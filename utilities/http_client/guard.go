@@ -0,0 +1,121 @@
+package http_client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var ErrNonPublicAddress = errors.New("address is not publicly routable")
+
+// nonPublicNetworks are the ranges, beyond loopback, private and link-local ones, no outbound request should reach
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",       // this network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved
+	"64:ff9b::/96",    // NAT64, may embed any IPv4 address
+	"2001:db8::/32",   // documentation
+)
+
+var publicClient *http.Client
+
+// GetPublicClient returns a client that only connects to publicly routable addresses. The address is checked once
+// resolved, right before connecting, so that a host resolving to a public address when validated and to an internal
+// one when called can't reach the internal network. Redirects are checked the same way.
+func GetPublicClient() *http.Client {
+	if publicClient != nil {
+		return publicClient
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			return CheckPublicIP(net.ParseIP(host))
+		},
+	}
+
+	publicClient = &http.Client{
+		Transport: &http.Transport{
+			// a proxy would be dialled instead of the host
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: 10,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     30 * time.Second,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: false,
+			},
+		},
+		Timeout: time.Second * 30,
+	}
+
+	return publicClient
+}
+
+// CheckPublicIP rejects the loopback, private, link-local (cloud metadata included), multicast and reserved addresses
+func CheckPublicIP(ip net.IP) error {
+	if ip == nil {
+		return ErrNonPublicAddress
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+		}
+	}
+
+	return nil
+}
+
+// CheckPublicHost resolves host and rejects it unless all its addresses are publicly routable
+func CheckPublicHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return CheckPublicIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+
+	for _, addr := range addrs {
+		if err = CheckPublicIP(addr.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks
+}
@@ -0,0 +1,70 @@
+package http_client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckPublicIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		wantErr bool
+	}{
+		{ip: "93.184.216.34", wantErr: false},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", wantErr: false},
+		{ip: "127.0.0.1", wantErr: true},
+		{ip: "::1", wantErr: true},
+		{ip: "::ffff:127.0.0.1", wantErr: true},
+		{ip: "10.1.2.3", wantErr: true},
+		{ip: "172.16.0.1", wantErr: true},
+		{ip: "192.168.1.1", wantErr: true},
+		{ip: "fd00:ec2::254", wantErr: true},
+		{ip: "169.254.169.254", wantErr: true},
+		{ip: "fe80::1", wantErr: true},
+		{ip: "100.100.100.200", wantErr: true},
+		{ip: "0.0.0.0", wantErr: true},
+		{ip: "224.0.0.1", wantErr: true},
+		{ip: "64:ff9b::a9fe:a9fe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			err := CheckPublicIP(net.ParseIP(tt.ip))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPublicIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrNonPublicAddress) {
+				t.Errorf("CheckPublicIP() error = %v, want %v", err, ErrNonPublicAddress)
+			}
+		})
+	}
+}
+
+func TestCheckPublicHost(t *testing.T) {
+	if err := CheckPublicHost(context.Background(), "localhost"); !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("CheckPublicHost(localhost) error = %v, want %v", err, ErrNonPublicAddress)
+	}
+	if err := CheckPublicHost(context.Background(), "169.254.169.254"); !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("CheckPublicHost(169.254.169.254) error = %v, want %v", err, ErrNonPublicAddress)
+	}
+}
+
+func TestGetPublicClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the server listens on loopback, which the dialer refuses whatever name it is reached by
+	resp, err := GetPublicClient().Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("GetPublicClient() reached a loopback server")
+	}
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("GetPublicClient() error = %v, want %v", err, ErrNonPublicAddress)
+	}
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"notiboy/config"
@@ -15,30 +16,15 @@ import (
 )
 
 type jwtClaims struct {
-	Chain   string `json:"chain"`
-	Address string `json:"address"`
-	Kind    string `json:"kind"`
-	Uuid    string `json:"uuid"`
+	Chain   string   `json:"chain"`
+	Address string   `json:"address"`
+	Kind    string   `json:"kind"`
+	Uuid    string   `json:"uuid"`
+	Session string   `json:"sid,omitempty"`
+	Scopes  []string `json:"scopes,omitempty"`
 	jwt.StandardClaims
 }
 
-func getRSAKeyPair() (*jose.JSONWebKey, *jose.JSONWebKey, error) {
-	var pvtKey, pubKey jose.JSONWebKey
-
-	pvtKeyBytes := []byte(pvtKeyRaw)
-	pubKeyBytes := []byte(pubKeyRaw)
-
-	if err := pvtKey.UnmarshalJSON(pvtKeyBytes); err != nil {
-		return nil, nil, err
-	}
-
-	if err := pubKey.UnmarshalJSON(pubKeyBytes); err != nil {
-		return nil, nil, err
-	}
-
-	return &pvtKey, &pubKey, nil
-}
-
 func signPayload(key *jose.JSONWebKey, payload []byte) (jws string, err error) {
 	signingKey := jose.SigningKey{Key: key, Algorithm: jose.RS256}
 
@@ -56,6 +42,22 @@ func signPayload(key *jose.JSONWebKey, payload []byte) (jws string, err error) {
 }
 
 func GenerateJWT(address, chain, kind, uuid string, ttl time.Duration) (string, int, error) {
+	return generateJWT(address, chain, kind, uuid, "", nil, ttl)
+}
+
+// GenerateScopedJWT generates a token that is only allowed what its scopes grant, no scopes grant everything
+func GenerateScopedJWT(address, chain, kind, uuid string, scopes []string, ttl time.Duration) (string, int, error) {
+	return generateJWT(address, chain, kind, uuid, "", scopes, ttl)
+}
+
+// GenerateSessionJWT generates a short-lived access token bound to the login session sessionID
+func GenerateSessionJWT(address, chain, sessionID string, ttl time.Duration) (string, int, error) {
+	return generateJWT(address, chain, "", "", sessionID, nil, ttl)
+}
+
+func generateJWT(
+	address, chain, kind, uuid, sessionID string, scopes []string, ttl time.Duration,
+) (string, int, error) {
 	log := utilities.NewLogger("GenerateJWT")
 
 	ttlInSecs := ttl.Seconds()
@@ -66,6 +68,8 @@ func GenerateJWT(address, chain, kind, uuid string, ttl time.Duration) (string,
 		address,
 		kind,
 		uuid,
+		sessionID,
+		scopes,
 		jwt.StandardClaims{
 			Subject:   config.GetConfig().DB.Keyspace,
 			Audience:  address,
@@ -80,12 +84,12 @@ func GenerateJWT(address, chain, kind, uuid string, ttl time.Duration) (string,
 		return "", 0, err
 	}
 
-	signingKey, _, err := getRSAKeyPair()
+	key, err := signingKey()
 	if err != nil {
 		return "", 0, err
 	}
 
-	jwtToken, err := signPayload(signingKey, payload)
+	jwtToken, err := signPayload(key, payload)
 	if err != nil {
 		return "", 0, err
 	}
@@ -105,9 +109,13 @@ func VerifyJWT(address, jwtToken string) (map[string]string, error) {
 		return nil, err
 	}
 
-	_, pubKey, err := getRSAKeyPair()
+	if len(jws.Signatures) != 1 {
+		return nil, fmt.Errorf("expected a single signature, found %d", len(jws.Signatures))
+	}
+
+	pubKey, err := verificationKey(jws.Signatures[0].Header.KeyID)
 	if err != nil {
-		log.WithError(err).Error("unable to get rsa key pair")
+		log.WithError(err).Error("unable to get verification key")
 		return nil, err
 	}
 
@@ -147,5 +155,7 @@ func VerifyJWT(address, jwtToken string) (map[string]string, error) {
 		"address": claims.Address,
 		"uuid":    claims.Uuid,
 		"kind":    claims.Kind,
+		"session": claims.Session,
+		"scopes":  strings.Join(claims.Scopes, " "),
 	}, nil
 }
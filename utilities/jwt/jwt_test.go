@@ -3,15 +3,29 @@ package jwt
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/spf13/cast"
 
 	"notiboy/config"
 )
 
+// setTestConfig signs with the development key of local mode
+func setTestConfig(t *testing.T) {
+	t.Helper()
+
+	config.SetConfig(
+		&config.NotiboyConfModel{
+			Mode:             "local",
+			LoginTokenExpiry: "999h",
+			DB:               config.DB{Keyspace: "notiboy"},
+		},
+	)
+	ring = &keyring{}
+}
+
 func TestGenerateJWT(t *testing.T) {
-	config.LoadConfig()
-	config.GetConfig().LoginTokenExpiry = "999h"
+	setTestConfig(t)
 	type args struct {
 		address    string
 		chain      string
@@ -54,3 +68,139 @@ func TestGenerateJWT(t *testing.T) {
 		)
 	}
 }
+
+func TestGenerateScopedJWT(t *testing.T) {
+	setTestConfig(t)
+
+	address := "EMAVMBG5P4AHJBNDSJSFH2USQSWIE6QQOVQLAPGXI2HQ3OJ7ILH6CDMOVU"
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   string
+	}{
+		{
+			name: "unscoped",
+		},
+		{
+			name:   "scoped",
+			scopes: []string{"notifications:send:1", "channels:read"},
+			want:   "notifications:send:1 channels:read",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				token, _, err := GenerateScopedJWT(address, "algorand", "normal", "uuid", tt.scopes, time.Hour)
+				if err != nil {
+					t.Fatalf("GenerateScopedJWT() error = %v", err)
+				}
+
+				claims, err := VerifyJWT(address, token)
+				if err != nil {
+					t.Fatalf("VerifyJWT() error = %v", err)
+				}
+				if claims["scopes"] != tt.want {
+					t.Errorf("scopes claim = %q, want %q", claims["scopes"], tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_loadKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		wantKid string
+		wantErr bool
+	}{
+		{
+			name:    "development key in local mode",
+			mode:    "local",
+			wantKid: "sig-1686381758",
+		},
+		{
+			name:    "no keys outside local mode",
+			mode:    "prod",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				config.SetConfig(&config.NotiboyConfModel{Mode: tt.mode, JWT: config.JWT{KeysDir: t.TempDir()}})
+
+				keys, err := loadKeys()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("loadKeys() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && (len(keys) != 1 || keys[tt.wantKid] == nil) {
+					t.Errorf("loadKeys() = %v, want the key %s", keys, tt.wantKid)
+				}
+			},
+		)
+	}
+}
+
+// TestGenerateKey rotates the development key of a deployment out, tokens it signed stay valid
+func TestGenerateKey(t *testing.T) {
+	dir := t.TempDir()
+	config.SetConfig(
+		&config.NotiboyConfModel{
+			Mode: "local",
+			DB:   config.DB{Keyspace: "notiboy"},
+			JWT:  config.JWT{KeysDir: dir},
+		},
+	)
+	ring = &keyring{}
+
+	address := "EMAVMBG5P4AHJBNDSJSFH2USQSWIE6QQOVQLAPGXI2HQ3OJ7ILH6CDMOVU"
+
+	devToken, _, err := GenerateJWT(address, "algorand", "", "", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWT() error = %v", err)
+	}
+
+	kid, err := GenerateKey(dir)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	// outside local mode the keys directory is all there is
+	config.GetConfig().Mode = "prod"
+
+	keys, err := loadKeys()
+	if err != nil {
+		t.Fatalf("loadKeys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[kid] == nil || keys["sig-1686381758"] == nil {
+		t.Fatalf("loadKeys() = %v, want the development key and %s", keys, kid)
+	}
+
+	// an instance that already picked up the new key signs with it
+	stale := ring
+	ring = &keyring{}
+	token, _, err := GenerateJWT(address, "algorand", "", "", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWT() error = %v", err)
+	}
+
+	// the token makes an instance that loaded its keys before the rotation read them again
+	ring = stale
+	ring.loadedAt = time.Now().Add(-keysRefreshInterval)
+	if _, err = VerifyJWT(address, token); err != nil {
+		t.Errorf("VerifyJWT() of the rotated key error = %v", err)
+	}
+	if _, err = VerifyJWT(address, devToken); err != nil {
+		t.Errorf("VerifyJWT() of the development key error = %v", err)
+	}
+
+	signing, err := signingKey()
+	if err != nil {
+		t.Fatalf("signingKey() error = %v", err)
+	}
+	if signing.KeyID != kid {
+		t.Errorf("signingKey() = %s, want %s", signing.KeyID, kid)
+	}
+}
@@ -2,6 +2,8 @@ package jwt
 
 /*
 Generated using https://mkjwk.org/
+
+Development key, only used in local mode when no keys are configured. It is public, never use it in production.
 */
 
 var pvtKeyRaw = `
@@ -20,14 +22,3 @@ var pvtKeyRaw = `
     "n": "gHGMjrcnJOJ8Df-9GH_GzW_kLYwZ8k2_Vru83cEuJZTnMrJArblrGoLtMm_7OBoj7hauq-zl8wZlFPW5p3RR-cBnkmGlip1QGQzyS3IxcD2d0bSU9KrgvClZKgqeQuZSbQdWyRKTWMaoV1ueGsJ2ulmxwA4phVGD30AEAZaFRYCpGd10-Qg4ufMyy9_nsLxhm0RRghKZES9LyArb02YnAIi9ZcUEnJBw9-UFAdOmenBSd-ud9ovU6NBVxuXPvYoHQlFYGVcqAdwDwL4zO05l0SvTLxTtvilKW4fC9eA0DId-CZjPo8kZh5t9COPuIhob2foptyTLPRWPmVvsciOyLw"
 }
 `
-
-var pubKeyRaw = `
-{
-    "kty": "RSA",
-    "e": "AQAB",
-    "use": "sig",
-    "kid": "sig-1686381758",
-    "alg": "RS256",
-    "n": "gHGMjrcnJOJ8Df-9GH_GzW_kLYwZ8k2_Vru83cEuJZTnMrJArblrGoLtMm_7OBoj7hauq-zl8wZlFPW5p3RR-cBnkmGlip1QGQzyS3IxcD2d0bSU9KrgvClZKgqeQuZSbQdWyRKTWMaoV1ueGsJ2ulmxwA4phVGD30AEAZaFRYCpGd10-Qg4ufMyy9_nsLxhm0RRghKZES9LyArb02YnAIi9ZcUEnJBw9-UFAdOmenBSd-ud9ovU6NBVxuXPvYoHQlFYGVcqAdwDwL4zO05l0SvTLxTtvilKW4fC9eA0DId-CZjPo8kZh5t9COPuIhob2foptyTLPRWPmVvsciOyLw"
-}
-`
@@ -0,0 +1,309 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"notiboy/config"
+	"notiboy/utilities"
+)
+
+// keysReloadInterval is how often the keys directory is read again, so that a rotated key is
+// picked up by every instance without a restart
+const keysReloadInterval = time.Minute
+
+// keysRefreshInterval limits how often a token signed by an unknown key makes the keys be read again
+const keysRefreshInterval = 5 * time.Second
+
+// keyring holds the active signing keys by kid. Every active key verifies tokens,
+// new tokens are signed with one of them.
+type keyring struct {
+	mu       sync.RWMutex
+	keys     map[string]*jose.JSONWebKey
+	loadedAt time.Time
+}
+
+var ring = &keyring{}
+
+func (r *keyring) get() (map[string]*jose.JSONWebKey, error) {
+	return r.load(keysReloadInterval)
+}
+
+// refresh reads the keys again unless they were read within keysRefreshInterval, a token may be
+// signed by a key another instance rotated to before this one reloaded its keys
+func (r *keyring) refresh() (map[string]*jose.JSONWebKey, error) {
+	return r.load(keysRefreshInterval)
+}
+
+// load returns the keys, read again when they were loaded longer than maxAge ago
+func (r *keyring) load(maxAge time.Duration) (map[string]*jose.JSONWebKey, error) {
+	r.mu.RLock()
+	keys, loadedAt := r.keys, r.loadedAt
+	r.mu.RUnlock()
+
+	if keys != nil && time.Since(loadedAt) < maxAge {
+		return keys, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.keys != nil && time.Since(r.loadedAt) < maxAge {
+		return r.keys, nil
+	}
+
+	keys, err := loadKeys()
+	if err != nil {
+		if r.keys == nil {
+			return nil, err
+		}
+		// keep verifying with the keys we have rather than failing every request
+		utilities.NewLogger("keyring").WithError(err).Error("failed to reload jwt keys")
+		keys = r.keys
+	}
+
+	r.keys, r.loadedAt = keys, time.Now()
+
+	return keys, nil
+}
+
+// loadKeys reads the private keys from the NOTIBOY_JWT_KEYS JWK set and the keys directory.
+// The compiled-in development key is public, it is only used in local mode when neither has a key.
+func loadKeys() (map[string]*jose.JSONWebKey, error) {
+	log := utilities.NewLogger("loadKeys")
+	conf := config.GetConfig().JWT
+
+	keys := make(map[string]*jose.JSONWebKey)
+
+	if conf.Keys != "" {
+		var set jose.JSONWebKeySet
+		if err := json.Unmarshal([]byte(conf.Keys), &set); err != nil {
+			return nil, fmt.Errorf("failed to parse jwt key set: %w", err)
+		}
+
+		for i := range set.Keys {
+			if err := addKey(keys, &set.Keys[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if conf.KeysDir != "" {
+		files, err := filepath.Glob(filepath.Join(conf.KeysDir, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jwt keys: %w", err)
+		}
+
+		for _, file := range files {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read jwt key %s: %w", file, err)
+			}
+
+			var key jose.JSONWebKey
+			if err = key.UnmarshalJSON(raw); err != nil {
+				return nil, fmt.Errorf("failed to parse jwt key %s: %w", file, err)
+			}
+			if key.KeyID == "" {
+				key.KeyID = strings.TrimSuffix(filepath.Base(file), ".json")
+			}
+
+			if err = addKey(keys, &key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		if config.GetConfig().Mode != "local" {
+			return nil, errors.New("no jwt keys configured, generate one with `notiboy rotate-jwt-key`")
+		}
+
+		log.Warn("no jwt keys configured, signing with the development key")
+
+		key, err := developmentKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[key.KeyID] = key
+	}
+
+	return keys, nil
+}
+
+func developmentKey() (*jose.JSONWebKey, error) {
+	var key jose.JSONWebKey
+	if err := key.UnmarshalJSON([]byte(pvtKeyRaw)); err != nil {
+		return nil, fmt.Errorf("failed to parse development key: %w", err)
+	}
+
+	return &key, nil
+}
+
+func addKey(keys map[string]*jose.JSONWebKey, key *jose.JSONWebKey) error {
+	if key.KeyID == "" {
+		return errors.New("jwt key without kid")
+	}
+	if key.IsPublic() || !key.Valid() {
+		return fmt.Errorf("jwt key %s is not a valid private key", key.KeyID)
+	}
+	if _, ok := keys[key.KeyID]; ok {
+		return fmt.Errorf("duplicate jwt key %s", key.KeyID)
+	}
+
+	keys[key.KeyID] = key
+
+	return nil
+}
+
+// signingKey is the key of jwt.signing_kid, or the newest key when it isn't set.
+// Keys generated by GenerateKey have kids that sort by creation time.
+func signingKey() (*jose.JSONWebKey, error) {
+	keys, err := ring.get()
+	if err != nil {
+		return nil, err
+	}
+
+	kid := config.GetConfig().JWT.SigningKid
+	if kid == "" {
+		for id := range keys {
+			if id > kid {
+				kid = id
+			}
+		}
+	}
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("signing key %s not found", kid)
+	}
+
+	return key, nil
+}
+
+// verificationKey is the public key of kid. Tokens without a kid are only accepted
+// when there is a single key.
+func verificationKey(kid string) (*jose.JSONWebKey, error) {
+	keys, err := ring.get()
+	if err != nil {
+		return nil, err
+	}
+
+	if kid != "" && keys[kid] == nil {
+		if keys, err = ring.refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	if kid == "" && len(keys) == 1 {
+		for id := range keys {
+			kid = id
+		}
+	}
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %s", kid)
+	}
+
+	pubKey := key.Public()
+
+	return &pubKey, nil
+}
+
+// PublicKeySet returns the public keys of every active key, as published at /.well-known/jwks.json
+func PublicKeySet() (jose.JSONWebKeySet, error) {
+	keys, err := ring.get()
+	if err != nil {
+		return jose.JSONWebKeySet{}, err
+	}
+
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(kids))}
+	for _, kid := range kids {
+		set.Keys = append(set.Keys, keys[kid].Public())
+	}
+
+	return set, nil
+}
+
+// GenerateKey writes a new RSA signing key to dir and returns its kid. Unless jwt.signing_kid pins
+// another key, it signs new tokens once instances reload their keys. A retired key file can be
+// removed once the tokens it signed have expired.
+// Without any configured key tokens were signed with the development key, the first key generated
+// is written along with it so that those tokens stay valid until they expire. Its file has to be
+// removed after login_token_expiry, the development key is public.
+func GenerateKey(dir string) (string, error) {
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list jwt keys: %w", err)
+	}
+	if len(existing) == 0 && config.GetConfig().JWT.Keys == "" {
+		devKey, err := developmentKey()
+		if err != nil {
+			return "", err
+		}
+		if err = writeKey(dir, devKey); err != nil {
+			return "", err
+		}
+	}
+
+	pvtKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate rsa key: %w", err)
+	}
+
+	kid := fmt.Sprintf("sig-%d", time.Now().Unix())
+	key := &jose.JSONWebKey{
+		Key:       pvtKey,
+		KeyID:     kid,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}
+
+	if err = writeKey(dir, key); err != nil {
+		return "", err
+	}
+
+	return kid, nil
+}
+
+// writeKey writes key to <kid>.json in dir, an existing key file is never overwritten
+func writeKey(dir string, key *jose.JSONWebKey) error {
+	raw, err := json.MarshalIndent(key, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal key: %w", err)
+	}
+
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create keys directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, key.KeyID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(raw); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"notiboy/config"
+	"notiboy/utilities"
+)
+
+var ErrInvalidToken = errors.New("invalid tracking token")
+
+// Claims identifies the notification, receiver and medium a tracking token was issued for
+type Claims struct {
+	Chain    string `json:"c"`
+	AppID    string `json:"a"`
+	UUID     string `json:"u"`
+	Receiver string `json:"r"`
+	Medium   string `json:"m"`
+}
+
+// Sign serialises the claims and appends an HMAC-SHA256 signature computed with secret
+func Sign(secret string, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tracking claims: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + signature(secret, encoded), nil
+}
+
+// Verify validates the signature of the token and returns the claims embedded in it
+func Verify(secret, token string) (*Claims, error) {
+	encoded, sig, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// LinkURL returns the click tracking redirect URL for the claims.
+// Empty links are returned as is since there is nothing to redirect to.
+func LinkURL(link string, claims Claims) (string, error) {
+	if link == "" {
+		return link, nil
+	}
+
+	token, err := Sign(config.GetConfig().Tracking.Secret, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return url.JoinPath(config.ServerBaseURL, "r", token)
+}
+
+// TrackedLink rewrites the link to the click tracking redirect URL for the claims, unless the receiver opted out of
+// tracking. It falls back to the link itself on failure so that the notification still goes out.
+func TrackedLink(link string, claims Claims, optOut bool) string {
+	if optOut {
+		return link
+	}
+
+	trackingURL, err := LinkURL(link, claims)
+	if err != nil {
+		utilities.NewLogger("TrackedLink").WithError(err).Error("failed to create tracking link")
+		return link
+	}
+
+	return trackingURL
+}
+
+// PixelURL returns the open tracking pixel URL for the claims
+func PixelURL(claims Claims) (string, error) {
+	token, err := Sign(config.GetConfig().Tracking.Secret, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return url.JoinPath(config.ServerBaseURL, "o", token)
+}
+
+func signature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
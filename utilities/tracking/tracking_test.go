@@ -0,0 +1,77 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+
+	"notiboy/config"
+)
+
+func TestSignVerify(t *testing.T) {
+	claims := Claims{
+		Chain:    "algorand",
+		AppID:    "c7b1ae2a-3a64-4a7c-93c1-0c5d5ee3b7f5",
+		UUID:     "f8a0d3e4-6a1b-4c9e-8f0a-3b2c1d4e5f60",
+		Receiver: "EMAVMBG5P4AHJBNDSJSFH2USQSWIE6QQOVQLAPGXI2HQ3OJ7ILH6CDMOVU",
+		Medium:   "email",
+	}
+
+	token, err := Sign("secret", claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	got, err := Verify("secret", token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if *got != claims {
+		t.Errorf("Verify() got = %+v, want %+v", *got, claims)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		token  string
+	}{
+		{name: "wrong secret", secret: "other", token: token},
+		{name: "tampered payload", secret: "secret", token: "e30" + token[3:]},
+		{name: "missing signature", secret: "secret", token: "e30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.secret, tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestTrackedLink(t *testing.T) {
+	config.SetConfig(&config.NotiboyConfModel{Tracking: config.Tracking{Secret: "secret"}})
+	config.ServerBaseURL = "https://app.notiboy.com/api/stage/v1"
+
+	claims := Claims{Chain: "algorand", AppID: "1", UUID: "uuid", Receiver: "alice", Medium: "email"}
+	token, err := Sign("secret", claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		link   string
+		optOut bool
+		want   string
+	}{
+		{name: "tracked", link: "https://notiboy.com", want: config.ServerBaseURL + "/r/" + token},
+		{name: "opted out", link: "https://notiboy.com", optOut: true, want: "https://notiboy.com"},
+		{name: "no link", link: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrackedLink(tt.link, claims, tt.optOut); got != tt.want {
+				t.Errorf("TrackedLink() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -43,6 +43,16 @@ func GenerateRandomToken() (string, error) {
 	return token, nil
 }
 
+// GenerateSecret returns n random bytes hex encoded, suitable as a signing secret
+func GenerateSecret(n int) (string, error) {
+	randBytes := make([]byte, n)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(randBytes), nil
+}
+
 func ContainsString(slice []string, str string) bool {
 	for _, s := range slice {
 		if s == str {
@@ -52,6 +62,20 @@ func ContainsString(slice []string, str string) bool {
 	return false
 }
 
+// Chunk splits items into consecutive chunks of at most size items
+func Chunk[T any](items []T, size int) [][]T {
+	if size <= 0 {
+		size = len(items)
+	}
+
+	chunks := make([][]T, 0, (len(items)+size-1)/max(size, 1))
+	for start := 0; start < len(items); start += size {
+		chunks = append(chunks, items[start:min(start+size, len(items))])
+	}
+
+	return chunks
+}
+
 func SliceToMap(sl []string) map[string]bool {
 	m := make(map[string]bool)
 
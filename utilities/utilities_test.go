@@ -0,0 +1,55 @@
+package utilities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		size  int
+		want  [][]string
+	}{
+		{
+			name:  "empty",
+			items: nil,
+			size:  2,
+			want:  [][]string{},
+		},
+		{
+			name:  "exact",
+			items: []string{"a", "b", "c", "d"},
+			size:  2,
+			want:  [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:  "remainder",
+			items: []string{"a", "b", "c"},
+			size:  2,
+			want:  [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name:  "bigger than items",
+			items: []string{"a", "b"},
+			size:  5,
+			want:  [][]string{{"a", "b"}},
+		},
+		{
+			name:  "no size",
+			items: []string{"a", "b", "c"},
+			size:  0,
+			want:  [][]string{{"a", "b", "c"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := Chunk(tt.items, tt.size); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Chunk() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}